@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 
 	// static assets
 	_ "github.com/SpectoLabs/hoverfly/core/statik"
@@ -28,7 +30,7 @@ func (this *AdminApi) StartAdminInterface(hoverfly *Hoverfly) {
 	mux = this.addDashboardRoutes(router)
 	n := negroni.New(negroni.NewRecovery())
 
-	n.UseHandler(mux)
+	n.UseHandler(this.newNamespacedRouter(mux, hoverfly))
 
 	// admin interface starting message
 	log.WithFields(log.Fields{
@@ -95,6 +97,7 @@ func (this *AdminApi) addDashboardRoutes(router *bone.Mux) *bone.Mux {
 func getAllHandlers(hoverfly *Hoverfly) []handlers.AdminHandler {
 	list := []handlers.AdminHandler{
 		&handlers.HealthHandler{},
+		&handlers.MetricsHandler{Hoverfly: hoverfly},
 
 		&v2.HoverflyHandler{Hoverfly: hoverfly},
 		&v2.HoverflyDestinationHandler{Hoverfly: hoverfly},
@@ -109,13 +112,74 @@ func getAllHandlers(hoverfly *Hoverfly) []handlers.AdminHandler {
 		&v2.CacheHandler{Hoverfly: hoverfly},
 		&v2.LogsHandler{Hoverfly: hoverfly.StoreLogsHook},
 		&v2.JournalHandler{Hoverfly: hoverfly.Journal},
-		&v2.ShutdownHandler{},
+		&v2.ShutdownHandler{Hoverfly: hoverfly},
 		&v2.StateHandler{Hoverfly: hoverfly},
 		&v2.DiffHandler{Hoverfly: hoverfly},
+		&v2.VerifyHandler{Hoverfly: hoverfly},
 		&v2.HoverflyPostServeActionDetailsHandler{Hoverfly: hoverfly},
+		&v2.HoverflyCustomMatchersHandler{Hoverfly: hoverfly},
+		&v2.SimulationMatchHandler{Hoverfly: hoverfly},
+		&v2.SimulationSourcesHandler{Hoverfly: hoverfly},
+		&v2.SimulationPairsHandler{Hoverfly: hoverfly},
+		&v2.ResourcesHandler{Hoverfly: hoverfly},
 		&v2.HoverflyTemplateDataSourceHandler{Hoverfly: hoverfly},
 		&v2.HoverflyJournalIndexHandler{Hoverfly: hoverfly},
+		&v2.NamespacesHandler{Hoverfly: hoverfly},
 	}
 
 	return list
 }
+
+// namespacedRouter sends API requests with a namespace parameter to the routes of that namespace, so that
+// every endpoint works on the simulation, state, journal and mode of the namespace
+type namespacedRouter struct {
+	adminApi *AdminApi
+	router   http.Handler
+	hoverfly *Hoverfly
+	routes   map[string]namespaceRoutes
+	mu       sync.Mutex
+}
+
+type namespaceRoutes struct {
+	namespace *Hoverfly
+	router    *bone.Mux
+}
+
+func (this *AdminApi) newNamespacedRouter(router http.Handler, hoverfly *Hoverfly) *namespacedRouter {
+	return &namespacedRouter{
+		adminApi: this,
+		router:   router,
+		hoverfly: hoverfly,
+		routes:   map[string]namespaceRoutes{},
+	}
+}
+
+func (this *namespacedRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("namespace")
+	// Shutting down stops the whole process, so it is always handled by the default namespace
+	if name == "" || !strings.HasPrefix(r.URL.Path, "/api/v2/") || strings.HasPrefix(r.URL.Path, "/api/v2/namespaces") ||
+		r.URL.Path == "/api/v2/shutdown" {
+		this.router.ServeHTTP(w, r)
+		return
+	}
+
+	namespace, err := this.hoverfly.Namespace(name)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	this.mu.Lock()
+	routes, ok := this.routes[name]
+	// A namespace which has been deleted and used again is a new Hoverfly, which needs its own routes
+	if !ok || routes.namespace != namespace {
+		routes = namespaceRoutes{
+			namespace: namespace,
+			router:    this.adminApi.addAdminApiRoutes(bone.New(), namespace),
+		}
+		this.routes[name] = routes
+	}
+	this.mu.Unlock()
+
+	routes.router.ServeHTTP(w, r)
+}
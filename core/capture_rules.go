@@ -0,0 +1,229 @@
+package hoverfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+var pathParameterRegex = regexp.MustCompile(`\{[^/{}]*\}`)
+
+// newCaptureRules validates the capture rules of a mode, filling in the default matchers
+func newCaptureRules(views []v2.CaptureRuleView) ([]modes.CaptureRule, error) {
+	var rules []modes.CaptureRule
+	for _, view := range views {
+		rule := modes.CaptureRule{
+			Field:   strings.ToLower(view.Field),
+			Key:     view.Key,
+			Matcher: strings.ToLower(view.Matcher),
+			Value:   view.Value,
+		}
+
+		switch rule.Field {
+		case "path":
+			if rule.Value == "" {
+				return nil, fmt.Errorf("Capture rule for path must have a value such as /users/{id}")
+			}
+		case "query", "header":
+			if rule.Key == "" {
+				return nil, fmt.Errorf("Capture rule for %s must have the %s name as the key", rule.Field, rule.Field)
+			}
+		case "body":
+			if !strings.HasPrefix(rule.Key, "$") {
+				return nil, fmt.Errorf("Capture rule for body must have a JSONPath such as $.requestId as the key")
+			}
+			segments, err := util.SplitJsonPath(rule.Key)
+			if err != nil {
+				return nil, err
+			}
+			if len(segments) == 0 {
+				return nil, fmt.Errorf("JSONPath %s does not select a field", rule.Key)
+			}
+			if _, ok := segments[len(segments)-1].(int); ok {
+				return nil, fmt.Errorf("Capture rule for body must select a field rather than an array item")
+			}
+		default:
+			return nil, fmt.Errorf("Capture rule field must be one of path, query, header or body")
+		}
+
+		if rule.Matcher == "" {
+			rule.Matcher = matchers.Glob
+			if rule.Field == "body" && rule.Value == "" {
+				rule.Matcher = matchers.JsonPartial
+			}
+		}
+
+		switch {
+		case rule.Matcher == matchers.Glob || rule.Matcher == matchers.Regex:
+		case rule.Matcher == matchers.JsonPartial && rule.Field == "body":
+		default:
+			return nil, fmt.Errorf("Capture rule for %s cannot use the %s matcher", rule.Field, view.Matcher)
+		}
+
+		if rule.Matcher == matchers.Regex && rule.Field != "path" && rule.Value != "" {
+			if _, err := regexp.Compile(rule.Value); err != nil {
+				return nil, fmt.Errorf("Capture rule for %s has an invalid regex: %s", rule.Field, err.Error())
+			}
+		}
+
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// applyCaptureRules replaces the exact matchers captured for a request with the matchers of the rules that apply
+// to it, so the pair also matches requests that only differ in those fields
+func applyCaptureRules(requestMatcher *models.RequestMatcher, request *models.RequestDetails, rules []modes.CaptureRule) {
+	var bodyRules []modes.CaptureRule
+	for _, rule := range rules {
+		switch rule.Field {
+		case "path":
+			if path, ok := generalisePath(rule, request.Path); ok {
+				requestMatcher.Path = []models.RequestFieldMatchers{path}
+			}
+		case "query":
+			if requestMatcher.Query == nil {
+				continue
+			}
+			for key := range *requestMatcher.Query {
+				if key == rule.Key {
+					(*requestMatcher.Query)[key] = []models.RequestFieldMatchers{newCaptureRuleMatcher(rule)}
+				}
+			}
+		case "header":
+			for key := range requestMatcher.Headers {
+				if strings.EqualFold(key, rule.Key) {
+					requestMatcher.Headers[key] = []models.RequestFieldMatchers{newCaptureRuleMatcher(rule)}
+				}
+			}
+		case "body":
+			bodyRules = append(bodyRules, rule)
+		}
+	}
+
+	if len(bodyRules) > 0 {
+		requestMatcher.Body = generaliseJsonBody(requestMatcher.Body, request.Body, bodyRules)
+	}
+}
+
+// generalisePath turns a path which fits the template of the rule, such as /users/{id}, into a glob or regex
+// where each parameter matches a single path segment
+func generalisePath(rule modes.CaptureRule, path string) (models.RequestFieldMatchers, bool) {
+	literals := pathParameterRegex.Split(rule.Value, -1)
+	for i, literal := range literals {
+		literals[i] = regexp.QuoteMeta(literal)
+	}
+	pattern := "^" + strings.Join(literals, "[^/]+") + "$"
+
+	if !regexp.MustCompile(pattern).MatchString(path) {
+		return models.RequestFieldMatchers{}, false
+	}
+
+	if rule.Matcher == matchers.Regex {
+		return models.RequestFieldMatchers{Matcher: matchers.Regex, Value: pattern}, true
+	}
+	return models.RequestFieldMatchers{Matcher: matchers.Glob, Value: pathParameterRegex.ReplaceAllString(rule.Value, "*")}, true
+}
+
+func newCaptureRuleMatcher(rule modes.CaptureRule) models.RequestFieldMatchers {
+	value := rule.Value
+	if value == "" && rule.Matcher == matchers.Regex {
+		value = ".*"
+	} else if value == "" {
+		value = "*"
+	}
+	return models.RequestFieldMatchers{Matcher: rule.Matcher, Value: value}
+}
+
+// generaliseJsonBody removes the paths of the rules from a captured JSON body, which is then matched partially.
+// A rule with a glob or regex also checks the value at the path with a jsonpath matcher.
+func generaliseJsonBody(body []models.RequestFieldMatchers, requestBody string, rules []modes.CaptureRule) []models.RequestFieldMatchers {
+	if len(body) != 1 || body[0].Matcher != matchers.Json {
+		return body
+	}
+
+	decoder := json.NewDecoder(bytes.NewBufferString(requestBody))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+
+	removed := false
+	var pathMatchers []models.RequestFieldMatchers
+	for _, rule := range rules {
+		segments, _ := util.SplitJsonPath(rule.Key)
+		if !removeJsonPath(data, segments) {
+			continue
+		}
+		removed = true
+		if rule.Matcher != matchers.JsonPartial {
+			pathMatcher := newCaptureRuleMatcher(rule)
+			pathMatchers = append(pathMatchers, models.RequestFieldMatchers{
+				Matcher: matchers.JsonPath,
+				Value:   rule.Key,
+				DoMatch: &pathMatcher,
+			})
+		}
+	}
+
+	// A body none of the rules apply to is matched exactly, as it would be without rules
+	if !removed {
+		return body
+	}
+
+	partialBody, err := json.Marshal(data)
+	if err != nil {
+		return body
+	}
+
+	return append([]models.RequestFieldMatchers{
+		{
+			Matcher: matchers.JsonPartial,
+			Value:   string(partialBody),
+		},
+	}, pathMatchers...)
+}
+
+// removeJsonPath deletes the value at the path, returning whether there was one
+func removeJsonPath(data interface{}, segments []interface{}) bool {
+	last := len(segments) == 1
+	switch segment := segments[0].(type) {
+	case string:
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		value, found := object[segment]
+		if !found {
+			return false
+		}
+		if last {
+			delete(object, segment)
+			return true
+		}
+		return removeJsonPath(value, segments[1:])
+	case int:
+		array, ok := data.([]interface{})
+		if !ok || last {
+			// Removing an item would shift the others, so only fields within items can be removed
+			return false
+		}
+		removed := false
+		for i, item := range array {
+			if segment == -1 || segment == i {
+				removed = removeJsonPath(item, segments[1:]) || removed
+			}
+		}
+		return removed
+	}
+	return false
+}
@@ -26,9 +26,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/SpectoLabs/goproxy"
@@ -42,6 +44,9 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	mw "github.com/SpectoLabs/hoverfly/core/middleware"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/persistence"
+	"github.com/SpectoLabs/hoverfly/core/templating"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 )
@@ -58,6 +63,7 @@ func (i *arrayFlags) Set(value string) error {
 }
 
 var importFlags arrayFlags
+var watchFlags arrayFlags
 var postServeActionFlags arrayFlags
 var templatingDataSourceFlags arrayFlags
 var destinationFlags arrayFlags
@@ -65,6 +71,7 @@ var logOutputFlags arrayFlags
 var responseBodyFilesPath string
 var responseBodyFilesAllowedOriginFlags arrayFlags
 var journalIndexingKeyFlags arrayFlags
+var grpcDescriptorSetFlags arrayFlags
 var journalBodySizeLimit util.MemorySize
 
 const boltBackend = "boltdb"
@@ -85,6 +92,7 @@ var (
 	adminPort     = flag.String("ap", "", "Admin port - run admin interface on another port (i.e. '-ap 1234' to run admin UI on port 1234)")
 	listenOnHost  = flag.String("listen-on-host", "", "Specify which network interface to bind to, eg. 0.0.0.0 will bind to all interfaces. By default hoverfly will only bind ports to loopback interface")
 	metrics       = flag.Bool("metrics", false, "Enable metrics logging to stdout")
+	otlpEndpoint  = flag.String("otlp-endpoint", "", "Export traces of proxied requests to an OpenTelemetry collector using OTLP over HTTP (i.e. '-otlp-endpoint http://localhost:4318')")
 	dev           = flag.Bool("dev", false, "Enable CORS headers to allow Hoverfly Admin UI development")
 	devCorsOrigin = flag.String("dev-cors-origin", "http://localhost:4200", "Custom CORS origin for dev mode")
 	destination   = flag.String("destination", ".", "Control which URLs Hoverfly should intercept and process, it can be string or regex")
@@ -111,6 +119,7 @@ var (
 	databasePath = flag.String("db-path", "", "A path to a BoltDB file with persisted user and token data for authentication (DEPRECATED)")
 	database     = flag.String("db", inmemoryBackend, "Storage to use - 'boltdb' or 'memory' which will not write anything to disk (DEPRECATED)")
 	disableCache = flag.Bool("disable-cache", false, "Disable the request/response cache (the cache that sits in front of matching)")
+	persistDir   = flag.String("persist-dir", "", "Directory in which captured pairs, state and templating data sources are stored, and reloaded from when Hoverfly starts")
 
 	logsFormat  = flag.String("logs", "plaintext", "Specify format for logs, options are \"plaintext\" and \"json\"")
 	logsSize    = flag.Int("logs-size", 1000, "Set the amount of logs to be stored in memory")
@@ -208,14 +217,16 @@ func isFlagPassed(name string) bool {
 func main() {
 	hoverfly := hv.NewHoverfly()
 
-	flag.Var(&importFlags, "import", "Import from file or from URL (i.e. '-import my_service.json' or '-import http://mypage.com/service_x.json'")
+	flag.Var(&importFlags, "import", "Import from file or from URL, either a simulation or an OpenAPI 3 document (i.e. '-import my_service.json' or '-import http://mypage.com/service_x.json' or '-import openapi.yaml'")
+	flag.Var(&watchFlags, "watch", "Load the simulation from files or directories of simulation files and reload it whenever they change, replacing the whole simulation. Cannot be used with -import or -persist-dir (i.e. '-watch simulations' or '-watch users.json -watch orders.json')")
 	flag.Var(&postServeActionFlags, "post-serve-action", "Set post serve action by passing the action name, binary and the path of the action script and delay in Ms separated by space. (i.e. i.e. '-post-serve-action \"webhook python script.py 2000\"')")
-	flag.Var(&templatingDataSourceFlags, "templating-data-source", "Set template data source (i.e. '-templating-data-source \"<datasource name> <file path>\"')")
+	flag.Var(&templatingDataSourceFlags, "templating-data-source", "Set template data source from a CSV, JSON or YAML file (i.e. '-templating-data-source \"<datasource name> <file path>\"')")
 	flag.Var(&destinationFlags, "dest", "Specify which hosts to process (i.e. '-dest fooservice.org -dest barservice.org -dest catservice.org') - other hosts will be ignored will passthrough'")
 	flag.Var(&logOutputFlags, "logs-output", "Specify locations for output logs, options are \"console\" and \"file\" (default \"console\")")
 	flag.StringVar(&responseBodyFilesPath, "response-body-files-path", "", "When a response contains a relative bodyFile, it will be resolved against this absolute path (default is CWD)")
 	flag.Var(&responseBodyFilesAllowedOriginFlags, "response-body-files-allow-origin", "When a response contains a url in bodyFile, it will be loaded only if the origin is allowed")
 	flag.Var(&journalIndexingKeyFlags, "journal-indexing-key", "Key to setup indexing on journal")
+	flag.Var(&grpcDescriptorSetFlags, "grpc-descriptor-set", "Load a protobuf descriptor set file so that gRPC messages are converted to and from JSON (i.e. '-grpc-descriptor-set greeter.protoset')")
 	flag.Var(&journalBodySizeLimit, "journal-body-size-limit", "Set the memory size limit for a request or response body in the journal (e.g., '128KB', '2MB'). Defaults to unbounded")
 
 	flag.Parse()
@@ -562,6 +573,28 @@ func main() {
 		}
 	}
 
+	// Every reload replaces the whole simulation, which would throw away anything loaded in another way
+	if len(watchFlags) > 0 && (len(importFlags) > 0 || *persistDir != "" || os.Getenv(hv.HoverflyImportRecordsEV) != "") {
+		log.Fatal("-watch cannot be used with -import, -persist-dir or " + hv.HoverflyImportRecordsEV + ", as each reload replaces the whole simulation")
+	}
+
+	if *persistDir != "" {
+		store, err := persistence.NewBoltStore(*persistDir)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"persistDir": *persistDir,
+			}).Fatal("Failed to open persistent store")
+		}
+		if err := hoverfly.SetPersistentStore(store); err != nil {
+			log.WithFields(log.Fields{
+				"error":      err.Error(),
+				"persistDir": *persistDir,
+			}).Fatal("Failed to load persisted data")
+		}
+		log.WithField("persistDir", *persistDir).Info("Persisting simulation, state and templating data sources")
+	}
+
 	// importing records if environment variable is set
 	ev := os.Getenv(hv.HoverflyImportRecordsEV)
 	if ev != "" {
@@ -627,7 +660,14 @@ func main() {
 				splitTemplateDataSource := strings.Split(v, " ")
 				if len(splitTemplateDataSource) == 2 {
 					if fileContents, err := os.ReadFile(splitTemplateDataSource[1]); err == nil {
-						err = hoverfly.SetCsvDataSource(splitTemplateDataSource[0], string(fileContents))
+						switch strings.ToLower(filepath.Ext(splitTemplateDataSource[1])) {
+						case ".json":
+							err = hoverfly.SetJsonDataSource(splitTemplateDataSource[0], templating.JsonFormat, string(fileContents))
+						case ".yaml", ".yml":
+							err = hoverfly.SetJsonDataSource(splitTemplateDataSource[0], templating.YamlFormat, string(fileContents))
+						default:
+							err = hoverfly.SetCsvDataSource(splitTemplateDataSource[0], string(fileContents))
+						}
 						if err != nil {
 							log.WithFields(log.Fields{
 								"error":  err.Error(),
@@ -646,6 +686,17 @@ func main() {
 
 	}
 
+	for _, descriptorSet := range grpcDescriptorSetFlags {
+		if descriptorSet != "" {
+			if err = hoverfly.AddGrpcDescriptorSetFromFile(descriptorSet); err != nil {
+				log.WithFields(log.Fields{
+					"error":  err.Error(),
+					"import": descriptorSet,
+				}).Fatal("Failed to load gRPC descriptor set")
+			}
+		}
+	}
+
 	if len(journalIndexingKeyFlags) > 0 {
 
 		for _, indexKey := range journalIndexingKeyFlags {
@@ -680,6 +731,15 @@ func main() {
 		hoverfly.CacheMatcher.PreloadCache(hoverfly.Simulation)
 	}
 
+	if len(watchFlags) > 0 {
+		if err := hoverfly.WatchSimulations(watchFlags); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+				"watch": watchFlags,
+			}).Fatal("Failed to watch simulations")
+		}
+	}
+
 	if *captureOnMiss && !*spy {
 		log.Fatal("-capture-on-miss can only be used with -spy mode")
 	}
@@ -698,6 +758,11 @@ func main() {
 		hoverfly.Counter.Init()
 	}
 
+	if *otlpEndpoint != "" {
+		hoverfly.Tracer = tracing.NewTracer(*otlpEndpoint)
+		log.WithField("endpoint", hoverfly.Tracer.Endpoint).Info("Exporting traces to OpenTelemetry collector")
+	}
+
 	cfg.Webserver = *webserver
 
 	if *pacFile != "" {
@@ -718,6 +783,16 @@ func main() {
 		}).Fatal("Failed to start proxy")
 	}
 
+	// The persistent store is closed on the way out, so that nothing written to it is lost
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		log.Warning("Shutting down")
+		hoverfly.Shutdown()
+		os.Exit(0)
+	}()
+
 	// starting admin interface, this is blocking
 	adminApi := hv.AdminApi{}
 	adminApi.StartAdminInterface(hoverfly)
@@ -0,0 +1,44 @@
+package fault
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Types of network fault which can be injected instead of writing a response normally
+const (
+	// ConnectionReset closes the connection without sending a response
+	ConnectionReset = "connectionReset"
+	// TruncatedBody sends the headers and the first bytes of the body, then closes the connection
+	TruncatedBody = "truncatedBody"
+	// SlowBody sends the body at a limited number of bytes per second
+	SlowBody = "slowBody"
+	// MalformedChunkedEncoding sends the body as a chunk which is not terminated properly
+	MalformedChunkedEncoding = "malformedChunkedEncoding"
+)
+
+type Fault struct {
+	Type           string
+	Bytes          int
+	BytesPerSecond int
+}
+
+func (this Fault) Validate() error {
+	switch this.Type {
+	case ConnectionReset, MalformedChunkedEncoding:
+		return nil
+	case TruncatedBody:
+		if this.Bytes < 0 {
+			return errors.New("Config error - fault bytes can't be less than 0")
+		}
+		return nil
+	case SlowBody:
+		if this.BytesPerSecond <= 0 {
+			return errors.New("Config error - fault bytesPerSecond must be greater than 0")
+		}
+		return nil
+	}
+
+	return fmt.Errorf("Config error - unknown fault type %s, it should be %s, %s, %s or %s",
+		this.Type, ConnectionReset, TruncatedBody, SlowBody, MalformedChunkedEncoding)
+}
@@ -0,0 +1,243 @@
+package fault
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var errAborted = errors.New("response aborted by fault injection")
+
+type contextKey struct{}
+
+// ResponseWriter writes a response while injecting the fault set on it with Inject, if any
+type ResponseWriter struct {
+	http.ResponseWriter
+
+	fault       *Fault
+	wroteHeader bool
+	aborted     bool
+	written     int
+
+	conn       net.Conn
+	connWriter *bufio.Writer
+	wroteChunk bool
+}
+
+// NewResponseWriter wraps a http.ResponseWriter so that a fault can be injected into the response to the
+// request. The returned request carries the writer in its context, unless it was already wrapped, in which
+// case the existing writer is kept and false is returned.
+func NewResponseWriter(w http.ResponseWriter, request *http.Request) (*ResponseWriter, *http.Request, bool) {
+	if writer, ok := request.Context().Value(contextKey{}).(*ResponseWriter); ok {
+		return writer, request, false
+	}
+
+	writer := &ResponseWriter{ResponseWriter: w}
+	return writer, request.WithContext(context.WithValue(request.Context(), contextKey{}, writer)), true
+}
+
+// Inject sets the fault to inject into the response to a request wrapped by NewResponseWriter
+func Inject(request *http.Request, fault *Fault) {
+	if writer, ok := request.Context().Value(contextKey{}).(*ResponseWriter); ok {
+		writer.fault = fault
+	}
+}
+
+func (this *ResponseWriter) WriteHeader(statusCode int) {
+	if this.wroteHeader || this.aborted {
+		return
+	}
+	this.wroteHeader = true
+
+	if this.fault == nil {
+		this.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+
+	switch this.fault.Type {
+	case ConnectionReset:
+		this.abort(true)
+	case MalformedChunkedEncoding:
+		this.writeChunkedHeader(statusCode)
+	case TruncatedBody:
+		this.ResponseWriter.WriteHeader(statusCode)
+		this.flush()
+		if this.fault.Bytes == 0 {
+			this.abort(false)
+		}
+	default:
+		this.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+func (this *ResponseWriter) Write(data []byte) (int, error) {
+	if !this.wroteHeader {
+		this.WriteHeader(http.StatusOK)
+	}
+
+	if this.aborted {
+		return 0, errAborted
+	}
+
+	if this.fault == nil {
+		return this.ResponseWriter.Write(data)
+	}
+
+	switch this.fault.Type {
+	case TruncatedBody:
+		return this.writeTruncated(data)
+	case SlowBody:
+		return this.writeSlowly(data)
+	case MalformedChunkedEncoding:
+		return this.writeChunk(data)
+	}
+
+	return this.ResponseWriter.Write(data)
+}
+
+func (this *ResponseWriter) Flush() {
+	if this.aborted || this.conn != nil {
+		return
+	}
+	this.flush()
+}
+
+func (this *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(this.ResponseWriter).Hijack()
+}
+
+func (this *ResponseWriter) Unwrap() http.ResponseWriter {
+	return this.ResponseWriter
+}
+
+// Finish completes a fault which can only be injected once the whole body has been written
+func (this *ResponseWriter) Finish() {
+	if this.conn == nil || this.aborted {
+		return
+	}
+
+	if this.wroteChunk {
+		this.connWriter.WriteString("\r\n")
+	}
+	// A chunk size has to be hexadecimal
+	this.connWriter.WriteString("zz\r\n\r\n")
+	this.connWriter.Flush()
+	this.aborted = true
+	this.conn.Close()
+}
+
+func (this *ResponseWriter) writeTruncated(data []byte) (int, error) {
+	remaining := this.fault.Bytes - this.written
+	if remaining >= len(data) {
+		n, err := this.ResponseWriter.Write(data)
+		this.written += n
+		return n, err
+	}
+
+	n, err := this.ResponseWriter.Write(data[:remaining])
+	this.written += n
+	if err != nil {
+		return n, err
+	}
+	this.flush()
+	this.abort(false)
+	return n, errAborted
+}
+
+func (this *ResponseWriter) writeSlowly(data []byte) (int, error) {
+	chunkSize := this.fault.BytesPerSecond / 10
+	if chunkSize < 1 {
+		chunkSize = 1
+	}
+	interval := time.Duration(chunkSize) * time.Second / time.Duration(this.fault.BytesPerSecond)
+
+	written := 0
+	for written < len(data) {
+		end := written + chunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+
+		n, err := this.ResponseWriter.Write(data[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+		this.flush()
+
+		if written < len(data) {
+			time.Sleep(interval)
+		}
+	}
+
+	return written, nil
+}
+
+// writeChunkedHeader takes over the connection to write a chunked response by hand, which is only
+// possible over HTTP/1.x. Other connections are reset instead.
+func (this *ResponseWriter) writeChunkedHeader(statusCode int) {
+	conn, readWriter, err := http.NewResponseController(this.ResponseWriter).Hijack()
+	if err != nil {
+		this.abort(true)
+		return
+	}
+	this.conn = conn
+	this.connWriter = readWriter.Writer
+
+	header := this.Header().Clone()
+	for name := range header {
+		if strings.HasPrefix(name, http.TrailerPrefix) {
+			header.Del(name)
+		}
+	}
+	header.Del("Content-Length")
+	header.Set("Transfer-Encoding", "chunked")
+	header.Set("Connection", "close")
+
+	fmt.Fprintf(this.connWriter, "HTTP/1.1 %03d %s\r\n", statusCode, http.StatusText(statusCode))
+	header.Write(this.connWriter)
+	this.connWriter.WriteString("\r\n")
+}
+
+func (this *ResponseWriter) writeChunk(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	if this.wroteChunk {
+		this.connWriter.WriteString("\r\n")
+	}
+	fmt.Fprintf(this.connWriter, "%x\r\n", len(data))
+	n, err := this.connWriter.Write(data)
+	this.wroteChunk = true
+	return n, err
+}
+
+func (this *ResponseWriter) flush() {
+	http.NewResponseController(this.ResponseWriter).Flush()
+}
+
+// abort closes the connection without completing the response. A TCP connection is reset if asked,
+// which discards anything not yet sent, so a partial body is followed by an ordinary close instead.
+// Connections which cannot be taken over, such as HTTP/2 streams, are aborted by net/http.
+func (this *ResponseWriter) abort(reset bool) {
+	this.aborted = true
+
+	if this.conn == nil {
+		conn, _, err := http.NewResponseController(this.ResponseWriter).Hijack()
+		if err != nil {
+			panic(http.ErrAbortHandler)
+		}
+		this.conn = conn
+	}
+
+	if tcpConn, ok := this.conn.(*net.TCPConn); ok && reset {
+		tcpConn.SetLinger(0)
+	}
+	this.conn.Close()
+}
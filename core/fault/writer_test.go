@@ -0,0 +1,150 @@
+package fault
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+const testBody = "0123456789abcdefghij"
+
+func newFaultyServer(fault *Fault) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writer, request, _ := NewResponseWriter(w, r)
+		defer writer.Finish()
+
+		Inject(request, fault)
+
+		writer.Header().Set("Content-Length", strconv.Itoa(len(testBody)))
+		writer.WriteHeader(http.StatusOK)
+		writer.Write([]byte(testBody))
+	}))
+}
+
+func newTestClient() *http.Client {
+	return &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+}
+
+func Test_Fault_Validate(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(Fault{Type: ConnectionReset}.Validate()).To(Succeed())
+	Expect(Fault{Type: MalformedChunkedEncoding}.Validate()).To(Succeed())
+	Expect(Fault{Type: TruncatedBody, Bytes: 10}.Validate()).To(Succeed())
+	Expect(Fault{Type: TruncatedBody, Bytes: -1}.Validate()).To(MatchError(ContainSubstring("bytes")))
+	Expect(Fault{Type: SlowBody, BytesPerSecond: 100}.Validate()).To(Succeed())
+	Expect(Fault{Type: SlowBody}.Validate()).To(MatchError(ContainSubstring("bytesPerSecond")))
+	Expect(Fault{Type: "timeout"}.Validate()).To(MatchError(ContainSubstring("unknown fault type timeout")))
+}
+
+func Test_ResponseWriter_WritesResponseWithoutFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(nil)
+	defer server.Close()
+
+	response, err := newTestClient().Get(server.URL)
+	Expect(err).To(BeNil())
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal(testBody))
+}
+
+func Test_ResponseWriter_ConnectionResetSendsNoResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(&Fault{Type: ConnectionReset})
+	defer server.Close()
+
+	_, err := newTestClient().Get(server.URL)
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_ResponseWriter_TruncatedBodySendsHeadersAndPartOfBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(&Fault{Type: TruncatedBody, Bytes: 5})
+	defer server.Close()
+
+	response, err := newTestClient().Get(server.URL)
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(response.ContentLength).To(Equal(int64(len(testBody))))
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+	Expect(string(body)).To(Equal("01234"))
+}
+
+func Test_ResponseWriter_TruncatedBodyWithoutBytesSendsOnlyHeaders(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(&Fault{Type: TruncatedBody})
+	defer server.Close()
+
+	response, err := newTestClient().Get(server.URL)
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+	Expect(body).To(BeEmpty())
+}
+
+func Test_ResponseWriter_SlowBodyDripsBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(&Fault{Type: SlowBody, BytesPerSecond: 40})
+	defer server.Close()
+
+	start := time.Now()
+	response, err := newTestClient().Get(server.URL)
+	Expect(err).To(BeNil())
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(Equal(testBody))
+	Expect(time.Since(start)).To(BeNumerically(">=", 400*time.Millisecond))
+}
+
+func Test_ResponseWriter_MalformedChunkedEncodingFailsAfterBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newFaultyServer(&Fault{Type: MalformedChunkedEncoding})
+	defer server.Close()
+
+	response, err := newTestClient().Get(server.URL)
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(response.TransferEncoding).To(Equal([]string{"chunked"}))
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("chunk"))
+	Expect(string(body)).To(Equal(testBody))
+}
+
+func Test_Inject_IgnoresRequestsWithoutResponseWriter(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	Expect(func() { Inject(request, &Fault{Type: ConnectionReset}) }).ToNot(Panic())
+}
+
+func Test_NewResponseWriter_KeepsExistingWriter(t *testing.T) {
+	RegisterTestingT(t)
+
+	writer, request, created := NewResponseWriter(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	Expect(created).To(BeTrue())
+
+	nested, _, created := NewResponseWriter(writer, request)
+	Expect(created).To(BeFalse())
+	Expect(nested).To(BeIdenticalTo(writer))
+}
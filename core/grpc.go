@@ -0,0 +1,239 @@
+package hoverfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// AddGrpcDescriptorSet loads the services of a protobuf descriptor set, so that the bodies of
+// gRPC calls to them are converted to JSON when captured and back to protobuf when simulated
+func (hf *Hoverfly) AddGrpcDescriptorSet(data []byte) error {
+	return hf.grpcDescriptors.Load(data)
+}
+
+// AddGrpcDescriptorSetFromFile loads a descriptor set file created by protoc --descriptor_set_out
+func (hf *Hoverfly) AddGrpcDescriptorSetFromFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return hf.AddGrpcDescriptorSet(data)
+}
+
+// findGrpcMethod returns the descriptor of the method called by a gRPC request, or nil if
+// the request is not a gRPC call or the method is not in the loaded descriptor sets
+func (hf *Hoverfly) findGrpcMethod(request *http.Request) protoreflect.MethodDescriptor {
+	if request == nil || !grpc.IsGrpcContentType(request.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	method, err := hf.grpcDescriptors.FindMethod(request.URL.Path)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"path":  request.URL.Path,
+		}).Debug("gRPC message will not be converted")
+		return nil
+	}
+	return method
+}
+
+// decodeGrpcRequest replaces the protobuf messages of a gRPC request with their JSON representation
+func (hf *Hoverfly) decodeGrpcRequest(request *http.Request) {
+	method := hf.findGrpcMethod(request)
+	if method == nil || request.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return
+	}
+
+	decoded, err := grpc.DecodeMessages(body, method.Input(), method.IsStreamingClient())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"method": method.FullName(),
+		}).Warn("Failed to decode gRPC request")
+		request.Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+
+	setGrpcRequestBody(request, []byte(decoded))
+}
+
+// encodeGrpcRequest replaces a JSON body of a gRPC request with protobuf messages before it is forwarded
+func encodeGrpcRequest(request *http.Request, method protoreflect.MethodDescriptor) {
+	if request.Body == nil {
+		return
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return
+	}
+
+	encoded, err := grpc.EncodeMessages(string(body), method.Input())
+	if err != nil {
+		request.Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+
+	setGrpcRequestBody(request, encoded)
+	request.Header.Set("Te", "trailers")
+}
+
+func setGrpcRequestBody(request *http.Request, body []byte) {
+	request.Body = io.NopCloser(bytes.NewBuffer(body))
+	request.ContentLength = int64(len(body))
+	request.Header.Del("Content-Length")
+}
+
+// decodeGrpcResponse replaces the protobuf messages of a forwarded gRPC response with their JSON
+// representation. The body is read to the end so the trailers are available afterwards.
+func decodeGrpcResponse(response *http.Response, method protoreflect.MethodDescriptor) {
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		response.Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+
+	decoded, err := grpc.DecodeMessages(body, method.Output(), method.IsStreamingServer())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"method": method.FullName(),
+		}).Warn("Failed to decode gRPC response")
+		response.Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+
+	response.Body = io.NopCloser(bytes.NewBufferString(decoded))
+	response.ContentLength = int64(len(decoded))
+	response.Header.Del("Content-Length")
+}
+
+// encodeGrpcResponse turns a JSON response to a gRPC request into protobuf messages, and makes sure
+// a successful response has the content type and grpc-status trailer the client expects
+func (hf *Hoverfly) encodeGrpcResponse(request *http.Request, response *http.Response) {
+	if response == nil || response.StatusCode != http.StatusOK {
+		return
+	}
+
+	method := hf.findGrpcMethod(request)
+	if method == nil {
+		return
+	}
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return
+	}
+
+	encoded, err := grpc.EncodeMessages(string(body), method.Output())
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"method": method.FullName(),
+		}).Warn("Failed to encode gRPC response")
+		response.Body = io.NopCloser(bytes.NewBuffer(body))
+		return
+	}
+
+	response.Body = io.NopCloser(bytes.NewBuffer(encoded))
+	response.ContentLength = int64(len(encoded))
+	response.Header.Set("Content-Length", strconv.Itoa(len(encoded)))
+
+	if response.Header.Get("Content-Type") == "" {
+		response.Header.Set("Content-Type", grpc.ContentType)
+	}
+
+	if response.Header.Get(grpc.StatusHeader) == "" && response.Trailer.Get(grpc.StatusHeader) == "" {
+		if response.Trailer == nil {
+			response.Trailer = make(http.Header)
+		}
+		response.Trailer.Set(grpc.StatusHeader, "0")
+	}
+}
+
+// newGrpcHttpClient returns a client which speaks HTTP/2 to the destination, using prior knowledge
+// for plain text connections, as gRPC servers rarely accept HTTP/1.1
+func (hf *Hoverfly) newGrpcHttpClient(client *http.Client) *http.Client {
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		return client
+	}
+
+	if client == hf.HTTP {
+		hf.grpcHTTPMu.Lock()
+		defer hf.grpcHTTPMu.Unlock()
+		if hf.grpcHTTP != nil && hf.grpcHTTPTransport == transport {
+			return hf.grpcHTTP
+		}
+	}
+
+	grpcTransport := transport.Clone()
+	grpcTransport.Protocols = new(http.Protocols)
+	grpcTransport.Protocols.SetHTTP2(true)
+	grpcTransport.Protocols.SetUnencryptedHTTP2(true)
+
+	grpcClient := &http.Client{
+		Transport:     grpcTransport,
+		CheckRedirect: client.CheckRedirect,
+		Jar:           client.Jar,
+		Timeout:       client.Timeout,
+	}
+
+	if client == hf.HTTP {
+		hf.grpcHTTP = grpcClient
+		hf.grpcHTTPTransport = transport
+	}
+
+	return grpcClient
+}
+
+// declareResponseTrailers turns the trailers of a response into headers which net/http sends as
+// trailers, so that they survive the response being copied to the http.ResponseWriter. This is
+// only done for requests read by an http.Server, as other responses are written with their trailers.
+func declareResponseTrailers(request *http.Request, response *http.Response) {
+	if response == nil || len(response.Trailer) == 0 {
+		return
+	}
+
+	if _, ok := request.Context().Value(http.ServerContextKey).(*http.Server); !ok {
+		return
+	}
+
+	for name, values := range response.Trailer {
+		for _, value := range values {
+			response.Header.Add(http.TrailerPrefix+name, value)
+		}
+	}
+	response.Trailer = nil
+
+	// HTTP/1.1 trailers can only follow a chunked body
+	if request.ProtoMajor == 1 {
+		response.Header.Del("Content-Length")
+	}
+}
+
+// isGrpcJsonBody returns true for gRPC requests whose messages were decoded to JSON
+func isGrpcJsonBody(request *models.RequestDetails) bool {
+	for _, contentType := range request.Headers["Content-Type"] {
+		if grpc.IsGrpcContentType(contentType) {
+			return json.Valid([]byte(request.Body))
+		}
+	}
+	return false
+}
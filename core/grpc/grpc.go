@@ -0,0 +1,193 @@
+package grpc
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	ContentType = "application/grpc"
+
+	StatusHeader = "Grpc-Status"
+
+	frameHeaderLength = 5
+)
+
+// Descriptors holds the services loaded from protobuf descriptor sets, so that gRPC
+// messages can be converted between their binary and JSON representations
+type Descriptors struct {
+	mutex sync.RWMutex
+	files *protoregistry.Files
+}
+
+func NewDescriptors() *Descriptors {
+	return &Descriptors{
+		files: new(protoregistry.Files),
+	}
+}
+
+// Load adds the files of a serialized FileDescriptorSet, as produced by
+// protoc --descriptor_set_out with --include_imports
+func (this *Descriptors) Load(data []byte) error {
+	descriptorSet := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, descriptorSet); err != nil {
+		return fmt.Errorf("invalid descriptor set: %s", err.Error())
+	}
+
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	files, err := protodesc.NewFiles(descriptorSet)
+	if err != nil {
+		return fmt.Errorf("invalid descriptor set: %s", err.Error())
+	}
+
+	var rangeErr error
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		if _, err := this.files.FindFileByPath(file.Path()); err == nil {
+			return true
+		}
+		if err := this.files.RegisterFile(file); err != nil {
+			rangeErr = err
+			return false
+		}
+		return true
+	})
+
+	return rangeErr
+}
+
+// FindMethod looks up a method from the path of a gRPC request, for example /helloworld.Greeter/SayHello
+func (this *Descriptors) FindMethod(path string) (protoreflect.MethodDescriptor, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%s is not a gRPC method path", path)
+	}
+
+	this.mutex.RLock()
+	defer this.mutex.RUnlock()
+
+	descriptor, err := this.files.FindDescriptorByName(protoreflect.FullName(parts[0]))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found in descriptor sets", parts[0])
+	}
+
+	service, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", parts[0])
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(parts[1]))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", parts[1], parts[0])
+	}
+
+	return method, nil
+}
+
+// IsGrpcContentType returns true for the content types of gRPC requests which carry protobuf messages
+func IsGrpcContentType(contentType string) bool {
+	contentType = strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return contentType == ContentType || contentType == ContentType+"+proto"
+}
+
+// DecodeMessages converts the length-prefixed messages of a gRPC body to JSON. A single message becomes
+// a JSON object, while the messages of a streaming call always become an array of objects.
+func DecodeMessages(body []byte, descriptor protoreflect.MessageDescriptor, streaming bool) (string, error) {
+	messages := []json.RawMessage{}
+	for len(body) > 0 {
+		if len(body) < frameHeaderLength {
+			return "", errors.New("gRPC message is truncated")
+		}
+		if body[0] != 0 {
+			return "", errors.New("compressed gRPC messages are not supported")
+		}
+
+		length := binary.BigEndian.Uint32(body[1:frameHeaderLength])
+		if uint64(len(body)-frameHeaderLength) < uint64(length) {
+			return "", errors.New("gRPC message is truncated")
+		}
+
+		message := dynamicpb.NewMessage(descriptor)
+		if err := proto.Unmarshal(body[frameHeaderLength:frameHeaderLength+int(length)], message); err != nil {
+			return "", err
+		}
+
+		marshalled, err := protojson.Marshal(message)
+		if err != nil {
+			return "", err
+		}
+		messages = append(messages, marshalled)
+		body = body[frameHeaderLength+int(length):]
+	}
+
+	if !streaming && len(messages) == 0 {
+		return "", nil
+	}
+
+	if !streaming && len(messages) == 1 {
+		return compact(messages[0])
+	}
+
+	marshalled, err := json.Marshal(messages)
+	if err != nil {
+		return "", err
+	}
+	return string(marshalled), nil
+}
+
+// EncodeMessages converts a JSON object, or an array of objects, to length-prefixed gRPC messages
+func EncodeMessages(body string, descriptor protoreflect.MessageDescriptor) ([]byte, error) {
+	trimmed := strings.TrimSpace(body)
+
+	var messages []json.RawMessage
+	if strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &messages); err != nil {
+			return nil, err
+		}
+	} else if trimmed != "" {
+		messages = []json.RawMessage{json.RawMessage(trimmed)}
+	}
+
+	var encoded bytes.Buffer
+	for _, rawMessage := range messages {
+		message := dynamicpb.NewMessage(descriptor)
+		if err := protojson.Unmarshal(rawMessage, message); err != nil {
+			return nil, err
+		}
+
+		marshalled, err := proto.Marshal(message)
+		if err != nil {
+			return nil, err
+		}
+
+		header := make([]byte, frameHeaderLength)
+		binary.BigEndian.PutUint32(header[1:], uint32(len(marshalled)))
+		encoded.Write(header)
+		encoded.Write(marshalled)
+	}
+
+	return encoded.Bytes(), nil
+}
+
+// protojson deliberately randomises its whitespace, so it is removed to keep captured bodies stable
+func compact(message json.RawMessage) (string, error) {
+	var buffer bytes.Buffer
+	if err := json.Compact(&buffer, message); err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
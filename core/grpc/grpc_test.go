@@ -0,0 +1,160 @@
+package grpc
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func greeterDescriptorSet() []byte {
+	stringField := func(name string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(1),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+
+	data, _ := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("greeter.proto"),
+				Package: proto.String("test"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("HelloRequest"), Field: []*descriptorpb.FieldDescriptorProto{stringField("name")}},
+					{Name: proto.String("HelloReply"), Field: []*descriptorpb.FieldDescriptorProto{stringField("message")}},
+				},
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{
+						Name: proto.String("Greeter"),
+						Method: []*descriptorpb.MethodDescriptorProto{
+							{Name: proto.String("SayHello"), InputType: proto.String(".test.HelloRequest"), OutputType: proto.String(".test.HelloReply")},
+							{Name: proto.String("SayHellos"), InputType: proto.String(".test.HelloRequest"), OutputType: proto.String(".test.HelloReply"), ServerStreaming: proto.Bool(true)},
+						},
+					},
+				},
+			},
+		},
+	})
+	return data
+}
+
+func sayHelloMethod() protoreflect.MethodDescriptor {
+	descriptors := NewDescriptors()
+	descriptors.Load(greeterDescriptorSet())
+	method, _ := descriptors.FindMethod("/test.Greeter/SayHello")
+	return method
+}
+
+func Test_Descriptors_FindMethod_FindsMethodByRequestPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewDescriptors()
+	Expect(unit.Load(greeterDescriptorSet())).To(Succeed())
+
+	method, err := unit.FindMethod("/test.Greeter/SayHellos")
+	Expect(err).To(BeNil())
+	Expect(string(method.FullName())).To(Equal("test.Greeter.SayHellos"))
+	Expect(method.IsStreamingServer()).To(BeTrue())
+	Expect(string(method.Output().FullName())).To(Equal("test.HelloReply"))
+}
+
+func Test_Descriptors_Load_IgnoresFilesAlreadyLoaded(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewDescriptors()
+	Expect(unit.Load(greeterDescriptorSet())).To(Succeed())
+	Expect(unit.Load(greeterDescriptorSet())).To(Succeed())
+}
+
+func Test_Descriptors_Load_ReturnsErrorForInvalidDescriptorSet(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewDescriptors()
+	Expect(unit.Load([]byte("not a descriptor set"))).ToNot(Succeed())
+}
+
+func Test_Descriptors_FindMethod_ReturnsErrorForUnknownMethods(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewDescriptors()
+	Expect(unit.Load(greeterDescriptorSet())).To(Succeed())
+
+	_, err := unit.FindMethod("/test.Greeter/SayGoodbye")
+	Expect(err).To(MatchError("method SayGoodbye not found in service test.Greeter"))
+
+	_, err = unit.FindMethod("/test.Farewell/SayGoodbye")
+	Expect(err).To(MatchError("service test.Farewell not found in descriptor sets"))
+
+	_, err = unit.FindMethod("/api/v2/simulation")
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_IsGrpcContentType(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(IsGrpcContentType("application/grpc")).To(BeTrue())
+	Expect(IsGrpcContentType("application/grpc+proto; charset=utf-8")).To(BeTrue())
+	Expect(IsGrpcContentType("application/grpc+json")).To(BeFalse())
+	Expect(IsGrpcContentType("application/json")).To(BeFalse())
+}
+
+func Test_EncodeMessages_And_DecodeMessages_RoundTripSingleMessage(t *testing.T) {
+	RegisterTestingT(t)
+
+	method := sayHelloMethod()
+
+	encoded, err := EncodeMessages(`{ "name": "hoverfly" }`, method.Input())
+	Expect(err).To(BeNil())
+	Expect(encoded).To(Equal([]byte{0, 0, 0, 0, 10, 10, 8, 'h', 'o', 'v', 'e', 'r', 'f', 'l', 'y'}))
+
+	decoded, err := DecodeMessages(encoded, method.Input(), false)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`{"name":"hoverfly"}`))
+}
+
+func Test_EncodeMessages_And_DecodeMessages_RoundTripStreamedMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	method := sayHelloMethod()
+
+	encoded, err := EncodeMessages(`[{"message":"one"},{"message":"two"}]`, method.Output())
+	Expect(err).To(BeNil())
+
+	decoded, err := DecodeMessages(encoded, method.Output(), true)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`[{"message":"one"},{"message":"two"}]`))
+}
+
+func Test_DecodeMessages_ReturnsEmptyBodyWhenThereAreNoMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	decoded, err := DecodeMessages([]byte{}, sayHelloMethod().Output(), false)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(""))
+}
+
+func Test_DecodeMessages_ReturnsErrorForTruncatedOrCompressedMessages(t *testing.T) {
+	RegisterTestingT(t)
+
+	method := sayHelloMethod()
+
+	_, err := DecodeMessages([]byte{0, 0, 0, 0, 10, 10}, method.Input(), false)
+	Expect(err).To(MatchError("gRPC message is truncated"))
+
+	_, err = DecodeMessages([]byte{1, 0, 0, 0, 0}, method.Input(), false)
+	Expect(err).To(MatchError("compressed gRPC messages are not supported"))
+}
+
+func Test_EncodeMessages_ReturnsErrorForUnknownFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := EncodeMessages(`{"unknown":"field"}`, sayHelloMethod().Input())
+	Expect(err).ToNot(BeNil())
+}
@@ -0,0 +1,210 @@
+package hoverfly
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/grpc"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func greeterDescriptorSet() []byte {
+	stringField := func(name string) *descriptorpb.FieldDescriptorProto {
+		return &descriptorpb.FieldDescriptorProto{
+			Name:     proto.String(name),
+			JsonName: proto.String(name),
+			Number:   proto.Int32(1),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		}
+	}
+
+	data, _ := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			{
+				Name:    proto.String("greeter.proto"),
+				Package: proto.String("test"),
+				Syntax:  proto.String("proto3"),
+				MessageType: []*descriptorpb.DescriptorProto{
+					{Name: proto.String("HelloRequest"), Field: []*descriptorpb.FieldDescriptorProto{stringField("name")}},
+					{Name: proto.String("HelloReply"), Field: []*descriptorpb.FieldDescriptorProto{stringField("message")}},
+				},
+				Service: []*descriptorpb.ServiceDescriptorProto{
+					{
+						Name: proto.String("Greeter"),
+						Method: []*descriptorpb.MethodDescriptorProto{
+							{Name: proto.String("SayHello"), InputType: proto.String(".test.HelloRequest"), OutputType: proto.String(".test.HelloReply")},
+						},
+					},
+				},
+			},
+		},
+	})
+	return data
+}
+
+var greeterSimulation = v2.SimulationViewV5{
+	DataViewV5: v2.DataViewV5{
+		RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+			{
+				RequestMatcher: v2.RequestMatcherViewV5{
+					Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/test.Greeter/SayHello")},
+					Body: []v2.MatcherViewV5{v2.NewMatcherView(matchers.JsonPartial, `{"name":"bob"}`)},
+				},
+				Response: v2.ResponseDetailsViewV5{
+					Status: 200,
+					Body:   `{"message":"hello bob"}`,
+				},
+			},
+		},
+	},
+	MetaView: *v2.NewMetaView("test"),
+}
+
+func newGrpcTestRequest(t *testing.T, method protoreflect.MethodDescriptor, target, body string) *http.Request {
+	encoded, err := grpc.EncodeMessages(body, method.Input())
+	Expect(err).To(BeNil())
+
+	request, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(encoded))
+	Expect(err).To(BeNil())
+	request.Header.Set("Content-Type", "application/grpc")
+	request.Header.Set("Te", "trailers")
+	return request
+}
+
+func Test_Hoverfly_SimulatesGrpcOverH2c(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	unit.Cfg.Webserver = true
+	Expect(unit.AddGrpcDescriptorSet(greeterDescriptorSet())).To(Succeed())
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(greeterSimulation).GetError()).To(BeNil())
+
+	server := httptest.NewUnstartedServer(NewWebserverProxy(unit))
+	server.Config.Protocols = newServerProtocols()
+	server.Start()
+	defer server.Close()
+
+	transport := &http.Transport{Protocols: new(http.Protocols)}
+	transport.Protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: transport}
+
+	method, _ := unit.grpcDescriptors.FindMethod("/test.Greeter/SayHello")
+	response, err := client.Do(newGrpcTestRequest(t, method, server.URL+"/test.Greeter/SayHello", `{"name":"bob"}`))
+	Expect(err).To(BeNil())
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(2))
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(response.Header.Get("Content-Type")).To(Equal("application/grpc"))
+	Expect(response.Trailer.Get("Grpc-Status")).To(Equal("0"))
+
+	decoded, err := grpc.DecodeMessages(body, method.Output(), false)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`{"message":"hello bob"}`))
+
+	journal, _ := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(journal.Journal).To(HaveLen(1))
+	Expect(*journal.Journal[0].Request.Body).To(Equal(`{"name":"bob"}`))
+	Expect(journal.Journal[0].Response.Body).To(Equal(`{"message":"hello bob"}`))
+}
+
+func Test_Hoverfly_CapturesGrpcAsJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.AddGrpcDescriptorSet(greeterDescriptorSet())).To(Succeed())
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "capture"})).To(Succeed())
+	method, _ := unit.grpcDescriptors.FindMethod("/test.Greeter/SayHello")
+
+	upstream := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request, err := grpc.DecodeMessages(body, method.Input(), false)
+		if r.ProtoMajor != 2 || err != nil || request != `{"name":"bob"}` {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		reply, _ := grpc.EncodeMessages(`{"message":"hello bob"}`, method.Output())
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		w.Write(reply)
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	upstream.Config.Protocols = newServerProtocols()
+	upstream.Start()
+	defer upstream.Close()
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	response, err := client.Do(newGrpcTestRequest(t, method, upstream.URL+"/test.Greeter/SayHello", `{"name":"bob"}`))
+	Expect(err).To(BeNil())
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+	Expect(response.Trailer.Get("Grpc-Status")).To(Equal("0"))
+
+	decoded, err := grpc.DecodeMessages(body, method.Output(), false)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`{"message":"hello bob"}`))
+
+	simulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+
+	pair := simulation.RequestResponsePairs[0]
+	Expect(pair.RequestMatcher.Body[0].Matcher).To(Equal(matchers.Json))
+	Expect(pair.RequestMatcher.Body[0].Value).To(Equal(`{"name":"bob"}`))
+	Expect(pair.Response.Body).To(Equal(`{"message":"hello bob"}`))
+	Expect(pair.Response.Headers["Trailer"]).To(ContainElement("Grpc-Status"))
+	Expect(pair.Response.Headers["Grpc-Status"]).To(Equal([]string{"0"}))
+}
+
+func Test_Hoverfly_SimulatesGrpcOverH2ThroughConnectTunnel(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.AddGrpcDescriptorSet(greeterDescriptorSet())).To(Succeed())
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(greeterSimulation).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:             http.ProxyURL(proxyURL),
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+
+	method, _ := unit.grpcDescriptors.FindMethod("/test.Greeter/SayHello")
+	response, err := client.Do(newGrpcTestRequest(t, method, "https://greeter.test/test.Greeter/SayHello", `{"name":"bob"}`))
+	Expect(err).To(BeNil())
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(response.ProtoMajor).To(Equal(2))
+	Expect(response.Trailer.Get("Grpc-Status")).To(Equal("0"))
+
+	decoded, err := grpc.DecodeMessages(body, method.Output(), false)
+	Expect(err).To(BeNil())
+	Expect(decoded).To(Equal(`{"message":"hello bob"}`))
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/metrics"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyMetrics interface {
+	WriteMetrics(io.Writer) error
+}
+
+// MetricsHandler serves the metrics of Hoverfly in the Prometheus text format
+type MetricsHandler struct {
+	Hoverfly HoverflyMetrics
+}
+
+func (this *MetricsHandler) RegisterRoutes(mux *bone.Mux, am *AuthHandler) {
+	mux.Get("/metrics", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+}
+
+func (this *MetricsHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	var buffer bytes.Buffer
+	if err := this.Hoverfly.WriteMetrics(&buffer); err != nil {
+		WriteErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	WriteResponseWithContentType(w, buffer.Bytes(), metrics.PrometheusContentType)
+}
@@ -0,0 +1,12 @@
+package v2
+
+type CustomMatchersView struct {
+	CustomMatchers []CustomMatcherView `json:"customMatchers"`
+}
+
+type CustomMatcherView struct {
+	Name          string `json:"name"`
+	Binary        string `json:"binary,omitempty"`
+	ScriptContent string `json:"script,omitempty"`
+	Remote        string `json:"remote,omitempty"`
+}
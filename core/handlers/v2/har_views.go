@@ -0,0 +1,295 @@
+package v2
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	v1 "github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+const HarFormat = "har"
+
+// HarView is an HTTP Archive, as recorded by browsers and described at http://www.softwareishard.com/blog/har-12-spec/
+type HarView struct {
+	Log HarLogView `json:"log"`
+}
+
+type HarLogView struct {
+	Version string         `json:"version"`
+	Creator HarCreatorView `json:"creator"`
+	Entries []HarEntryView `json:"entries"`
+}
+
+type HarCreatorView struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HarEntryView struct {
+	StartedDateTime string          `json:"startedDateTime"`
+	Time            float64         `json:"time"`
+	Request         HarRequestView  `json:"request"`
+	Response        HarResponseView `json:"response"`
+	Cache           struct{}        `json:"cache"`
+	Timings         HarTimingsView  `json:"timings"`
+}
+
+type HarRequestView struct {
+	Method      string             `json:"method"`
+	Url         string             `json:"url"`
+	HttpVersion string             `json:"httpVersion"`
+	Cookies     []HarNameValueView `json:"cookies"`
+	Headers     []HarNameValueView `json:"headers"`
+	QueryString []HarNameValueView `json:"queryString"`
+	PostData    *HarPostDataView   `json:"postData,omitempty"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type HarResponseView struct {
+	Status      int                `json:"status"`
+	StatusText  string             `json:"statusText"`
+	HttpVersion string             `json:"httpVersion"`
+	Cookies     []HarNameValueView `json:"cookies"`
+	Headers     []HarNameValueView `json:"headers"`
+	Content     HarContentView     `json:"content"`
+	RedirectURL string             `json:"redirectURL"`
+	HeadersSize int                `json:"headersSize"`
+	BodySize    int                `json:"bodySize"`
+}
+
+type HarNameValueView struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HarPostDataView struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HarContentView struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type HarTimingsView struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Response headers which no longer describe the body once it has been decoded into the archive
+var harSkippedResponseHeaders = []string{"Content-Encoding", "Content-Length", "Transfer-Encoding"}
+
+// NewSimulationViewFromHar converts the entries of an HTTP Archive into request response pairs, matching
+// requests the same way as they would be captured
+func NewSimulationViewFromHar(data []byte) (SimulationViewV5, error) {
+	var har HarView
+	if err := json.Unmarshal(data, &har); err != nil {
+		return SimulationViewV5{}, errors.New("Invalid HAR: " + err.Error())
+	}
+
+	pairs := []RequestMatcherResponsePairViewV5{}
+	for i, entry := range har.Log.Entries {
+		pair, err := newPairViewFromHarEntry(entry)
+		if err != nil {
+			return SimulationViewV5{}, fmt.Errorf("Invalid HAR: entry %d: %s", i, err.Error())
+		}
+		pairs = append(pairs, pair)
+	}
+
+	return SimulationViewV5{
+		DataViewV5: DataViewV5{
+			RequestResponsePairs: pairs,
+			GlobalActions: GlobalActionsView{
+				Delays:          []v1.ResponseDelayView{},
+				DelaysLogNormal: []v1.ResponseDelayLogNormalView{},
+			},
+		},
+		MetaView: *NewMetaView(""),
+	}, nil
+}
+
+func newPairViewFromHarEntry(entry HarEntryView) (RequestMatcherResponsePairViewV5, error) {
+	requestUrl, err := url.Parse(entry.Request.Url)
+	if err != nil {
+		return RequestMatcherResponsePairViewV5{}, err
+	}
+	if requestUrl.Host == "" {
+		return RequestMatcherResponsePairViewV5{}, errors.New("request url " + entry.Request.Url + " is not absolute")
+	}
+
+	requestHeaders := harHeaders(entry.Request.Headers, nil)
+
+	requestBody := ""
+	if entry.Request.PostData != nil {
+		requestBody = entry.Request.PostData.Text
+	}
+	bodyMatcher := matchers.Exact
+	switch util.GetContentTypeFromHeaders(requestHeaders) {
+	case "json":
+		bodyMatcher = matchers.Json
+	case "xml":
+		bodyMatcher = matchers.Xml
+	}
+
+	var query *QueryMatcherViewV5
+	if values := requestUrl.Query(); len(values) > 0 {
+		query = &QueryMatcherViewV5{}
+		for key, value := range values {
+			if len(value) > 1 {
+				(*query)[key] = []MatcherViewV5{NewMatcherView(matchers.Array, value)}
+			} else {
+				(*query)[key] = []MatcherViewV5{NewMatcherView(matchers.Exact, value[0])}
+			}
+		}
+	}
+
+	response := ResponseDetailsViewV5{
+		Status:  entry.Response.Status,
+		Body:    entry.Response.Content.Text,
+		Headers: harHeaders(entry.Response.Headers, harSkippedResponseHeaders),
+	}
+	if entry.Response.Content.Encoding == "base64" {
+		response.EncodedBody = true
+	} else if entry.Response.Content.Encoding != "" {
+		return RequestMatcherResponsePairViewV5{}, errors.New("unsupported content encoding " + entry.Response.Content.Encoding)
+	}
+
+	return RequestMatcherResponsePairViewV5{
+		RequestMatcher: RequestMatcherViewV5{
+			Path:        []MatcherViewV5{NewMatcherView(matchers.Exact, requestUrl.Path)},
+			Method:      []MatcherViewV5{NewMatcherView(matchers.Exact, strings.ToUpper(entry.Request.Method))},
+			Destination: []MatcherViewV5{NewMatcherView(matchers.Exact, requestUrl.Host)},
+			Scheme:      []MatcherViewV5{NewMatcherView(matchers.Exact, requestUrl.Scheme)},
+			Query:       query,
+			Body:        []MatcherViewV5{NewMatcherView(bodyMatcher, requestBody)},
+		},
+		Response: response,
+	}, nil
+}
+
+// harHeaders groups the headers of an archive by name, leaving out HTTP/2 pseudo-headers and any skipped ones
+func harHeaders(headers []HarNameValueView, skipped []string) map[string][]string {
+	result := map[string][]string{}
+	for _, header := range headers {
+		if strings.HasPrefix(header.Name, ":") {
+			continue
+		}
+		name := http.CanonicalHeaderKey(header.Name)
+		if util.Contains(skipped, []string{name}) {
+			continue
+		}
+		result[name] = append(result[name], header.Value)
+	}
+	return result
+}
+
+// NewHarViewFromJournal converts journal entries into an HTTP Archive, so that they can be opened in browser devtools
+func NewHarViewFromJournal(journal JournalView) HarView {
+	entries := []HarEntryView{}
+	for _, entry := range journal.Journal {
+		entries = append(entries, newHarEntryView(entry))
+	}
+
+	return HarView{
+		Log: HarLogView{
+			Version: "1.2",
+			Creator: HarCreatorView{Name: "Hoverfly"},
+			Entries: entries,
+		},
+	}
+}
+
+func newHarEntryView(entry JournalEntryView) HarEntryView {
+	request := entry.Request
+	query := util.PointerToString(request.Query)
+
+	requestUrl := url.URL{
+		Scheme:   util.PointerToString(request.Scheme),
+		Host:     util.PointerToString(request.Destination),
+		Path:     util.PointerToString(request.Path),
+		RawQuery: query,
+	}
+
+	queryString := []HarNameValueView{}
+	values, _ := url.ParseQuery(query)
+	for _, key := range sortedKeys(values) {
+		for _, value := range values[key] {
+			queryString = append(queryString, HarNameValueView{Name: key, Value: value})
+		}
+	}
+
+	harRequest := HarRequestView{
+		Method:      util.PointerToString(request.Method),
+		Url:         requestUrl.String(),
+		HttpVersion: "HTTP/1.1",
+		Cookies:     []HarNameValueView{},
+		Headers:     harNameValues(request.Headers),
+		QueryString: queryString,
+		HeadersSize: -1,
+		BodySize:    len(util.PointerToString(request.Body)),
+	}
+	if body := util.PointerToString(request.Body); body != "" {
+		harRequest.PostData = &HarPostDataView{
+			MimeType: http.Header(request.Headers).Get("Content-Type"),
+			Text:     body,
+		}
+	}
+
+	content := HarContentView{
+		Size:     len(entry.Response.Body),
+		MimeType: http.Header(entry.Response.Headers).Get("Content-Type"),
+		Text:     entry.Response.Body,
+	}
+	if entry.Response.EncodedBody {
+		content.Encoding = "base64"
+	}
+
+	return HarEntryView{
+		StartedDateTime: entry.TimeStarted,
+		Time:            entry.Latency,
+		Request:         harRequest,
+		Response: HarResponseView{
+			Status:      entry.Response.Status,
+			StatusText:  http.StatusText(entry.Response.Status),
+			HttpVersion: "HTTP/1.1",
+			Cookies:     []HarNameValueView{},
+			Headers:     harNameValues(entry.Response.Headers),
+			Content:     content,
+			RedirectURL: http.Header(entry.Response.Headers).Get("Location"),
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Timings: HarTimingsView{Wait: entry.Latency},
+	}
+}
+
+func harNameValues(headers map[string][]string) []HarNameValueView {
+	result := []HarNameValueView{}
+	for _, name := range sortedKeys(headers) {
+		for _, value := range headers[name] {
+			result = append(result, HarNameValueView{Name: name, Value: value})
+		}
+	}
+	return result
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,147 @@
+package v2
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+const testHar = `{
+	"log": {
+		"version": "1.2",
+		"creator": {"name": "Firefox", "version": "115.0"},
+		"entries": [
+			{
+				"startedDateTime": "2024-01-01T10:00:00.000Z",
+				"time": 12,
+				"request": {
+					"method": "post",
+					"url": "https://api.example.com/users?team=qa&tag=a&tag=b",
+					"httpVersion": "HTTP/2",
+					"headers": [
+						{"name": ":authority", "value": "api.example.com"},
+						{"name": "content-type", "value": "application/json"}
+					],
+					"postData": {"mimeType": "application/json", "text": "{\"name\":\"Ben\"}"}
+				},
+				"response": {
+					"status": 201,
+					"headers": [
+						{"name": "content-type", "value": "application/json"},
+						{"name": "content-encoding", "value": "gzip"},
+						{"name": "set-cookie", "value": "a=1"},
+						{"name": "set-cookie", "value": "b=2"}
+					],
+					"content": {"size": 11, "mimeType": "application/json", "text": "{\"id\":\"1\"}"}
+				}
+			},
+			{
+				"startedDateTime": "2024-01-01T10:00:01.000Z",
+				"time": 3,
+				"request": {"method": "GET", "url": "http://api.example.com:8080/logo.png", "headers": []},
+				"response": {
+					"status": 200,
+					"headers": [{"name": "Content-Type", "value": "image/png"}],
+					"content": {"size": 4, "mimeType": "image/png", "text": "iVBORw==", "encoding": "base64"}
+				}
+			}
+		]
+	}
+}`
+
+func Test_NewSimulationViewFromHar_ConvertsEntriesToPairs(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulationViewFromHar([]byte(testHar))
+	Expect(err).To(BeNil())
+	Expect(simulation.SchemaVersion).To(Equal("v5.3"))
+	Expect(simulation.RequestResponsePairs).To(HaveLen(2))
+
+	pair := simulation.RequestResponsePairs[0]
+	Expect(pair.RequestMatcher.Method).To(ConsistOf(NewMatcherView(matchers.Exact, "POST")))
+	Expect(pair.RequestMatcher.Scheme).To(ConsistOf(NewMatcherView(matchers.Exact, "https")))
+	Expect(pair.RequestMatcher.Destination).To(ConsistOf(NewMatcherView(matchers.Exact, "api.example.com")))
+	Expect(pair.RequestMatcher.Path).To(ConsistOf(NewMatcherView(matchers.Exact, "/users")))
+	Expect(*pair.RequestMatcher.Query).To(Equal(QueryMatcherViewV5{
+		"team": {NewMatcherView(matchers.Exact, "qa")},
+		"tag":  {NewMatcherView(matchers.Array, []string{"a", "b"})},
+	}))
+	Expect(pair.RequestMatcher.Body).To(ConsistOf(NewMatcherView(matchers.Json, `{"name":"Ben"}`)))
+	Expect(pair.RequestMatcher.Headers).To(BeNil())
+
+	Expect(pair.Response.Status).To(Equal(201))
+	Expect(pair.Response.Body).To(Equal(`{"id":"1"}`))
+	Expect(pair.Response.EncodedBody).To(BeFalse())
+	Expect(pair.Response.Headers).To(Equal(map[string][]string{
+		"Content-Type": {"application/json"},
+		"Set-Cookie":   {"a=1", "b=2"},
+	}))
+
+	pair = simulation.RequestResponsePairs[1]
+	Expect(pair.RequestMatcher.Destination).To(ConsistOf(NewMatcherView(matchers.Exact, "api.example.com:8080")))
+	Expect(pair.RequestMatcher.Query).To(BeNil())
+	Expect(pair.RequestMatcher.Body).To(ConsistOf(NewMatcherView(matchers.Exact, "")))
+	Expect(pair.Response.Body).To(Equal("iVBORw=="))
+	Expect(pair.Response.EncodedBody).To(BeTrue())
+}
+
+func Test_NewSimulationViewFromHar_ReturnsErrorForInvalidHar(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewSimulationViewFromHar([]byte(`{"log": `))
+	Expect(err).To(MatchError(ContainSubstring("Invalid HAR")))
+
+	_, err = NewSimulationViewFromHar([]byte(`{"log": {"entries": [{"request": {"url": "/relative"}}]}}`))
+	Expect(err).To(MatchError("Invalid HAR: entry 0: request url /relative is not absolute"))
+}
+
+func Test_NewHarViewFromJournal_ConvertsEntries(t *testing.T) {
+	RegisterTestingT(t)
+
+	method, scheme, destination, path, query, body := "POST", "http", "test.com", "/users", "b=2&a=1", "name=ben"
+	har := NewHarViewFromJournal(JournalView{
+		Journal: []JournalEntryView{{
+			Request: RequestDetailsView{
+				Method:      &method,
+				Scheme:      &scheme,
+				Destination: &destination,
+				Path:        &path,
+				Query:       &query,
+				Body:        &body,
+				Headers:     map[string][]string{"Content-Type": {"application/x-www-form-urlencoded"}},
+			},
+			Response: ResponseDetailsView{
+				Status:      200,
+				Body:        "aGVsbG8=",
+				EncodedBody: true,
+				Headers:     map[string][]string{"Content-Type": {"text/plain"}},
+			},
+			TimeStarted: "2024-01-01T10:00:00.000Z",
+			Latency:     5.5,
+		}},
+	})
+
+	Expect(har.Log.Version).To(Equal("1.2"))
+	Expect(har.Log.Creator.Name).To(Equal("Hoverfly"))
+	Expect(har.Log.Entries).To(HaveLen(1))
+
+	entry := har.Log.Entries[0]
+	Expect(entry.StartedDateTime).To(Equal("2024-01-01T10:00:00.000Z"))
+	Expect(entry.Time).To(Equal(5.5))
+	Expect(entry.Request.Method).To(Equal("POST"))
+	Expect(entry.Request.Url).To(Equal("http://test.com/users?b=2&a=1"))
+	Expect(entry.Request.QueryString).To(Equal([]HarNameValueView{{"a", "1"}, {"b", "2"}}))
+	Expect(entry.Request.PostData).To(Equal(&HarPostDataView{MimeType: "application/x-www-form-urlencoded", Text: "name=ben"}))
+	Expect(entry.Response.Status).To(Equal(200))
+	Expect(entry.Response.StatusText).To(Equal("OK"))
+	Expect(entry.Response.Headers).To(Equal([]HarNameValueView{{"Content-Type", "text/plain"}}))
+	Expect(entry.Response.Content).To(Equal(HarContentView{Size: 8, MimeType: "text/plain", Text: "aGVsbG8=", Encoding: "base64"}))
+}
+
+func Test_NewHarViewFromJournal_WithoutEntriesReturnsEmptyLog(t *testing.T) {
+	RegisterTestingT(t)
+
+	har := NewHarViewFromJournal(JournalView{})
+	Expect(har.Log.Entries).To(Equal([]HarEntryView{}))
+}
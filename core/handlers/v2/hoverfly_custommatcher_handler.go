@@ -0,0 +1,82 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyCustomMatchers interface {
+	GetCustomMatchers() CustomMatchersView
+	SetCustomMatcher(CustomMatcherView) error
+	DeleteCustomMatcher(string) error
+}
+
+type HoverflyCustomMatchersHandler struct {
+	Hoverfly HoverflyCustomMatchers
+}
+
+func (this *HoverflyCustomMatchersHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/hoverfly/custom-matchers", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Put("/api/v2/hoverfly/custom-matchers", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Put),
+	))
+	mux.Options("/api/v2/hoverfly/custom-matchers", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+	mux.Delete("/api/v2/hoverfly/custom-matchers/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/hoverfly/custom-matchers/:name", negroni.New(
+		negroni.HandlerFunc(this.OptionsName),
+	))
+}
+
+func (this *HoverflyCustomMatchersHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetCustomMatchers())
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *HoverflyCustomMatchersHandler) Put(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	var customMatcherView CustomMatcherView
+	err := handlers.ReadFromRequest(req, &customMatcherView)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), 400)
+		return
+	}
+
+	if err := this.Hoverfly.SetCustomMatcher(customMatcherView); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), 400)
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *HoverflyCustomMatchersHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if err := this.Hoverfly.DeleteCustomMatcher(bone.GetValue(req, "name")); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), 404)
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *HoverflyCustomMatchersHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, PUT")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *HoverflyCustomMatchersHandler) OptionsName(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
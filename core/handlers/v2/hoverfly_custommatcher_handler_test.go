@@ -0,0 +1,111 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/go-zoo/bone"
+	. "github.com/onsi/gomega"
+)
+
+type HoverflyCustomMatchersStub struct {
+	CustomMatchers []CustomMatcherView
+	Err            error
+}
+
+func (this *HoverflyCustomMatchersStub) GetCustomMatchers() CustomMatchersView {
+	return CustomMatchersView{CustomMatchers: this.CustomMatchers}
+}
+
+func (this *HoverflyCustomMatchersStub) SetCustomMatcher(customMatcher CustomMatcherView) error {
+	if this.Err != nil {
+		return this.Err
+	}
+	this.CustomMatchers = append(this.CustomMatchers, customMatcher)
+	return nil
+}
+
+func (this *HoverflyCustomMatchersStub) DeleteCustomMatcher(name string) error {
+	if this.Err != nil {
+		return this.Err
+	}
+	this.CustomMatchers = nil
+	return nil
+}
+
+func Test_CustomMatchersHandler_GetReturnsCustomMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyCustomMatchersStub{CustomMatchers: []CustomMatcherView{{Name: "hmac", Remote: "http://localhost:8080"}}}
+	unit := HoverflyCustomMatchersHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/hoverfly/custom-matchers", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var customMatchersView CustomMatchersView
+	Expect(json.Unmarshal(response.Body.Bytes(), &customMatchersView)).To(Succeed())
+	Expect(customMatchersView.CustomMatchers).To(ConsistOf(CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080"}))
+}
+
+func Test_CustomMatchersHandler_PutSetsCustomMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyCustomMatchersStub{}
+	unit := HoverflyCustomMatchersHandler{Hoverfly: stubHoverfly}
+
+	bodyBytes, _ := json.Marshal(CustomMatcherView{Name: "hmac", Binary: "python3", ScriptContent: "script"})
+	request, err := http.NewRequest("PUT", "/api/v2/hoverfly/custom-matchers", io.NopCloser(bytes.NewBuffer(bodyBytes)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Put, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.CustomMatchers).To(ConsistOf(CustomMatcherView{Name: "hmac", Binary: "python3", ScriptContent: "script"}))
+}
+
+func Test_CustomMatchersHandler_PutReturnsBadRequestOnError(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyCustomMatchersStub{Err: errors.New("remote is not a valid URL")}
+	unit := HoverflyCustomMatchersHandler{Hoverfly: stubHoverfly}
+
+	bodyBytes, _ := json.Marshal(CustomMatcherView{Name: "hmac", Remote: "not a url"})
+	request, err := http.NewRequest("PUT", "/api/v2/hoverfly/custom-matchers", io.NopCloser(bytes.NewBuffer(bodyBytes)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Put, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("remote is not a valid URL"))
+}
+
+func Test_CustomMatchersHandler_DeleteReturnsNotFoundOnError(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyCustomMatchersStub{Err: errors.New("Custom matcher hmac not found")}
+	unit := HoverflyCustomMatchersHandler{Hoverfly: stubHoverfly}
+
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+
+	request, err := http.NewRequest("DELETE", "/api/v2/hoverfly/custom-matchers/hmac", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, request)
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Custom matcher hmac not found"))
+}
@@ -72,6 +72,14 @@ func (this *HoverflyStub) IsWebServer() bool {
 	return false
 }
 
+func (this *HoverflyStub) GetWatch() *WatchView {
+	return &WatchView{
+		Paths: []string{"simulations"},
+		Files: []string{"simulations/test.json"},
+		Error: "test-error",
+	}
+}
+
 func TestHoverflyHandlerGetReturnsTheCorrectMode(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -96,6 +104,11 @@ func TestHoverflyHandlerGetReturnsTheCorrectMode(t *testing.T) {
 	Expect(hoverflyView.Version).To(Equal("test-version"))
 	Expect(hoverflyView.UpstreamProxy).To(Equal("test-proxy.com:8080"))
 	Expect(hoverflyView.IsWebServer).To(BeFalse())
+	Expect(hoverflyView.Watch).To(Equal(&WatchView{
+		Paths: []string{"simulations"},
+		Files: []string{"simulations/test.json"},
+		Error: "test-error",
+	}))
 }
 
 func Test_HoverflyHandler_Options_GetsOptions(t *testing.T) {
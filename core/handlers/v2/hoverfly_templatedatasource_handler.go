@@ -12,6 +12,8 @@ import (
 type HoverflyTemplateDataSource interface {
 	SetCsvDataSource(string, string) error
 	DeleteDataSource(string)
+	SetJsonDataSource(string, string, string) error
+	DeleteJsonDataSource(string)
 	GetAllDataSources() TemplateDataSourceView
 }
 
@@ -35,6 +37,21 @@ func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) RegisterRoute
 		negroni.HandlerFunc(am.RequireTokenAuthentication),
 		negroni.HandlerFunc(templateDataSourceHandler.Delete),
 	))
+
+	mux.Get("/api/v2/hoverfly/templating-data-source/json", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(templateDataSourceHandler.Get),
+	))
+
+	mux.Put("/api/v2/hoverfly/templating-data-source/json", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(templateDataSourceHandler.PutJson),
+	))
+
+	mux.Delete("/api/v2/hoverfly/templating-data-source/json/:dataSourceName", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(templateDataSourceHandler.DeleteJson),
+	))
 }
 
 func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) Put(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
@@ -57,6 +74,26 @@ func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) Delete(rw htt
 	templateDataSourceHandler.Get(rw, req, next)
 }
 
+func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) PutJson(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	var templateDataSourceRequest JSONDataSourceView
+	err := handlers.ReadFromRequest(req, &templateDataSourceRequest)
+	if err != nil {
+		handlers.WriteErrorResponse(rw, err.Error(), 400)
+		return
+	}
+	if err := templateDataSourceHandler.Hoverfly.SetJsonDataSource(templateDataSourceRequest.Name, templateDataSourceRequest.Format, templateDataSourceRequest.Data); err != nil {
+		handlers.WriteErrorResponse(rw, err.Error(), 400)
+		return
+	}
+	templateDataSourceHandler.Get(rw, req, next)
+}
+
+func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) DeleteJson(rw http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	dataSourceName := bone.GetValue(req, "dataSourceName")
+	templateDataSourceHandler.Hoverfly.DeleteJsonDataSource(dataSourceName)
+	templateDataSourceHandler.Get(rw, req, next)
+}
+
 func (templateDataSourceHandler HoverflyTemplateDataSourceHandler) Get(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
 
 	templateDataSourceView := templateDataSourceHandler.Hoverfly.GetAllDataSources()
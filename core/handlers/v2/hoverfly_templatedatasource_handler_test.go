@@ -3,6 +3,7 @@ package v2
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"testing"
@@ -32,6 +33,16 @@ func (HoverflyTemplateDataSourceStub) SetCsvDataSource(string, string) error {
 func (HoverflyTemplateDataSourceStub) DeleteDataSource(string) {
 }
 
+func (HoverflyTemplateDataSourceStub) SetJsonDataSource(name, format, data string) error {
+	if format != "" && format != "json" {
+		return fmt.Errorf("Data source format must be json or yaml")
+	}
+	return nil
+}
+
+func (HoverflyTemplateDataSourceStub) DeleteJsonDataSource(string) {
+}
+
 func Test_TemplateDataSourceHandler_DeleteDataSource(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -78,3 +89,52 @@ func Test_TemplateDataSourceHandler_GetAllDataSources(t *testing.T) {
 	Expect(responseBody).To(Equal(string(expectedResponseBodyBytes)))
 
 }
+
+func Test_TemplateDataSourceHandler_SetJsonDataSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyTemplateDataSourceStub{}
+	unit := HoverflyTemplateDataSourceHandler{Hoverfly: stubHoverfly}
+
+	bodyBytes, err := json.Marshal(JSONDataSourceView{Name: "users", Data: `{"users": []}`})
+	Expect(err).To(BeNil())
+
+	request, err := http.NewRequest("PUT", "/api/v2/hoverfly/templating-data-source/json", io.NopCloser(bytes.NewBuffer(bodyBytes)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutJson, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+}
+
+func Test_TemplateDataSourceHandler_SetJsonDataSourceReturnsBadRequestForAnUnknownFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyTemplateDataSourceStub{}
+	unit := HoverflyTemplateDataSourceHandler{Hoverfly: stubHoverfly}
+
+	bodyBytes, err := json.Marshal(JSONDataSourceView{Name: "users", Format: "xml", Data: "<users/>"})
+	Expect(err).To(BeNil())
+
+	request, err := http.NewRequest("PUT", "/api/v2/hoverfly/templating-data-source/json", io.NopCloser(bytes.NewBuffer(bodyBytes)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.PutJson, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Data source format must be json or yaml"))
+}
+
+func Test_TemplateDataSourceHandler_DeleteJsonDataSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyTemplateDataSourceStub{}
+	unit := HoverflyTemplateDataSourceHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("DELETE", "/api/v2/hoverfly/templating-data-source/json/users", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.DeleteJson, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+}
@@ -19,6 +19,7 @@ type HoverflyJournal interface {
 	GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (JournalView, error)
 	GetFilteredEntries(journalEntryFilterView JournalEntryFilterView) ([]JournalEntryView, error)
 	DeleteEntries() error
+	VerifyRequests(requestVerificationView RequestVerificationView) (RequestVerificationResultView, error)
 }
 
 type JournalHandler struct {
@@ -41,6 +42,13 @@ func (this *JournalHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandl
 	mux.Options("/api/v2/journal", negroni.New(
 		negroni.HandlerFunc(this.Options),
 	))
+	mux.Post("/api/v2/journal/verify", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Verify),
+	))
+	mux.Options("/api/v2/journal/verify", negroni.New(
+		negroni.HandlerFunc(this.OptionsVerify),
+	))
 }
 
 func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
@@ -51,6 +59,12 @@ func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Requ
 	fromTime := util.GetUnixTimeQueryParam(request, "from")
 	toTime := util.GetUnixTimeQueryParam(request, "to")
 	sort := queryParams.Get("sort")
+	format := queryParams.Get("format")
+
+	if format != "" && format != "json" && format != HarFormat {
+		handlers.WriteErrorResponse(response, "Unsupported journal format "+format, http.StatusBadRequest)
+		return
+	}
 
 	if limit <= 0 {
 		limit = DefaultJournalLimit
@@ -66,6 +80,12 @@ func (this *JournalHandler) Get(response http.ResponseWriter, request *http.Requ
 		return
 	}
 
+	if format == HarFormat {
+		bytes, _ := json.Marshal(NewHarViewFromJournal(journalView))
+		handlers.WriteResponse(response, bytes)
+		return
+	}
+
 	bytes, _ := json.Marshal(journalView)
 	handlers.WriteResponse(response, bytes)
 }
@@ -96,6 +116,30 @@ func (this *JournalHandler) Post(response http.ResponseWriter, request *http.Req
 	handlers.WriteResponse(response, bytes)
 }
 
+func (this *JournalHandler) Verify(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
+	var requestVerificationView RequestVerificationView
+
+	err := handlers.ReadFromRequest(request, &requestVerificationView)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := requestVerificationView.Validate(); err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := this.Hoverfly.VerifyRequests(requestVerificationView)
+	if err != nil {
+		handlers.WriteErrorResponse(response, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	bytes, _ := json.Marshal(result)
+	handlers.WriteResponse(response, bytes)
+}
+
 func (this *JournalHandler) Delete(response http.ResponseWriter, request *http.Request, next http.HandlerFunc) {
 	err := this.Hoverfly.DeleteEntries()
 	if err != nil {
@@ -110,3 +154,8 @@ func (this *JournalHandler) Options(w http.ResponseWriter, r *http.Request, next
 	w.Header().Add("Allow", "OPTIONS, GET, DELETE, POST")
 	handlers.WriteResponse(w, []byte(""))
 }
+
+func (this *JournalHandler) OptionsVerify(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -24,6 +24,7 @@ type HoverflyJournalStub struct {
 	from                   *time.Time
 	to                     *time.Time
 	journalEntryFilterView JournalEntryFilterView
+	requestVerification    RequestVerificationView
 }
 
 func (this *HoverflyJournalStub) GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (JournalView, error) {
@@ -75,6 +76,22 @@ func (this *HoverflyJournalStub) DeleteEntries() error {
 	return nil
 }
 
+func (this *HoverflyJournalStub) VerifyRequests(requestVerificationView RequestVerificationView) (RequestVerificationResultView, error) {
+	if this.error {
+		return RequestVerificationResultView{}, fmt.Errorf("verify error")
+	}
+
+	this.requestVerification = requestVerificationView
+	return RequestVerificationResultView{
+		Passed:  false,
+		Message: "Expected at least 1 matching request but received 0 matching requests",
+		ClosestMisses: []JournalClosestMissView{{
+			Id:           "1",
+			MissedFields: []string{"path"},
+		}},
+	}, nil
+}
+
 func Test_JournalHandler_Get_ReturnsJournal(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -95,6 +112,40 @@ func Test_JournalHandler_Get_ReturnsJournal(t *testing.T) {
 	Expect(journalView.Journal[0].Mode).To(Equal("test"))
 }
 
+func Test_JournalHandler_Get_WithHarFormatReturnsHar(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal?format=har&limit=10", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.limit).To(Equal(10))
+
+	var har HarView
+	Expect(json.Unmarshal(response.Body.Bytes(), &har)).To(Succeed())
+	Expect(har.Log.Version).To(Equal("1.2"))
+	Expect(har.Log.Entries).To(HaveLen(1))
+}
+
+func Test_JournalHandler_Get_WithUnsupportedFormatReturnsBadRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/journal?format=csv", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
+
 func Test_JournalHandler_Get_SetDefaultPagingQueryIfNotSpecified(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -410,3 +461,75 @@ func unmarshalJournalView(buffer *bytes.Buffer) (JournalView, error) {
 
 	return journalView, nil
 }
+
+func Test_JournalHandler_Verify_CallsVerifyRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{"request": {"path": [{"matcher": "exact", "value": "/hello"}]}, "exactly": 2}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Verify, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.requestVerification.Request.Path[0].Value).To(Equal("/hello"))
+	Expect(*stubHoverfly.requestVerification.Exactly).To(Equal(2))
+
+	var resultView RequestVerificationResultView
+	Expect(json.Unmarshal(response.Body.Bytes(), &resultView)).To(Succeed())
+	Expect(resultView.Passed).To(BeFalse())
+	Expect(resultView.ClosestMisses).To(HaveLen(1))
+	Expect(resultView.ClosestMisses[0].MissedFields).To(ConsistOf("path"))
+}
+
+func Test_JournalHandler_Verify_ReturnsBadRequestForInvalidExpectation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{"request": {}, "never": true, "atLeast": 1}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Verify, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal(`"never" cannot be combined with "exactly", "atLeast" or "atMost"`))
+}
+
+func Test_JournalHandler_Verify_ReturnsBadRequestWithoutRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{"exactly": 1}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Verify, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
+
+func Test_JournalHandler_Verify_ReturnsInternalServerErrorWhenJournalFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyJournalStub{error: true}
+	unit := JournalHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/journal/verify", bytes.NewBufferString(`{"request": {}}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Verify, request)
+
+	Expect(response.Code).To(Equal(http.StatusInternalServerError))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("verify error"))
+}
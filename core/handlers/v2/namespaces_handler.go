@@ -0,0 +1,53 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyNamespaces interface {
+	GetNamespaces() NamespacesView
+	DeleteNamespace(name string) error
+}
+
+type NamespacesHandler struct {
+	Hoverfly HoverflyNamespaces
+}
+
+func (this *NamespacesHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/namespaces", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Delete("/api/v2/namespaces/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/namespaces", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *NamespacesHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetNamespaces())
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *NamespacesHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	err := this.Hoverfly.DeleteNamespace(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *NamespacesHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -0,0 +1,105 @@
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/go-zoo/bone"
+	. "github.com/onsi/gomega"
+)
+
+type HoverflyNamespacesStub struct {
+	namespaces []string
+}
+
+func (this *HoverflyNamespacesStub) GetNamespaces() NamespacesView {
+	return NamespacesView{Namespaces: this.namespaces}
+}
+
+func (this *HoverflyNamespacesStub) DeleteNamespace(name string) error {
+	for i, namespace := range this.namespaces {
+		if namespace == name {
+			this.namespaces = append(this.namespaces[:i], this.namespaces[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("Namespace %s not found", name)
+}
+
+func Test_NamespacesHandler_Get_ReturnsNamespaces(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyNamespacesStub{namespaces: []string{"suite-a", "suite-b"}}
+	unit := NamespacesHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "/api/v2/namespaces", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var namespacesView NamespacesView
+	Expect(json.Unmarshal(response.Body.Bytes(), &namespacesView)).To(Succeed())
+	Expect(namespacesView.Namespaces).To(ConsistOf("suite-a", "suite-b"))
+}
+
+func Test_NamespacesHandler_Delete_DeletesNamespaceFromPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyNamespacesStub{namespaces: []string{"suite-a", "suite-b"}}
+	unit := NamespacesHandler{Hoverfly: stubHoverfly}
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+
+	request, err := http.NewRequest("DELETE", "/api/v2/namespaces/suite-a", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var namespacesView NamespacesView
+	Expect(json.Unmarshal(response.Body.Bytes(), &namespacesView)).To(Succeed())
+	Expect(namespacesView.Namespaces).To(ConsistOf("suite-b"))
+}
+
+func Test_NamespacesHandler_Delete_ReturnsNotFoundForUnknownNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyNamespacesStub{namespaces: []string{"suite-a"}}
+	unit := NamespacesHandler{Hoverfly: stubHoverfly}
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+
+	request, err := http.NewRequest("DELETE", "/api/v2/namespaces/suite-b", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Namespace suite-b not found"))
+}
+
+func Test_NamespacesHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NamespacesHandler{Hoverfly: &HoverflyNamespacesStub{}}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/namespaces", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Options, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, DELETE"))
+}
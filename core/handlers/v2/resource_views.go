@@ -0,0 +1,14 @@
+package v2
+
+// ResourceViewV5 declares a REST collection served from an in-memory store. In a simulation the data is the seed
+// the store starts from and is reset to; from the resources endpoint it is what the store currently holds.
+type ResourceViewV5 struct {
+	Path        string                   `json:"path"`
+	Destination string                   `json:"destination,omitempty"`
+	IdField     string                   `json:"idField,omitempty"`
+	Data        []map[string]interface{} `json:"data"`
+}
+
+type ResourcesView struct {
+	Resources []ResourceViewV5 `json:"resources"`
+}
@@ -0,0 +1,51 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyResources interface {
+	GetResources() ResourcesView
+	ResetResources()
+}
+
+type ResourcesHandler struct {
+	Hoverfly HoverflyResources
+}
+
+func (this *ResourcesHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/resources", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Delete("/api/v2/resources", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Delete),
+	))
+	mux.Options("/api/v2/resources", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *ResourcesHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetResources())
+
+	handlers.WriteResponse(w, bytes)
+}
+
+// Delete resets the stores of the resources to the data of the simulation, rather than removing the resources
+func (this *ResourcesHandler) Delete(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	this.Hoverfly.ResetResources()
+
+	this.Get(w, req, next)
+}
+
+func (this *ResourcesHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
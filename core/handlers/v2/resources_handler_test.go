@@ -0,0 +1,76 @@
+package v2
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type HoverflyResourcesStub struct {
+	Resources []ResourceViewV5
+	Reset     bool
+}
+
+func (this *HoverflyResourcesStub) GetResources() ResourcesView {
+	return ResourcesView{Resources: this.Resources}
+}
+
+func (this *HoverflyResourcesStub) ResetResources() {
+	this.Reset = true
+}
+
+func TestResourcesHandler_Get_ReturnsResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflyResourcesStub{
+		Resources: []ResourceViewV5{{
+			Path: "/api/users",
+			Data: []map[string]interface{}{{"id": "1", "name": "Ben"}},
+		}},
+	}
+	unit := ResourcesHandler{Hoverfly: stub}
+
+	request, err := http.NewRequest("GET", "/api/v2/resources", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(BeNil())
+	Expect(string(body)).To(MatchJSON(`{"resources": [{"path": "/api/users", "data": [{"id": "1", "name": "Ben"}]}]}`))
+}
+
+func TestResourcesHandler_Delete_ResetsResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflyResourcesStub{}
+	unit := ResourcesHandler{Hoverfly: stub}
+
+	request, err := http.NewRequest("DELETE", "/api/v2/resources", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Delete, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stub.Reset).To(BeTrue())
+
+	var resourcesView ResourcesView
+	Expect(json.NewDecoder(response.Body).Decode(&resourcesView)).To(Succeed())
+}
+
+func TestResourcesHandler_Options_GetsOptions(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := ResourcesHandler{Hoverfly: &HoverflyResourcesStub{}}
+
+	request, err := http.NewRequest("OPTIONS", "/api/v2/resources", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Options, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(response.Header().Get("Allow")).To(Equal("OPTIONS, GET, DELETE"))
+}
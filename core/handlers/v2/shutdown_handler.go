@@ -10,7 +10,12 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
+type HoverflyShutdown interface {
+	Shutdown()
+}
+
 type ShutdownHandler struct {
+	Hoverfly HoverflyShutdown
 }
 
 func (this *ShutdownHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
@@ -27,6 +32,7 @@ func (this *ShutdownHandler) Delete(w http.ResponseWriter, req *http.Request, ne
 	handlers.WriteResponse(w, []byte(""))
 	go func() {
 		log.Warning("Shutting down")
+		this.Hoverfly.Shutdown()
 		os.Exit(0)
 	}()
 }
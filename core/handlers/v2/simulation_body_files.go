@@ -0,0 +1,56 @@
+package v2
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// BodyFilesDirectory is the default directory of the body files referenced by ReferenceBodyFiles
+const BodyFilesDirectory = "bodies"
+
+// ReferenceBodyFiles gives each response with a body longer than the threshold a bodyFile in the directory, named
+// after the hash of the body so identical bodies share a file. The body is kept for whoever writes the files.
+func ReferenceBodyFiles(simulation *SimulationViewV5, threshold int, directory string) {
+	for i := range simulation.RequestResponsePairs {
+		referenceBodyFile(&simulation.RequestResponsePairs[i].Response, threshold, directory)
+		for j := range simulation.RequestResponsePairs[i].Responses {
+			referenceBodyFile(&simulation.RequestResponsePairs[i].Responses[j], threshold, directory)
+		}
+	}
+}
+
+func referenceBodyFile(response *ResponseDetailsViewV5, threshold int, directory string) {
+	if response.BodyFile != "" || len(response.Body) <= threshold {
+		return
+	}
+
+	sum := sha256.Sum256([]byte(response.Body))
+	response.BodyFile = strings.TrimSuffix(directory, "/") + "/" + hex.EncodeToString(sum[:]) + bodyFileExtension(response)
+}
+
+// bodyFileExtension makes body files easier to browse. Encoded bodies are written as they are exported, in base64.
+func bodyFileExtension(response *ResponseDetailsViewV5) string {
+	if response.EncodedBody {
+		return ".base64"
+	}
+
+	contentType := ""
+	for key, values := range response.Headers {
+		if strings.EqualFold(key, "Content-Type") && len(values) > 0 {
+			contentType = strings.ToLower(values[0])
+		}
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		return ".json"
+	case strings.Contains(contentType, "xml"):
+		return ".xml"
+	case strings.Contains(contentType, "html"):
+		return ".html"
+	case strings.HasPrefix(contentType, "text/"):
+		return ".txt"
+	}
+	return ""
+}
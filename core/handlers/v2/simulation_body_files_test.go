@@ -0,0 +1,47 @@
+package v2
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ReferenceBodyFiles_ReferencesBodiesLongerThanTheThreshold(t *testing.T) {
+	RegisterTestingT(t)
+
+	largeBody := `{"items": [1, 2, 3, 4, 5, 6, 7, 8, 9]}`
+	simulation := SimulationViewV5{
+		DataViewV5: DataViewV5{
+			RequestResponsePairs: []RequestMatcherResponsePairViewV5{
+				{
+					Response: ResponseDetailsViewV5{
+						Body:    largeBody,
+						Headers: map[string][]string{"content-type": {"application/json"}},
+					},
+					Responses: []ResponseDetailsViewV5{
+						{Body: "small"},
+						{Body: "aGVsbG8gd29ybGQgaGVsbG8gd29ybGQ=", EncodedBody: true},
+						{Body: largeBody, BodyFile: "responses/items.json"},
+					},
+				},
+				{
+					Response: ResponseDetailsViewV5{
+						Body:    largeBody,
+						Headers: map[string][]string{"Content-Type": {"application/json; charset=utf-8"}},
+					},
+				},
+			},
+		},
+	}
+
+	ReferenceBodyFiles(&simulation, 20, "bodies")
+
+	first := simulation.RequestResponsePairs[0]
+	Expect(first.Response.BodyFile).To(MatchRegexp(`^bodies/[0-9a-f]{64}\.json$`))
+	Expect(first.Response.Body).To(Equal(largeBody))
+	Expect(first.Responses[0].BodyFile).To(BeEmpty())
+	Expect(first.Responses[1].BodyFile).To(MatchRegexp(`^bodies/[0-9a-f]{64}\.base64$`))
+	Expect(first.Responses[2].BodyFile).To(Equal("responses/items.json"))
+
+	Expect(simulation.RequestResponsePairs[1].Response.BodyFile).To(Equal(first.Response.BodyFile))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/SpectoLabs/hoverfly/core/handlers"
 	"github.com/SpectoLabs/hoverfly/core/util"
@@ -16,6 +18,7 @@ import (
 type HoverflySimulation interface {
 	GetSimulation() (SimulationViewV5, error)
 	GetFilteredSimulation(string) (SimulationViewV5, error)
+	GetRedactedSimulation(string, []RedactionRuleView) (SimulationViewV5, error)
 	PutSimulation(SimulationViewV5) SimulationImportResult
 	DeleteSimulation()
 }
@@ -56,10 +59,26 @@ func (this *SimulationHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHa
 
 func (this *SimulationHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
 	urlPattern := req.URL.Query().Get("urlPattern")
+	redactionRules := getRedactionRulesFromQuery(req.URL.Query())
+
+	bodyFileThreshold := -1
+	if threshold := req.URL.Query().Get("bodyFileThreshold"); threshold != "" {
+		var err error
+		if bodyFileThreshold, err = strconv.Atoi(threshold); err != nil || bodyFileThreshold < 0 {
+			handlers.WriteErrorResponse(w, "bodyFileThreshold must be a number of bytes", http.StatusBadRequest)
+			return
+		}
+	}
 
 	var err error
 	var simulationView SimulationViewV5
-	if urlPattern == "" {
+	if len(redactionRules) > 0 {
+		simulationView, err = this.Hoverfly.GetRedactedSimulation(urlPattern, redactionRules)
+		if err != nil {
+			handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	} else if urlPattern == "" {
 		simulationView, err = this.Hoverfly.GetSimulation()
 	} else {
 		simulationView, err = this.Hoverfly.GetFilteredSimulation(urlPattern)
@@ -69,6 +88,10 @@ func (this *SimulationHandler) Get(w http.ResponseWriter, req *http.Request, nex
 		return
 	}
 
+	if bodyFileThreshold >= 0 {
+		ReferenceBodyFiles(&simulationView, bodyFileThreshold, BodyFilesDirectory)
+	}
+
 	bytes, _ := util.JSONMarshal(simulationView)
 
 	handlers.WriteResponse(w, bytes)
@@ -115,7 +138,16 @@ func (this *SimulationHandler) OptionsSchema(w http.ResponseWriter, r *http.Requ
 func (this *SimulationHandler) addSimulation(w http.ResponseWriter, req *http.Request, overrideExisting bool) error {
 	body, _ := io.ReadAll(req.Body)
 
-	simulationView, err := NewSimulationViewFromRequestBody(body)
+	var simulationView SimulationViewV5
+	var err error
+	switch format := req.URL.Query().Get("format"); format {
+	case "", "json":
+		simulationView, err = NewSimulationViewFromRequestBody(body)
+	case HarFormat:
+		simulationView, err = NewSimulationViewFromHar(body)
+	default:
+		err = fmt.Errorf("Unsupported simulation format %s", format)
+	}
 	if err != nil {
 		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
 		return err
@@ -143,3 +175,27 @@ func (this *SimulationHandler) addSimulation(w http.ResponseWriter, req *http.Re
 	}
 	return nil
 }
+
+// getRedactionRulesFromQuery reads the redaction rules of an export, where each of the redactHeader, redactQuery,
+// redactJsonPath, redactXPath and redactRegex parameters can be given more than once
+func getRedactionRulesFromQuery(query url.Values) []RedactionRuleView {
+	hash := query.Get("redactHash") == "true"
+
+	var rules []RedactionRuleView
+	for _, header := range query["redactHeader"] {
+		rules = append(rules, RedactionRuleView{Header: header, Hash: hash})
+	}
+	for _, key := range query["redactQuery"] {
+		rules = append(rules, RedactionRuleView{Query: key, Hash: hash})
+	}
+	for _, jsonPath := range query["redactJsonPath"] {
+		rules = append(rules, RedactionRuleView{JsonPath: jsonPath, Hash: hash})
+	}
+	for _, xpath := range query["redactXPath"] {
+		rules = append(rules, RedactionRuleView{XPath: xpath, Hash: hash})
+	}
+	for _, regex := range query["redactRegex"] {
+		rules = append(rules, RedactionRuleView{Regex: regex, Hash: hash})
+	}
+	return rules
+}
@@ -15,10 +15,11 @@ import (
 )
 
 type HoverflySimulationStub struct {
-	Deleted    bool
-	Simulation SimulationViewV5
-	UrlPattern string
-	Filtered   bool
+	Deleted        bool
+	Simulation     SimulationViewV5
+	UrlPattern     string
+	Filtered       bool
+	RedactionRules []RedactionRuleView
 }
 
 func (this HoverflySimulationStub) GetSimulation() (SimulationViewV5, error) {
@@ -62,6 +63,12 @@ func (this *HoverflySimulationStub) GetFilteredSimulation(urlPattern string) (Si
 	return this.GetSimulation()
 }
 
+func (this *HoverflySimulationStub) GetRedactedSimulation(urlPattern string, rules []RedactionRuleView) (SimulationViewV5, error) {
+	this.UrlPattern = urlPattern
+	this.RedactionRules = rules
+	return this.GetSimulation()
+}
+
 func (this *HoverflySimulationStub) DeleteSimulation() {
 	this.Deleted = true
 }
@@ -81,6 +88,10 @@ func (this HoverflySimulationErrorStub) GetFilteredSimulation(urlPattern string)
 	return SimulationViewV5{}, fmt.Errorf("error")
 }
 
+func (this HoverflySimulationErrorStub) GetRedactedSimulation(urlPattern string, rules []RedactionRuleView) (SimulationViewV5, error) {
+	return SimulationViewV5{}, fmt.Errorf("error")
+}
+
 func (this *HoverflySimulationErrorStub) DeleteSimulation() {}
 
 func (this *HoverflySimulationErrorStub) PutSimulation(simulation SimulationViewV5) SimulationImportResult {
@@ -99,6 +110,10 @@ func (this HoverflySimulationWarningStub) GetFilteredSimulation(urlPattern strin
 	return SimulationViewV5{}, fmt.Errorf("error")
 }
 
+func (this HoverflySimulationWarningStub) GetRedactedSimulation(urlPattern string, rules []RedactionRuleView) (SimulationViewV5, error) {
+	return SimulationViewV5{}, fmt.Errorf("error")
+}
+
 func (this *HoverflySimulationWarningStub) DeleteSimulation() {}
 
 func (this *HoverflySimulationWarningStub) PutSimulation(simulation SimulationViewV5) SimulationImportResult {
@@ -202,6 +217,76 @@ func TestSimulationHandler_Get_WithUrlPatternShouldFilterSimulation(t *testing.T
 	Expect(stubHoverfly.UrlPattern).To(Equal("foo.com"))
 }
 
+func TestSimulationHandler_Get_WithRedactionParametersShouldRedactSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("GET", "?urlPattern=foo.com&redactHeader=Authorization&redactHeader=Cookie&redactJsonPath=$.password&redactHash=true", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.Filtered).To(BeFalse())
+	Expect(stubHoverfly.UrlPattern).To(Equal("foo.com"))
+	Expect(stubHoverfly.RedactionRules).To(Equal([]RedactionRuleView{
+		{Header: "Authorization", Hash: true},
+		{Header: "Cookie", Hash: true},
+		{JsonPath: "$.password", Hash: true},
+	}))
+}
+
+func TestSimulationHandler_Get_ReturnsBadRequestIfRedactionFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := SimulationHandler{Hoverfly: &HoverflySimulationErrorStub{}}
+
+	request, err := http.NewRequest("GET", "?redactRegex=[", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
+
+func TestSimulationHandler_Get_WithBodyFileThresholdShouldReferenceBodyFiles(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := SimulationHandler{Hoverfly: &HoverflySimulationStub{}}
+
+	request, err := http.NewRequest("GET", "?bodyFileThreshold=5", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	simulationView, err := unmarshalSimulationViewV5(response.Body)
+	Expect(err).To(BeNil())
+
+	Expect(simulationView.RequestResponsePairs[0].Response.BodyFile).To(MatchRegexp(`^bodies/[0-9a-f]{64}$`))
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("test-body"))
+}
+
+func TestSimulationHandler_Get_ReturnsBadRequestIfBodyFileThresholdIsNotANumber(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := SimulationHandler{Hoverfly: &HoverflySimulationStub{}}
+
+	request, err := http.NewRequest("GET", "?bodyFileThreshold=large", nil)
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Get, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("bodyFileThreshold must be a number of bytes"))
+}
+
 func TestSimulationHandler_Delete_CallsDelete(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -637,3 +722,39 @@ func unmarshalResultView(buffer *bytes.Buffer) (SimulationImportResult, error) {
 
 	return result, nil
 }
+
+func TestSimulationHandler_Post_WithHarFormatImportsEntries(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/simulation?format=har", io.NopCloser(bytes.NewBuffer([]byte(testHar))))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	Expect(stubHoverfly.Deleted).To(BeFalse())
+	Expect(stubHoverfly.Simulation.RequestResponsePairs).To(HaveLen(2))
+	Expect(stubHoverfly.Simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/users"))
+}
+
+func TestSimulationHandler_Post_WithUnsupportedFormatReturnsBadRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationStub{}
+
+	unit := SimulationHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/simulation?format=xml", io.NopCloser(bytes.NewBuffer([]byte(testHar))))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Unsupported simulation format xml"))
+}
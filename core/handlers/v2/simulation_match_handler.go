@@ -0,0 +1,51 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflySimulationMatch interface {
+	ExplainMatch(RequestDetailsView) (MatchExplanationView, error)
+}
+
+type SimulationMatchHandler struct {
+	Hoverfly HoverflySimulationMatch
+}
+
+func (this *SimulationMatchHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Post("/api/v2/simulation/match", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Post),
+	))
+	mux.Options("/api/v2/simulation/match", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *SimulationMatchHandler) Post(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	var requestView RequestDetailsView
+	if err := handlers.ReadFromRequest(req, &requestView); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	explanation, err := this.Hoverfly.ExplainMatch(requestView)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(explanation)
+
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationMatchHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
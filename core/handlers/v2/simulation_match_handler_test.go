@@ -0,0 +1,81 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type HoverflySimulationMatchStub struct {
+	RequestView RequestDetailsView
+	Err         error
+}
+
+func (this *HoverflySimulationMatchStub) ExplainMatch(requestView RequestDetailsView) (MatchExplanationView, error) {
+	if this.Err != nil {
+		return MatchExplanationView{}, this.Err
+	}
+	this.RequestView = requestView
+	return MatchExplanationView{
+		Strategy: "strongest",
+		Matched:  true,
+		Candidates: []MatchCandidateView{
+			{Index: 0, Selected: true, Matched: true, Score: 2},
+		},
+	}, nil
+}
+
+func Test_SimulationMatchHandler_PostReturnsExplanation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflySimulationMatchStub{}
+	unit := SimulationMatchHandler{Hoverfly: stubHoverfly}
+
+	path := "/orders"
+	bodyBytes, _ := json.Marshal(RequestDetailsView{Path: &path})
+	request, err := http.NewRequest("POST", "/api/v2/simulation/match", io.NopCloser(bytes.NewBuffer(bodyBytes)))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(*stubHoverfly.RequestView.Path).To(Equal("/orders"))
+
+	var explanation MatchExplanationView
+	Expect(json.Unmarshal(response.Body.Bytes(), &explanation)).To(Succeed())
+	Expect(explanation.Matched).To(BeTrue())
+	Expect(explanation.Candidates).To(HaveLen(1))
+	Expect(explanation.Candidates[0].Selected).To(BeTrue())
+}
+
+func Test_SimulationMatchHandler_PostReturnsBadRequestOnInvalidBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := SimulationMatchHandler{Hoverfly: &HoverflySimulationMatchStub{}}
+
+	request, err := http.NewRequest("POST", "/api/v2/simulation/match", io.NopCloser(bytes.NewBufferString("not json")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
+
+func Test_SimulationMatchHandler_PostReturnsBadRequestOnError(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := SimulationMatchHandler{Hoverfly: &HoverflySimulationMatchStub{Err: errors.New("invalid request")}}
+
+	request, err := http.NewRequest("POST", "/api/v2/simulation/match", io.NopCloser(bytes.NewBufferString("{}")))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("invalid request"))
+}
@@ -0,0 +1,46 @@
+package v2
+
+// MatchExplanationView shows how every pair of the simulation scored against a request, and which of them
+// Hoverfly would respond with
+type MatchExplanationView struct {
+	Strategy   string               `json:"strategy"`
+	Matched    bool                 `json:"matched"`
+	Candidates []MatchCandidateView `json:"candidates"`
+}
+
+type MatchCandidateView struct {
+	Index          int                    `json:"index"`
+	Id             string                 `json:"id,omitempty"`
+	Disabled       bool                   `json:"disabled,omitempty"`
+	Labels         []string               `json:"labels,omitempty"`
+	Selected       bool                   `json:"selected"`
+	Matched        bool                   `json:"matched"`
+	Score          int                    `json:"score"`
+	MissedFields   []string               `json:"missedFields,omitempty"`
+	RequestMatcher RequestMatcherViewV5   `json:"requestMatcher"`
+	Fields         []FieldExplanationView `json:"fields"`
+}
+
+type FieldExplanationView struct {
+	Field    string                   `json:"field"`
+	Matched  bool                     `json:"matched"`
+	Score    int                      `json:"score"`
+	Matchers []MatcherExplanationView `json:"matchers,omitempty"`
+}
+
+// MatcherExplanationView is the result of one matcher, with a step for it and for each matcher chained to it
+// with doMatch. The key is the header, query, form field, multipart part or state key the matcher is for.
+type MatcherExplanationView struct {
+	Key     string            `json:"key,omitempty"`
+	Matched bool              `json:"matched"`
+	Score   int               `json:"score"`
+	Missing bool              `json:"missing,omitempty"`
+	Steps   []MatcherStepView `json:"steps,omitempty"`
+}
+
+type MatcherStepView struct {
+	Matcher string      `json:"matcher"`
+	Value   interface{} `json:"value"`
+	Actual  string      `json:"actual"`
+	Matched bool        `json:"matched"`
+}
@@ -0,0 +1,20 @@
+package v2
+
+// SimulationPairView is a pair along with the name of the source it belongs to, which is empty for a pair of the
+// simulation itself
+type SimulationPairView struct {
+	Source string `json:"source,omitempty"`
+	RequestMatcherResponsePairViewV5
+}
+
+// SimulationPairsView lists every pair in the order they are matched
+type SimulationPairsView struct {
+	Pairs []SimulationPairView `json:"pairs"`
+}
+
+// SimulationPairPatchView disables or enables a pair, and moves it to a position among the pairs of the simulation
+// or of the source it belongs to. Fields which are not set are left as they are.
+type SimulationPairPatchView struct {
+	Disabled *bool `json:"disabled,omitempty"`
+	Position *int  `json:"position,omitempty"`
+}
@@ -0,0 +1,157 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflySimulationPairs interface {
+	GetSimulationPairs() SimulationPairsView
+	GetSimulationPair(id string) (SimulationPairView, error)
+	AddSimulationPair(pairView RequestMatcherResponsePairViewV5) (SimulationPairView, error)
+	UpdateSimulationPair(id string, pairView RequestMatcherResponsePairViewV5) (SimulationPairView, error)
+	PatchSimulationPair(id string, patchView SimulationPairPatchView) (SimulationPairView, error)
+	DeleteSimulationPair(id string) error
+}
+
+type SimulationPairsHandler struct {
+	Hoverfly HoverflySimulationPairs
+}
+
+func (this *SimulationPairsHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/simulation/pairs", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Post("/api/v2/simulation/pairs", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Post),
+	))
+	mux.Options("/api/v2/simulation/pairs", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+
+	mux.Get("/api/v2/simulation/pairs/:id", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetPair),
+	))
+	mux.Put("/api/v2/simulation/pairs/:id", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutPair),
+	))
+	mux.Patch("/api/v2/simulation/pairs/:id", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PatchPair),
+	))
+	mux.Delete("/api/v2/simulation/pairs/:id", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.DeletePair),
+	))
+	mux.Options("/api/v2/simulation/pairs/:id", negroni.New(
+		negroni.HandlerFunc(this.OptionsPair),
+	))
+}
+
+func (this *SimulationPairsHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := util.JSONMarshal(this.Hoverfly.GetSimulationPairs())
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationPairsHandler) Post(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	var pairView RequestMatcherResponsePairViewV5
+	if err := handlers.ReadFromRequest(req, &pairView); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	added, err := this.Hoverfly.AddSimulationPair(pairView)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(added)
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationPairsHandler) GetPair(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	pairView, err := this.Hoverfly.GetSimulationPair(bone.GetValue(req, "id"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(pairView)
+	handlers.WriteResponse(w, bytes)
+}
+
+// PutPair replaces a pair, keeping its id and its place in the matching order
+func (this *SimulationPairsHandler) PutPair(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	id := bone.GetValue(req, "id")
+	if _, err := this.Hoverfly.GetSimulationPair(id); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var pairView RequestMatcherResponsePairViewV5
+	if err := handlers.ReadFromRequest(req, &pairView); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, err := this.Hoverfly.UpdateSimulationPair(id, pairView)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(updated)
+	handlers.WriteResponse(w, bytes)
+}
+
+// PatchPair disables, enables or moves a pair
+func (this *SimulationPairsHandler) PatchPair(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	id := bone.GetValue(req, "id")
+	if _, err := this.Hoverfly.GetSimulationPair(id); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	var patchView SimulationPairPatchView
+	if err := handlers.ReadFromRequest(req, &patchView); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	patched, err := this.Hoverfly.PatchSimulationPair(id, patchView)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(patched)
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationPairsHandler) DeletePair(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if err := this.Hoverfly.DeleteSimulationPair(bone.GetValue(req, "id")); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationPairsHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SimulationPairsHandler) OptionsPair(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, PUT, PATCH, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -0,0 +1,223 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/go-zoo/bone"
+	. "github.com/onsi/gomega"
+)
+
+type HoverflySimulationPairsStub struct {
+	pairs []SimulationPairView
+	patch SimulationPairPatchView
+}
+
+func (this *HoverflySimulationPairsStub) GetSimulationPairs() SimulationPairsView {
+	return SimulationPairsView{Pairs: this.pairs}
+}
+
+func (this *HoverflySimulationPairsStub) GetSimulationPair(id string) (SimulationPairView, error) {
+	for _, pair := range this.pairs {
+		if pair.Id == id {
+			return pair, nil
+		}
+	}
+	return SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+}
+
+func (this *HoverflySimulationPairsStub) AddSimulationPair(pairView RequestMatcherResponsePairViewV5) (SimulationPairView, error) {
+	if pairView.ResponsesMode != "" {
+		return SimulationPairView{}, fmt.Errorf("invalid responsesMode %s", pairView.ResponsesMode)
+	}
+	pairView.Id = "added"
+	this.pairs = append(this.pairs, SimulationPairView{RequestMatcherResponsePairViewV5: pairView})
+	return this.pairs[len(this.pairs)-1], nil
+}
+
+func (this *HoverflySimulationPairsStub) UpdateSimulationPair(id string, pairView RequestMatcherResponsePairViewV5) (SimulationPairView, error) {
+	for i, pair := range this.pairs {
+		if pair.Id == id {
+			pairView.Id = id
+			this.pairs[i].RequestMatcherResponsePairViewV5 = pairView
+			return this.pairs[i], nil
+		}
+	}
+	return SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+}
+
+func (this *HoverflySimulationPairsStub) PatchSimulationPair(id string, patchView SimulationPairPatchView) (SimulationPairView, error) {
+	this.patch = patchView
+	if patchView.Position != nil && *patchView.Position >= len(this.pairs) {
+		return SimulationPairView{}, fmt.Errorf("Position %d is out of range, there are %d pairs", *patchView.Position, len(this.pairs))
+	}
+	return this.GetSimulationPair(id)
+}
+
+func (this *HoverflySimulationPairsStub) DeleteSimulationPair(id string) error {
+	for i, pair := range this.pairs {
+		if pair.Id == id {
+			this.pairs = append(this.pairs[:i], this.pairs[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("Pair %s not found", id)
+}
+
+func newSimulationPairsStub() *HoverflySimulationPairsStub {
+	return &HoverflySimulationPairsStub{pairs: []SimulationPairView{
+		{RequestMatcherResponsePairViewV5: RequestMatcherResponsePairViewV5{Id: "first", Response: ResponseDetailsViewV5{Body: "first"}}},
+		{Source: "payments", RequestMatcherResponsePairViewV5: RequestMatcherResponsePairViewV5{Id: "second", Response: ResponseDetailsViewV5{Body: "second"}}},
+	}}
+}
+
+func serveSimulationPairsRequest(stub *HoverflySimulationPairsStub, method, path, body string) *httptest.ResponseRecorder {
+	unit := SimulationPairsHandler{Hoverfly: stub}
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+
+	request, err := http.NewRequest(method, path, bytes.NewBufferString(body))
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	mux.ServeHTTP(response, request)
+	return response
+}
+
+func Test_SimulationPairsHandler_Get_ListsPairs(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "GET", "/api/v2/simulation/pairs", "")
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var pairsView SimulationPairsView
+	Expect(json.Unmarshal(response.Body.Bytes(), &pairsView)).To(Succeed())
+	Expect(pairsView.Pairs).To(HaveLen(2))
+	Expect(pairsView.Pairs[1].Id).To(Equal("second"))
+	Expect(pairsView.Pairs[1].Source).To(Equal("payments"))
+}
+
+func Test_SimulationPairsHandler_Post_AddsPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := newSimulationPairsStub()
+
+	response := serveSimulationPairsRequest(stub, "POST", "/api/v2/simulation/pairs", `{"request": {}, "response": {"status": 200, "body": "added"}}`)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stub.pairs).To(HaveLen(3))
+
+	var pairView SimulationPairView
+	Expect(json.Unmarshal(response.Body.Bytes(), &pairView)).To(Succeed())
+	Expect(pairView.Id).To(Equal("added"))
+	Expect(pairView.Response.Body).To(Equal("added"))
+}
+
+func Test_SimulationPairsHandler_Post_ReturnsBadRequestWhenPairIsRejected(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "POST", "/api/v2/simulation/pairs", `{"request": {}, "response": {}, "responsesMode": "shuffle"}`)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("invalid responsesMode shuffle"))
+}
+
+func Test_SimulationPairsHandler_GetPair_ReturnsNotFoundForUnknownPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "GET", "/api/v2/simulation/pairs/unknown", "")
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Pair unknown not found"))
+}
+
+func Test_SimulationPairsHandler_PutPair_UpdatesPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := newSimulationPairsStub()
+
+	response := serveSimulationPairsRequest(stub, "PUT", "/api/v2/simulation/pairs/first", `{"request": {}, "response": {"status": 200, "body": "updated"}}`)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stub.pairs[0].Id).To(Equal("first"))
+	Expect(stub.pairs[0].Response.Body).To(Equal("updated"))
+}
+
+func Test_SimulationPairsHandler_PutPair_ReturnsBadRequestForMalformedJSON(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "PUT", "/api/v2/simulation/pairs/first", `{"request": `)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Malformed JSON"))
+}
+
+func Test_SimulationPairsHandler_PutPair_ReturnsNotFoundForUnknownPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "PUT", "/api/v2/simulation/pairs/unknown", `{"request": {}, "response": {}}`)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
+
+func Test_SimulationPairsHandler_PatchPair_PassesPatchToHoverfly(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := newSimulationPairsStub()
+
+	response := serveSimulationPairsRequest(stub, "PATCH", "/api/v2/simulation/pairs/second", `{"disabled": true, "position": 0}`)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(*stub.patch.Disabled).To(BeTrue())
+	Expect(*stub.patch.Position).To(Equal(0))
+}
+
+func Test_SimulationPairsHandler_PatchPair_ReturnsBadRequestForPositionOutOfRange(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "PATCH", "/api/v2/simulation/pairs/second", `{"position": 5}`)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Position 5 is out of range, there are 2 pairs"))
+}
+
+func Test_SimulationPairsHandler_DeletePair_DeletesPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := newSimulationPairsStub()
+
+	response := serveSimulationPairsRequest(stub, "DELETE", "/api/v2/simulation/pairs/first", "")
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var pairsView SimulationPairsView
+	Expect(json.Unmarshal(response.Body.Bytes(), &pairsView)).To(Succeed())
+	Expect(pairsView.Pairs).To(HaveLen(1))
+	Expect(pairsView.Pairs[0].Id).To(Equal("second"))
+}
+
+func Test_SimulationPairsHandler_DeletePair_ReturnsNotFoundForUnknownPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	response := serveSimulationPairsRequest(newSimulationPairsStub(), "DELETE", "/api/v2/simulation/pairs/unknown", "")
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
@@ -0,0 +1,13 @@
+package v2
+
+// SimulationSourceView summarises a named simulation source. Sources are listed in the order they are matched,
+// after the pairs added to the simulation itself.
+type SimulationSourceView struct {
+	Name           string `json:"name"`
+	Pairs          int    `json:"pairs"`
+	WebSocketPairs int    `json:"webSocketPairs"`
+}
+
+type SimulationSourcesView struct {
+	Sources []SimulationSourceView `json:"sources"`
+}
@@ -0,0 +1,109 @@
+package v2
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflySimulationSources interface {
+	GetSimulationSources() SimulationSourcesView
+	GetSimulationSource(name string) (SimulationViewV5, error)
+	PutSimulationSource(name string, simulationView SimulationViewV5) SimulationImportResult
+	DeleteSimulationSource(name string) error
+}
+
+type SimulationSourcesHandler struct {
+	Hoverfly HoverflySimulationSources
+}
+
+func (this *SimulationSourcesHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Get("/api/v2/simulation/sources", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Get),
+	))
+	mux.Options("/api/v2/simulation/sources", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+
+	mux.Get("/api/v2/simulation/sources/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.GetSource),
+	))
+	mux.Put("/api/v2/simulation/sources/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.PutSource),
+	))
+	mux.Delete("/api/v2/simulation/sources/:name", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.DeleteSource),
+	))
+	mux.Options("/api/v2/simulation/sources/:name", negroni.New(
+		negroni.HandlerFunc(this.OptionsSource),
+	))
+}
+
+func (this *SimulationSourcesHandler) Get(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	bytes, _ := json.Marshal(this.Hoverfly.GetSimulationSources())
+	handlers.WriteResponse(w, bytes)
+}
+
+func (this *SimulationSourcesHandler) GetSource(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	simulationView, err := this.Hoverfly.GetSimulationSource(bone.GetValue(req, "name"))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	bytes, _ := util.JSONMarshal(simulationView)
+	handlers.WriteResponse(w, bytes)
+}
+
+// PutSource adds or replaces a source with a simulation in any of the schema versions accepted by
+// PUT /api/v2/simulation
+func (this *SimulationSourcesHandler) PutSource(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	body, _ := io.ReadAll(req.Body)
+
+	simulationView, err := NewSimulationViewFromRequestBody(body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result := this.Hoverfly.PutSimulationSource(bone.GetValue(req, "name"), simulationView)
+	if result.GetError() != nil {
+		handlers.WriteErrorResponse(w, "An error occurred: "+result.GetError().Error(), http.StatusBadRequest)
+		return
+	}
+	if len(result.WarningMessages) > 0 {
+		bytes, _ := util.JSONMarshal(result)
+		handlers.WriteResponse(w, bytes)
+		return
+	}
+
+	this.GetSource(w, req, next)
+}
+
+func (this *SimulationSourcesHandler) DeleteSource(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	if err := this.Hoverfly.DeleteSimulationSource(bone.GetValue(req, "name")); err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	this.Get(w, req, next)
+}
+
+func (this *SimulationSourcesHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET")
+	handlers.WriteResponse(w, []byte(""))
+}
+
+func (this *SimulationSourcesHandler) OptionsSource(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, GET, PUT, DELETE")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -0,0 +1,210 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/go-zoo/bone"
+	. "github.com/onsi/gomega"
+)
+
+type HoverflySimulationSourcesStub struct {
+	names       []string
+	simulations map[string]SimulationViewV5
+}
+
+func (this *HoverflySimulationSourcesStub) GetSimulationSources() SimulationSourcesView {
+	view := SimulationSourcesView{Sources: []SimulationSourceView{}}
+	for _, name := range this.names {
+		view.Sources = append(view.Sources, SimulationSourceView{
+			Name:  name,
+			Pairs: len(this.simulations[name].RequestResponsePairs),
+		})
+	}
+	return view
+}
+
+func (this *HoverflySimulationSourcesStub) GetSimulationSource(name string) (SimulationViewV5, error) {
+	simulation, ok := this.simulations[name]
+	if !ok {
+		return SimulationViewV5{}, fmt.Errorf("Simulation source %s not found", name)
+	}
+	return simulation, nil
+}
+
+func (this *HoverflySimulationSourcesStub) PutSimulationSource(name string, simulationView SimulationViewV5) SimulationImportResult {
+	result := SimulationImportResult{}
+	if len(simulationView.GlobalLiterals) > 0 {
+		result.SetError(fmt.Errorf("A simulation source can only have pairs and webSocketPairs"))
+		return result
+	}
+	if _, ok := this.simulations[name]; !ok {
+		this.names = append(this.names, name)
+	}
+	this.simulations[name] = simulationView
+	return result
+}
+
+func (this *HoverflySimulationSourcesStub) DeleteSimulationSource(name string) error {
+	for i, sourceName := range this.names {
+		if sourceName == name {
+			this.names = append(this.names[:i], this.names[i+1:]...)
+			delete(this.simulations, name)
+			return nil
+		}
+	}
+	return fmt.Errorf("Simulation source %s not found", name)
+}
+
+func newSimulationSourcesMux(stub *HoverflySimulationSourcesStub) *bone.Mux {
+	unit := SimulationSourcesHandler{Hoverfly: stub}
+	mux := bone.New()
+	unit.RegisterRoutes(mux, &handlers.AuthHandler{})
+	return mux
+}
+
+const simulationSourceBody = `{
+	"data": {
+		"pairs": [{
+			"request": {"destination": [{"matcher": "exact", "value": "payments.com"}]},
+			"response": {"status": 200, "body": "paid"}
+		}]
+	},
+	"meta": {"schemaVersion": "v5.2"}
+}`
+
+func Test_SimulationSourcesHandler_Get_ListsSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{names: []string{"payments"}, simulations: map[string]SimulationViewV5{
+		"payments": {DataViewV5: DataViewV5{RequestResponsePairs: []RequestMatcherResponsePairViewV5{{}}}},
+	}}
+
+	request, err := http.NewRequest("GET", "/api/v2/simulation/sources", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var sourcesView SimulationSourcesView
+	Expect(json.Unmarshal(response.Body.Bytes(), &sourcesView)).To(Succeed())
+	Expect(sourcesView.Sources).To(Equal([]SimulationSourceView{{Name: "payments", Pairs: 1}}))
+}
+
+func Test_SimulationSourcesHandler_PutSource_SetsSourceFromPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{simulations: map[string]SimulationViewV5{}}
+
+	request, err := http.NewRequest("PUT", "/api/v2/simulation/sources/payments", bytes.NewBufferString(simulationSourceBody))
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stub.names).To(Equal([]string{"payments"}))
+
+	var simulationView SimulationViewV5
+	Expect(json.Unmarshal(response.Body.Bytes(), &simulationView)).To(Succeed())
+	Expect(simulationView.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulationView.RequestResponsePairs[0].Response.Body).To(Equal("paid"))
+}
+
+func Test_SimulationSourcesHandler_PutSource_ReturnsBadRequestForInvalidSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{simulations: map[string]SimulationViewV5{}}
+
+	request, err := http.NewRequest("PUT", "/api/v2/simulation/sources/payments", bytes.NewBufferString(`{"data": `))
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+	Expect(stub.names).To(BeEmpty())
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Invalid JSON"))
+}
+
+func Test_SimulationSourcesHandler_PutSource_ReturnsBadRequestWhenSourceIsRejected(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{simulations: map[string]SimulationViewV5{}}
+
+	body := `{"data": {"pairs": [], "literals": [{"name": "x", "value": "y"}]}, "meta": {"schemaVersion": "v5.2"}}`
+	request, err := http.NewRequest("PUT", "/api/v2/simulation/sources/payments", bytes.NewBufferString(body))
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("An error occurred: A simulation source can only have pairs and webSocketPairs"))
+}
+
+func Test_SimulationSourcesHandler_GetSource_ReturnsNotFoundForUnknownSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{simulations: map[string]SimulationViewV5{}}
+
+	request, err := http.NewRequest("GET", "/api/v2/simulation/sources/payments", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+
+	errorView, err := unmarshalErrorView(response.Body)
+	Expect(err).To(BeNil())
+	Expect(errorView.Error).To(Equal("Simulation source payments not found"))
+}
+
+func Test_SimulationSourcesHandler_DeleteSource_DeletesSourceFromPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{names: []string{"payments", "users"}, simulations: map[string]SimulationViewV5{
+		"payments": {},
+		"users":    {},
+	}}
+
+	request, err := http.NewRequest("DELETE", "/api/v2/simulation/sources/payments", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var sourcesView SimulationSourcesView
+	Expect(json.Unmarshal(response.Body.Bytes(), &sourcesView)).To(Succeed())
+	Expect(sourcesView.Sources).To(Equal([]SimulationSourceView{{Name: "users"}}))
+}
+
+func Test_SimulationSourcesHandler_DeleteSource_ReturnsNotFoundForUnknownSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	stub := &HoverflySimulationSourcesStub{simulations: map[string]SimulationViewV5{}}
+
+	request, err := http.NewRequest("DELETE", "/api/v2/simulation/sources/payments", nil)
+	Expect(err).To(BeNil())
+
+	response := httptest.NewRecorder()
+	newSimulationSourcesMux(stub).ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusNotFound))
+}
@@ -287,6 +287,29 @@ func Test_NewSimulationViewFromRequestBody_ReturnErrorMessagesOnInvalidSimulatio
 	Expect(simulation.GlobalActions.Delays).To(HaveLen(0))
 }
 
+func Test_NewSimulationViewFromRequestBody_WontCreateSimulationWithResponseWeightOfZero(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := v2.NewSimulationViewFromRequestBody([]byte(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {},
+				"response": {"status": 200},
+				"responses": [{"status": 200, "weight": 0}, {"status": 503, "weight": 1}],
+				"responsesMode": "random"
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v5.3"
+	}
+}`))
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("weight"))
+}
+
 func Test_NewSimulationViewFromRequestBody_WontCreateSimulationFromUnknownSchemaVersion(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -14,12 +14,39 @@ type DataViewV5 struct {
 	GlobalActions        GlobalActionsView                  `json:"globalActions"`
 	GlobalLiterals       []GlobalLiteralViewV5              `json:"literals,omitempty"`
 	GlobalVariables      []GlobalVariableViewV5             `json:"variables,omitempty"`
+	WebSocketPairs       []WebSocketPairViewV5              `json:"webSocketPairs,omitempty"`
+	CustomMatchers       []CustomMatcherView                `json:"customMatchers,omitempty"`
+	Resources            []ResourceViewV5                   `json:"resources,omitempty"`
 }
 
 type RequestMatcherResponsePairViewV5 struct {
-	Labels         []string              `json:"labels,omitempty"`
-	RequestMatcher RequestMatcherViewV5  `json:"request"`
-	Response       ResponseDetailsViewV5 `json:"response"`
+	Id             string                  `json:"id,omitempty"`
+	Disabled       bool                    `json:"disabled,omitempty"`
+	Labels         []string                `json:"labels,omitempty"`
+	RequestMatcher RequestMatcherViewV5    `json:"request"`
+	Response       ResponseDetailsViewV5   `json:"response"`
+	Responses      []ResponseDetailsViewV5 `json:"responses,omitempty"`
+	ResponsesMode  string                  `json:"responsesMode,omitempty"`
+}
+
+// WebSocketPairViewV5 is used when marshalling and unmarshalling a simulated WebSocket conversation
+type WebSocketPairViewV5 struct {
+	Labels         []string               `json:"labels,omitempty"`
+	RequestMatcher RequestMatcherViewV5   `json:"request"`
+	Frames         []WebSocketFrameViewV5 `json:"frames,omitempty"`
+	Replies        []WebSocketReplyViewV5 `json:"replies,omitempty"`
+}
+
+type WebSocketFrameViewV5 struct {
+	Type        string `json:"type,omitempty"`
+	Data        string `json:"data"`
+	EncodedData bool   `json:"encodedData,omitempty"`
+	Delay       int    `json:"delay,omitempty"`
+}
+
+type WebSocketReplyViewV5 struct {
+	Matcher []MatcherViewV5        `json:"matcher"`
+	Frames  []WebSocketFrameViewV5 `json:"frames,omitempty"`
 }
 
 // RequestDetailsView is used when marshalling and unmarshalling RequestDetails
@@ -43,6 +70,14 @@ type MatcherViewV5 struct {
 	DoMatch *MatcherViewV5         `json:"doMatch,omitempty"`
 }
 
+// MultipartPartMatcherViewV5 is the value of a multipart matcher for one part, keyed by the part name
+type MultipartPartMatcherViewV5 struct {
+	Value       []MatcherViewV5            `json:"value,omitempty"`
+	Filename    []MatcherViewV5            `json:"filename,omitempty"`
+	ContentType []MatcherViewV5            `json:"contentType,omitempty"`
+	Headers     map[string][]MatcherViewV5 `json:"headers,omitempty"`
+}
+
 type GlobalVariableViewV5 struct {
 	Name      string        `json:"name"`
 	Function  string        `json:"function"`
@@ -76,6 +111,15 @@ type ResponseDetailsViewV5 struct {
 	FixedDelay       int                    `json:"fixedDelay,omitempty"`
 	LogNormalDelay   *LogNormalDelayOptions `json:"logNormalDelay,omitempty"`
 	PostServeAction  string                 `json:"postServeAction,omitempty"`
+	Weight           int                    `json:"weight,omitempty"`
+	Fault            *FaultViewV5           `json:"fault,omitempty"`
+}
+
+// FaultViewV5 describes a network fault to inject while writing a response
+type FaultViewV5 struct {
+	Type           string `json:"type"`
+	Bytes          int    `json:"bytes,omitempty"`
+	BytesPerSecond int    `json:"bytesPerSecond,omitempty"`
 }
 
 // Gets Status - required for interfaces.Response
@@ -1,10 +1,17 @@
 package v2
 
 type TemplateDataSourceView struct {
-	DataSources []CSVDataSourceView `json:"csvDataSources,omitempty"`
+	DataSources     []CSVDataSourceView  `json:"csvDataSources,omitempty"`
+	JsonDataSources []JSONDataSourceView `json:"jsonDataSources,omitempty"`
 }
 
 type CSVDataSourceView struct {
 	Name string `json:"name"`
 	Data string `json:"data"`
 }
+
+type JSONDataSourceView struct {
+	Name   string `json:"name"`
+	Format string `json:"format,omitempty"`
+	Data   string `json:"data"`
+}
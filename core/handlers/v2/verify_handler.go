@@ -0,0 +1,59 @@
+package v2
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/codegangsta/negroni"
+	"github.com/go-zoo/bone"
+)
+
+type HoverflyVerify interface {
+	VerifySimulation(diffFilterView DiffFilterView) VerificationView
+}
+
+type VerifyHandler struct {
+	Hoverfly HoverflyVerify
+}
+
+func (this *VerifyHandler) RegisterRoutes(mux *bone.Mux, am *handlers.AuthHandler) {
+	mux.Post("/api/v2/verify", negroni.New(
+		negroni.HandlerFunc(am.RequireTokenAuthentication),
+		negroni.HandlerFunc(this.Post),
+	))
+	mux.Options("/api/v2/verify", negroni.New(
+		negroni.HandlerFunc(this.Options),
+	))
+}
+
+func (this *VerifyHandler) Post(w http.ResponseWriter, req *http.Request, next http.HandlerFunc) {
+	requestBody, err := io.ReadAll(req.Body)
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var diffFilterView DiffFilterView
+	if len(requestBody) > 0 {
+		err = json.Unmarshal(requestBody, &diffFilterView)
+		if err != nil {
+			handlers.WriteErrorResponse(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	marshal, err := json.Marshal(this.Hoverfly.VerifySimulation(diffFilterView))
+	if err != nil {
+		handlers.WriteErrorResponse(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	handlers.WriteResponse(w, marshal)
+}
+
+func (this *VerifyHandler) Options(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	w.Header().Add("Allow", "OPTIONS, POST")
+	handlers.WriteResponse(w, []byte(""))
+}
@@ -0,0 +1,74 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+type HoverflyVerifyStub struct {
+	diffFilterView DiffFilterView
+}
+
+func (this *HoverflyVerifyStub) VerifySimulation(diffFilterView DiffFilterView) VerificationView {
+	this.diffFilterView = diffFilterView
+	return VerificationView{
+		Passed: false,
+		Results: []PairVerificationView{
+			{
+				Request: SimpleRequestDefinitionView{Method: "GET", Host: "test.com", Path: "/"},
+				DiffReport: &DiffReport{
+					DiffEntries: []DiffReportEntry{{Field: "status", Expected: "200", Actual: "500"}},
+				},
+			},
+		},
+	}
+}
+
+func Test_VerifyHandler_Post_ReturnsVerification(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyVerifyStub{}
+	unit := VerifyHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/verify", bytes.NewBufferString(""))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var verificationView VerificationView
+	Expect(json.Unmarshal(response.Body.Bytes(), &verificationView)).To(Succeed())
+	Expect(verificationView.Passed).To(BeFalse())
+	Expect(verificationView.Results).To(HaveLen(1))
+	Expect(verificationView.Results[0].DiffReport.DiffEntries[0].Field).To(Equal("status"))
+}
+
+func Test_VerifyHandler_Post_PassesFilterToHoverfly(t *testing.T) {
+	RegisterTestingT(t)
+
+	stubHoverfly := &HoverflyVerifyStub{}
+	unit := VerifyHandler{Hoverfly: stubHoverfly}
+
+	request, err := http.NewRequest("POST", "/api/v2/verify", bytes.NewBufferString(`{"excludedHeaders": ["Date"]}`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+	Expect(stubHoverfly.diffFilterView.ExcludedHeaders).To(Equal([]string{"Date"}))
+}
+
+func Test_VerifyHandler_Post_ReturnsBadRequestForInvalidFilter(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := VerifyHandler{Hoverfly: &HoverflyVerifyStub{}}
+
+	request, err := http.NewRequest("POST", "/api/v2/verify", bytes.NewBufferString(`not json`))
+	Expect(err).To(BeNil())
+
+	response := makeRequestOnHandler(unit.Post, request)
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
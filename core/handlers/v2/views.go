@@ -1,6 +1,9 @@
 package v2
 
 import (
+	"errors"
+	"time"
+
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 )
 
@@ -34,12 +37,37 @@ type ModeView struct {
 }
 
 type ModeArgumentsView struct {
-	Headers            []string `json:"headersWhitelist,omitempty"`
-	MatchingStrategy   *string  `json:"matchingStrategy,omitempty"`
-	Stateful           bool     `json:"stateful,omitempty"`
-	OverwriteDuplicate bool     `json:"overwriteDuplicate,omitempty"`
-	CaptureOnMiss      bool     `json:"captureOnMiss,omitempty"`
-	CaptureDelay       bool     `json:"captureDelay,omitempty"`
+	Headers            []string            `json:"headersWhitelist,omitempty"`
+	MatchingStrategy   *string             `json:"matchingStrategy,omitempty"`
+	Stateful           bool                `json:"stateful,omitempty"`
+	OverwriteDuplicate bool                `json:"overwriteDuplicate,omitempty"`
+	CaptureOnMiss      bool                `json:"captureOnMiss,omitempty"`
+	CaptureDelay       bool                `json:"captureDelay,omitempty"`
+	CaptureRules       []CaptureRuleView   `json:"captureRules,omitempty"`
+	RedactionRules     []RedactionRuleView `json:"redactionRules,omitempty"`
+}
+
+// CaptureRuleView generalises a field of the requests captured in capture or spy mode, so the captured pair
+// matches the requests that differ only in that field. The field is path, query, header or body. The key is the
+// query or header name, or a JSONPath into the body.
+type CaptureRuleView struct {
+	Field   string `json:"field"`
+	Key     string `json:"key,omitempty"`
+	Matcher string `json:"matcher,omitempty"`
+	Value   string `json:"value,omitempty"`
+}
+
+// RedactionRuleView hides secrets and personal data in captured or exported requests and responses. Exactly one
+// of header, query, jsonPath, xpath and regex selects the values, which are replaced with the placeholder, or with
+// a hash of the value so that different values stay different.
+type RedactionRuleView struct {
+	Header      string `json:"header,omitempty"`
+	Query       string `json:"query,omitempty"`
+	JsonPath    string `json:"jsonPath,omitempty"`
+	XPath       string `json:"xpath,omitempty"`
+	Regex       string `json:"regex,omitempty"`
+	Placeholder string `json:"placeholder,omitempty"`
+	Hash        bool   `json:"hash,omitempty"`
 }
 
 type IsWebServerView struct {
@@ -63,6 +91,16 @@ type HoverflyView struct {
 	UsageView
 	VersionView
 	UpstreamProxyView
+	Watch *WatchView `json:"watch,omitempty"`
+}
+
+// WatchView is the status of the simulation files watched for changes. Files are those found in the watched paths
+// at the last reload, and error is why that reload failed, in which case the simulation last loaded is kept.
+type WatchView struct {
+	Paths      []string `json:"paths"`
+	Files      []string `json:"files"`
+	LastLoaded string   `json:"lastLoaded,omitempty"`
+	Error      string   `json:"error,omitempty"`
 }
 
 type LogsView struct {
@@ -102,6 +140,15 @@ type JournalEntryView struct {
 	Latency              float64                   `json:"latency"`
 	Id                   string                    `json:"id"`
 	PostServeActionEntry *PostServeActionEntryView `json:"postServeAction,omitEmpty"`
+	WebSocketMessages    []WebSocketMessageView    `json:"webSocketMessages,omitempty"`
+}
+
+type WebSocketMessageView struct {
+	Direction   string    `json:"direction"`
+	Type        string    `json:"type"`
+	Data        string    `json:"data"`
+	EncodedData bool      `json:"encodedData,omitempty"`
+	Time        time.Time `json:"time"`
 }
 
 type PostServeActionEntryView struct {
@@ -116,6 +163,62 @@ type JournalEntryFilterView struct {
 	Request *RequestMatcherViewV5 `json:"request"`
 }
 
+// RequestVerificationView describes requests expected in the journal, either a request received a number of
+// times, at least once by default, or a list of requests received in order
+type RequestVerificationView struct {
+	Request *RequestMatcherViewV5  `json:"request,omitempty"`
+	Exactly *int                   `json:"exactly,omitempty"`
+	AtLeast *int                   `json:"atLeast,omitempty"`
+	AtMost  *int                   `json:"atMost,omitempty"`
+	Never   bool                   `json:"never,omitempty"`
+	InOrder []RequestMatcherViewV5 `json:"inOrder,omitempty"`
+}
+
+func (this RequestVerificationView) Validate() error {
+	if this.Request == nil && len(this.InOrder) == 0 {
+		return errors.New("No \"request\" or \"inOrder\" object in verification")
+	}
+	if this.Request != nil && len(this.InOrder) > 0 {
+		return errors.New("Only one of \"request\" or \"inOrder\" can be verified at a time")
+	}
+
+	hasCount := this.Exactly != nil || this.AtLeast != nil || this.AtMost != nil
+	if len(this.InOrder) > 0 && (hasCount || this.Never) {
+		return errors.New("\"inOrder\" cannot be combined with \"exactly\", \"atLeast\", \"atMost\" or \"never\"")
+	}
+	if this.Never && hasCount {
+		return errors.New("\"never\" cannot be combined with \"exactly\", \"atLeast\" or \"atMost\"")
+	}
+	if this.Exactly != nil && (this.AtLeast != nil || this.AtMost != nil) {
+		return errors.New("\"exactly\" cannot be combined with \"atLeast\" or \"atMost\"")
+	}
+	for _, count := range []*int{this.Exactly, this.AtLeast, this.AtMost} {
+		if count != nil && *count < 0 {
+			return errors.New("Expected number of requests cannot be negative")
+		}
+	}
+	if this.AtLeast != nil && this.AtMost != nil && *this.AtLeast > *this.AtMost {
+		return errors.New("\"atLeast\" cannot be greater than \"atMost\"")
+	}
+
+	return nil
+}
+
+type RequestVerificationResultView struct {
+	Passed        bool                     `json:"passed"`
+	Message       string                   `json:"message"`
+	Count         int                      `json:"count"`
+	ClosestMisses []JournalClosestMissView `json:"closestMisses,omitempty"`
+}
+
+// JournalClosestMissView is a journal entry which came closest to matching an expected request
+type JournalClosestMissView struct {
+	Id           string             `json:"id"`
+	TimeStarted  string             `json:"timeStarted"`
+	Request      RequestDetailsView `json:"request"`
+	MissedFields []string           `json:"missedFields"`
+}
+
 type StateView struct {
 	State map[string]string `json:"state" validate:"required"`
 }
@@ -152,6 +255,20 @@ type DiffFilterView struct {
 	ExcludedResponseFields []string `json:"excludedResponseFields"`
 }
 
+type VerificationView struct {
+	Passed  bool                   `json:"passed"`
+	Results []PairVerificationView `json:"results"`
+}
+
+type PairVerificationView struct {
+	Request    SimpleRequestDefinitionView `json:"request"`
+	Labels     []string                    `json:"labels,omitempty"`
+	Passed     bool                        `json:"passed"`
+	DiffReport *DiffReport                 `json:"diffReport,omitempty"`
+	Skipped    string                      `json:"skipped,omitempty"`
+	Error      string                      `json:"error,omitempty"`
+}
+
 type JournalIndexView struct {
 	Name    string                  `json:"name"`
 	Entries []JournalIndexEntryView `json:"entries,omitempty"`
@@ -165,3 +282,7 @@ type JournalIndexEntryView struct {
 type JournalIndexRequestView struct {
 	Name string `json:"name"`
 }
+
+type NamespacesView struct {
+	Namespaces []string `json:"namespaces"`
+}
@@ -12,14 +12,19 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/authentication/backends"
 	"github.com/SpectoLabs/hoverfly/core/cache"
 	"github.com/SpectoLabs/hoverfly/core/delay"
+	"github.com/SpectoLabs/hoverfly/core/fault"
+	"github.com/SpectoLabs/hoverfly/core/grpc"
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/journal"
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/persistence"
+	"github.com/SpectoLabs/hoverfly/core/resources"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/templating"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -31,6 +36,8 @@ type Hoverfly struct {
 	HTTP    *http.Client
 	Cfg     *Configuration
 	Counter *metrics.CounterByMode
+	Metrics *metrics.Collector
+	Tracer  *tracing.Tracer
 
 	Proxy   *goproxy.ProxyHttpServer
 	SL      *StoppableListener
@@ -42,12 +49,25 @@ type Hoverfly struct {
 	state *state.State
 
 	Simulation             *models.Simulation
+	Resources              *resources.Resources
 	StoreLogsHook          *StoreLogsHook
 	Journal                *journal.Journal
 	templator              *templating.Templator
 	PostServeActionDetails *action.PostServeActionDetails
 	responsesDiff          map[v2.SimpleRequestDefinitionView][]v2.DiffReport
 	responsesDiffMu        sync.RWMutex
+
+	store persistence.Store
+
+	grpcDescriptors   *grpc.Descriptors
+	grpcHTTP          *http.Client
+	grpcHTTPTransport *http.Transport
+	grpcHTTPMu        sync.Mutex
+
+	namespaces   map[string]*Hoverfly
+	namespacesMu sync.Mutex
+
+	watch *simulationWatch
 }
 
 func NewHoverfly() *Hoverfly {
@@ -57,21 +77,34 @@ func NewHoverfly() *Hoverfly {
 	newJournal := journal.NewJournal()
 	hoverfly := &Hoverfly{
 		Simulation:             models.NewSimulation(),
+		Resources:              resources.NewResources(),
 		Authentication:         authBackend,
 		Counter:                metrics.NewModeCounter([]string{modes.Simulate, modes.Synthesize, modes.Modify, modes.Capture, modes.Spy, modes.Diff}),
+		Metrics:                metrics.NewCollector(),
 		StoreLogsHook:          NewStoreLogsHook(),
 		Journal:                newJournal,
 		Cfg:                    InitSettings(),
 		state:                  state.NewState(),
 		templator:              templating.NewEnrichedTemplator(newJournal),
 		responsesDiff:          make(map[v2.SimpleRequestDefinitionView][]v2.DiffReport),
+		grpcDescriptors:        grpc.NewDescriptors(),
 		PostServeActionDetails: action.NewPostServeActionDetails(),
 	}
 
 	hoverfly.version = "v1.12.10"
+	newJournal.CustomMatchers = hoverfly.Simulation.CustomMatchers
 
 	log.AddHook(hoverfly.StoreLogsHook)
 
+	hoverfly.modeMap = newModeMap(hoverfly)
+	hoverfly.Metrics.SetJournalSize(hoverfly.journalSize)
+
+	hoverfly.HTTP = GetDefaultHoverflyHTTPClient(hoverfly.Cfg.TLSVerification, hoverfly.Cfg.UpstreamProxy)
+
+	return hoverfly
+}
+
+func newModeMap(hoverfly *Hoverfly) map[string]modes.Mode {
 	modeMap := make(map[string]modes.Mode)
 
 	modeMap[modes.Capture] = &modes.CaptureMode{Hoverfly: hoverfly}
@@ -81,11 +114,7 @@ func NewHoverfly() *Hoverfly {
 	modeMap[modes.Spy] = &modes.SpyMode{Hoverfly: hoverfly}
 	modeMap[modes.Diff] = &modes.DiffMode{Hoverfly: hoverfly}
 
-	hoverfly.modeMap = modeMap
-
-	hoverfly.HTTP = GetDefaultHoverflyHTTPClient(hoverfly.Cfg.TLSVerification, hoverfly.Cfg.UpstreamProxy)
-
-	return hoverfly
+	return modeMap
 }
 
 func NewHoverflyWithConfiguration(cfg *Configuration) *Hoverfly {
@@ -162,7 +191,9 @@ func (hf *Hoverfly) StartProxy() error {
 		return err
 	}
 	hf.SL = sl
-	server := http.Server{}
+	server := http.Server{
+		Protocols: newServerProtocols(),
+	}
 
 	hf.Cfg.ProxyControlWG.Add(1)
 
@@ -172,7 +203,11 @@ func (hf *Hoverfly) StartProxy() error {
 			hf.Cfg.ProxyControlWG.Done()
 		}()
 		log.Info("serving proxy")
-		server.Handler = hf.Proxy
+		if hf.Cfg.Webserver {
+			server.Handler = hf.Proxy
+		} else {
+			server.Handler = proxyHandler(hf, hf.Proxy)
+		}
 		log.Warn(server.Serve(sl))
 	}()
 
@@ -185,19 +220,53 @@ func (hf *Hoverfly) StopProxy() {
 	hf.Cfg.ProxyControlWG.Wait()
 }
 
+// Shutdown releases what Hoverfly holds open, such as the persistent store, before the process exits
+// and sends the spans the tracer has not exported yet
+func (hf *Hoverfly) Shutdown() {
+	hf.Tracer.Shutdown()
+
+	if hf.store != nil {
+		if err := hf.store.Close(); err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Failed to close persistent store")
+		}
+	}
+}
+
 // processRequest - processes incoming requests and based on proxy state (record/playback)
 // returns HTTP response.
 func (hf *Hoverfly) processRequest(req *http.Request) (*http.Response, chan string) {
+	span := hf.Tracer.StartSpan(req.Method, req.Header)
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("server.address", req.Host)
+	span.SetAttribute("url.path", req.URL.Path)
+	span.SetAttribute("hoverfly.mode", hf.Cfg.GetMode())
+
+	response, journalIDChannel := hf.processTracedRequest(req, span)
+	if response != nil {
+		span.SetAttribute("http.response.status_code", response.StatusCode)
+	}
+	span.Finish()
+
+	return response, journalIDChannel
+}
+
+func (hf *Hoverfly) processTracedRequest(req *http.Request, span *tracing.Span) (*http.Response, chan string) {
 	if hf.Cfg.CORS.Enabled {
 		response := hf.Cfg.CORS.InterceptPreflightRequest(req)
 		if response != nil {
 			return response, nil
 		}
 	}
+	hf.decodeGrpcRequest(req)
+
 	requestDetails, err := models.NewRequestDetailsFromHttpRequest(req)
 	if err != nil {
+		span.SetError(err)
 		return modes.ErrorResponse(req, err, "Could not interpret HTTP request").Response, nil
 	}
+	requestDetails.SetSpan(span)
 
 	modeName := hf.Cfg.GetMode()
 	mode := hf.modeMap[modeName]
@@ -210,9 +279,11 @@ func (hf *Hoverfly) processRequest(req *http.Request) (*http.Response, chan stri
 	// and definitely don't delay people in capture mode
 	// Don't delete the error
 	if err != nil || modeName == modes.Capture {
+		span.SetError(err)
 		return result.Response, nil
 	}
 
+	delaySpan := span.StartChild("delay", tracing.KindInternal)
 	if result.IsResponseDelayable() {
 		log.Debug("Applying response delay")
 		hf.applyResponseDelay(result)
@@ -220,15 +291,20 @@ func (hf *Hoverfly) processRequest(req *http.Request) (*http.Response, chan stri
 		log.Debug("Applying global delay")
 		hf.applyGlobalDelay(requestDetails)
 	}
+	delaySpan.Finish()
+
+	if result.Fault != nil {
+		fault.Inject(req, result.Fault)
+	}
 
 	if result.PostServeActionInputDetails != nil && result.PostServeActionInputDetails.PostServeAction != "" {
 		if postServeAction, ok := hf.PostServeActionDetails.Actions[result.PostServeActionInputDetails.PostServeAction]; ok {
 			journalIDChannel := make(chan string, 1)
-			go postServeAction.Execute(result.PostServeActionInputDetails.Pair, journalIDChannel, hf.Journal)
+			go hf.executePostServeAction(result.PostServeActionInputDetails.PostServeAction, &postServeAction, result.PostServeActionInputDetails.Pair, journalIDChannel, span)
 			return result.Response, journalIDChannel
 		} else if hf.PostServeActionDetails.FallbackAction != nil {
 			journalIDChannel := make(chan string, 1)
-			go hf.PostServeActionDetails.FallbackAction.Execute(result.PostServeActionInputDetails.Pair, journalIDChannel, hf.Journal)
+			go hf.executePostServeAction(result.PostServeActionInputDetails.PostServeAction, hf.PostServeActionDetails.FallbackAction, result.PostServeActionInputDetails.Pair, journalIDChannel, span)
 			return result.Response, journalIDChannel
 		}
 	}
@@ -236,6 +312,39 @@ func (hf *Hoverfly) processRequest(req *http.Request) (*http.Response, chan stri
 	return result.Response, nil
 }
 
+// executePostServeAction executes a post serve action, recording how long it took and whether it failed
+func (hf *Hoverfly) executePostServeAction(name string, postServeAction *action.Action, pair *models.RequestResponsePair, journalIDChannel chan string, requestSpan *tracing.Span) {
+	span := requestSpan.StartChild("post serve action", tracing.KindInternal)
+	span.SetAttribute("hoverfly.post_serve_action", name)
+	defer span.Finish()
+
+	start := time.Now()
+	err := postServeAction.Execute(pair, journalIDChannel, hf.Journal)
+	hf.Metrics.PostServeActionDuration.Observe(time.Since(start), name)
+	if err != nil {
+		hf.Metrics.PostServeActionFailures.Inc(name)
+		span.SetError(err)
+	}
+}
+
+// countRequest counts a request served in the given mode
+func (hf *Hoverfly) countRequest(mode string, request *http.Request, status int) {
+	hf.Counter.Count(mode)
+	hf.Metrics.CountRequest(mode, request.Host, request.Method, status)
+}
+
+// journalSize returns the number of entries held in the journals of Hoverfly and its namespaces
+func (hf *Hoverfly) journalSize() int {
+	size := hf.Journal.Count()
+
+	hf.namespacesMu.Lock()
+	defer hf.namespacesMu.Unlock()
+	for _, namespace := range hf.namespaces {
+		size += namespace.Journal.Count()
+	}
+	return size
+}
+
 func (hf *Hoverfly) applyResponseDelay(result modes.ProcessResult) {
 	if result.FixedDelay > 0 {
 		time.Sleep(time.Duration(result.FixedDelay) * time.Millisecond)
@@ -252,12 +361,12 @@ func (hf *Hoverfly) applyResponseDelay(result modes.ProcessResult) {
 }
 
 func (hf *Hoverfly) applyGlobalDelay(requestDetails models.RequestDetails) {
-	respDelay := hf.Simulation.ResponseDelays.GetDelay(requestDetails)
+	respDelay := hf.Simulation.GetResponseDelays().GetDelay(requestDetails)
 	if respDelay != nil {
 		respDelay.Execute()
 	}
 
-	respDelayLogNormal := hf.Simulation.ResponseDelaysLogNormal.GetDelay(requestDetails)
+	respDelayLogNormal := hf.Simulation.GetResponseDelaysLogNormal().GetDelay(requestDetails)
 	if respDelayLogNormal != nil {
 		respDelayLogNormal.Execute()
 	}
@@ -1,6 +1,7 @@
 package hoverfly
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -14,6 +15,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	"github.com/SpectoLabs/raymond"
 	log "github.com/sirupsen/logrus"
@@ -29,13 +31,41 @@ func (hf *Hoverfly) DoRequest(request *http.Request) (*http.Response, *time.Dura
 	if err != nil {
 		return nil, nil, err
 	}
+
+	grpcMethod := hf.findGrpcMethod(request)
+	if grpcMethod != nil {
+		client = hf.newGrpcHttpClient(client)
+		encodeGrpcRequest(request, grpcMethod)
+	}
+
+	span := tracing.SpanFromContext(request.Context()).StartChild("upstream request", tracing.KindClient)
+	defer span.Finish()
+	if span != nil {
+		span.SetAttribute("http.request.method", request.Method)
+		span.SetAttribute("server.address", request.Host)
+		span.SetAttribute("url.full", request.URL.String())
+		// The headers can be shared with the request details, which must keep the traceparent they arrived with
+		request.Header = request.Header.Clone()
+		if request.Header == nil {
+			request.Header = http.Header{}
+		}
+		request.Header.Set(tracing.TraceparentHeader, span.Traceparent())
+	}
+
 	start := time.Now()
 	resp, err := client.Do(request)
 	elapsed := time.Since(start)
+	hf.Metrics.UpstreamDuration.Observe(elapsed, request.Host, request.Method)
 
 	if err != nil {
+		span.SetError(err)
 		return nil, nil, err
 	}
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+
+	if grpcMethod != nil {
+		decodeGrpcResponse(resp, grpcMethod)
+	}
 
 	resp.Header.Set("Hoverfly", "Was-Here")
 
@@ -48,48 +78,84 @@ func (hf *Hoverfly) DoRequest(request *http.Request) (*http.Response, *time.Dura
 
 // GetResponse returns stored response from cache
 func (hf *Hoverfly) GetResponse(requestDetails models.RequestDetails) (*models.ResponseDetails, *errors.HoverflyError) {
-	var response models.ResponseDetails
+	response, err := hf.getResponse(requestDetails)
+	if err != nil {
+		hf.Metrics.SimulationMisses.Inc()
+	} else {
+		hf.Metrics.SimulationHits.Inc()
+	}
+
+	return response, err
+}
+
+func (hf *Hoverfly) getResponse(requestDetails models.RequestDetails) (*models.ResponseDetails, *errors.HoverflyError) {
+	var pair *models.RequestMatcherResponsePair
 	var cachedResponse *models.CachedResponse
 
+	cacheSpan := requestDetails.Span().StartChild("cache lookup", tracing.KindInternal)
 	cachedResponse, cacheErr := hf.CacheMatcher.GetCachedResponse(&requestDetails)
+	cacheSpan.SetAttribute("hoverfly.cache.hit", cacheErr == nil)
+	cacheSpan.Finish()
+	if cacheErr == nil {
+		hf.Metrics.CacheHits.Inc()
+	} else if hf.CacheMatcher.RequestCache != nil {
+		hf.Metrics.CacheMisses.Inc()
+	}
 
 	// Get the cached response and return if there is a miss
 	if cacheErr == nil && cachedResponse.MatchingPair == nil {
-		return nil, errors.MatchingFailedError(cachedResponse.ClosestMiss)
+		return hf.getResourceResponse(requestDetails, errors.MatchingFailedError(cachedResponse.ClosestMiss))
 		// If it's cached, use that response
 	} else if cacheErr == nil {
-		response = cachedResponse.MatchingPair.Response
+		pair = cachedResponse.MatchingPair
 		//If it's not cached, perform matching to find a hit
 	} else {
 		mode := (hf.modeMap[modes.Simulate]).(*modes.SimulateMode)
 
 		// Matching
+		matchingSpan := requestDetails.Span().StartChild("matching", tracing.KindInternal)
 		result := matching.Match(mode.MatchingStrategy, requestDetails, hf.Cfg.Webserver, hf.Simulation, hf.state)
+		matchingSpan.SetAttribute("hoverfly.matching.strategy", mode.MatchingStrategy)
+		matchingSpan.SetAttribute("hoverfly.matching.matched", result.Error == nil)
+		matchingSpan.Finish()
 
 		// Cache result
 		if result.Cacheable {
 			cachedResponse, _ = hf.CacheMatcher.SaveRequestMatcherResponsePair(requestDetails, result.Pair, result.Error)
 		}
 
-		// If we miss, just return
+		// If we miss, the request can still be for a resource
 		if result.Error != nil {
-			log.WithFields(log.Fields{
-				"error":       result.Error.Error(),
-				"query":       requestDetails.Query,
-				"path":        requestDetails.Path,
-				"destination": requestDetails.Destination,
-				"method":      requestDetails.Method,
-			}).Warn("Failed to find matching request from simulation")
-
-			return nil, errors.MatchingFailedError(result.Error.ClosestMiss)
+			response, err := hf.getResourceResponse(requestDetails, errors.MatchingFailedError(result.Error.ClosestMiss))
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error":       result.Error.Error(),
+					"query":       requestDetails.Query,
+					"path":        requestDetails.Path,
+					"destination": requestDetails.Destination,
+					"method":      requestDetails.Method,
+				}).Warn("Failed to find matching request from simulation")
+			}
+
+			return response, err
 		} else {
-			response = result.Pair.Response
+			pair = result.Pair
 		}
 	}
 
+	response, changedState := pair.NextResponse(hf.state)
+	hf.persistStatePatch(changedState)
+
+	// Templates cached alongside the pair only hold for its single response
+	if pair.HasResponses() {
+		cachedResponse = nil
+	}
+
 	// Templating applies at the end, once we have loaded a response. Comes BEFORE state transitions,
 	// as we use the current state in templates
 	if response.Templated == true {
+		templatingSpan := requestDetails.Span().StartChild("templating", tracing.KindInternal)
+
 		responseBody, err := hf.applyBodyTemplating(&requestDetails, &response, cachedResponse)
 		if err == nil {
 			response.Body = responseBody
@@ -110,51 +176,78 @@ func (hf *Hoverfly) GetResponse(requestDetails models.RequestDetails) (*models.R
 		} else {
 			log.Warnf("Failed to applying transitions state templating: %s", err.Error())
 		}
+		templatingSpan.Finish()
 	}
 
 	// State transitions after we have the response
 	if response.TransitionsState != nil {
 		hf.state.PatchState(response.TransitionsState)
+		hf.persistStatePatch(response.TransitionsState)
 	}
 
 	if response.RemovesState != nil {
 		hf.state.RemoveState(response.RemovesState)
+		hf.persistStateRemoval(response.RemovesState)
 	}
 
 	return &response, nil
 }
 
+// getResourceResponse serves a request which matched no pair from the resources, returning the matching error
+// when there is no resource for it either
+func (hf *Hoverfly) getResourceResponse(requestDetails models.RequestDetails, matchingErr *errors.HoverflyError) (*models.ResponseDetails, *errors.HoverflyError) {
+	if response, found := hf.Resources.Handle(requestDetails); found {
+		return response, nil
+	}
+	return nil, matchingErr
+}
+
 func (hf *Hoverfly) readResponseBodyFiles(pairs []v2.RequestMatcherResponsePairViewV5) v2.SimulationImportResult {
 	result := v2.SimulationImportResult{}
 
-	for i, pair := range pairs {
-		if len(pair.Response.GetBody()) > 0 && len(pair.Response.GetBodyFile()) > 0 {
-			result.AddBodyAndBodyFileWarning(i)
-			continue
+	for i := range pairs {
+		if err := hf.readResponseBodyFileOf(&pairs[i].Response, i, &result); err != nil {
+			result.SetError(fmt.Errorf("data.pairs[%d].response %s", i, err.Error()))
+			return result
+		}
+
+		for j := range pairs[i].Responses {
+			if err := hf.readResponseBodyFileOf(&pairs[i].Responses[j], i, &result); err != nil {
+				result.SetError(fmt.Errorf("data.pairs[%d].responses[%d] %s", i, j, err.Error()))
+				return result
+			}
 		}
+	}
 
-		if len(pair.Response.GetBody()) == 0 && len(pair.Response.GetBodyFile()) > 0 {
-			var content string
-			var err error
+	return result
+}
 
-			bodyFile := pair.Response.GetBodyFile()
+func (hf *Hoverfly) readResponseBodyFileOf(response *v2.ResponseDetailsViewV5, pairIndex int, result *v2.SimulationImportResult) error {
+	if len(response.GetBody()) > 0 && len(response.GetBodyFile()) > 0 {
+		result.AddBodyAndBodyFileWarning(pairIndex)
+		return nil
+	}
 
-			if util.IsURL(bodyFile) {
-				content, err = hf.readResponseBodyURL(bodyFile)
-			} else {
-				content, err = hf.readResponseBodyFile(bodyFile)
-			}
+	if len(response.GetBody()) == 0 && len(response.GetBodyFile()) > 0 {
+		var content string
+		var err error
 
-			if err != nil {
-				result.SetError(fmt.Errorf("data.pairs[%d].response %s", i, err.Error()))
-				return result
-			}
+		bodyFile := response.GetBodyFile()
 
-			pairs[i].Response.Body = content
+		if util.IsURL(bodyFile) {
+			content, err = hf.readResponseBodyURL(bodyFile)
+		} else {
+			content, err = hf.readResponseBodyFile(bodyFile)
+		}
+
+		if err != nil {
+			return err
 		}
+
+		response.Body = content
 	}
 
-	return result
+	return nil
 }
 
 func (hf *Hoverfly) readResponseBodyURL(fileURL string) (string, error) {
@@ -226,7 +319,7 @@ func (hf *Hoverfly) applyTransitionsStateTemplating(requestDetails *models.Reque
 	state := make(map[string]string)
 
 	for k, v := range stateTemplates {
-		state[k], err = hf.templator.RenderTemplate(v, requestDetails, response, hf.Simulation.Literals, hf.Simulation.Vars, hf.state.State)
+		state[k], err = hf.templator.RenderTemplate(v, requestDetails, response, hf.Simulation.GetLiterals(), hf.Simulation.GetVariables(), hf.state.State)
 		if err != nil {
 			return nil, err
 		}
@@ -247,7 +340,7 @@ func (hf *Hoverfly) applyBodyTemplating(requestDetails *models.RequestDetails, r
 		}
 	}
 
-	return hf.templator.RenderTemplate(template, requestDetails, response, hf.Simulation.Literals, hf.Simulation.Vars, hf.state.State)
+	return hf.templator.RenderTemplate(template, requestDetails, response, hf.Simulation.GetLiterals(), hf.Simulation.GetVariables(), hf.state.State)
 }
 
 func (hf *Hoverfly) applyHeadersTemplating(requestDetails *models.RequestDetails, response *models.ResponseDetails, cachedResponse *models.CachedResponse) (map[string][]string, error) {
@@ -282,7 +375,7 @@ func (hf *Hoverfly) applyHeadersTemplating(requestDetails *models.RequestDetails
 	for k, v := range headersTemplates {
 		header = make([]string, len(v))
 		for i, h := range v {
-			header[i], err = hf.templator.RenderTemplate(h, requestDetails, response, hf.Simulation.Literals, hf.Simulation.Vars, hf.state.State)
+			header[i], err = hf.templator.RenderTemplate(h, requestDetails, response, hf.Simulation.GetLiterals(), hf.Simulation.GetVariables(), hf.state.State)
 
 			if err != nil {
 				return nil, err
@@ -296,6 +389,77 @@ func (hf *Hoverfly) applyHeadersTemplating(requestDetails *models.RequestDetails
 
 // save gets request fingerprint, extracts request body, status code and headers, then saves it to cache
 func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.ResponseDetails, modeArgs *modes.ModeArguments) error {
+	pair := models.RequestMatcherResponsePair{
+		RequestMatcher: modeArgs.Redactor.RequestMatcher(newRequestMatcherFromRequest(request, modeArgs)),
+		Response:       modeArgs.Redactor.Response(*response),
+	}
+	if modeArgs.Stateful {
+		hf.Simulation.AddPairInSequence(&pair, hf.state)
+		hf.persistSimulation()
+		hf.persistState()
+	} else if modeArgs.OverwriteDuplicate {
+		hf.Simulation.AddPairWithOverwritingDuplicate(&pair)
+		hf.persistSimulation()
+	} else if hf.Simulation.AddPair(&pair) {
+		hf.persistPair(&pair)
+	}
+
+	if hf.Cfg.GetMode() == modes.Spy {
+		_, _ = hf.CacheMatcher.SaveRequestMatcherResponsePair(*request, &pair, nil)
+	}
+
+	return nil
+}
+
+// SaveWebSocket stores a captured WebSocket conversation as a WebSocket pair
+func (hf *Hoverfly) SaveWebSocket(request *models.RequestDetails, opened time.Time, messages []models.WebSocketMessage, modeArgs *modes.ModeArguments) {
+	redactedMessages := make([]models.WebSocketMessage, len(messages))
+	for i, message := range messages {
+		if message.Type == models.WebSocketText {
+			message.Data = modeArgs.Redactor.Body(message.Data)
+		}
+		redactedMessages[i] = message
+	}
+
+	pair := models.NewWebSocketPairFromMessages(modeArgs.Redactor.RequestMatcher(newRequestMatcherFromRequest(request, modeArgs)), opened, redactedMessages)
+	if modeArgs.OverwriteDuplicate {
+		hf.Simulation.AddWebSocketPairWithOverwritingDuplicate(pair)
+	} else {
+		hf.Simulation.AddWebSocketPair(pair)
+	}
+	hf.persistSimulation()
+}
+
+// newGraphQLBodyMatcher matches a GraphQL request on its query rather than its body, as clients are free to
+// format the query as they like, with the variables matched as JSON
+func newGraphQLBodyMatcher(request *models.RequestDetails, contentType string) ([]models.RequestFieldMatchers, bool) {
+	if contentType != "json" && contentType != "graphql" {
+		return nil, false
+	}
+
+	graphQLRequest, ok := util.ParseGraphQLRequest(request.Body)
+	if !ok {
+		return nil, false
+	}
+	if _, err := util.ParseGraphQLOperation(graphQLRequest.Query, graphQLRequest.OperationName); err != nil {
+		return nil, false
+	}
+
+	body := models.RequestFieldMatchers{
+		Matcher: matchers.GraphQL,
+		Value:   graphQLRequest.Query,
+	}
+	if len(graphQLRequest.Variables) > 0 {
+		variables, _ := json.Marshal(graphQLRequest.Variables)
+		body.DoMatch = &models.RequestFieldMatchers{
+			Matcher: matchers.Json,
+			Value:   string(variables),
+		}
+	}
+	return []models.RequestFieldMatchers{body}, true
+}
+
+func newRequestMatcherFromRequest(request *models.RequestDetails, modeArgs *modes.ModeArguments) models.RequestMatcher {
 	body := []models.RequestFieldMatchers{
 		{
 			Matcher: matchers.Exact,
@@ -303,7 +467,9 @@ func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.Respon
 		},
 	}
 	contentType := util.GetContentTypeFromHeaders(request.Headers)
-	if contentType == "json" {
+	if graphQLBody, ok := newGraphQLBodyMatcher(request, contentType); ok {
+		body = graphQLBody
+	} else if contentType == "json" || isGrpcJsonBody(request) {
 		body = []models.RequestFieldMatchers{
 			{
 				Matcher: matchers.Json,
@@ -335,6 +501,30 @@ func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.Respon
 				},
 			}
 		}
+	} else if contentType == "multipart" {
+		// The boundary changes on every request, so the parts are matched rather than the body
+		parts, err := util.ParseMultipartBody(request.Headers["Content-Type"][0], request.Body)
+		if err == nil && len(parts) > 0 {
+			multipart := make(map[string]models.MultipartPartMatchers)
+			for _, part := range parts {
+				partMatchers := models.MultipartPartMatchers{
+					Value: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: part.Value}},
+				}
+				if part.Filename != "" {
+					partMatchers.Filename = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: part.Filename}}
+				}
+				if part.ContentType != "" {
+					partMatchers.ContentType = []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: part.ContentType}}
+				}
+				multipart[part.Name] = partMatchers
+			}
+			body = []models.RequestFieldMatchers{
+				{
+					Matcher: "multipart",
+					Value:   multipart,
+				},
+			}
+		}
 	}
 
 	var headers map[string][]string
@@ -369,56 +559,55 @@ func (hf *Hoverfly) Save(request *models.RequestDetails, response *models.Respon
 		}
 	}
 
-	pair := models.RequestMatcherResponsePair{
-		RequestMatcher: models.RequestMatcher{
-			Path: []models.RequestFieldMatchers{
-				{
-					Matcher: matchers.Exact,
-					Value:   request.Path,
-				},
+	requestMatcher := models.RequestMatcher{
+		Path: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   request.Path,
 			},
-			Method: []models.RequestFieldMatchers{
-				{
-					Matcher: matchers.Exact,
-					Value:   request.Method,
-				},
+		},
+		Method: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   request.Method,
 			},
-			Destination: []models.RequestFieldMatchers{
-				{
-					Matcher: matchers.Exact,
-					Value:   request.Destination,
-				},
+		},
+		Destination: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   request.Destination,
 			},
-			Scheme: []models.RequestFieldMatchers{
-				{
-					Matcher: matchers.Exact,
-					Value:   request.Scheme,
-				},
+		},
+		Scheme: []models.RequestFieldMatchers{
+			{
+				Matcher: matchers.Exact,
+				Value:   request.Scheme,
 			},
-			Query:   queries,
-			Body:    body,
-			Headers: requestHeaders,
 		},
-		Response: *response,
-	}
-	if modeArgs.Stateful {
-		hf.Simulation.AddPairInSequence(&pair, hf.state)
-	} else if modeArgs.OverwriteDuplicate {
-		hf.Simulation.AddPairWithOverwritingDuplicate(&pair)
-	} else {
-		hf.Simulation.AddPair(&pair)
-	}
-
-	if hf.Cfg.GetMode() == modes.Spy {
-		_, _ = hf.CacheMatcher.SaveRequestMatcherResponsePair(*request, &pair, nil)
+		Query:   queries,
+		Body:    body,
+		Headers: requestHeaders,
 	}
+	applyCaptureRules(&requestMatcher, request, modeArgs.CaptureRules)
 
-	return nil
+	return requestMatcher
 }
 
 func (hf *Hoverfly) ApplyMiddleware(pair models.RequestResponsePair) (models.RequestResponsePair, error) {
 	if hf.Cfg.Middleware.IsSet() {
-		return hf.Cfg.Middleware.Execute(pair)
+		span := pair.Request.Span().StartChild("middleware", tracing.KindInternal)
+		defer span.Finish()
+
+		start := time.Now()
+		result, err := hf.Cfg.Middleware.Execute(pair)
+		hf.Metrics.MiddlewareDuration.Observe(time.Since(start))
+		if err != nil {
+			hf.Metrics.MiddlewareFailures.Inc()
+			span.SetError(err)
+		}
+		// The pair returned by the middleware is read from its output, which does not carry the span
+		result.Request.SetSpan(pair.Request.Span())
+		return result, err
 	}
 
 	return pair, nil
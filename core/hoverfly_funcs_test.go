@@ -14,6 +14,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/redaction"
 	. "github.com/onsi/gomega"
 )
 
@@ -246,6 +247,86 @@ func Test_Hoverfly_GetResponse_WillCacheMisses(t *testing.T) {
 	Expect(cachedResponse.ClosestMiss).To(BeNil())
 }
 
+func Test_Hoverfly_GetResponse_CountsSimulationAndCacheHitsAndMisses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "somehost.com",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Status: 200,
+			Body:   "response body",
+		},
+	})
+
+	unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	unit.GetResponse(models.RequestDetails{Destination: "somehost.com"})
+	unit.GetResponse(models.RequestDetails{Destination: "otherhost.com"})
+
+	Expect(unit.Metrics.SimulationHits.Value()).To(Equal(2.0))
+	Expect(unit.Metrics.SimulationMisses.Value()).To(Equal(1.0))
+	Expect(unit.Metrics.CacheHits.Value()).To(Equal(1.0))
+	Expect(unit.Metrics.CacheMisses.Value()).To(Equal(2.0))
+}
+
+func Test_Hoverfly_GetResponse_ServesResourcesWhenNoPairMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/users/2",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 503,
+					},
+				},
+			},
+			Resources: []v2.ResourceViewV5{
+				{
+					Path: "/api/users",
+					Data: []map[string]interface{}{{"id": float64(1), "name": "Ben"}, {"id": float64(2), "name": "Tommy"}},
+				},
+			},
+		},
+	})
+
+	response, err := unit.GetResponse(models.RequestDetails{Method: "GET", Path: "/api/users/1"})
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(200))
+	Expect(response.Body).To(MatchJSON(`{"id": 1, "name": "Ben"}`))
+
+	// The miss is cached, which must not stop the resource from seeing later changes
+	_, err = unit.GetResponse(models.RequestDetails{Method: "DELETE", Path: "/api/users/1"})
+	Expect(err).To(BeNil())
+	response, err = unit.GetResponse(models.RequestDetails{Method: "GET", Path: "/api/users/1"})
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(404))
+
+	response, err = unit.GetResponse(models.RequestDetails{Method: "GET", Path: "/api/users/2"})
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(503))
+
+	_, err = unit.GetResponse(models.RequestDetails{Method: "GET", Path: "/api/teams"})
+	Expect(err).ToNot(BeNil())
+}
+
 func Test_Hoverfly_GetResponse_WillCacheClosestMiss(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1079,6 +1160,219 @@ func Test_Hoverfly_Save_SavesRequestBodyAsXmlPathIfContentTypeIsXml(t *testing.T
 	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Value).To(Equal(`<xml>`))
 }
 
+func Test_Hoverfly_Save_SavesRequestBodyAsMultipartIfContentTypeIsMultipart(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body: "--xyz\r\n" +
+			"Content-Disposition: form-data; name=\"file\"; filename=\"hello.txt\"\r\n" +
+			"Content-Type: text/plain\r\n\r\n" +
+			"hello world\r\n" +
+			"--xyz--\r\n",
+		Headers: map[string][]string{
+			"Content-Type": {"multipart/form-data; boundary=xyz"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{})
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+
+	body := unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body
+	Expect(body).To(HaveLen(1))
+	Expect(body[0].Matcher).To(Equal("multipart"))
+	Expect(body[0].Value).To(Equal(map[string]models.MultipartPartMatchers{
+		"file": {
+			Value:       []models.RequestFieldMatchers{{Matcher: "exact", Value: "hello world"}},
+			Filename:    []models.RequestFieldMatchers{{Matcher: "exact", Value: "hello.txt"}},
+			ContentType: []models.RequestFieldMatchers{{Matcher: "exact", Value: "text/plain"}},
+		},
+	}))
+}
+
+func Test_Hoverfly_Save_SavesRequestBodyAsGraphQLIfBodyIsGraphQL(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body: `{"query": "query GetUser($id: ID!) { user(id: $id) { name } }", "variables": {"id": "1"}}`,
+		Headers: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{})
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(1))
+
+	body := unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body
+	Expect(body).To(HaveLen(1))
+	Expect(body[0].Matcher).To(Equal("graphql"))
+	Expect(body[0].Value).To(Equal("query GetUser($id: ID!) { user(id: $id) { name } }"))
+	Expect(body[0].DoMatch).To(Equal(&models.RequestFieldMatchers{
+		Matcher: "json",
+		Value:   `{"id":"1"}`,
+	}))
+}
+
+func Test_Hoverfly_Save_SavesJsonBodyWithQueryWhichIsNotGraphQLAsJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body: `{"query": "red shoes"}`,
+		Headers: map[string][]string{
+			"Content-Type": {"application/json"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{})
+
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body[0].Matcher).To(Equal("json"))
+}
+
+func Test_Hoverfly_Save_GeneralisesPathWithCaptureRule(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	modeArgs := &modes.ModeArguments{
+		CaptureRules: []modes.CaptureRule{{Field: "path", Matcher: matchers.Glob, Value: "/users/{id}/orders"}},
+	}
+	_ = unit.Save(&models.RequestDetails{Method: "GET", Path: "/users/1/orders"}, &models.ResponseDetails{Status: 200}, modeArgs)
+	_ = unit.Save(&models.RequestDetails{Method: "GET", Path: "/users/2/orders"}, &models.ResponseDetails{Status: 200}, modeArgs)
+	_ = unit.Save(&models.RequestDetails{Method: "GET", Path: "/accounts/1"}, &models.ResponseDetails{Status: 200}, modeArgs)
+
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(2))
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Path).To(Equal([]models.RequestFieldMatchers{
+		{Matcher: matchers.Glob, Value: "/users/*/orders"},
+	}))
+	Expect(unit.Simulation.GetMatchingPairs()[1].RequestMatcher.Path).To(Equal([]models.RequestFieldMatchers{
+		{Matcher: matchers.Exact, Value: "/accounts/1"},
+	}))
+}
+
+func Test_Hoverfly_Save_GeneralisesPathAsRegexWithCaptureRule(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{Path: "/users/1.json"}, &models.ResponseDetails{}, &modes.ModeArguments{
+		CaptureRules: []modes.CaptureRule{{Field: "path", Matcher: matchers.Regex, Value: "/users/{id}.json"}},
+	})
+
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Path).To(Equal([]models.RequestFieldMatchers{
+		{Matcher: matchers.Regex, Value: `^/users/[^/]+\.json$`},
+	}))
+}
+
+func Test_Hoverfly_Save_GeneralisesQueryAndHeadersWithCaptureRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Query: map[string][]string{
+			"page":      {"1"},
+			"timestamp": {"1700000000"},
+		},
+		Headers: map[string][]string{
+			"Authorization": {"Bearer abc"},
+			"Accept":        {"application/json"},
+		},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{
+		Headers: []string{"*"},
+		CaptureRules: []modes.CaptureRule{
+			{Field: "query", Key: "timestamp", Matcher: matchers.Regex, Value: "^[0-9]+$"},
+			{Field: "header", Key: "authorization", Matcher: matchers.Glob},
+		},
+	})
+
+	requestMatcher := unit.Simulation.GetMatchingPairs()[0].RequestMatcher
+	Expect(*requestMatcher.Query).To(HaveKeyWithValue("page", []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "1"}}))
+	Expect(*requestMatcher.Query).To(HaveKeyWithValue("timestamp", []models.RequestFieldMatchers{{Matcher: matchers.Regex, Value: "^[0-9]+$"}}))
+	Expect(requestMatcher.Headers).To(HaveKeyWithValue("Accept", []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "application/json"}}))
+	Expect(requestMatcher.Headers).To(HaveKeyWithValue("Authorization", []models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "*"}}))
+}
+
+func Test_Hoverfly_Save_IgnoresJsonBodyPathsWithCaptureRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body:    `{"requestId": "5f1c", "items": [{"id": 1, "addedAt": "today"}], "total": 10}`,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{
+		CaptureRules: []modes.CaptureRule{
+			{Field: "body", Key: "$.requestId", Matcher: matchers.Regex, Value: "^[0-9a-f]+$"},
+			{Field: "body", Key: "$.items[*].addedAt", Matcher: matchers.JsonPartial},
+		},
+	})
+
+	body := unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body
+	Expect(body).To(HaveLen(2))
+	Expect(body[0].Matcher).To(Equal(matchers.JsonPartial))
+	Expect(body[0].Value).To(MatchJSON(`{"items": [{"id": 1}], "total": 10}`))
+	Expect(body[1]).To(Equal(models.RequestFieldMatchers{
+		Matcher: matchers.JsonPath,
+		Value:   "$.requestId",
+		DoMatch: &models.RequestFieldMatchers{Matcher: matchers.Regex, Value: "^[0-9a-f]+$"},
+	}))
+
+	Expect(matching.FieldMatcher(body, `{"requestId": "9a2b", "items": [{"id": 1, "addedAt": "tomorrow"}], "total": 10}`, nil).Matched).To(BeTrue())
+	Expect(matching.FieldMatcher(body, `{"requestId": "9a2b", "items": [{"id": 2, "addedAt": "tomorrow"}], "total": 10}`, nil).Matched).To(BeFalse())
+	Expect(matching.FieldMatcher(body, `{"requestId": "not hex", "items": [{"id": 1}], "total": 10}`, nil).Matched).To(BeFalse())
+}
+
+func Test_Hoverfly_Save_KeepsJsonBodyWhenNoCaptureRulePathIsFound(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_ = unit.Save(&models.RequestDetails{
+		Body:    `{"items": [{"id": 1}], "total": 10}`,
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+	}, &models.ResponseDetails{}, &modes.ModeArguments{
+		CaptureRules: []modes.CaptureRule{
+			{Field: "body", Key: "$.requestId", Matcher: matchers.Regex, Value: "^[0-9a-f]+$"},
+		},
+	})
+
+	body := unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Body
+	Expect(body).To(HaveLen(1))
+	Expect(body[0].Matcher).To(Equal(matchers.Json))
+	Expect(body[0].Value).To(MatchJSON(`{"items": [{"id": 1}], "total": 10}`))
+}
+
+func Test_Hoverfly_Save_RedactsRequestAndResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	redactor, err := redaction.NewRedactor([]v2.RedactionRuleView{
+		{Header: "Authorization"},
+		{Header: "Set-Cookie"},
+		{JsonPath: "$.email", Hash: true},
+	})
+	Expect(err).To(BeNil())
+
+	_ = unit.Save(&models.RequestDetails{
+		Path:    "/users",
+		Headers: map[string][]string{"Authorization": {"Bearer abc"}, "Content-Type": {"application/json"}},
+		Body:    `{"email": "ben@example.com"}`,
+	}, &models.ResponseDetails{
+		Status:  201,
+		Headers: map[string][]string{"Set-Cookie": {"session=1"}},
+		Body:    `{"id": 1, "email": "ben@example.com"}`,
+	}, &modes.ModeArguments{Headers: []string{"*"}, Redactor: redactor})
+
+	pair := unit.Simulation.GetMatchingPairs()[0]
+	Expect(pair.RequestMatcher.Headers["Authorization"]).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "[REDACTED]"}}))
+	Expect(pair.RequestMatcher.Body[0].Value).To(MatchRegexp(`^\{"email":"sha256:[0-9a-f]{16}"\}$`))
+	Expect(pair.Response.Headers["Set-Cookie"]).To(Equal([]string{"[REDACTED]"}))
+	Expect(pair.Response.Body).To(ContainSubstring(`"id":1`))
+	Expect(pair.Response.Body).ToNot(ContainSubstring("ben@example.com"))
+}
+
 func Test_Hoverfly_Save_CanAddPairStatefully(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1136,3 +1430,42 @@ func Test_Hoverfly_Save_CanOverwriteExistingDuplicatedPair(t *testing.T) {
 
 	Expect(unit.Simulation.GetMatchingPairs()[0].Response.Status).To(Equal(200))
 }
+
+func Test_Hoverfly_GetResponse_ReturnsResponsesInSequenceAndKeepsPositionInState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pair := &models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/flaky",
+				},
+			},
+		},
+		Responses: []models.ResponseDetails{
+			{Status: 503, Body: "unavailable"},
+			{Status: 503, Body: "unavailable"},
+			{Status: 200, Body: "ok"},
+		},
+	}
+	unit.Simulation.AddPair(pair)
+
+	var statuses []int
+	for i := 0; i < 4; i++ {
+		response, err := unit.GetResponse(models.RequestDetails{Path: "/flaky"})
+		Expect(err).To(BeNil())
+		statuses = append(statuses, response.Status)
+	}
+
+	Expect(statuses).To(Equal([]int{503, 503, 200, 200}))
+	Expect(unit.GetState()).To(HaveKeyWithValue(pair.ResponsesStateKey(), "3"))
+
+	unit.ClearState()
+
+	response, err := unit.GetResponse(models.RequestDetails{Path: "/flaky"})
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(503))
+}
@@ -3,6 +3,8 @@ package hoverfly
 import (
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"regexp"
 	"sort"
 
@@ -15,11 +17,13 @@ import (
 
 	v1 "github.com/SpectoLabs/hoverfly/core/handlers/v1"
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/metrics"
 	"github.com/SpectoLabs/hoverfly/core/middleware"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/redaction"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
@@ -89,6 +93,16 @@ func (hf *Hoverfly) SetModeWithArguments(modeView v2.ModeView) error {
 		}
 	}
 
+	captureRules, err := newCaptureRules(modeView.Arguments.CaptureRules)
+	if err != nil {
+		return err
+	}
+
+	redactor, err := redaction.NewRedactor(modeView.Arguments.RedactionRules)
+	if err != nil {
+		return err
+	}
+
 	matchingStrategy := modeView.Arguments.MatchingStrategy
 	if modeView.Mode == modes.Simulate {
 		if matchingStrategy == nil {
@@ -112,10 +126,19 @@ func (hf *Hoverfly) SetModeWithArguments(modeView v2.ModeView) error {
 		OverwriteDuplicate: modeView.Arguments.OverwriteDuplicate,
 		CaptureOnMiss:      modeView.Arguments.CaptureOnMiss,
 		CaptureDelay:       modeView.Arguments.CaptureDelay,
+		CaptureRules:       captureRules,
+		Redactor:           redactor,
 	}
 
 	hf.modeMap[hf.Cfg.GetMode()].SetArguments(modeArguments)
 
+	// Requests captured with redaction rules are redacted in the journal too
+	if hf.Cfg.GetMode() == modes.Capture || hf.Cfg.GetMode() == modes.Spy {
+		hf.Journal.SetRedactor(redactor)
+	} else {
+		hf.Journal.SetRedactor(nil)
+	}
+
 	log.WithFields(log.Fields{
 		"mode": hf.Cfg.GetMode(),
 	}).Info("Mode has been changed")
@@ -207,7 +230,7 @@ func (hf *Hoverfly) SetResponseDelays(payloadView v1.ResponseDelayPayloadView) e
 		})
 	}
 
-	hf.Simulation.ResponseDelays = &responseDelays
+	hf.Simulation.SetResponseDelays(&responseDelays)
 	return nil
 }
 
@@ -251,66 +274,128 @@ func (hf *Hoverfly) SetResponseDelaysLogNormal(payloadView v1.ResponseDelayLogNo
 		})
 	}
 
-	hf.Simulation.ResponseDelaysLogNormal = &responseDelaysLogNormal
+	hf.Simulation.SetResponseDelaysLogNormal(&responseDelaysLogNormal)
 	return nil
 }
 
 func (hf *Hoverfly) DeleteResponseDelays() {
-	hf.Simulation.ResponseDelays = &models.ResponseDelayList{}
+	hf.Simulation.SetResponseDelays(&models.ResponseDelayList{})
 }
 
 func (hf *Hoverfly) DeleteResponseDelaysLogNormal() {
-	hf.Simulation.ResponseDelaysLogNormal = &models.ResponseDelayLogNormalList{}
+	hf.Simulation.SetResponseDelaysLogNormal(&models.ResponseDelayLogNormalList{})
 }
 
 func (hf *Hoverfly) GetStats() metrics.Stats {
 	return hf.Counter.Flush()
 }
 
+// WriteMetrics writes the metrics of the proxy traffic and matching in the Prometheus text format
+func (hf *Hoverfly) WriteMetrics(w io.Writer) error {
+	return hf.Metrics.Write(w)
+}
+
 func (hf *Hoverfly) GetSimulation() (v2.SimulationViewV5, error) {
-	pairViews := make([]v2.RequestMatcherResponsePairViewV5, 0)
+	return hf.getSimulation(nil, nil), nil
+}
 
-	for _, v := range hf.Simulation.GetMatchingPairs() {
-		pairViews = append(pairViews, v.BuildView())
+func (hf *Hoverfly) GetFilteredSimulation(urlPattern string) (v2.SimulationViewV5, error) {
+	regexPattern, err := regexp.Compile(urlPattern)
+	if err != nil {
+		return v2.SimulationViewV5{}, err
 	}
 
-	return v2.BuildSimulationView(pairViews,
-		hf.Simulation.ResponseDelays.ConvertToResponseDelayPayloadView(),
-		hf.Simulation.ResponseDelaysLogNormal.ConvertToResponseDelayLogNormalPayloadView(),
-		hf.Simulation.Vars.ConvertToGlobalVariablesPayloadView(),
-		hf.Simulation.Literals.ConvertToGlobalLiteralsPayloadView(),
-		hf.version), nil
+	return hf.getSimulation(regexPattern, nil), nil
 }
 
-func (hf *Hoverfly) GetFilteredSimulation(urlPattern string) (v2.SimulationViewV5, error) {
-	pairViews := make([]v2.RequestMatcherResponsePairViewV5, 0)
-	regexPattern, err := regexp.Compile(urlPattern)
+// GetRedactedSimulation exports the simulation with the values selected by the redaction rules replaced. The
+// simulation held by Hoverfly is not changed.
+func (hf *Hoverfly) GetRedactedSimulation(urlPattern string, rules []v2.RedactionRuleView) (v2.SimulationViewV5, error) {
+	var regexPattern *regexp.Regexp
+	if urlPattern != "" {
+		var err error
+		if regexPattern, err = regexp.Compile(urlPattern); err != nil {
+			return v2.SimulationViewV5{}, err
+		}
+	}
 
+	redactor, err := redaction.NewRedactor(rules)
 	if err != nil {
 		return v2.SimulationViewV5{}, err
 	}
 
-	for _, v := range hf.Simulation.GetMatchingPairs() {
+	return hf.getSimulation(regexPattern, redactor), nil
+}
+
+func (hf *Hoverfly) getSimulation(regexPattern *regexp.Regexp, redactor *redaction.Redactor) v2.SimulationViewV5 {
+	return hf.buildSimulationView(hf.Simulation.GetMatchingPairs(), hf.Simulation.GetWebSocketPairs(), regexPattern, redactor)
+}
+
+func (hf *Hoverfly) buildSimulationView(pairs []models.RequestMatcherResponsePair, webSocketPairs []models.WebSocketPair, regexPattern *regexp.Regexp, redactor *redaction.Redactor) v2.SimulationViewV5 {
+	pairViews := make([]v2.RequestMatcherResponsePairViewV5, 0)
 
-		var urlStringToMatch string
-		if v.RequestMatcher.Destination != nil && len(v.RequestMatcher.Destination) != 0 && v.RequestMatcher.Destination[0].Matcher == matchers.Exact {
-			urlStringToMatch += v.RequestMatcher.Destination[0].Value.(string)
+	for _, v := range pairs {
+		if regexPattern == nil || regexPattern.MatchString(getUrlStringToMatch(v.RequestMatcher)) {
+			pair := redactPair(v, redactor)
+			pairViews = append(pairViews, pair.BuildView())
 		}
-		if v.RequestMatcher.Path != nil && len(v.RequestMatcher.Path) != 0 && v.RequestMatcher.Path[0].Matcher == matchers.Exact {
-			urlStringToMatch += v.RequestMatcher.Path[0].Value.(string)
+	}
+
+	simulationView := v2.BuildSimulationView(pairViews,
+		hf.Simulation.GetResponseDelays().ConvertToResponseDelayPayloadView(),
+		hf.Simulation.GetResponseDelaysLogNormal().ConvertToResponseDelayLogNormalPayloadView(),
+		hf.Simulation.GetVariables().ConvertToGlobalVariablesPayloadView(),
+		hf.Simulation.GetLiterals().ConvertToGlobalLiteralsPayloadView(),
+		hf.version)
+
+	for _, v := range webSocketPairs {
+		if regexPattern == nil || regexPattern.MatchString(getUrlStringToMatch(v.RequestMatcher)) {
+			if redactor != nil {
+				v.RequestMatcher = redactor.RequestMatcher(v.RequestMatcher)
+			}
+			simulationView.WebSocketPairs = append(simulationView.WebSocketPairs, v.BuildView())
 		}
+	}
+
+	for _, resource := range hf.Resources.GetDefinitions() {
+		if regexPattern == nil || regexPattern.MatchString(resource.Destination+resource.Path) {
+			simulationView.Resources = append(simulationView.Resources, resource)
+		}
+	}
+
+	if customMatchers := hf.GetCustomMatchers().CustomMatchers; len(customMatchers) > 0 {
+		simulationView.CustomMatchers = customMatchers
+	}
+
+	return simulationView
+}
 
-		if regexPattern.MatchString(urlStringToMatch) {
-			pairViews = append(pairViews, v.BuildView())
+func redactPair(pair models.RequestMatcherResponsePair, redactor *redaction.Redactor) models.RequestMatcherResponsePair {
+	if redactor == nil {
+		return pair
+	}
+
+	pair.RequestMatcher = redactor.RequestMatcher(pair.RequestMatcher)
+	pair.Response = redactor.Response(pair.Response)
+	if pair.Responses != nil {
+		responses := make([]models.ResponseDetails, len(pair.Responses))
+		for i, response := range pair.Responses {
+			responses[i] = redactor.Response(response)
 		}
+		pair.Responses = responses
 	}
+	return pair
+}
 
-	return v2.BuildSimulationView(pairViews,
-		hf.Simulation.ResponseDelays.ConvertToResponseDelayPayloadView(),
-		hf.Simulation.ResponseDelaysLogNormal.ConvertToResponseDelayLogNormalPayloadView(),
-		hf.Simulation.Vars.ConvertToGlobalVariablesPayloadView(),
-		hf.Simulation.Literals.ConvertToGlobalLiteralsPayloadView(),
-		hf.version), nil
+func getUrlStringToMatch(requestMatcher models.RequestMatcher) string {
+	var urlStringToMatch string
+	if requestMatcher.Destination != nil && len(requestMatcher.Destination) != 0 && requestMatcher.Destination[0].Matcher == matchers.Exact {
+		urlStringToMatch += requestMatcher.Destination[0].Value.(string)
+	}
+	if requestMatcher.Path != nil && len(requestMatcher.Path) != 0 && requestMatcher.Path[0].Matcher == matchers.Exact {
+		urlStringToMatch += requestMatcher.Path[0].Value.(string)
+	}
+	return urlStringToMatch
 }
 
 func (hf *Hoverfly) putOrReplaceSimulation(simulationView v2.SimulationViewV5, overrideExisting bool) v2.SimulationImportResult {
@@ -320,7 +405,14 @@ func (hf *Hoverfly) putOrReplaceSimulation(simulationView v2.SimulationViewV5, o
 	}
 
 	if overrideExisting {
-		hf.DeleteSimulation()
+		hf.deleteSimulation()
+	}
+
+	for _, customMatcherView := range simulationView.CustomMatchers {
+		if err := hf.SetCustomMatcher(customMatcherView); err != nil {
+			bodyFilesResult.SetError(err)
+			return bodyFilesResult
+		}
 	}
 
 	result := hf.importRequestResponsePairViewsWithCustomData(simulationView.DataViewV5.RequestResponsePairs, simulationView.GlobalLiterals, simulationView.GlobalVariables)
@@ -328,6 +420,15 @@ func (hf *Hoverfly) putOrReplaceSimulation(simulationView v2.SimulationViewV5, o
 		return result
 	}
 
+	hf.importWebSocketPairViews(simulationView.WebSocketPairs)
+
+	for _, resourceView := range simulationView.Resources {
+		if err := hf.Resources.Add(resourceView); err != nil {
+			result.SetError(err)
+			return result
+		}
+	}
+
 	if err := hf.SetResponseDelays(v1.ResponseDelayPayloadView{Data: simulationView.GlobalActions.Delays}); err != nil {
 		result.SetError(err)
 		return result
@@ -345,16 +446,49 @@ func (hf *Hoverfly) putOrReplaceSimulation(simulationView v2.SimulationViewV5, o
 	return result
 }
 
+// ReplaceSimulation imports a simulation into a scratch namespace, then swaps it in for the whole simulation at
+// once. Requests never see a partly imported simulation, and nothing is changed if the import fails.
 func (hf *Hoverfly) ReplaceSimulation(simulationView v2.SimulationViewV5) v2.SimulationImportResult {
-	return hf.putOrReplaceSimulation(simulationView, true)
+	scratch := hf.newNamespace()
+	result := scratch.putOrReplaceSimulation(simulationView, true)
+	if result.GetError() != nil {
+		scratch.deleteSimulation()
+		return result
+	}
+
+	hf.replaceSimulation(scratch)
+	hf.persistSimulation()
+	hf.persistState()
+	return result
+}
+
+// replaceSimulation takes the simulation, resources and sequences of a scratch namespace in one go
+func (hf *Hoverfly) replaceSimulation(scratch *Hoverfly) {
+	hf.Simulation.Replace(scratch.Simulation, func() {
+		hf.Resources.Replace(scratch.Resources)
+		if hf.state == nil {
+			hf.state = state.NewState()
+		}
+		hf.state.InitializeSequences(scratch.state.State)
+	})
+	hf.FlushCache()
 }
 
 func (hf *Hoverfly) PutSimulation(simulationView v2.SimulationViewV5) v2.SimulationImportResult {
-	return hf.putOrReplaceSimulation(simulationView, false)
+	result := hf.putOrReplaceSimulation(simulationView, false)
+	hf.persistSimulation()
+	hf.persistState()
+	return result
 }
 
 func (hf *Hoverfly) DeleteSimulation() {
+	hf.deleteSimulation()
+	hf.persistSimulation()
+}
+
+func (hf *Hoverfly) deleteSimulation() {
 	hf.Simulation.DeleteMatchingPairsAlongWithCustomData()
+	hf.Resources.Delete()
 	hf.DeleteResponseDelays()
 	hf.DeleteResponseDelaysLogNormal()
 	hf.FlushCache()
@@ -396,14 +530,31 @@ func (hf *Hoverfly) GetState() map[string]string {
 
 func (hf *Hoverfly) SetState(state map[string]string) {
 	hf.state.SetState(state)
+	hf.persistState()
 }
 
 func (hf *Hoverfly) PatchState(toPatch map[string]string) {
 	hf.state.PatchState(toPatch)
+	hf.persistStatePatch(toPatch)
 }
 
 func (hf *Hoverfly) ClearState() {
 	hf.state = state.NewState()
+	hf.persistState()
+}
+
+// GetResources returns the resources of the simulation with the items their stores hold
+func (hf *Hoverfly) GetResources() v2.ResourcesView {
+	resources := hf.Resources.GetItems()
+	if resources == nil {
+		resources = []v2.ResourceViewV5{}
+	}
+	return v2.ResourcesView{Resources: resources}
+}
+
+// ResetResources puts the data of the simulation back into the stores of its resources
+func (hf *Hoverfly) ResetResources() {
+	hf.Resources.Reset()
 }
 
 func (hf *Hoverfly) GetDiff() map[v2.SimpleRequestDefinitionView][]v2.DiffReport {
@@ -540,6 +691,98 @@ func (hf *Hoverfly) DeletePostServeAction(actionName string) error {
 	return nil
 }
 
+// ExplainMatch scores the pairs of the simulation against a request without responding to it, so neither the
+// state nor the journal are changed
+func (hf *Hoverfly) ExplainMatch(requestView v2.RequestDetailsView) (v2.MatchExplanationView, error) {
+	scheme := util.PointerToString(requestView.Scheme)
+	if scheme == "" {
+		scheme = "http"
+	}
+	method := util.PointerToString(requestView.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	requestUrl := scheme + "://" + util.PointerToString(requestView.Destination) + util.PointerToString(requestView.Path)
+	if query := util.PointerToString(requestView.Query); query != "" {
+		requestUrl += "?" + query
+	}
+
+	request, err := http.NewRequest(method, requestUrl, strings.NewReader(util.PointerToString(requestView.Body)))
+	if err != nil {
+		return v2.MatchExplanationView{}, err
+	}
+	for name, values := range requestView.Headers {
+		request.Header[name] = values
+	}
+
+	requestDetails, err := models.NewRequestDetailsFromHttpRequest(request)
+	if err != nil {
+		return v2.MatchExplanationView{}, err
+	}
+
+	mode := (hf.modeMap[modes.Simulate]).(*modes.SimulateMode)
+	return matching.Explain(mode.MatchingStrategy, requestDetails, hf.Cfg.Webserver, hf.Simulation, hf.state), nil
+}
+
+func (hf *Hoverfly) GetCustomMatchers() v2.CustomMatchersView {
+	customMatcherViews := []v2.CustomMatcherView{}
+	for _, name := range hf.Simulation.CustomMatchers.Names() {
+		if customMatcher, ok := hf.Simulation.CustomMatchers.Get(name); ok {
+			customMatcherViews = append(customMatcherViews, v2.CustomMatcherView{
+				Name:          name,
+				Binary:        customMatcher.Binary,
+				ScriptContent: customMatcher.GetScript(),
+				Remote:        customMatcher.Remote,
+			})
+		}
+	}
+
+	return v2.CustomMatchersView{CustomMatchers: customMatcherViews}
+}
+
+// SetCustomMatcher registers a custom matcher. One which runs a local binary can execute anything on the host,
+// so like middleware it can only be set when the middleware API is enabled.
+func (hf *Hoverfly) SetCustomMatcher(customMatcherView v2.CustomMatcherView) error {
+	var customMatcher *matchers.CustomMatcher
+	var err error
+
+	if customMatcherView.Remote != "" {
+		if customMatcherView.Binary != "" || customMatcherView.ScriptContent != "" {
+			return fmt.Errorf("Custom matcher %s cannot have both a remote and a binary", customMatcherView.Name)
+		}
+		customMatcher, err = matchers.NewRemoteCustomMatcher(customMatcherView.Remote)
+	} else {
+		if !hf.Cfg.EnableMiddlewareAPI {
+			return fmt.Errorf("Custom matcher %s runs a local binary, which can only be set when Hoverfly is started with -enable-middleware-api", customMatcherView.Name)
+		}
+		customMatcher, err = matchers.NewLocalCustomMatcher(customMatcherView.Binary, customMatcherView.ScriptContent)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := hf.Simulation.CustomMatchers.Set(customMatcherView.Name, customMatcher); err != nil {
+		return err
+	}
+
+	// Cached responses may have been matched before the custom matcher changed
+	hf.FlushCache()
+
+	log.WithFields(log.Fields{
+		"name": customMatcherView.Name,
+	}).Info("Custom matcher is set")
+	return nil
+}
+
+func (hf *Hoverfly) DeleteCustomMatcher(name string) error {
+	if err := hf.Simulation.CustomMatchers.Delete(name); err != nil {
+		return err
+	}
+
+	hf.FlushCache()
+	return nil
+}
+
 func needsToExcludeDiffEntry(diffReportEntry *v2.DiffReportEntry, diffFilterView *v2.DiffFilterView) bool {
 
 	//check for header... headers which are ignored during configuration
@@ -570,12 +813,31 @@ func (hf *Hoverfly) SetCsvDataSource(dataSourceName, dataSourceContent string) e
 		return err
 	}
 	hf.templator.TemplateHelper.TemplateDataSource.SetDataSource(dataSourceName, dataStore)
+	hf.persistDataSource(dataSourceName, dataSourceContent)
 	return nil
 }
 
 func (hf *Hoverfly) DeleteDataSource(dataSourceName string) {
 
 	hf.templator.TemplateHelper.TemplateDataSource.DeleteDataSource(dataSourceName)
+	hf.persistDataSourceDeletion(dataSourceName)
+}
+
+func (hf *Hoverfly) SetJsonDataSource(dataSourceName, format, dataSourceContent string) error {
+
+	dataStore, err := templating.NewJsonDataSource(dataSourceName, format, dataSourceContent)
+	if err != nil {
+		return err
+	}
+	hf.templator.TemplateHelper.TemplateDataSource.SetJsonDataSource(dataSourceName, dataStore)
+	hf.persistJsonDataSource(dataSourceName, dataStore.Format, dataSourceContent)
+	return nil
+}
+
+func (hf *Hoverfly) DeleteJsonDataSource(dataSourceName string) {
+
+	hf.templator.TemplateHelper.TemplateDataSource.DeleteJsonDataSource(dataSourceName)
+	hf.persistJsonDataSourceDeletion(dataSourceName)
 }
 
 func (hf *Hoverfly) GetAllDataSources() v2.TemplateDataSourceView {
@@ -585,7 +847,16 @@ func (hf *Hoverfly) GetAllDataSources() v2.TemplateDataSourceView {
 		csvDataSource, _ := value.GetDataSourceView()
 		csvDataSourceViews = append(csvDataSourceViews, csvDataSource)
 	}
-	return v2.TemplateDataSourceView{DataSources: csvDataSourceViews}
+
+	var jsonDataSourceViews []v2.JSONDataSourceView
+	for _, value := range hf.templator.TemplateHelper.TemplateDataSource.GetAllJsonDataSources() {
+		jsonDataSource, _ := value.GetDataSourceView()
+		jsonDataSourceViews = append(jsonDataSourceViews, jsonDataSource)
+	}
+	sort.Slice(jsonDataSourceViews, func(i, j int) bool {
+		return jsonDataSourceViews[i].Name < jsonDataSourceViews[j].Name
+	})
+	return v2.TemplateDataSourceView{DataSources: csvDataSourceViews, JsonDataSources: jsonDataSourceViews}
 }
 
 func (hf *Hoverfly) AddJournalIndex(indexKey string) error {
@@ -1001,6 +1001,122 @@ func Test_Hoverfly_SetModeWithArguments_SpyCaptureOnMiss(t *testing.T) {
 	Expect(storedMode.Arguments.CaptureOnMiss).To(BeTrue())
 }
 
+func Test_Hoverfly_SetModeWithArguments_CaptureRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode: "capture",
+		Arguments: v2.ModeArgumentsView{
+			CaptureRules: []v2.CaptureRuleView{
+				{Field: "path", Value: "/users/{id}"},
+				{Field: "Body", Key: "$.requestId"},
+				{Field: "query", Key: "timestamp", Matcher: "Regex", Value: "[0-9]+"},
+			},
+		},
+	})).To(Succeed())
+
+	storedMode := unit.modeMap[modes.Capture].View()
+	Expect(storedMode.Arguments.CaptureRules).To(Equal([]v2.CaptureRuleView{
+		{Field: "path", Matcher: "glob", Value: "/users/{id}"},
+		{Field: "body", Key: "$.requestId", Matcher: "jsonpartial"},
+		{Field: "query", Key: "timestamp", Matcher: "regex", Value: "[0-9]+"},
+	}))
+}
+
+func Test_Hoverfly_SetModeWithArguments_RejectsInvalidCaptureRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	for _, rule := range []v2.CaptureRuleView{
+		{Field: "cookie", Key: "session"},
+		{Field: "path"},
+		{Field: "header"},
+		{Field: "body", Key: "requestId"},
+		{Field: "body", Key: "$.items[0]"},
+		{Field: "query", Key: "page", Matcher: "jsonpartial"},
+		{Field: "query", Key: "page", Matcher: "regex", Value: "[0-9"},
+	} {
+		err := unit.SetModeWithArguments(v2.ModeView{
+			Mode:      "capture",
+			Arguments: v2.ModeArgumentsView{CaptureRules: []v2.CaptureRuleView{rule}},
+		})
+		Expect(err).ToNot(BeNil(), "capture rule %v", rule)
+	}
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode:      "capture",
+		Arguments: v2.ModeArgumentsView{CaptureRules: []v2.CaptureRuleView{{Field: "cookie"}}},
+	})).To(MatchError("Capture rule field must be one of path, query, header or body"))
+}
+
+func Test_Hoverfly_SetModeWithArguments_RedactionRulesAlsoApplyToJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode: "capture",
+		Arguments: v2.ModeArgumentsView{
+			RedactionRules: []v2.RedactionRuleView{{Header: "Authorization"}},
+		},
+	})).To(Succeed())
+
+	storedMode := unit.modeMap[modes.Capture].View()
+	Expect(storedMode.Arguments.RedactionRules).To(Equal([]v2.RedactionRuleView{{Header: "Authorization"}}))
+	Expect(unit.Journal.GetRedactor()).ToNot(BeNil())
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.Journal.GetRedactor()).To(BeNil())
+
+	Expect(unit.SetModeWithArguments(v2.ModeView{
+		Mode: "spy",
+		Arguments: v2.ModeArgumentsView{
+			RedactionRules: []v2.RedactionRuleView{{Regex: "["}},
+		},
+	})).ToNot(Succeed())
+}
+
+func Test_Hoverfly_GetRedactedSimulation_RedactsExportWithoutChangingSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "foo.com"}},
+			Headers: map[string][]models.RequestFieldMatchers{
+				"Authorization": {{Matcher: matchers.Exact, Value: "Bearer abc"}},
+			},
+		},
+		Response: models.ResponseDetails{Status: 200, Body: "token=abc123"},
+	})
+	unit.Simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "bar.com"}},
+		},
+		Response: models.ResponseDetails{Status: 200},
+	})
+
+	simulation, err := unit.GetRedactedSimulation("foo.com", []v2.RedactionRuleView{
+		{Header: "Authorization"},
+		{Regex: "token=([a-z0-9]+)"},
+	})
+	Expect(err).To(BeNil())
+
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Headers["Authorization"][0].Value).To(Equal("[REDACTED]"))
+	Expect(simulation.RequestResponsePairs[0].Response.Body).To(Equal("token=[REDACTED]"))
+
+	Expect(unit.Simulation.GetMatchingPairs()[0].RequestMatcher.Headers["Authorization"][0].Value).To(Equal("Bearer abc"))
+	Expect(unit.Simulation.GetMatchingPairs()[0].Response.Body).To(Equal("token=abc123"))
+
+	_, err = unit.GetRedactedSimulation("", []v2.RedactionRuleView{{JsonPath: "password"}})
+	Expect(err).ToNot(BeNil())
+}
+
 func Test_Hoverfly_AddDiff_AddEntry(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1210,6 +1326,63 @@ func Test_Hoverfly_ReplaceSimulation_OverridesSimulation(t *testing.T) {
 	Expect(simulation.RequestResponsePairs[0].Response.Body).To(Equal(pairTwo.Response.Body))
 }
 
+func Test_Hoverfly_ReplaceSimulation_KeepsSimulationWhenImportFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	Expect(unit.ReplaceSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pairOne},
+			CustomMatchers:       []v2.CustomMatcherView{{Name: "hmac", Remote: "http://localhost:8080/hmac"}},
+		},
+	}).GetError()).To(BeNil())
+
+	invalid := pairTwo
+	invalid.Response.Fault = &v2.FaultViewV5{Type: "slowBody"}
+	importResult := unit.ReplaceSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pairTwo, invalid},
+			CustomMatchers:       []v2.CustomMatcherView{{Name: "other", Remote: "http://localhost:8080/other"}},
+		},
+	})
+	Expect(importResult.GetError()).ToNot(BeNil())
+
+	simulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.RequestResponsePairs[0].Response.Body).To(Equal(pairOne.Response.Body))
+	Expect(simulation.CustomMatchers).To(ConsistOf(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"}))
+}
+
+func Test_Hoverfly_ReplaceSimulation_IsSafeToRunAlongsideRequests(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	simulation := v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pairOne, pairTwo},
+			GlobalActions: v2.GlobalActionsView{
+				Delays: []v1.ResponseDelayView{{UrlPattern: ".", Delay: 0}},
+			},
+		},
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			unit.ReplaceSimulation(simulation)
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		exported, err := unit.GetSimulation()
+		Expect(err).To(BeNil())
+		Expect(len(exported.RequestResponsePairs)).To(BeElementOf(0, 2))
+	}
+	<-done
+}
+
 func Test_Hoverfly_PutSimulation_NotOverridesSimulation(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1656,6 +1829,29 @@ func TestHoverfly_GetTemplateDataSources(t *testing.T) {
 	Expect(templateDataSourceView.DataSources[0].Data).To(Equal(content))
 }
 
+func TestHoverfly_SetGetAndDeleteJsonTemplateDataSources(t *testing.T) {
+
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetJsonDataSource("users", "", `{"users": [{"id": 1, "name": "Test1"}]}`)).To(Succeed())
+	Expect(unit.SetJsonDataSource("teams", "yaml", "teams:\n- core\n")).To(Succeed())
+	Expect(unit.SetJsonDataSource("roles", "json", `{"roles": [`)).NotTo(Succeed())
+
+	templateDataSourceView := unit.GetAllDataSources()
+	Expect(templateDataSourceView.DataSources).To(BeEmpty())
+	Expect(templateDataSourceView.JsonDataSources).To(Equal([]v2.JSONDataSourceView{
+		{Name: "teams", Format: "yaml", Data: "teams:\n- core\n"},
+		{Name: "users", Format: "json", Data: `{"users":[{"id":1,"name":"Test1"}]}`},
+	}))
+
+	unit.DeleteJsonDataSource("users")
+
+	Expect(unit.GetAllDataSources().JsonDataSources).To(HaveLen(1))
+	Expect(unit.GetAllDataSources().JsonDataSources[0].Name).To(Equal("teams"))
+}
+
 func TestHoverfly_AddAndGetJournalIndex(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1690,3 +1886,250 @@ func addIndexes(unit *Hoverfly, indexName1, indexName2 string) (string, string)
 	Expect(err2).To(BeNil())
 	return indexName1, indexName2
 }
+
+func Test_Hoverfly_PutSimulation_ImportsResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pair := pairTwo
+	pair.Responses = []v2.ResponseDetailsViewV5{
+		{Status: 503, Body: "unavailable", Weight: 1},
+		{Status: 200, Body: "ok", Weight: 9},
+	}
+	pair.ResponsesMode = "random"
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pair},
+		},
+	})
+	Expect(result.GetError()).To(BeNil())
+
+	importedSimulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(importedSimulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(importedSimulation.RequestResponsePairs[0].ResponsesMode).To(Equal("random"))
+	Expect(importedSimulation.RequestResponsePairs[0].Responses).To(HaveLen(2))
+	Expect(importedSimulation.RequestResponsePairs[0].Responses[1].Status).To(Equal(200))
+	Expect(importedSimulation.RequestResponsePairs[0].Responses[1].Weight).To(Equal(9))
+}
+
+func Test_Hoverfly_PutSimulation_ReturnsErrorForInvalidResponsesMode(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pair := pairTwo
+	pair.Responses = []v2.ResponseDetailsViewV5{{Status: 200}}
+	pair.ResponsesMode = "shuffle"
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pair},
+		},
+	})
+	Expect(result.GetError()).ToNot(BeNil())
+	Expect(result.GetError().Error()).To(ContainSubstring("invalid responsesMode shuffle"))
+}
+
+func Test_Hoverfly_PutSimulation_ReturnsErrorForInvalidWeight(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pair := pairTwo
+	pair.Responses = []v2.ResponseDetailsViewV5{{Status: 200}, {Status: 503, Weight: -1}}
+	pair.ResponsesMode = "random"
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pair},
+		},
+	})
+	Expect(result.GetError()).ToNot(BeNil())
+	Expect(result.GetError().Error()).To(ContainSubstring("data.pairs[0].responses[1] has an invalid weight -1"))
+}
+
+func Test_Hoverfly_PutSimulation_ReturnsErrorForInvalidFault(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pair := pairTwo
+	pair.Response.Fault = &v2.FaultViewV5{Type: "slowBody"}
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pair},
+		},
+	})
+	Expect(result.GetError()).ToNot(BeNil())
+	Expect(result.GetError().Error()).To(ContainSubstring("bytesPerSecond"))
+	Expect(unit.Simulation.GetMatchingPairs()).To(BeEmpty())
+}
+
+func TestHoverfly_SetCustomMatcher_Remote(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.SetCustomMatcher(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"})
+	Expect(err).To(BeNil())
+
+	Expect(unit.GetCustomMatchers().CustomMatchers).To(ConsistOf(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"}))
+}
+
+func TestHoverfly_SetCustomMatcher_LocalRequiresMiddlewareApi(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.SetCustomMatcher(v2.CustomMatcherView{Name: "hmac", Binary: "python3", ScriptContent: "script"})
+	Expect(err).To(MatchError("Custom matcher hmac runs a local binary, which can only be set when Hoverfly is started with -enable-middleware-api"))
+	Expect(unit.GetCustomMatchers().CustomMatchers).To(BeEmpty())
+
+	unit.Cfg.EnableMiddlewareAPI = true
+
+	err = unit.SetCustomMatcher(v2.CustomMatcherView{Name: "hmac", Binary: "python3", ScriptContent: "script"})
+	Expect(err).To(BeNil())
+	Expect(unit.GetCustomMatchers().CustomMatchers).To(ConsistOf(v2.CustomMatcherView{Name: "hmac", Binary: "python3", ScriptContent: "script"}))
+}
+
+func TestHoverfly_PutSimulation_ImportsAndExportsCustomMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	simulation := v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+			CustomMatchers:       []v2.CustomMatcherView{{Name: "hmac", Remote: "http://localhost:8080/hmac"}},
+		},
+		MetaView: *v2.NewMetaView("test"),
+	}
+
+	result := unit.PutSimulation(simulation)
+	Expect(result.GetError()).To(BeNil())
+
+	exported, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(exported.CustomMatchers).To(ConsistOf(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"}))
+}
+
+func TestHoverfly_DeleteSimulation_DeletesCustomMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.SetCustomMatcher(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"})).To(Succeed())
+
+	unit.DeleteSimulation()
+
+	Expect(unit.GetCustomMatchers().CustomMatchers).To(BeEmpty())
+}
+
+func TestHoverfly_PutSimulation_ImportsAndExportsResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	resource := v2.ResourceViewV5{
+		Path:        "/api/users",
+		Destination: "users.com",
+		Data:        []map[string]interface{}{{"id": float64(1), "name": "Ben"}},
+	}
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+			Resources:            []v2.ResourceViewV5{resource},
+		},
+		MetaView: *v2.NewMetaView("test"),
+	})
+	Expect(result.GetError()).To(BeNil())
+
+	response, err := unit.GetResponse(models.RequestDetails{Method: "POST", Destination: "users.com", Path: "/api/users", Body: `{"name": "Kate"}`})
+	Expect(err).To(BeNil())
+	Expect(response.Status).To(Equal(201))
+
+	exported, exportErr := unit.GetSimulation()
+	Expect(exportErr).To(BeNil())
+	Expect(exported.Resources).To(HaveLen(1))
+	Expect(exported.Resources[0].Data).To(HaveLen(1))
+
+	filtered, exportErr := unit.GetFilteredSimulation("other.com")
+	Expect(exportErr).To(BeNil())
+	Expect(filtered.Resources).To(BeEmpty())
+
+	Expect(unit.GetResources().Resources[0].Data).To(HaveLen(2))
+	unit.ResetResources()
+	Expect(unit.GetResources().Resources[0].Data).To(HaveLen(1))
+
+	unit.DeleteSimulation()
+	Expect(unit.GetResources().Resources).To(BeEmpty())
+}
+
+func TestHoverfly_PutSimulation_ReturnsErrorForInvalidResource(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	result := unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+			Resources:            []v2.ResourceViewV5{{Path: "api/users"}},
+		},
+		MetaView: *v2.NewMetaView("test"),
+	})
+
+	Expect(result.GetError()).To(MatchError("Resource path api/users must start with /"))
+}
+
+func TestHoverfly_DeleteCustomMatcher_ErrorsWhenNotFound(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.DeleteCustomMatcher("missing")).To(MatchError("Custom matcher missing not found"))
+}
+
+func TestHoverfly_ExplainMatch_DoesNotChangeStateOrJournal(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Journal.EntryLimit = 100
+
+	unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path:   []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/orders")},
+						Method: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "POST")},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status:           201,
+						TransitionsState: map[string]string{"orders": "1"},
+					},
+				},
+			},
+		},
+		MetaView: *v2.NewMetaView("test"),
+	})
+
+	path := "/orders"
+	method := "POST"
+	destination := "test.com"
+	explanation, err := unit.ExplainMatch(v2.RequestDetailsView{Path: &path, Method: &method, Destination: &destination})
+	Expect(err).To(BeNil())
+
+	Expect(explanation.Matched).To(BeTrue())
+	Expect(explanation.Candidates).To(HaveLen(1))
+	Expect(explanation.Candidates[0].Selected).To(BeTrue())
+
+	Expect(unit.GetState()).To(BeEmpty())
+	journal, err := unit.Journal.GetEntries(0, 100, nil, nil, "")
+	Expect(err).To(BeNil())
+	Expect(journal.Journal).To(BeEmpty())
+}
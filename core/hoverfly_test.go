@@ -5,8 +5,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/SpectoLabs/hoverfly/core/cors"
 	"github.com/SpectoLabs/hoverfly/core/modes"
@@ -16,6 +21,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/handlers/v1"
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	. "github.com/onsi/gomega"
 )
 
@@ -733,3 +739,85 @@ func Test_Hoverfly_StartProxy_StartProxyWOPort(t *testing.T) {
 	err := unit.StartProxy()
 	Expect(err).ToNot(BeNil())
 }
+
+func Test_Hoverfly_processRequest_PropagatesTraceparentToUpstreamWhenTracing(t *testing.T) {
+	RegisterTestingT(t)
+
+	var upstreamTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var exported []string
+	var exportedMu sync.Mutex
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		exportedMu.Lock()
+		exported = append(exported, string(body))
+		exportedMu.Unlock()
+	}))
+	defer collector.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.Tracer = tracing.NewTracer(collector.URL)
+	unit.HTTP = &http.Client{Transport: &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return url.Parse(server.URL)
+		},
+	}}
+	unit.Cfg.SetMode("spy")
+
+	r, err := http.NewRequest("GET", "http://somehost.com", nil)
+	Expect(err).To(BeNil())
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	resp, _ := unit.processRequest(r)
+
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	Expect(upstreamTraceparent).To(HavePrefix("00-4bf92f3577b34da6a3ce929d0e0e4736-"))
+	Expect(upstreamTraceparent).To(HaveSuffix("-01"))
+	Expect(upstreamTraceparent).ToNot(ContainSubstring("00f067aa0ba902b7"))
+
+	Expect(r.Header.Get("traceparent")).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+
+	Eventually(func() string {
+		exportedMu.Lock()
+		defer exportedMu.Unlock()
+		return strings.Join(exported, "")
+	}, 5*time.Second).Should(And(
+		ContainSubstring(`"name":"GET"`),
+		ContainSubstring(`"parentSpanId":"00f067aa0ba902b7"`),
+		ContainSubstring(`"name":"cache lookup"`),
+		ContainSubstring(`"name":"matching"`),
+		ContainSubstring(`"name":"upstream request"`),
+	))
+}
+
+func Test_Hoverfly_processRequest_DoesNotChangeTraceparentWhenNotTracing(t *testing.T) {
+	RegisterTestingT(t)
+
+	var upstreamTraceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		upstreamTraceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.HTTP = &http.Client{Transport: &http.Transport{
+		Proxy: func(req *http.Request) (*url.URL, error) {
+			return url.Parse(server.URL)
+		},
+	}}
+	unit.Cfg.SetMode("spy")
+
+	r, err := http.NewRequest("GET", "http://somehost.com", nil)
+	Expect(err).To(BeNil())
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	unit.processRequest(r)
+
+	Expect(upstreamTraceparent).To(Equal("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+}
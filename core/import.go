@@ -11,6 +11,7 @@ import (
 
 	"github.com/SpectoLabs/hoverfly/core/delay"
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/openapi"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
 
@@ -33,8 +34,8 @@ func (hf *Hoverfly) Import(uri string) error {
 	}
 	// assuming file URI is disk location
 	ext := path.Ext(uri)
-	if ext != ".json" {
-		return fmt.Errorf("Failed to import payloads, only JSON files are acceppted. Given file: %s", uri)
+	if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+		return fmt.Errorf("Failed to import payloads, only JSON files or OpenAPI YAML files are acceppted. Given file: %s", uri)
 	}
 	// checking whether it exists
 	exists, err := exists(uri)
@@ -68,14 +69,12 @@ func (hf *Hoverfly) ImportFromDisk(path string) error {
 	}
 	defer pairsFile.Close()
 
-	var simulation v2.SimulationViewV5
-
 	body, err := io.ReadAll(pairsFile)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
 
-	err = json.Unmarshal(body, &simulation)
+	simulation, err := newSimulationView(body)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
@@ -93,14 +92,12 @@ func (hf *Hoverfly) ImportFromURL(url string) error {
 		return fmt.Errorf("Failed to fetch given URL, error %s", err.Error())
 	}
 
-	var simulation v2.SimulationViewV5
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
 
-	err = json.Unmarshal(body, &simulation)
+	simulation, err := newSimulationView(body)
 	if err != nil {
 		return fmt.Errorf("Got error while parsing payloads, error %s", err.Error())
 	}
@@ -108,6 +105,17 @@ func (hf *Hoverfly) ImportFromURL(url string) error {
 	return hf.PutSimulation(simulation).GetError()
 }
 
+// newSimulationView reads a simulation, or generates one if given an OpenAPI 3 document
+func newSimulationView(body []byte) (v2.SimulationViewV5, error) {
+	if openapi.IsDocument(body) {
+		return openapi.NewSimulation(body)
+	}
+
+	var simulation v2.SimulationViewV5
+	err := json.Unmarshal(body, &simulation)
+	return simulation, err
+}
+
 // importRequestResponsePairViews along with custom data - a function to save given pairs into the database. custom data is loaded before request/response pair so that in case someone access it, it ll be able to render
 func (hf *Hoverfly) importRequestResponsePairViewsWithCustomData(pairViews []v2.RequestMatcherResponsePairViewV5, literals []v2.GlobalLiteralViewV5, variables []v2.GlobalVariableViewV5) v2.SimulationImportResult {
 	importResult := v2.SimulationImportResult{}
@@ -124,13 +132,47 @@ func (hf *Hoverfly) importRequestResponsePairViewsWithCustomData(pairViews []v2.
 		failed := 0
 		for i, pairView := range pairViews {
 
-			if _, ok := hf.PostServeActionDetails.Actions[pairView.Response.PostServeAction]; pairView.Response.PostServeAction != "" && !ok && hf.PostServeActionDetails.FallbackAction == nil {
+			if !hf.isPostServeActionAvailable(pairView.Response.PostServeAction) {
+				importResult.SetError(fmt.Errorf("invalid post server action name provided"))
+				break
+			}
+
+			if !models.IsValidResponsesMode(pairView.ResponsesMode) {
+				importResult.SetError(fmt.Errorf("data.pairs[%d] has an invalid responsesMode %s, it should be sequence, cycle or random", i, pairView.ResponsesMode))
+				break
+			}
+
+			invalidWeight := -1
+			for j, response := range pairView.Responses {
+				if response.Weight < 0 {
+					invalidWeight = j
+					break
+				}
+			}
+			if invalidWeight >= 0 {
+				importResult.SetError(fmt.Errorf("data.pairs[%d].responses[%d] has an invalid weight %d, it should be 1 or more", i, invalidWeight, pairView.Responses[invalidWeight].Weight))
+				break
+			}
+
+			invalidPostServeAction := false
+			for _, response := range pairView.Responses {
+				if !hf.isPostServeActionAvailable(response.PostServeAction) {
+					invalidPostServeAction = true
+				}
+			}
+			if invalidPostServeAction {
 				importResult.SetError(fmt.Errorf("invalid post server action name provided"))
 				break
 			}
 
 			pair := models.NewRequestMatcherResponsePairFromView(&pairView)
 
+			if err := validateResponseFaults(pair); err != nil {
+				failed++
+				importResult.SetError(fmt.Errorf("data.pairs[%d] %s", i, err.Error()))
+				break
+			}
+
 			if pairView.Response.LogNormalDelay != nil {
 				d := *pairView.Response.LogNormalDelay
 				if err := delay.ValidateLogNormalDelayOptions(d.Min, d.Max, d.Mean, d.Median); err != nil {
@@ -186,3 +228,40 @@ func (hf *Hoverfly) importRequestResponsePairViewsWithCustomData(pairViews []v2.
 
 	return importResult
 }
+
+func (hf *Hoverfly) isPostServeActionAvailable(postServeAction string) bool {
+	if postServeAction == "" || hf.PostServeActionDetails.FallbackAction != nil {
+		return true
+	}
+	_, ok := hf.PostServeActionDetails.Actions[postServeAction]
+	return ok
+}
+
+func validateResponseFaults(pair *models.RequestMatcherResponsePair) error {
+	responses := append([]models.ResponseDetails{pair.Response}, pair.Responses...)
+	for _, response := range responses {
+		if response.Fault == nil {
+			continue
+		}
+		if err := response.Fault.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (hf *Hoverfly) importWebSocketPairViews(pairViews []v2.WebSocketPairViewV5) {
+	success := 0
+	for _, pairView := range pairViews {
+		if hf.Simulation.AddWebSocketPair(models.NewWebSocketPairFromView(&pairView)) {
+			success++
+		}
+	}
+
+	if len(pairViews) > 0 {
+		log.WithFields(log.Fields{
+			"total":      len(pairViews),
+			"successful": success,
+		}).Info("WebSocket pairs imported")
+	}
+}
@@ -2,6 +2,7 @@ package hoverfly
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -93,6 +94,29 @@ func TestImportFromDisk(t *testing.T) {
 	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(2))
 }
 
+func TestImportFromDiskOpenAPI(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.Import("openapi/testdata/petstore.yaml")
+	Expect(err).To(BeNil())
+	Expect(unit.Simulation.GetMatchingPairs()).To(HaveLen(5))
+
+	response, matchingErr := unit.GetResponse(models.RequestDetails{
+		Method: "GET",
+		Path:   "/v1/pets/7",
+	})
+	Expect(matchingErr).To(BeNil())
+	Expect(response.Status).To(Equal(200))
+
+	var pet map[string]interface{}
+	Expect(json.Unmarshal([]byte(response.Body), &pet)).To(Succeed())
+	Expect(pet["status"]).To(Equal("available"))
+	Expect(pet["id"]).To(BeNumerically(">=", 1))
+	Expect(pet["name"]).ToNot(BeEmpty())
+}
+
 func TestImportFromDiskBlankPath(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -241,6 +265,7 @@ func TestImportRequestResponsePairs_CanImportASinglePair(t *testing.T) {
 	Expect(result.WarningMessages).To(HaveLen(0))
 
 	Expect(hv.Simulation.GetMatchingPairs()[0]).To(Equal(models.RequestMatcherResponsePair{
+		Id: hv.Simulation.GetMatchingPairs()[0].Id,
 		Response: models.ResponseDetails{
 			Status:    200,
 			Body:      "hello_world",
@@ -368,6 +393,7 @@ func TestImportImportRequestResponsePairs_CanImportAMultiplePairsAndSetTemplateE
 
 	Expect(hv.Simulation.GetMatchingPairs()).To(HaveLen(3))
 	Expect(hv.Simulation.GetMatchingPairs()[0]).To(Equal(models.RequestMatcherResponsePair{
+		Id: hv.Simulation.GetMatchingPairs()[0].Id,
 		Response: models.ResponseDetails{
 			Status:    200,
 			Body:      "hello_world",
@@ -417,6 +443,7 @@ func TestImportImportRequestResponsePairs_CanImportAMultiplePairsAndSetTemplateE
 	}))
 
 	Expect(hv.Simulation.GetMatchingPairs()[1]).To(Equal(models.RequestMatcherResponsePair{
+		Id: hv.Simulation.GetMatchingPairs()[1].Id,
 		Response: models.ResponseDetails{
 			Status:    200,
 			Body:      "hello_world",
@@ -466,6 +493,7 @@ func TestImportImportRequestResponsePairs_CanImportAMultiplePairsAndSetTemplateE
 	}))
 
 	Expect(hv.Simulation.GetMatchingPairs()[2]).To(Equal(models.RequestMatcherResponsePair{
+		Id: hv.Simulation.GetMatchingPairs()[2].Id,
 		Response: models.ResponseDetails{
 			Status:    200,
 			Body:      "hello_world",
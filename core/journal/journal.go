@@ -13,7 +13,9 @@ import (
 
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/redaction"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 )
@@ -28,6 +30,7 @@ type JournalEntry struct {
 	Latency              time.Duration
 	Id                   string
 	PostServeActionEntry *PostServeActionEntry
+	WebSocketMessages    []models.WebSocketMessage
 }
 
 type PostServeActionEntry struct {
@@ -43,7 +46,10 @@ type Journal struct {
 	Indexes       []Index
 	EntryLimit    int
 	BodySizeLimit util.MemorySize
-	mutex         sync.Mutex
+	redactor      *redaction.Redactor
+	// CustomMatchers are those of the simulation, which filters and verifications can use as well
+	CustomMatchers *matchers.CustomMatchers
+	mutex          sync.Mutex
 }
 
 func NewJournal() *Journal {
@@ -54,6 +60,19 @@ func NewJournal() *Journal {
 	}
 }
 
+// SetRedactor sets the redactor applied to the requests and responses of new entries, nil to redact nothing
+func (this *Journal) SetRedactor(redactor *redaction.Redactor) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	this.redactor = redactor
+}
+
+func (this *Journal) GetRedactor() *redaction.Redactor {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.redactor
+}
+
 func (this *Journal) AddIndex(indexKey string) error {
 
 	this.mutex.Lock()
@@ -99,6 +118,15 @@ func (this *Journal) GetAllIndexes() []v2.JournalIndexView {
 }
 
 func (this *Journal) NewEntry(request *http.Request, response *http.Response, mode string, started time.Time) (string, error) {
+	return this.newEntry(request, response, nil, mode, started)
+}
+
+// NewWebSocketEntry records a WebSocket connection along with the messages exchanged on it
+func (this *Journal) NewWebSocketEntry(request *http.Request, response *http.Response, messages []models.WebSocketMessage, mode string, started time.Time) (string, error) {
+	return this.newEntry(request, response, messages, mode, started)
+}
+
+func (this *Journal) newEntry(request *http.Request, response *http.Response, messages []models.WebSocketMessage, mode string, started time.Time) (string, error) {
 	if this.EntryLimit == 0 {
 		return "", fmt.Errorf("Journal disabled")
 	}
@@ -118,18 +146,35 @@ func (this *Journal) NewEntry(request *http.Request, response *http.Response, mo
 		Headers: response.Header,
 	}
 
+	if redactor := this.GetRedactor(); redactor != nil {
+		payloadRequest = redactor.Request(payloadRequest)
+		*payloadResponse = redactor.Response(*payloadResponse)
+
+		if messages != nil {
+			redactedMessages := make([]models.WebSocketMessage, len(messages))
+			for i, message := range messages {
+				if message.Type == models.WebSocketText {
+					message.Data = redactor.Body(message.Data)
+				}
+				redactedMessages[i] = message
+			}
+			messages = redactedMessages
+		}
+	}
+
 	this.mutex.Lock()
 	if len(this.entries) >= this.EntryLimit {
 		this.entries = append(this.entries[:0], this.entries[1:]...)
 	}
 
 	entry := JournalEntry{
-		Request:     &payloadRequest,
-		Response:    payloadResponse,
-		Mode:        mode,
-		TimeStarted: started,
-		Latency:     time.Since(started),
-		Id:          util.RandStringFromTimestamp(15),
+		Request:           &payloadRequest,
+		Response:          payloadResponse,
+		Mode:              mode,
+		TimeStarted:       started,
+		Latency:           time.Since(started),
+		Id:                util.RandStringFromTimestamp(15),
+		WebSocketMessages: messages,
 	}
 
 	this.entries = append(this.entries, entry)
@@ -159,6 +204,14 @@ func (this *Journal) NewEntry(request *http.Request, response *http.Response, mo
 	return entry.Id, nil
 }
 
+// Count returns the number of entries held in the journal
+func (this *Journal) Count() int {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	return len(this.entries)
+}
+
 func (this *Journal) GetEntries(offset int, limit int, from *time.Time, to *time.Time, sort string) (v2.JournalView, error) {
 
 	journalView := v2.JournalView{
@@ -236,15 +289,7 @@ func (this *Journal) GetFilteredEntries(journalEntryFilterView v2.JournalEntryFi
 		return filteredEntries, fmt.Errorf("Journal disabled")
 	}
 
-	requestMatcher := models.RequestMatcher{
-		Path:        models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Path),
-		Method:      models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Method),
-		Destination: models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Destination),
-		Scheme:      models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Scheme),
-		Body:        models.NewRequestFieldMatchersFromView(journalEntryFilterView.Request.Body),
-		Query:       models.NewQueryRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Query),
-		Headers:     models.NewRequestFieldMatchersFromMapView(journalEntryFilterView.Request.Headers),
-	}
+	requestMatcher := newRequestMatcherFromView(journalEntryFilterView.Request)
 
 	for _, entry := range this.entries {
 		if requestMatcher.Body == nil && requestMatcher.Destination == nil &&
@@ -254,25 +299,7 @@ func (this *Journal) GetFilteredEntries(journalEntryFilterView v2.JournalEntryFi
 			continue
 		}
 
-		if !matching.BodyMatching(requestMatcher.Body, *entry.Request).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Destination, entry.Request.Destination).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Method, entry.Request.Method).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Path, entry.Request.Path).Matched {
-			continue
-		}
-		if !matching.FieldMatcher(requestMatcher.Scheme, entry.Request.Scheme).Matched {
-			continue
-		}
-		if !matching.QueryMatching(requestMatcher, entry.Request.Query).Matched {
-			continue
-		}
-		if !matching.HeaderMatching(requestMatcher, entry.Request.Headers).Matched {
+		if _, missedFields := matchRequest(requestMatcher, *entry.Request, this.CustomMatchers); len(missedFields) > 0 {
 			continue
 		}
 		filteredEntries = append(filteredEntries, convertJournalEntry(entry))
@@ -281,6 +308,46 @@ func (this *Journal) GetFilteredEntries(journalEntryFilterView v2.JournalEntryFi
 	return filteredEntries, nil
 }
 
+func newRequestMatcherFromView(view *v2.RequestMatcherViewV5) models.RequestMatcher {
+	return models.RequestMatcher{
+		Path:        models.NewRequestFieldMatchersFromView(view.Path),
+		Method:      models.NewRequestFieldMatchersFromView(view.Method),
+		Destination: models.NewRequestFieldMatchersFromView(view.Destination),
+		Scheme:      models.NewRequestFieldMatchersFromView(view.Scheme),
+		Body:        models.NewRequestFieldMatchersFromView(view.Body),
+		Query:       models.NewQueryRequestFieldMatchersFromMapView(view.Query),
+		Headers:     models.NewRequestFieldMatchersFromMapView(view.Headers),
+	}
+}
+
+// matchRequest matches a recorded request against a request matcher, returning the score of the fields
+// which matched and the names of the ones which did not
+func matchRequest(requestMatcher models.RequestMatcher, request models.RequestDetails, customMatchers *matchers.CustomMatchers) (int, []string) {
+	fieldMatches := []struct {
+		field string
+		match *matching.FieldMatch
+	}{
+		{"body", matching.BodyMatching(requestMatcher.Body, request, customMatchers)},
+		{"destination", matching.FieldMatcher(requestMatcher.Destination, request.Destination, customMatchers)},
+		{"method", matching.FieldMatcher(requestMatcher.Method, request.Method, customMatchers)},
+		{"path", matching.FieldMatcher(requestMatcher.Path, request.Path, customMatchers)},
+		{"scheme", matching.FieldMatcher(requestMatcher.Scheme, request.Scheme, customMatchers)},
+		{"query", matching.QueryMatching(requestMatcher, request.Query, customMatchers)},
+		{"headers", matching.HeaderMatching(requestMatcher, request.Headers, customMatchers)},
+	}
+
+	score := 0
+	missedFields := []string{}
+	for _, fieldMatch := range fieldMatches {
+		if fieldMatch.match.Matched {
+			score += fieldMatch.match.Score
+		} else {
+			missedFields = append(missedFields, fieldMatch.field)
+		}
+	}
+	return score, missedFields
+}
+
 func (this *Journal) DeleteEntries() error {
 	if this.EntryLimit == 0 {
 		return fmt.Errorf("Journal disabled")
@@ -318,6 +385,7 @@ func convertJournalEntries(entries []JournalEntry) []v2.JournalEntryView {
 			TimeStarted:          journalEntry.TimeStarted.Format(RFC3339Milli),
 			Latency:              journalEntry.Latency.Seconds() * 1e3,
 			Id:                   journalEntry.Id,
+			WebSocketMessages:    convertWebSocketMessages(journalEntry.WebSocketMessages),
 		})
 	}
 
@@ -347,7 +415,16 @@ func convertJournalEntry(entry JournalEntry) v2.JournalEntryView {
 		TimeStarted:          entry.TimeStarted.Format(RFC3339Milli),
 		Latency:              entry.Latency.Seconds() * 1e3,
 		PostServeActionEntry: getPostServeActionEntryView(entry.PostServeActionEntry),
+		WebSocketMessages:    convertWebSocketMessages(entry.WebSocketMessages),
+	}
+}
+
+func convertWebSocketMessages(messages []models.WebSocketMessage) []v2.WebSocketMessageView {
+	var views []v2.WebSocketMessageView
+	for _, message := range messages {
+		views = append(views, message.BuildView())
 	}
+	return views
 }
 
 func getSortParameters(sort string) (string, string, error) {
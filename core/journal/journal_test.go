@@ -15,6 +15,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/journal"
 	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/redaction"
 	. "github.com/onsi/gomega"
 )
 
@@ -113,6 +114,66 @@ func Test_Journal_NewEntryWithMemoryLimit_TruncateBody(t *testing.T) {
 	Expect(entries[0].Response.Body).To(Equal("large respon..."))
 }
 
+func Test_Journal_NewEntryWithRedactor_RedactsRequestAndResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	redactor, _ := redaction.NewRedactor([]v2.RedactionRuleView{
+		{Header: "Authorization"},
+		{Query: "apiKey"},
+		{Regex: "secret-[0-9]+"},
+	})
+	unit.SetRedactor(redactor)
+
+	request, _ := http.NewRequest("GET", "http://hoverfly.io/path?apiKey=123", nil)
+	request.Header.Set("Authorization", "Bearer abc")
+
+	responseHeader := http.Header{"Authorization": []string{"Bearer def"}}
+	_, err := unit.NewEntry(request, &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString("your key is secret-42")),
+		Header:     responseHeader,
+	}, "capture", time.Now())
+	Expect(err).To(BeNil())
+
+	journalView, err := unit.GetEntries(0, 25, nil, nil, "")
+	Expect(err).To(BeNil())
+	entries := journalView.Journal
+
+	Expect(entries[0].Request.Headers["Authorization"]).To(Equal([]string{"[REDACTED]"}))
+	Expect(*entries[0].Request.Query).To(Equal("apiKey=[REDACTED]"))
+	Expect(entries[0].Response.Body).To(Equal("your key is [REDACTED]"))
+	Expect(entries[0].Response.Headers["Authorization"]).To(Equal([]string{"[REDACTED]"}))
+
+	Expect(responseHeader["Authorization"]).To(Equal([]string{"Bearer def"}))
+}
+
+func Test_Journal_SetRedactor_IsSafeToRunAlongsideNewEntries(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	redactor, _ := redaction.NewRedactor([]v2.RedactionRuleView{{Header: "Authorization"}})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			unit.SetRedactor(redactor)
+			unit.SetRedactor(nil)
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		request, _ := http.NewRequest("GET", "http://hoverfly.io/path", nil)
+		_, err := unit.NewEntry(request, &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString("body")),
+		}, "capture", time.Now())
+		Expect(err).To(BeNil())
+	}
+	<-done
+}
+
 func Test_Journal_UpdateEntry_AddsRemotePostServeActionToJournalEntry(t *testing.T) {
 	RegisterTestingT(t)
 
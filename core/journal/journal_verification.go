@@ -0,0 +1,154 @@
+package journal
+
+import (
+	"fmt"
+	sorting "sort"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+const closestMissesLimit = 3
+
+// VerifyRequests checks the requests in the journal against an expectation. When it fails because requests
+// are missing, the entries which came closest to matching are returned along with the fields they missed on.
+func (this *Journal) VerifyRequests(verificationView v2.RequestVerificationView) (v2.RequestVerificationResultView, error) {
+	if this.EntryLimit == 0 {
+		return v2.RequestVerificationResultView{}, fmt.Errorf("Journal disabled")
+	}
+
+	this.mutex.Lock()
+	entries := append([]JournalEntry{}, this.entries...)
+	this.mutex.Unlock()
+
+	if len(verificationView.InOrder) > 0 {
+		return verifyRequestsInOrder(entries, verificationView.InOrder, this.CustomMatchers), nil
+	}
+
+	requestMatcher := newRequestMatcherFromView(verificationView.Request)
+
+	var misses []JournalEntry
+	count := 0
+	for _, entry := range entries {
+		if _, missedFields := matchRequest(requestMatcher, *entry.Request, this.CustomMatchers); len(missedFields) == 0 {
+			count++
+		} else {
+			misses = append(misses, entry)
+		}
+	}
+
+	atLeast, atMost, expectation := expectedCount(verificationView)
+
+	result := v2.RequestVerificationResultView{
+		Passed: count >= atLeast && (atMost < 0 || count <= atMost),
+		Count:  count,
+	}
+
+	if result.Passed {
+		result.Message = fmt.Sprintf("Received %s, as expected", pluralRequests(count))
+	} else {
+		result.Message = fmt.Sprintf("Expected %s but received %s", expectation, pluralRequests(count))
+	}
+
+	if count < atLeast {
+		result.ClosestMisses = closestMisses(requestMatcher, misses, this.CustomMatchers)
+	}
+
+	return result, nil
+}
+
+// expectedCount returns the bounds on the number of matching requests, where an upper bound of -1 means
+// there is none, along with a description of the expectation
+func expectedCount(verificationView v2.RequestVerificationView) (int, int, string) {
+	switch {
+	case verificationView.Never:
+		return 0, 0, "no matching requests"
+	case verificationView.Exactly != nil:
+		return *verificationView.Exactly, *verificationView.Exactly, "exactly " + pluralRequests(*verificationView.Exactly)
+	case verificationView.AtLeast != nil && verificationView.AtMost != nil:
+		return *verificationView.AtLeast, *verificationView.AtMost,
+			fmt.Sprintf("between %d and %d matching requests", *verificationView.AtLeast, *verificationView.AtMost)
+	case verificationView.AtMost != nil:
+		return 0, *verificationView.AtMost, "at most " + pluralRequests(*verificationView.AtMost)
+	case verificationView.AtLeast != nil:
+		return *verificationView.AtLeast, -1, "at least " + pluralRequests(*verificationView.AtLeast)
+	}
+
+	return 1, -1, "at least 1 matching request"
+}
+
+// verifyRequestsInOrder checks that each request was received after the one before it, with any other
+// requests allowed in between
+func verifyRequestsInOrder(entries []JournalEntry, requestMatcherViews []v2.RequestMatcherViewV5, customMatchers *matchers.CustomMatchers) v2.RequestVerificationResultView {
+	next := 0
+	remaining := entries
+	for position := 0; position < len(entries) && next < len(requestMatcherViews); position++ {
+		requestMatcher := newRequestMatcherFromView(&requestMatcherViews[next])
+		if _, missedFields := matchRequest(requestMatcher, *entries[position].Request, customMatchers); len(missedFields) == 0 {
+			next++
+			remaining = entries[position+1:]
+		}
+	}
+
+	if next == len(requestMatcherViews) {
+		return v2.RequestVerificationResultView{
+			Passed:  true,
+			Message: fmt.Sprintf("Received %d requests in the expected order", len(requestMatcherViews)),
+			Count:   next,
+		}
+	}
+
+	message := fmt.Sprintf("Expected %d requests in order but request %d was not received", len(requestMatcherViews), next+1)
+	if next > 0 {
+		message += fmt.Sprintf(" after request %d", next)
+	}
+
+	return v2.RequestVerificationResultView{
+		Passed:        false,
+		Message:       message,
+		Count:         next,
+		ClosestMisses: closestMisses(newRequestMatcherFromView(&requestMatcherViews[next]), remaining, customMatchers),
+	}
+}
+
+func closestMisses(requestMatcher models.RequestMatcher, entries []JournalEntry, customMatchers *matchers.CustomMatchers) []v2.JournalClosestMissView {
+	type scoredMiss struct {
+		entry        JournalEntry
+		score        int
+		missedFields []string
+	}
+
+	var scoredMisses []scoredMiss
+	for _, entry := range entries {
+		score, missedFields := matchRequest(requestMatcher, *entry.Request, customMatchers)
+		if len(missedFields) > 0 {
+			scoredMisses = append(scoredMisses, scoredMiss{entry, score, missedFields})
+		}
+	}
+
+	sorting.SliceStable(scoredMisses, func(i, j int) bool {
+		if len(scoredMisses[i].missedFields) != len(scoredMisses[j].missedFields) {
+			return len(scoredMisses[i].missedFields) < len(scoredMisses[j].missedFields)
+		}
+		return scoredMisses[i].score > scoredMisses[j].score
+	})
+
+	misses := []v2.JournalClosestMissView{}
+	for i := 0; i < len(scoredMisses) && i < closestMissesLimit; i++ {
+		misses = append(misses, v2.JournalClosestMissView{
+			Id:           scoredMisses[i].entry.Id,
+			TimeStarted:  scoredMisses[i].entry.TimeStarted.Format(RFC3339Milli),
+			Request:      scoredMisses[i].entry.Request.ConvertToRequestDetailsView(),
+			MissedFields: scoredMisses[i].missedFields,
+		})
+	}
+	return misses
+}
+
+func pluralRequests(count int) string {
+	if count == 1 {
+		return "1 matching request"
+	}
+	return fmt.Sprintf("%d matching requests", count)
+}
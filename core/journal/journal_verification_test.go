@@ -0,0 +1,171 @@
+package journal_test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func newVerificationJournal(urls ...string) *journal.Journal {
+	unit := journal.NewJournal()
+	for _, requestUrl := range urls {
+		request, _ := http.NewRequest("GET", requestUrl, bytes.NewBufferString(""))
+		unit.NewEntry(request, &http.Response{
+			StatusCode: 200,
+			Body:       io.NopCloser(bytes.NewBufferString("test body")),
+		}, "test-mode", time.Now())
+	}
+	return unit
+}
+
+func pathMatcherView(path string) *v2.RequestMatcherViewV5 {
+	return &v2.RequestMatcherViewV5{
+		Path: []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: path}},
+	}
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+func Test_Journal_VerifyRequests_PassesWhenAtLeastOneRequestMatchesByDefault(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal("http://hoverfly.io/one", "http://hoverfly.io/two")
+
+	result, err := unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/two")})
+	Expect(err).To(BeNil())
+
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Count).To(Equal(1))
+	Expect(result.Message).To(Equal("Received 1 matching request, as expected"))
+	Expect(result.ClosestMisses).To(BeEmpty())
+}
+
+func Test_Journal_VerifyRequests_ChecksExactCount(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal("http://hoverfly.io/one", "http://hoverfly.io/one", "http://hoverfly.io/two")
+
+	result, err := unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), Exactly: intPointer(2)})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeTrue())
+
+	result, err = unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), Exactly: intPointer(3)})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Count).To(Equal(2))
+	Expect(result.Message).To(Equal("Expected exactly 3 matching requests but received 2 matching requests"))
+	Expect(result.ClosestMisses).To(HaveLen(1))
+	Expect(*result.ClosestMisses[0].Request.Path).To(Equal("/two"))
+	Expect(result.ClosestMisses[0].MissedFields).To(ConsistOf("path"))
+
+	result, err = unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), Exactly: intPointer(1)})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.ClosestMisses).To(BeEmpty())
+}
+
+func Test_Journal_VerifyRequests_ChecksBounds(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal("http://hoverfly.io/one", "http://hoverfly.io/one")
+
+	result, _ := unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), AtLeast: intPointer(1), AtMost: intPointer(2)})
+	Expect(result.Passed).To(BeTrue())
+
+	result, _ = unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), AtMost: intPointer(1)})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Expected at most 1 matching request but received 2 matching requests"))
+
+	result, _ = unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), AtLeast: intPointer(3)})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Expected at least 3 matching requests but received 2 matching requests"))
+}
+
+func Test_Journal_VerifyRequests_ChecksNever(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal("http://hoverfly.io/one")
+
+	result, _ := unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/two"), Never: true})
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Message).To(Equal("Received 0 matching requests, as expected"))
+
+	result, _ = unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one"), Never: true})
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Message).To(Equal("Expected no matching requests but received 1 matching request"))
+}
+
+func Test_Journal_VerifyRequests_OrdersClosestMissesByFewestMissedFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal(
+		"https://other.io/one",
+		"http://hoverfly.io/two",
+		"https://other.io/two",
+		"http://other.io/three",
+		"http://other.io/four",
+	)
+
+	result, err := unit.VerifyRequests(v2.RequestVerificationView{
+		Request: &v2.RequestMatcherViewV5{
+			Scheme:      []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "http"}},
+			Destination: []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "hoverfly.io"}},
+			Path:        []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "/one"}},
+		},
+	})
+	Expect(err).To(BeNil())
+
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.ClosestMisses).To(HaveLen(3))
+	Expect(result.ClosestMisses[0].MissedFields).To(ConsistOf("path"))
+	Expect(*result.ClosestMisses[0].Request.Destination).To(Equal("hoverfly.io"))
+	Expect(result.ClosestMisses[1].MissedFields).To(ConsistOf("scheme", "destination"))
+	Expect(*result.ClosestMisses[1].Request.Path).To(Equal("/one"))
+	Expect(result.ClosestMisses[2].MissedFields).To(ConsistOf("destination", "path"))
+	Expect(result.ClosestMisses[0].Id).ToNot(BeEmpty())
+	Expect(result.ClosestMisses[0].TimeStarted).ToNot(BeEmpty())
+}
+
+func Test_Journal_VerifyRequests_ChecksOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newVerificationJournal("http://hoverfly.io/login", "http://hoverfly.io/other", "http://hoverfly.io/basket", "http://hoverfly.io/checkout")
+
+	result, err := unit.VerifyRequests(v2.RequestVerificationView{
+		InOrder: []v2.RequestMatcherViewV5{*pathMatcherView("/login"), *pathMatcherView("/basket"), *pathMatcherView("/checkout")},
+	})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeTrue())
+	Expect(result.Count).To(Equal(3))
+	Expect(result.Message).To(Equal("Received 3 requests in the expected order"))
+
+	result, err = unit.VerifyRequests(v2.RequestVerificationView{
+		InOrder: []v2.RequestMatcherViewV5{*pathMatcherView("/basket"), *pathMatcherView("/login")},
+	})
+	Expect(err).To(BeNil())
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Count).To(Equal(1))
+	Expect(result.Message).To(Equal("Expected 2 requests in order but request 2 was not received after request 1"))
+	Expect(result.ClosestMisses).To(HaveLen(1))
+	Expect(*result.ClosestMisses[0].Request.Path).To(Equal("/checkout"))
+}
+
+func Test_Journal_VerifyRequests_ReturnsErrorWhenJournalIsDisabled(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := journal.NewJournal()
+	unit.EntryLimit = 0
+
+	_, err := unit.VerifyRequests(v2.RequestVerificationView{Request: pathMatcherView("/one")})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Journal disabled"))
+}
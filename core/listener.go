@@ -1,8 +1,12 @@
 package hoverfly
 
 import (
+	"bufio"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -59,3 +63,62 @@ func (sl *StoppableListener) Accept() (net.Conn, error) {
 func (sl *StoppableListener) Stop() {
 	close(sl.stop)
 }
+
+// TLS record type of a handshake, which is the first byte a client sends when starting TLS
+const tlsHandshakeRecordType = 0x16
+
+// peekedConn - connection which can be inspected before it is read from
+type peekedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func newPeekedConn(conn net.Conn) *peekedConn {
+	return &peekedConn{
+		Conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+func (c *peekedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+// singleConnListener - listener which accepts an already established connection once,
+// so that a hijacked connection can be served by http.Server
+type singleConnListener struct {
+	conn net.Conn
+	once sync.Once
+}
+
+func newSingleConnListener(conn net.Conn) *singleConnListener {
+	return &singleConnListener{conn: conn}
+}
+
+func (l *singleConnListener) Accept() (net.Conn, error) {
+	var conn net.Conn
+	l.once.Do(func() {
+		conn = l.conn
+	})
+	if conn == nil {
+		return nil, io.EOF
+	}
+	return conn, nil
+}
+
+func (l *singleConnListener) Close() error {
+	return nil
+}
+
+func (l *singleConnListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// newServerProtocols enables HTTP/2 alongside HTTP/1, both over TLS and as h2c with prior knowledge
+func newServerProtocols() *http.Protocols {
+	protocols := new(http.Protocols)
+	protocols.SetHTTP1(true)
+	protocols.SetHTTP2(true)
+	protocols.SetUnencryptedHTTP2(true)
+	return protocols
+}
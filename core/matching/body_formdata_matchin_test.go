@@ -93,13 +93,101 @@ var bodyMatchingTests = []bodyMatchingTest{
 		},
 		equals: BeFalse(),
 	},
+	{
+		name: "MatchesTrueWithMultipartMatchOnValueFilenameContentTypeAndHeaders",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: "multipart",
+				Value: map[string]models.MultipartPartMatchers{
+					"name": {
+						Value: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "foo"}},
+					},
+					"file": {
+						Value:       []models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "hello*"}},
+						Filename:    []models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "*.txt"}},
+						ContentType: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "text/plain"}},
+						Headers: map[string][]models.RequestFieldMatchers{
+							"X-Part": {{Matcher: matchers.Exact, Value: "yes"}},
+						},
+					},
+				},
+			},
+		},
+		toMatch: models.RequestDetails{
+			Headers: map[string][]string{"Content-Type": {"multipart/form-data; boundary=xyz"}},
+			Body:    multipartBody,
+		},
+		equals:      BeTrue(),
+		matchEquals: BeNumerically(">", 0),
+	},
+	{
+		name: "MatchesTrueWithMultipartMatchWithoutContentType",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: "multipart",
+				Value: map[string]models.MultipartPartMatchers{
+					"name": {
+						Value: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "foo"}},
+					},
+				},
+			},
+		},
+		toMatch: models.RequestDetails{
+			Body: multipartBody,
+		},
+		equals: BeTrue(),
+	},
+	{
+		name: "MatchesFalseWithMultipartMatchOnFilename",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: "multipart",
+				Value: map[string]models.MultipartPartMatchers{
+					"file": {
+						Filename: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "other.txt"}},
+					},
+				},
+			},
+		},
+		toMatch: models.RequestDetails{
+			Headers: map[string][]string{"Content-Type": {"multipart/form-data; boundary=xyz"}},
+			Body:    multipartBody,
+		},
+		equals: BeFalse(),
+	},
+	{
+		name: "MatchesFalseWithMultipartMatchOnMissingPart",
+		matchers: []models.RequestFieldMatchers{
+			{
+				Matcher: "multipart",
+				Value: map[string]models.MultipartPartMatchers{
+					"missing": {},
+				},
+			},
+		},
+		toMatch: models.RequestDetails{
+			Headers: map[string][]string{"Content-Type": {"multipart/form-data; boundary=xyz"}},
+			Body:    multipartBody,
+		},
+		equals: BeFalse(),
+	},
 }
 
+var multipartBody = "--xyz\r\n" +
+	"Content-Disposition: form-data; name=\"name\"\r\n\r\n" +
+	"foo\r\n" +
+	"--xyz\r\n" +
+	"Content-Disposition: form-data; name=\"file\"; filename=\"hello.txt\"\r\n" +
+	"Content-Type: text/plain\r\n" +
+	"X-Part: yes\r\n\r\n" +
+	"hello world\r\n" +
+	"--xyz--\r\n"
+
 func Test_BodyMatching(t *testing.T) {
 	RegisterTestingT(t)
 
 	for _, test := range bodyMatchingTests {
-		result := matching.BodyMatching(test.matchers, test.toMatch)
+		result := matching.BodyMatching(test.matchers, test.toMatch, nil)
 
 		Expect(result.Matched).To(test.equals, test.name)
 		if test.matchEquals != nil {
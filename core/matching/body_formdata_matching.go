@@ -1,10 +1,12 @@
 package matching
 
 import (
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/util"
 )
 
-func BodyMatching(fields []models.RequestFieldMatchers, req models.RequestDetails) *FieldMatch {
+func BodyMatching(fields []models.RequestFieldMatchers, req models.RequestDetails, customMatchers *matchers.CustomMatchers) *FieldMatch {
 
 	matched := true
 	hasForm := false
@@ -18,15 +20,23 @@ func BodyMatching(fields []models.RequestFieldMatchers, req models.RequestDetail
 		if field.Matcher == "form" {
 			hasForm = true
 			formMatchers := field.Value.(map[string][]models.RequestFieldMatchers)
-			formMatched := processFormMatcher(formMatchers, req.FormData)
+			formMatched := processFormMatcher(formMatchers, req.FormData, customMatchers)
 			if !formMatched.Matched {
 				matched = false
 			}
 			score += formMatched.Score
+		} else if field.Matcher == "multipart" {
+			hasForm = true
+			partMatchers, _ := field.Value.(map[string]models.MultipartPartMatchers)
+			multipartMatched := processMultipartMatcher(partMatchers, req, customMatchers)
+			if !multipartMatched.Matched {
+				matched = false
+			}
+			score += multipartMatched.Score
 		}
 	}
 	if !hasForm {
-		bodyMatched := FieldMatcher(fields, req.Body)
+		bodyMatched := FieldMatcher(fields, req.Body, customMatchers)
 		if !bodyMatched.Matched {
 			matched = false
 		}
@@ -39,7 +49,7 @@ func BodyMatching(fields []models.RequestFieldMatchers, req models.RequestDetail
 	}
 }
 
-func processFormMatcher(formFields map[string][]models.RequestFieldMatchers, formData map[string][]string) *FieldMatch {
+func processFormMatcher(formFields map[string][]models.RequestFieldMatchers, formData map[string][]string, customMatchers *matchers.CustomMatchers) *FieldMatch {
 	matched := true
 	var score int
 
@@ -49,7 +59,7 @@ func processFormMatcher(formFields map[string][]models.RequestFieldMatchers, for
 			matched = false
 			continue
 		}
-		formMatched := FieldMatcher(formMatchers, formValue[0])
+		formMatched := FieldMatcher(formMatchers, formValue[0], customMatchers)
 		if !formMatched.Matched {
 			matched = false
 		}
@@ -61,3 +71,62 @@ func processFormMatcher(formFields map[string][]models.RequestFieldMatchers, for
 		Score:   score,
 	}
 }
+
+// processMultipartMatcher matches each named part against the parts of the body with that name. As a name can be
+// sent more than once, such as with several files, the part which matches with the highest score is used.
+func processMultipartMatcher(partMatchers map[string]models.MultipartPartMatchers, req models.RequestDetails, customMatchers *matchers.CustomMatchers) *FieldMatch {
+	contentType := ""
+	if contentTypes := req.Headers["Content-Type"]; len(contentTypes) > 0 {
+		contentType = contentTypes[0]
+	}
+	parts, _ := util.ParseMultipartBody(contentType, req.Body)
+
+	matched := true
+	var score int
+
+	for partName, partMatcher := range partMatchers {
+		var bestMatch *FieldMatch
+		for _, part := range parts {
+			if part.Name != partName {
+				continue
+			}
+			partMatched := matchMultipartPart(partMatcher, part, customMatchers)
+			if partMatched.Matched && (bestMatch == nil || partMatched.Score > bestMatch.Score) {
+				bestMatch = partMatched
+			}
+		}
+		if bestMatch == nil {
+			matched = false
+			continue
+		}
+		score += bestMatch.Score
+	}
+
+	return &FieldMatch{
+		Matched: matched,
+		Score:   score,
+	}
+}
+
+func matchMultipartPart(partMatcher models.MultipartPartMatchers, part util.MultipartPart, customMatchers *matchers.CustomMatchers) *FieldMatch {
+	fieldMatches := []*FieldMatch{
+		FieldMatcher(partMatcher.Value, part.Value, customMatchers),
+		FieldMatcher(partMatcher.Filename, part.Filename, customMatchers),
+		FieldMatcher(partMatcher.ContentType, part.ContentType, customMatchers),
+		HeaderMatching(models.RequestMatcher{Headers: partMatcher.Headers}, part.Headers, customMatchers),
+	}
+
+	matched := true
+	var score int
+	for _, fieldMatch := range fieldMatches {
+		if !fieldMatch.Matched {
+			matched = false
+		}
+		score += fieldMatch.Score
+	}
+
+	return &FieldMatch{
+		Matched: matched,
+		Score:   score,
+	}
+}
@@ -145,6 +145,9 @@ func (this *CacheMatcher) PreloadCache(simulation *models.Simulation) error {
 	}
 	cacheRequestCount := 0
 	for _, pair := range simulation.GetMatchingPairs() {
+		if pair.Disabled {
+			continue
+		}
 
 		if requestDetails := pair.RequestMatcher.ToEagerlyCacheable(); requestDetails != nil {
 			pairCopy := pair
@@ -0,0 +1,261 @@
+package matching
+
+import (
+	"sort"
+	"strings"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+// Explain scores every pair of the simulation against the request the same way as Match, reporting how each
+// field and matcher fared. The pair the strategy would respond with is marked as selected. Nothing is changed,
+// so it can be used to debug a simulation.
+func Explain(strategy string, req models.RequestDetails, webserver bool, simulation *models.Simulation, state *state.State) v2.MatchExplanationView {
+	state.RWMutex.RLock()
+	copyState := util.CopyMap(state.State)
+	state.RWMutex.RUnlock()
+
+	strongest := strings.ToLower(strategy) == "strongest"
+	explanation := v2.MatchExplanationView{
+		Strategy:   "first",
+		Candidates: []v2.MatchCandidateView{},
+	}
+	if strongest {
+		explanation.Strategy = "strongest"
+	}
+
+	selected := -1
+	for index, pair := range simulation.GetMatchingPairs() {
+		requestMatcher := pair.RequestMatcher
+		candidate := v2.MatchCandidateView{
+			Index:          index,
+			Id:             pair.Id,
+			Disabled:       pair.Disabled,
+			Labels:         pair.Labels,
+			Matched:        true,
+			RequestMatcher: pair.BuildView().RequestMatcher,
+			Fields:         []v2.FieldExplanationView{},
+		}
+
+		matchRequestFields(func(fieldMatch *FieldMatch, field string) {
+			if !fieldMatch.Matched {
+				candidate.Matched = false
+				candidate.MissedFields = append(candidate.MissedFields, field)
+			}
+			candidate.Score += fieldMatch.Score
+			candidate.Fields = append(candidate.Fields, v2.FieldExplanationView{
+				Field:    field,
+				Matched:  fieldMatch.Matched,
+				Score:    fieldMatch.Score,
+				Matchers: explainField(field, requestMatcher, req, copyState, simulation.CustomMatchers),
+			})
+		}, requestMatcher, req, webserver, copyState, simulation.CustomMatchers)
+
+		// A disabled pair is still explained, so that it can be seen whether it would have matched
+		if pair.Disabled {
+			candidate.Matched = false
+		}
+
+		explanation.Candidates = append(explanation.Candidates, candidate)
+
+		// As with the strategies, the strongest match is the last of the pairs with the highest score
+		if candidate.Matched && (selected < 0 || strongest && candidate.Score >= explanation.Candidates[selected].Score) {
+			selected = index
+		}
+	}
+
+	if selected >= 0 {
+		explanation.Matched = true
+		explanation.Candidates[selected].Selected = true
+	}
+
+	return explanation
+}
+
+func explainField(field string, requestMatcher models.RequestMatcher, req models.RequestDetails, state map[string]string, customMatchers *matchers.CustomMatchers) []v2.MatcherExplanationView {
+	switch field {
+	case "body":
+		return explainBody(requestMatcher.Body, req, customMatchers)
+	case "destination":
+		return explainMatchers("", requestMatcher.Destination, req.Destination, customMatchers)
+	case "path":
+		return explainMatchers("", requestMatcher.Path, req.Path, customMatchers)
+	case "method":
+		return explainMatchers("", requestMatcher.Method, req.Method, customMatchers)
+	case "headers":
+		return explainMapMatchers(requestMatcher.Headers, req.Headers, customMatchers)
+	case "queries":
+		if requestMatcher.Query == nil {
+			return nil
+		}
+		return explainMapMatchers(*requestMatcher.Query, req.Query, customMatchers)
+	case "state":
+		return explainState(requestMatcher.RequiresState, state)
+	}
+	return nil
+}
+
+func explainMatchers(key string, fields []models.RequestFieldMatchers, toMatch string, customMatchers *matchers.CustomMatchers) []v2.MatcherExplanationView {
+	var explanations []v2.MatcherExplanationView
+	for _, field := range fields {
+		var steps []matcherStep
+		explanation := v2.MatcherExplanationView{
+			Key:     key,
+			Matched: isMatchingWithSteps(field, toMatch, customMatchers, &steps),
+		}
+		if explanation.Matched {
+			explanation.Score = matcherScore(field)
+		}
+		for _, step := range steps {
+			explanation.Steps = append(explanation.Steps, v2.MatcherStepView{
+				Matcher: step.matcher,
+				Value:   step.value,
+				Actual:  step.actual,
+				Matched: step.matched,
+			})
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+// explainMapMatchers explains header and query matchers, where keys are not case sensitive and the values of a
+// key are joined as they are when matching
+func explainMapMatchers(fieldMatchers map[string][]models.RequestFieldMatchers, toMatch map[string][]string, customMatchers *matchers.CustomMatchers) []v2.MatcherExplanationView {
+	lowercaseKeyMap := make(map[string][]string)
+	for key, value := range toMatch {
+		lowercaseKeyMap[strings.ToLower(key)] = value
+	}
+
+	var explanations []v2.MatcherExplanationView
+	for _, key := range sortedMatcherKeys(fieldMatchers) {
+		values, found := lowercaseKeyMap[strings.ToLower(key)]
+		if !found {
+			explanations = append(explanations, v2.MatcherExplanationView{Key: key, Missing: true})
+			continue
+		}
+		explanations = append(explanations, explainMatchers(key, fieldMatchers[key], strings.Join(values, ";"), customMatchers)...)
+	}
+	return explanations
+}
+
+func explainBody(fields []models.RequestFieldMatchers, req models.RequestDetails, customMatchers *matchers.CustomMatchers) []v2.MatcherExplanationView {
+	var explanations []v2.MatcherExplanationView
+	structured := false
+
+	for _, field := range fields {
+		switch field.Matcher {
+		case "form":
+			structured = true
+			formMatchers, _ := field.Value.(map[string][]models.RequestFieldMatchers)
+			for _, key := range sortedMatcherKeys(formMatchers) {
+				formValue, found := req.FormData[key]
+				if !found {
+					explanations = append(explanations, v2.MatcherExplanationView{Key: key, Missing: true})
+					continue
+				}
+				explanations = append(explanations, explainMatchers(key, formMatchers[key], formValue[0], customMatchers)...)
+			}
+		case "multipart":
+			structured = true
+			partMatchers, _ := field.Value.(map[string]models.MultipartPartMatchers)
+			explanations = append(explanations, explainMultipart(partMatchers, req, customMatchers)...)
+		}
+	}
+
+	if !structured {
+		explanations = explainMatchers("", fields, req.Body, customMatchers)
+	}
+	return explanations
+}
+
+// explainMultipart explains the part which matched, or the first part with the name when none did
+func explainMultipart(partMatchers map[string]models.MultipartPartMatchers, req models.RequestDetails, customMatchers *matchers.CustomMatchers) []v2.MatcherExplanationView {
+	contentType := ""
+	if contentTypes := req.Headers["Content-Type"]; len(contentTypes) > 0 {
+		contentType = contentTypes[0]
+	}
+	parts, _ := util.ParseMultipartBody(contentType, req.Body)
+
+	names := make([]string, 0, len(partMatchers))
+	for name := range partMatchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var explanations []v2.MatcherExplanationView
+	for _, name := range names {
+		partMatcher := partMatchers[name]
+
+		var explained *util.MultipartPart
+		for index, part := range parts {
+			if part.Name != name {
+				continue
+			}
+			matched := matchMultipartPart(partMatcher, part, customMatchers).Matched
+			if explained == nil || matched {
+				explained = &parts[index]
+			}
+			if matched {
+				break
+			}
+		}
+		if explained == nil {
+			explanations = append(explanations, v2.MatcherExplanationView{Key: name, Missing: true})
+			continue
+		}
+
+		explanations = append(explanations, explainMatchers(name+".value", partMatcher.Value, explained.Value, customMatchers)...)
+		explanations = append(explanations, explainMatchers(name+".filename", partMatcher.Filename, explained.Filename, customMatchers)...)
+		explanations = append(explanations, explainMatchers(name+".contentType", partMatcher.ContentType, explained.ContentType, customMatchers)...)
+		for _, explanation := range explainMapMatchers(partMatcher.Headers, explained.Headers, customMatchers) {
+			explanation.Key = name + ".headers." + explanation.Key
+			explanations = append(explanations, explanation)
+		}
+	}
+	return explanations
+}
+
+func explainState(requiredState map[string]string, state map[string]string) []v2.MatcherExplanationView {
+	keys := make([]string, 0, len(requiredState))
+	for key := range requiredState {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var explanations []v2.MatcherExplanationView
+	for _, key := range keys {
+		actual, found := state[key]
+		explanation := v2.MatcherExplanationView{
+			Key:     key,
+			Matched: found && actual == requiredState[key],
+			Missing: !found,
+		}
+		if explanation.Matched {
+			explanation.Score = 1
+		}
+		if found {
+			explanation.Steps = []v2.MatcherStepView{{
+				Matcher: "exact",
+				Value:   requiredState[key],
+				Actual:  actual,
+				Matched: explanation.Matched,
+			}}
+		}
+		explanations = append(explanations, explanation)
+	}
+	return explanations
+}
+
+func sortedMatcherKeys(fieldMatchers map[string][]models.RequestFieldMatchers) []string {
+	keys := make([]string, 0, len(fieldMatchers))
+	for key := range fieldMatchers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,166 @@
+package matching_test
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	. "github.com/onsi/gomega"
+)
+
+func newExplainSimulation() *models.Simulation {
+	simulation := models.NewSimulation()
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{Matcher: matchers.Glob, Value: "/users/*"},
+			},
+		},
+		Response: models.ResponseDetails{Body: "glob"},
+	})
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{Matcher: matchers.Exact, Value: "/users/1"},
+			},
+			Headers: map[string][]models.RequestFieldMatchers{
+				"Authorization": {{Matcher: matchers.Exact, Value: "secret"}},
+			},
+		},
+		Response: models.ResponseDetails{Body: "exact with header"},
+	})
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{Matcher: matchers.Exact, Value: "/users/1"},
+			},
+		},
+		Response: models.ResponseDetails{Body: "exact"},
+	})
+
+	return simulation
+}
+
+func Test_Explain_MarksTheStrongestMatchAsSelected(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/users/1"}
+	simulation := newExplainSimulation()
+	unit := matching.Explain("strongest", request, false, simulation, &state.State{State: map[string]string{}})
+
+	Expect(unit.Strategy).To(Equal("strongest"))
+	Expect(unit.Matched).To(BeTrue())
+	Expect(unit.Candidates).To(HaveLen(3))
+
+	Expect(unit.Candidates[0].Matched).To(BeTrue())
+	Expect(unit.Candidates[0].Selected).To(BeFalse())
+
+	Expect(unit.Candidates[1].Matched).To(BeFalse())
+	Expect(unit.Candidates[1].MissedFields).To(ConsistOf("headers"))
+
+	Expect(unit.Candidates[2].Matched).To(BeTrue())
+	Expect(unit.Candidates[2].Selected).To(BeTrue())
+	Expect(unit.Candidates[2].Score).To(BeNumerically(">", unit.Candidates[0].Score))
+
+	result := matching.Match("strongest", request, false, simulation, &state.State{State: map[string]string{}})
+	Expect(result.Pair.Response.Body).To(Equal("exact"))
+}
+
+func Test_Explain_MarksTheFirstMatchAsSelected(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/users/1"}
+	unit := matching.Explain("first", request, false, newExplainSimulation(), &state.State{State: map[string]string{}})
+
+	Expect(unit.Strategy).To(Equal("first"))
+	Expect(unit.Candidates[0].Selected).To(BeTrue())
+	Expect(unit.Candidates[2].Selected).To(BeFalse())
+}
+
+func Test_Explain_BreaksDownFieldsAndMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	request := models.RequestDetails{Method: "GET", Destination: "test.com", Path: "/users/1"}
+	unit := matching.Explain("strongest", request, false, newExplainSimulation(), &state.State{State: map[string]string{}})
+
+	var path, headers v2.FieldExplanationView
+	for _, field := range unit.Candidates[1].Fields {
+		switch field.Field {
+		case "path":
+			path = field
+		case "headers":
+			headers = field
+		}
+	}
+
+	Expect(path.Matched).To(BeTrue())
+	Expect(path.Score).To(Equal(2))
+	Expect(path.Matchers).To(Equal([]v2.MatcherExplanationView{{
+		Matched: true,
+		Score:   2,
+		Steps:   []v2.MatcherStepView{{Matcher: "exact", Value: "/users/1", Actual: "/users/1", Matched: true}},
+	}}))
+
+	Expect(headers.Matched).To(BeFalse())
+	Expect(headers.Matchers).To(Equal([]v2.MatcherExplanationView{{Key: "Authorization", Missing: true}}))
+}
+
+func Test_Explain_ShowsDoMatchChainSteps(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Body: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.JsonPath,
+					Value:   "$.user.id",
+					DoMatch: &models.RequestFieldMatchers{Matcher: matchers.Exact, Value: "2"},
+				},
+			},
+		},
+	})
+
+	request := models.RequestDetails{Body: `{"user": {"id": 1}}`}
+	unit := matching.Explain("strongest", request, false, simulation, &state.State{State: map[string]string{}})
+
+	Expect(unit.Matched).To(BeFalse())
+	Expect(unit.Candidates[0].Fields[0].Field).To(Equal("body"))
+	Expect(unit.Candidates[0].Fields[0].Matchers[0].Steps).To(Equal([]v2.MatcherStepView{
+		{Matcher: "jsonpath", Value: "$.user.id", Actual: `{"user": {"id": 1}}`, Matched: true},
+		{Matcher: "exact", Value: "2", Actual: "1", Matched: false},
+	}))
+}
+
+func Test_Explain_ShowsRequiredState(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			RequiresState: map[string]string{"basket": "full", "loggedIn": "true"},
+		},
+	})
+
+	unit := matching.Explain("strongest", models.RequestDetails{}, false, simulation, &state.State{State: map[string]string{"basket": "empty"}})
+
+	stateField := unit.Candidates[0].Fields[len(unit.Candidates[0].Fields)-1]
+	Expect(stateField.Field).To(Equal("state"))
+	Expect(stateField.Matched).To(BeFalse())
+	Expect(stateField.Matchers).To(Equal([]v2.MatcherExplanationView{
+		{
+			Key:   "basket",
+			Steps: []v2.MatcherStepView{{Matcher: "exact", Value: "full", Actual: "empty", Matched: false}},
+		},
+		{
+			Key:     "loggedIn",
+			Missing: true,
+		},
+	}))
+}
@@ -7,7 +7,7 @@ import (
 	"github.com/SpectoLabs/hoverfly/core/models"
 )
 
-func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string) *FieldMatch {
+func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string, customMatchers *matchers.CustomMatchers) *FieldMatch {
 
 	fieldMatch := &FieldMatch{Matched: true}
 
@@ -16,12 +16,8 @@ func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string) *FieldMa
 	}
 
 	for _, field := range fields {
-		if isMatching(field, toMatch) {
-			if field.Matcher == matchers.Exact || (field.Matcher == matchers.Array && field.Config == nil) {
-				fieldMatch.Score = fieldMatch.Score + 2
-			} else {
-				fieldMatch.Score = fieldMatch.Score + 1
-			}
+		if isMatching(field, toMatch, customMatchers) {
+			fieldMatch.Score = fieldMatch.Score + matcherScore(field)
 		} else {
 			fieldMatch.Matched = false
 		}
@@ -30,23 +26,73 @@ func FieldMatcher(fields []models.RequestFieldMatchers, toMatch string) *FieldMa
 	return fieldMatch
 }
 
-func isMatching(field models.RequestFieldMatchers, toMatch string) bool {
+// matcherScore is how much a matcher adds to the score when it matches. Exact matchers are worth more, as they
+// are the most specific.
+func matcherScore(field models.RequestFieldMatchers) int {
+	if field.Matcher == matchers.Exact || (field.Matcher == matchers.Array && field.Config == nil) {
+		return 2
+	}
+	return 1
+}
+
+func isMatching(field models.RequestFieldMatchers, toMatch string, customMatchers *matchers.CustomMatchers) bool {
+	return isMatchingWithSteps(field, toMatch, customMatchers, nil)
+}
+
+// matcherStep is one matcher of a doMatch chain along with the value it was given, recorded when explaining
+// a match
+type matcherStep struct {
+	matcher string
+	value   interface{}
+	actual  string
+	matched bool
+}
+
+func isMatchingWithSteps(field models.RequestFieldMatchers, toMatch string, customMatchers *matchers.CustomMatchers, steps *[]matcherStep) bool {
 	currentMatcher := field
 	actual := toMatch
 	result := false
+	record := func(matched bool) {
+		if steps != nil {
+			*steps = append(*steps, matcherStep{currentMatcher.Matcher, currentMatcher.Value, actual, matched})
+		}
+	}
 	for {
 
+		if customMatcher, ok := customMatchers.Get(currentMatcher.Matcher); ok {
+			isMatched, value := customMatcher.Match(currentMatcher.Value, actual, currentMatcher.Config)
+			record(isMatched)
+			if !isMatched || currentMatcher.DoMatch == nil {
+				return isMatched
+			}
+			actual = value
+			currentMatcher = *currentMatcher.DoMatch
+			continue
+		}
+
 		var matcherDetails matchers.MatcherDetails
 		isMatched := false
 		if currentMatcher.Config == nil {
 			matcherDetails = matchers.Matchers[strings.ToLower(currentMatcher.Matcher)]
-			isMatched = matcherDetails.MatcherFunction.(func(interface{}, string) bool)(currentMatcher.Value, actual)
+			matcherFunction, ok := matcherDetails.MatcherFunction.(func(interface{}, string) bool)
+			// An unknown matcher, such as a custom matcher which has since been deleted, never matches
+			if !ok {
+				record(false)
+				return false
+			}
+			isMatched = matcherFunction(currentMatcher.Value, actual)
 
 		} else {
 			matcherDetails = matchers.MatchersWithConfig[strings.ToLower(currentMatcher.Matcher)]
-			isMatched = matcherDetails.MatcherFunction.(func(interface{}, string, map[string]interface{}) bool)(currentMatcher.Value, actual, currentMatcher.Config)
+			matcherFunction, ok := matcherDetails.MatcherFunction.(func(interface{}, string, map[string]interface{}) bool)
+			if !ok {
+				record(false)
+				return false
+			}
+			isMatched = matcherFunction(currentMatcher.Value, actual, currentMatcher.Config)
 
 		}
+		record(isMatched)
 		if !isMatched {
 			return false
 		}
@@ -1,6 +1,10 @@
 package matching_test
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/SpectoLabs/hoverfly/core/matching"
@@ -210,7 +214,7 @@ func Test_FieldMatcher(t *testing.T) {
 	RegisterTestingT(t)
 
 	for _, test := range fieldMatcherTests {
-		result := matching.FieldMatcher(test.matchers, test.toMatch)
+		result := matching.FieldMatcher(test.matchers, test.toMatch, nil)
 		if test.equals != nil {
 			Expect(result.Matched).To(test.equals, test.name)
 		}
@@ -220,3 +224,67 @@ func Test_FieldMatcher(t *testing.T) {
 	}
 
 }
+
+func Test_FieldMatcher_UsesCustomMatcherAndChainsItsValue(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Actual string `json:"actual"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"matched": strings.HasPrefix(request.Actual, "v"),
+			"value":   strings.TrimPrefix(request.Actual, "v"),
+		})
+	}))
+	defer server.Close()
+
+	customMatcher, err := matchers.NewRemoteCustomMatcher(server.URL)
+	Expect(err).To(BeNil())
+	customMatchers := matchers.NewCustomMatchers()
+	Expect(customMatchers.Set("version", customMatcher)).To(Succeed())
+	defer customMatchers.DeleteAll()
+
+	fields := []models.RequestFieldMatchers{
+		{
+			Matcher: "version",
+			DoMatch: &models.RequestFieldMatchers{
+				Matcher: matchers.Exact,
+				Value:   "1.2.3",
+			},
+		},
+	}
+
+	result := matching.FieldMatcher(fields, "v1.2.3", customMatchers)
+	Expect(result.Matched).To(BeTrue())
+	Expect(result.Score).To(BeNumerically(">", 0))
+
+	Expect(matching.FieldMatcher(fields, "1.2.3", customMatchers).Matched).To(BeFalse())
+	Expect(matching.FieldMatcher(fields, "v1.2.4", customMatchers).Matched).To(BeFalse())
+}
+
+func Test_FieldMatcher_DoesNotMatchUnknownMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	result := matching.FieldMatcher([]models.RequestFieldMatchers{{Matcher: "unknown", Value: "test"}}, "test", nil)
+	Expect(result.Matched).To(BeFalse())
+}
+
+func Test_FieldMatcher_ChainsGraphQLMatcherToVariables(t *testing.T) {
+	RegisterTestingT(t)
+
+	fields := []models.RequestFieldMatchers{
+		{
+			Matcher: matchers.GraphQL,
+			Value:   `query GetUser($id: ID!) { user(id: $id) { id name } }`,
+			DoMatch: &models.RequestFieldMatchers{
+				Matcher: matchers.JsonPartial,
+				Value:   `{"id": "1"}`,
+			},
+		},
+	}
+
+	Expect(matching.FieldMatcher(fields, `{"query": "query GetUser($id: ID!) { user(id: $id) { name id } }", "variables": {"id": "1", "locale": "en"}}`, nil).Matched).To(BeTrue())
+	Expect(matching.FieldMatcher(fields, `{"query": "query GetUser($id: ID!) { user(id: $id) { name id } }", "variables": {"id": "2"}}`, nil).Matched).To(BeFalse())
+}
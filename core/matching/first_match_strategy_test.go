@@ -1129,3 +1129,39 @@ func Test_FirstMatchShouldBeCacheableIfMatchedOnEverythingApartFromStateZeroTime
 	Expect(result.Error).ToNot(BeNil())
 	Expect(result.Cacheable).To(BeTrue())
 }
+
+func Test_FirstMatchStrategy_SkipsDisabledPairs(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		Disabled: true,
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "somehost.com",
+				},
+			},
+		},
+		Response: models.ResponseDetails{
+			Body: "disabled",
+		},
+	})
+	simulation.AddPair(&models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{},
+		Response: models.ResponseDetails{
+			Body: "request matched",
+		},
+	})
+
+	r := models.RequestDetails{
+		Method:      "GET",
+		Destination: "somehost.com",
+	}
+	result := matching.MatchingStrategyRunner(r, false, simulation, &state.State{State: make(map[string]string)}, &matching.FirstMatchStrategy{})
+
+	Expect(result.Error).To(BeNil())
+	Expect(result.Pair.Response.Body).To(Equal("request matched"))
+}
@@ -3,10 +3,11 @@ package matching
 import (
 	"strings"
 
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 )
 
-func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]string) *FieldMatch {
+func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]string, customMatchers *matchers.CustomMatchers) *FieldMatch {
 
 	// // Make everything lowercase, as headers are case insensitive
 	// for requestHeaderKey, requestHeaderValues := range toMatch {
@@ -33,7 +34,7 @@ func HeaderMatching(requestMatcher models.RequestMatcher, toMatch map[string][]s
 			continue
 		}
 
-		fieldMatch := FieldMatcher(matcherHeaderValue, strings.Join(toMatchHeaderValues, ";"))
+		fieldMatch := FieldMatcher(matcherHeaderValue, strings.Join(toMatchHeaderValues, ";"), customMatchers)
 		matcherHeaderValueMatched = fieldMatch.Matched
 		score += fieldMatch.Score
 
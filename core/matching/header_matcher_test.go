@@ -168,7 +168,7 @@ func Test_HeaderMatching(t *testing.T) {
 	for _, test := range tests {
 		result := matching.HeaderMatching(models.RequestMatcher{
 			Headers: test.headers,
-		}, test.toMatchHeaders)
+		}, test.toMatchHeaders, nil)
 
 		Expect(result.Matched).To(test.equals, test.name)
 		if test.matchEquals != nil {
@@ -194,7 +194,7 @@ func Test_HeaderMatching_NotModifyingOriginalRequestHeaders(t *testing.T) {
 			},
 		},
 	},
-		requestHeaders)
+		requestHeaders, nil)
 
 	Expect(result.Matched).To(BeTrue())
 	Expect(requestHeaders).To(Equal(map[string][]string{
@@ -0,0 +1,293 @@
+package matchers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// CustomMatcherTimeout caps the time a custom matcher has to decide whether a value matches. Exported so
+// tests can lower it.
+var CustomMatcherTimeout = 5 * time.Second
+
+var customMatcherName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// CustomMatcher decides whether a value matches outside of Hoverfly, by running a script with a binary or by
+// calling a remote HTTP endpoint, the same way as middleware. It is sent the matcher value, the value to match
+// and any config as JSON, and answers with whether it matched and, optionally, the value to feed to the next
+// matcher in a doMatch chain.
+type CustomMatcher struct {
+	Binary string
+	Script *os.File
+	Remote string
+}
+
+type customMatcherRequest struct {
+	Value  interface{}            `json:"value"`
+	Actual string                 `json:"actual"`
+	Config map[string]interface{} `json:"config,omitempty"`
+}
+
+type customMatcherResponse struct {
+	Matched bool    `json:"matched"`
+	Value   *string `json:"value"`
+}
+
+func NewLocalCustomMatcher(binary, scriptContent string) (*CustomMatcher, error) {
+	if binary == "" {
+		return nil, errors.New("custom matcher needs a binary or a remote")
+	}
+
+	customMatcher := &CustomMatcher{Binary: binary}
+	if scriptContent == "" {
+		return customMatcher, nil
+	}
+
+	tempDir := path.Join(os.TempDir(), "hoverfly")
+	os.Mkdir(tempDir, 0777)
+
+	script, err := os.CreateTemp(tempDir, "hoverfly_matcher_")
+	if err != nil {
+		return nil, err
+	}
+	defer script.Close()
+
+	if _, err := script.Write([]byte(scriptContent)); err != nil {
+		return nil, err
+	}
+
+	customMatcher.Script = script
+	return customMatcher, nil
+}
+
+func NewRemoteCustomMatcher(remote string) (*CustomMatcher, error) {
+	remoteUrl, err := url.ParseRequestURI(remote)
+	if err != nil || remoteUrl.Host == "" {
+		return nil, errors.New("remote is not a valid URL")
+	}
+
+	return &CustomMatcher{Remote: remote}, nil
+}
+
+// Match returns whether the value matches, along with the value for the next matcher in a chain. Any error
+// running the matcher is logged and counts as no match.
+func (this *CustomMatcher) Match(value interface{}, toMatch string, config map[string]interface{}) (bool, string) {
+	payload, err := json.Marshal(customMatcherRequest{Value: value, Actual: toMatch, Config: config})
+	if err != nil {
+		return false, ""
+	}
+
+	var output []byte
+	if this.Remote != "" {
+		output, err = this.callRemote(payload)
+	} else {
+		output, err = this.runLocal(payload)
+	}
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Warn("Custom matcher failed")
+		return false, ""
+	}
+
+	var response customMatcherResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		log.WithFields(log.Fields{
+			"error":  err.Error(),
+			"output": string(output),
+		}).Warn("Custom matcher returned invalid JSON")
+		return false, ""
+	}
+
+	if response.Value == nil {
+		return response.Matched, toMatch
+	}
+	return response.Matched, *response.Value
+}
+
+func (this *CustomMatcher) runLocal(payload []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), CustomMatcherTimeout)
+	defer cancel()
+
+	var command *exec.Cmd
+	if this.Script != nil {
+		command = exec.CommandContext(ctx, this.Binary, this.Script.Name())
+	} else {
+		command = exec.CommandContext(ctx, this.Binary)
+	}
+
+	var stdout, stderr bytes.Buffer
+	command.Stdin = bytes.NewReader(payload)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	if err := command.Run(); err != nil {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("%s: %s", err.Error(), strings.TrimSpace(stderr.String()))
+		}
+		return nil, err
+	}
+
+	return stdout.Bytes(), nil
+}
+
+func (this *CustomMatcher) callRemote(payload []byte) ([]byte, error) {
+	client := &http.Client{Timeout: CustomMatcherTimeout}
+	response, err := client.Post(this.Remote, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(response.Body); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("remote custom matcher responded with status %d", response.StatusCode)
+	}
+
+	return body.Bytes(), nil
+}
+
+// GetScript returns the content of the script the binary is run with
+func (this *CustomMatcher) GetScript() string {
+	if this.Script == nil {
+		return ""
+	}
+	contents, _ := os.ReadFile(this.Script.Name())
+	return string(contents)
+}
+
+func (this *CustomMatcher) deleteScript() {
+	if this.Script != nil {
+		os.Remove(this.Script.Name())
+	}
+}
+
+// CustomMatchers holds the custom matchers of a simulation by name. Each Hoverfly has its own, so custom matchers
+// set in one namespace cannot change the matching of another.
+type CustomMatchers struct {
+	customMatchers map[string]*CustomMatcher
+	mu             sync.RWMutex
+}
+
+func NewCustomMatchers() *CustomMatchers {
+	return &CustomMatchers{customMatchers: map[string]*CustomMatcher{}}
+}
+
+// Set registers a custom matcher under a name, which can then be used as the matcher of any field. The name cannot
+// be one of the built-in matchers.
+func (this *CustomMatchers) Set(name string, customMatcher *CustomMatcher) error {
+	name = strings.ToLower(name)
+	if !customMatcherName.MatchString(name) {
+		return fmt.Errorf("Invalid custom matcher name %s, a name can only contain letters, digits, '_' and '-'", name)
+	}
+	if _, ok := Matchers[name]; ok || name == "form" || name == "multipart" {
+		return fmt.Errorf("Custom matcher cannot be named %s, as there is a built-in matcher with that name", name)
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if existing, ok := this.customMatchers[name]; ok {
+		existing.deleteScript()
+	}
+	this.customMatchers[name] = customMatcher
+
+	return nil
+}
+
+// Get returns the custom matcher of a name. A nil CustomMatchers has none.
+func (this *CustomMatchers) Get(name string) (*CustomMatcher, bool) {
+	if this == nil {
+		return nil, false
+	}
+
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	customMatcher, ok := this.customMatchers[strings.ToLower(name)]
+	return customMatcher, ok
+}
+
+// Names returns the names of the registered custom matchers, in order
+func (this *CustomMatchers) Names() []string {
+	names := []string{}
+	if this == nil {
+		return names
+	}
+
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	for name := range this.customMatchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Replace takes the custom matchers of another, which is left empty, in place of these
+func (this *CustomMatchers) Replace(customMatchers *CustomMatchers) {
+	customMatchers.mu.Lock()
+	replacement := customMatchers.customMatchers
+	customMatchers.customMatchers = map[string]*CustomMatcher{}
+	customMatchers.mu.Unlock()
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for name, customMatcher := range this.customMatchers {
+		if replacement[name] != customMatcher {
+			customMatcher.deleteScript()
+		}
+	}
+	this.customMatchers = replacement
+}
+
+func (this *CustomMatchers) Delete(name string) error {
+	name = strings.ToLower(name)
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	customMatcher, ok := this.customMatchers[name]
+	if !ok {
+		return fmt.Errorf("Custom matcher %s not found", name)
+	}
+	customMatcher.deleteScript()
+	delete(this.customMatchers, name)
+
+	return nil
+}
+
+// DeleteAll removes every custom matcher along with its script
+func (this *CustomMatchers) DeleteAll() {
+	if this == nil {
+		return
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for _, customMatcher := range this.customMatchers {
+		customMatcher.deleteScript()
+	}
+	this.customMatchers = map[string]*CustomMatcher{}
+}
@@ -0,0 +1,158 @@
+package matchers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func newRemoteMatcherServer(handler func(value interface{}, actual string) (bool, *string)) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Value  interface{} `json:"value"`
+			Actual string      `json:"actual"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		matched, value := handler(request.Value, request.Actual)
+		json.NewEncoder(w).Encode(map[string]interface{}{"matched": matched, "value": value})
+	}))
+}
+
+func Test_CustomMatcher_RemoteMatchesWhenTheEndpointSaysSo(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newRemoteMatcherServer(func(value interface{}, actual string) (bool, *string) {
+		return strings.EqualFold(value.(string), actual), nil
+	})
+	defer server.Close()
+
+	unit, err := matchers.NewRemoteCustomMatcher(server.URL)
+	Expect(err).To(BeNil())
+
+	matched, value := unit.Match("HELLO", "hello", nil)
+	Expect(matched).To(BeTrue())
+	Expect(value).To(Equal("hello"))
+
+	matched, _ = unit.Match("HELLO", "goodbye", nil)
+	Expect(matched).To(BeFalse())
+}
+
+func Test_CustomMatcher_RemoteCanReturnTheValueForTheNextMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newRemoteMatcherServer(func(value interface{}, actual string) (bool, *string) {
+		upper := strings.ToUpper(actual)
+		return true, &upper
+	})
+	defer server.Close()
+
+	unit, err := matchers.NewRemoteCustomMatcher(server.URL)
+	Expect(err).To(BeNil())
+
+	matched, value := unit.Match("", "hello", nil)
+	Expect(matched).To(BeTrue())
+	Expect(value).To(Equal("HELLO"))
+}
+
+func Test_CustomMatcher_RemoteDoesNotMatchWhenTheEndpointFails(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"matched": true}`))
+	}))
+	defer server.Close()
+
+	unit, err := matchers.NewRemoteCustomMatcher(server.URL)
+	Expect(err).To(BeNil())
+
+	matched, _ := unit.Match("", "hello", nil)
+	Expect(matched).To(BeFalse())
+}
+
+func Test_NewRemoteCustomMatcher_ErrorsWithInvalidUrl(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := matchers.NewRemoteCustomMatcher("not a url")
+	Expect(err).To(MatchError("remote is not a valid URL"))
+}
+
+func Test_CustomMatcher_LocalRunsTheScriptWithTheBinary(t *testing.T) {
+	RegisterTestingT(t)
+
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+
+	unit, err := matchers.NewLocalCustomMatcher("sh", `grep -q '"actual":"hello"' && echo '{"matched": true}' || echo '{"matched": false}'`)
+	Expect(err).To(BeNil())
+	customMatchers := matchers.NewCustomMatchers()
+	defer customMatchers.DeleteAll()
+	Expect(customMatchers.Set("local-test", unit)).To(Succeed())
+
+	matched, _ := unit.Match("", "hello", nil)
+	Expect(matched).To(BeTrue())
+
+	matched, _ = unit.Match("", "goodbye", nil)
+	Expect(matched).To(BeFalse())
+}
+
+func Test_NewLocalCustomMatcher_ErrorsWithoutBinary(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := matchers.NewLocalCustomMatcher("", "script")
+	Expect(err).To(MatchError("custom matcher needs a binary or a remote"))
+}
+
+func Test_CustomMatchers_Set_RegistersByLowerCaseName(t *testing.T) {
+	RegisterTestingT(t)
+
+	customMatchers := matchers.NewCustomMatchers()
+
+	unit, _ := matchers.NewRemoteCustomMatcher("http://localhost:4321")
+	Expect(customMatchers.Set("Semver", unit)).To(Succeed())
+
+	customMatcher, ok := customMatchers.Get("SEMVER")
+	Expect(ok).To(BeTrue())
+	Expect(customMatcher).To(Equal(unit))
+	Expect(customMatchers.Names()).To(Equal([]string{"semver"}))
+}
+
+func Test_CustomMatchers_Set_RejectsBuiltInAndInvalidNames(t *testing.T) {
+	RegisterTestingT(t)
+
+	customMatchers := matchers.NewCustomMatchers()
+
+	unit, _ := matchers.NewRemoteCustomMatcher("http://localhost:4321")
+
+	Expect(customMatchers.Set("glob", unit)).To(MatchError("Custom matcher cannot be named glob, as there is a built-in matcher with that name"))
+	Expect(customMatchers.Set("has space", unit)).To(MatchError("Invalid custom matcher name has space, a name can only contain letters, digits, '_' and '-'"))
+}
+
+func Test_CustomMatchers_Delete_ErrorsWhenNotFound(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.NewCustomMatchers().Delete("missing")).To(MatchError("Custom matcher missing not found"))
+}
+
+func Test_CustomMatchers_AreNotShared(t *testing.T) {
+	RegisterTestingT(t)
+
+	first := matchers.NewCustomMatchers()
+	second := matchers.NewCustomMatchers()
+
+	unit, _ := matchers.NewRemoteCustomMatcher("http://localhost:4321")
+	Expect(first.Set("semver", unit)).To(Succeed())
+
+	_, ok := second.Get("semver")
+	Expect(ok).To(BeFalse())
+
+	first.DeleteAll()
+	Expect(first.Names()).To(BeEmpty())
+}
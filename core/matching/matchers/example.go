@@ -0,0 +1,134 @@
+package matchers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp/syntax"
+	"strings"
+)
+
+// ExampleValue returns a value which the matcher matches, for the matchers where one can be worked out from
+// their value alone. Matchers which query into a value, such as jsonpath, or negate one have no example.
+func ExampleValue(matcher string, value interface{}) (string, bool) {
+	switch strings.ToLower(matcher) {
+	case "", Exact, Json, JsonPartial, Xml, XmlTemplated:
+		return fmt.Sprint(value), true
+	case Glob:
+		// Each wildcard stands for a placeholder, as an empty string reads badly and may not be valid where the
+		// example is used, such as a host of *.hoverfly.io
+		return strings.ReplaceAll(fmt.Sprint(value), "*", "x"), true
+	case Regex:
+		return regexExample(fmt.Sprint(value))
+	case Array:
+		if values, ok := arrayValues(value); ok && len(values) > 0 {
+			return values[0], true
+		}
+	case GraphQL:
+		body, err := json.Marshal(map[string]string{"query": fmt.Sprint(value)})
+		return string(body), err == nil
+	}
+
+	return "", false
+}
+
+// ExampleValues is like ExampleValue, but returns every value of an array matcher
+func ExampleValues(matcher string, value interface{}) ([]string, bool) {
+	if strings.ToLower(matcher) == Array {
+		return arrayValues(value)
+	}
+
+	example, ok := ExampleValue(matcher, value)
+	if !ok {
+		return nil, false
+	}
+	return []string{example}, true
+}
+
+func arrayValues(value interface{}) ([]string, bool) {
+	switch values := value.(type) {
+	case []string:
+		return values, true
+	case []interface{}:
+		result := []string{}
+		for _, item := range values {
+			result = append(result, fmt.Sprint(item))
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+func regexExample(pattern string) (string, bool) {
+	regex, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+
+	var example strings.Builder
+	if !writeRegexExample(regex.Simplify(), &example) {
+		return "", false
+	}
+	return example.String(), true
+}
+
+// writeRegexExample writes the shortest string matching the regex, taking the first branch of any alternation
+func writeRegexExample(regex *syntax.Regexp, example *strings.Builder) bool {
+	switch regex.Op {
+	case syntax.OpNoMatch:
+		return false
+	case syntax.OpLiteral:
+		example.WriteString(string(regex.Rune))
+	case syntax.OpCharClass:
+		character, ok := charClassExample(regex.Rune)
+		if !ok {
+			return false
+		}
+		example.WriteRune(character)
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		example.WriteRune('a')
+	case syntax.OpCapture, syntax.OpPlus:
+		return writeRegexExample(regex.Sub[0], example)
+	case syntax.OpRepeat:
+		for i := 0; i < regex.Min; i++ {
+			if !writeRegexExample(regex.Sub[0], example) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range regex.Sub {
+			if !writeRegexExample(sub, example) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return writeRegexExample(regex.Sub[0], example)
+	}
+
+	return true
+}
+
+// charClassExample picks a readable character from a class, given as pairs of inclusive ranges
+func charClassExample(ranges []rune) (rune, bool) {
+	if len(ranges) == 0 {
+		return 0, false
+	}
+
+	for _, candidate := range "a0A-_." {
+		for i := 0; i < len(ranges); i += 2 {
+			if ranges[i] <= candidate && candidate <= ranges[i+1] {
+				return candidate, true
+			}
+		}
+	}
+
+	for i := 0; i < len(ranges); i += 2 {
+		if ranges[i+1] > ' ' {
+			if ranges[i] > ' ' {
+				return ranges[i], true
+			}
+			return '!', true
+		}
+	}
+
+	return ranges[0], true
+}
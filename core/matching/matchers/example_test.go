@@ -0,0 +1,68 @@
+package matchers_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func Test_ExampleValue_ReturnsValueOfExactMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, matcher := range []string{"", matchers.Exact, matchers.Json, matchers.JsonPartial, matchers.Xml} {
+		value, ok := matchers.ExampleValue(matcher, "value")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("value"))
+	}
+}
+
+func Test_ExampleValue_ReplacesWildcardsOfGlob(t *testing.T) {
+	RegisterTestingT(t)
+
+	value, ok := matchers.ExampleValue(matchers.Glob, "*.hoverfly.io/api/*")
+	Expect(ok).To(BeTrue())
+	Expect(value).To(Equal("x.hoverfly.io/api/x"))
+	Expect(matchers.GlobMatch("*.hoverfly.io/api/*", value)).To(BeTrue())
+}
+
+func Test_ExampleValue_GeneratesValueMatchingRegex(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, pattern := range []string{
+		`^/api/v[0-9]+/users/[^/]+$`,
+		`^(GET|POST)$`,
+		`\d{3}-\w{2,}`,
+		`^[A-Z][a-z]*\.json$`,
+		`.+@example\.com`,
+	} {
+		value, ok := matchers.ExampleValue(matchers.Regex, pattern)
+		Expect(ok).To(BeTrue(), pattern)
+		Expect(regexp.MustCompile(pattern).MatchString(value)).To(BeTrue(), pattern+" does not match "+value)
+	}
+}
+
+func Test_ExampleValue_ReturnsFalseForMatchersWithoutExample(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, matcher := range []string{matchers.JsonPath, matchers.Xpath, matchers.JWT, matchers.Negation} {
+		_, ok := matchers.ExampleValue(matcher, "value")
+		Expect(ok).To(BeFalse())
+	}
+
+	_, ok := matchers.ExampleValue(matchers.Regex, "[")
+	Expect(ok).To(BeFalse())
+}
+
+func Test_ExampleValues_ReturnsValuesOfArray(t *testing.T) {
+	RegisterTestingT(t)
+
+	values, ok := matchers.ExampleValues(matchers.Array, []interface{}{"a", "b"})
+	Expect(ok).To(BeTrue())
+	Expect(values).To(Equal([]string{"a", "b"}))
+
+	values, ok = matchers.ExampleValues(matchers.Exact, "a")
+	Expect(ok).To(BeTrue())
+	Expect(values).To(Equal([]string{"a"}))
+}
@@ -0,0 +1,81 @@
+package matchers
+
+import (
+	"encoding/json"
+
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+var GraphQL = "graphql"
+
+func GraphQLMatch(match interface{}, toMatch string) bool {
+	return GraphQLMatchWithConfig(match, toMatch, nil)
+}
+
+// GraphQLMatchWithConfig parses the query in the matcher value and the one in the request body, and compares the
+// operations ignoring whitespace, comments and the order of fields and arguments. With ignoreUnknown, the request
+// can select fields the matcher does not.
+func GraphQLMatchWithConfig(match interface{}, toMatch string, config map[string]interface{}) bool {
+	matchString, ok := match.(string)
+	if !ok {
+		return false
+	}
+
+	request, ok := util.ParseGraphQLRequest(toMatch)
+	if !ok {
+		return false
+	}
+
+	actual, err := util.ParseGraphQLOperation(request.Query, request.OperationName)
+	if err != nil {
+		return false
+	}
+
+	expected, err := util.ParseGraphQLOperation(matchString, actual.Name)
+	if err != nil {
+		// The matcher may hold a single operation while the request names one of several it sent
+		expected, err = util.ParseGraphQLOperation(matchString, "")
+		if err != nil {
+			return false
+		}
+	}
+
+	if !util.GetBoolOrDefault(config, IgnoreUnknown, false) {
+		return expected.String() == actual.String()
+	}
+
+	return expected.Type == actual.Type &&
+		(expected.Name == "" || expected.Name == actual.Name) &&
+		hasGraphQLSelections(expected.Selections, actual.Selections)
+}
+
+// GraphQLMatchValueGenerator passes the variables of the request on to the next matcher, so they can be matched
+// with the JSON matchers
+func GraphQLMatchValueGenerator(match interface{}, toMatch string) string {
+	request, _ := util.ParseGraphQLRequest(toMatch)
+	if request.Variables == nil {
+		return "{}"
+	}
+
+	variables, err := json.Marshal(request.Variables)
+	if err != nil {
+		return "{}"
+	}
+	return string(variables)
+}
+
+func hasGraphQLSelections(expected, actual []util.GraphQLSelection) bool {
+	for _, expectedSelection := range expected {
+		found := false
+		for _, actualSelection := range actual {
+			if expectedSelection.Key == actualSelection.Key && hasGraphQLSelections(expectedSelection.Selections, actualSelection.Selections) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
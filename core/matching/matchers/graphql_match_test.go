@@ -0,0 +1,67 @@
+package matchers_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func Test_GraphQLMatch_MatchesFalseWithIncorrectDataType(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatch(1, `{"query": "{ user { id } }"}`)).To(BeFalse())
+}
+
+func Test_GraphQLMatch_MatchesTrueWhenOnlyFormattingAndOrderDiffer(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatch(
+		`query GetUser { user(id: "1") { id name } }`,
+		`{"query": "query GetUser {\n  user(id: \"1\") {\n    name\n    id\n  }\n}"}`,
+	)).To(BeTrue())
+}
+
+func Test_GraphQLMatch_MatchesRawGraphQLBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatch(`{ user { id } }`, `query { user { id } }`)).To(BeTrue())
+}
+
+func Test_GraphQLMatch_MatchesFalseWhenSelectedFieldsDiffer(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatch(`query { user { id } }`, `{"query": "query { user { id name } }"}`)).To(BeFalse())
+}
+
+func Test_GraphQLMatch_MatchesFalseWhenOperationNameDiffers(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatch(`query GetUser { user { id } }`, `{"query": "query GetAdmin { user { id } }"}`)).To(BeFalse())
+}
+
+func Test_GraphQLMatch_MatchesTheOperationNamedInTheRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := `{"query": "query GetUser { user { id } } query GetPosts { posts { id } }", "operationName": "GetPosts"}`
+
+	Expect(matchers.GraphQLMatch(`query GetPosts { posts { id } }`, body)).To(BeTrue())
+	Expect(matchers.GraphQLMatch(`query GetUser { user { id } }`, body)).To(BeFalse())
+}
+
+func Test_GraphQLMatchWithConfig_IgnoreUnknownAllowsExtraFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	config := map[string]interface{}{matchers.IgnoreUnknown: true}
+
+	Expect(matchers.GraphQLMatchWithConfig(`query { user { id } }`, `{"query": "query GetUser { user { id name } posts { id } }"}`, config)).To(BeTrue())
+	Expect(matchers.GraphQLMatchWithConfig(`query { user { email } }`, `{"query": "query { user { id name } }"}`, config)).To(BeFalse())
+	Expect(matchers.GraphQLMatchWithConfig(`mutation { user { id } }`, `{"query": "query { user { id } }"}`, config)).To(BeFalse())
+}
+
+func Test_GraphQLMatchValueGenerator_ReturnsVariables(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(matchers.GraphQLMatchValueGenerator("", `{"query": "{ user { id } }", "variables": {"id": "1"}}`)).To(Equal(`{"id":"1"}`))
+	Expect(matchers.GraphQLMatchValueGenerator("", `{"query": "{ user { id } }"}`)).To(Equal(`{}`))
+}
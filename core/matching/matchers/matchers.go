@@ -68,6 +68,10 @@ var Matchers = map[string]MatcherDetails{
 		MatcherFunction:     NegationMatch,
 		MatchValueGenerator: IdentityValueGenerator,
 	},
+	GraphQL: {
+		MatcherFunction:     GraphQLMatch,
+		MatchValueGenerator: GraphQLMatchValueGenerator,
+	},
 }
 
 type MatcherDetails struct {
@@ -80,4 +84,8 @@ var MatchersWithConfig = map[string]MatcherDetails{
 		MatcherFunction:     ArrayMatch,
 		MatchValueGenerator: IdentityValueGenerator,
 	},
+	GraphQL: {
+		MatcherFunction:     GraphQLMatchWithConfig,
+		MatchValueGenerator: GraphQLMatchValueGenerator,
+	},
 }
@@ -1,6 +1,7 @@
 package matching
 
 import (
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 	"github.com/SpectoLabs/hoverfly/core/state"
 	"github.com/SpectoLabs/hoverfly/core/util"
@@ -18,29 +19,36 @@ func MatchingStrategyRunner(req models.RequestDetails, webserver bool, simulatio
 	copyState := util.CopyMap(state.State)
 	state.RWMutex.RUnlock()
 	for _, matchingPair := range simulation.GetMatchingPairs() {
+		if matchingPair.Disabled {
+			continue
+		}
 		requestMatcher := matchingPair.RequestMatcher
 		strategy.PreMatching()
 
-		strategy.Matching(BodyMatching(requestMatcher.Body, req), "body")
+		matchRequestFields(strategy.Matching, requestMatcher, req, webserver, copyState, simulation.CustomMatchers)
 
-		if !webserver {
-			strategy.Matching(FieldMatcher(requestMatcher.Destination, req.Destination), "destination")
+		if result := strategy.PostMatching(req, requestMatcher, matchingPair, copyState); result != nil {
+			return result
 		}
+	}
 
-		strategy.Matching(FieldMatcher(requestMatcher.Path, req.Path), "path")
+	return strategy.Result()
+}
 
-		strategy.Matching(FieldMatcher(requestMatcher.Method, req.Method), "method")
+func matchRequestFields(matching func(*FieldMatch, string), requestMatcher models.RequestMatcher, req models.RequestDetails, webserver bool, state map[string]string, customMatchers *matchers.CustomMatchers) {
+	matching(BodyMatching(requestMatcher.Body, req, customMatchers), "body")
 
-		strategy.Matching(HeaderMatching(requestMatcher, req.Headers), "headers")
+	if !webserver {
+		matching(FieldMatcher(requestMatcher.Destination, req.Destination, customMatchers), "destination")
+	}
 
-		strategy.Matching(QueryMatching(requestMatcher, req.Query), "queries")
+	matching(FieldMatcher(requestMatcher.Path, req.Path, customMatchers), "path")
 
-		strategy.Matching(StateMatcher(copyState, requestMatcher.RequiresState), "state")
+	matching(FieldMatcher(requestMatcher.Method, req.Method, customMatchers), "method")
 
-		if result := strategy.PostMatching(req, requestMatcher, matchingPair, copyState); result != nil {
-			return result
-		}
-	}
+	matching(HeaderMatching(requestMatcher, req.Headers, customMatchers), "headers")
 
-	return strategy.Result()
+	matching(QueryMatching(requestMatcher, req.Query, customMatchers), "queries")
+
+	matching(StateMatcher(state, requestMatcher.RequiresState), "state")
 }
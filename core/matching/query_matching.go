@@ -3,10 +3,11 @@ package matching
 import (
 	"strings"
 
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/models"
 )
 
-func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]string) *FieldMatch {
+func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]string, customMatchers *matchers.CustomMatchers) *FieldMatch {
 
 	matched := true
 	var score int
@@ -45,7 +46,7 @@ func QueryMatching(requestMatcher models.RequestMatcher, toMatch map[string][]st
 			continue
 		}
 
-		fieldMatch := FieldMatcher(matcherQueryValue, strings.Join(toMatchQueryValues, ";"))
+		fieldMatch := FieldMatcher(matcherQueryValue, strings.Join(toMatchQueryValues, ";"), customMatchers)
 		matcherHeaderValueMatched = fieldMatch.Matched
 		score += fieldMatch.Score
 
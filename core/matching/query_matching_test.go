@@ -173,7 +173,7 @@ func Test_QueryMatching(t *testing.T) {
 	for _, test := range queryMatchingTests {
 		result := matching.QueryMatching(models.RequestMatcher{
 			Query: test.queriesWithMatchers,
-		}, test.toMatchQueries)
+		}, test.toMatchQueries, nil)
 
 		Expect(result.Matched).To(test.equals, test.name)
 		if test.matchEquals != nil {
@@ -199,7 +199,7 @@ func Test_QueryMatching_ShouldNotModifySourceQueries(t *testing.T) {
 				},
 			},
 		},
-	}, toMatch)
+	}, toMatch, nil)
 
 	Expect(result.Matched).To(BeTrue())
 	Expect(len(toMatch)).To(Equal(1))
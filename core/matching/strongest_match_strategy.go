@@ -55,6 +55,8 @@ func (s *StrongestMatchStrategy) PostMatching(req models.RequestDetails, request
 		s.requestMatch = &models.RequestMatcherResponsePair{
 			RequestMatcher: requestMatcher,
 			Response:       matchingPair.Response,
+			Responses:      matchingPair.Responses,
+			ResponsesMode:  matchingPair.ResponsesMode,
 		}
 		s.strongestMatchScore = s.score
 		s.closestMiss = nil
@@ -0,0 +1,55 @@
+package matching
+
+import (
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/SpectoLabs/hoverfly/core/util"
+)
+
+// WebSocketMatch finds the strongest WebSocket pair matching the upgrade request
+func WebSocketMatch(req models.RequestDetails, webserver bool, simulation *models.Simulation, state *state.State) (*models.WebSocketPair, *models.MatchError) {
+	state.RWMutex.RLock()
+	copyState := util.CopyMap(state.State)
+	state.RWMutex.RUnlock()
+
+	var match *models.WebSocketPair
+	strongestMatchScore := 0
+	for _, pair := range simulation.GetWebSocketPairs() {
+		matched := true
+		score := 0
+		matchRequestFields(func(fieldMatch *FieldMatch, field string) {
+			if !fieldMatch.Matched {
+				matched = false
+			}
+			score += fieldMatch.Score
+		}, pair.RequestMatcher, req, webserver, copyState, simulation.CustomMatchers)
+
+		if matched && score >= strongestMatchScore {
+			matchedPair := pair
+			match = &matchedPair
+			strongestMatchScore = score
+		}
+	}
+
+	if match == nil {
+		return nil, models.NewMatchError("No WebSocket match found")
+	}
+
+	return match, nil
+}
+
+// WebSocketReplyMatch finds the strongest reply matching a message sent by the client
+func WebSocketReplyMatch(replies []models.WebSocketReply, message models.WebSocketMessage, customMatchers *matchers.CustomMatchers) *models.WebSocketReply {
+	var match *models.WebSocketReply
+	strongestMatchScore := 0
+	for i, reply := range replies {
+		fieldMatch := FieldMatcher(reply.Matcher, message.MatchableData(), customMatchers)
+		if fieldMatch.Matched && fieldMatch.Score >= strongestMatchScore {
+			match = &replies[i]
+			strongestMatchScore = fieldMatch.Score
+		}
+	}
+
+	return match
+}
@@ -0,0 +1,65 @@
+package matching_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	. "github.com/onsi/gomega"
+)
+
+func Test_WebSocketMatch_ReturnsStrongestMatchingPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+	simulation.AddWebSocketPair(&models.WebSocketPair{
+		Labels: []string{"glob"},
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "/*"}},
+		},
+	})
+	simulation.AddWebSocketPair(&models.WebSocketPair{
+		Labels: []string{"exact"},
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/chat"}},
+		},
+	})
+
+	pair, err := matching.WebSocketMatch(models.RequestDetails{Path: "/chat", Method: "GET"}, false, simulation, state.NewState())
+
+	Expect(err).To(BeNil())
+	Expect(pair.Labels).To(ConsistOf("exact"))
+}
+
+func Test_WebSocketMatch_ReturnsErrorWhenNothingMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation := models.NewSimulation()
+	simulation.AddWebSocketPair(&models.WebSocketPair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/chat"}},
+		},
+	})
+
+	pair, err := matching.WebSocketMatch(models.RequestDetails{Path: "/other"}, false, simulation, state.NewState())
+
+	Expect(pair).To(BeNil())
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_WebSocketReplyMatch_MatchesBinaryMessagesOnBase64(t *testing.T) {
+	RegisterTestingT(t)
+
+	replies := []models.WebSocketReply{
+		{Matcher: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "ping"}}},
+		{Matcher: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "AQID"}}},
+	}
+
+	reply := matching.WebSocketReplyMatch(replies, models.WebSocketMessage{Type: models.WebSocketBinary, Data: "\x01\x02\x03"}, nil)
+	Expect(reply).To(Equal(&replies[1]))
+
+	reply = matching.WebSocketReplyMatch(replies, models.WebSocketMessage{Type: models.WebSocketText, Data: "pong"}, nil)
+	Expect(reply).To(BeNil())
+}
@@ -0,0 +1,311 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PrometheusContentType is the content type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultDurationBuckets are the upper bounds, in seconds, of the buckets used for duration histograms
+var DefaultDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Collector - holds the metrics of the proxy traffic and matching, which are written in the Prometheus text format
+type Collector struct {
+	Requests                *CounterVec
+	SimulationHits          *CounterVec
+	SimulationMisses        *CounterVec
+	CacheHits               *CounterVec
+	CacheMisses             *CounterVec
+	UpstreamDuration        *HistogramVec
+	MiddlewareDuration      *HistogramVec
+	MiddlewareFailures      *CounterVec
+	PostServeActionDuration *HistogramVec
+	PostServeActionFailures *CounterVec
+
+	journalSize func() int
+	mu          sync.Mutex
+}
+
+// NewCollector - returns a collector with every metric at zero
+func NewCollector() *Collector {
+	return &Collector{
+		Requests: NewCounterVec("hoverfly_requests_total",
+			"Requests served by the proxy.", "mode", "destination", "method", "status"),
+		SimulationHits: NewCounterVec("hoverfly_simulation_hits_total",
+			"Requests which matched the simulation."),
+		SimulationMisses: NewCounterVec("hoverfly_simulation_misses_total",
+			"Requests which did not match the simulation."),
+		CacheHits: NewCounterVec("hoverfly_cache_hits_total",
+			"Requests whose matching result was found in the cache."),
+		CacheMisses: NewCounterVec("hoverfly_cache_misses_total",
+			"Requests whose matching result was not found in the cache."),
+		UpstreamDuration: NewHistogramVec("hoverfly_upstream_request_duration_seconds",
+			"Time taken by requests to upstream servers.", DefaultDurationBuckets, "destination", "method"),
+		MiddlewareDuration: NewHistogramVec("hoverfly_middleware_duration_seconds",
+			"Time taken to execute the middleware.", DefaultDurationBuckets),
+		MiddlewareFailures: NewCounterVec("hoverfly_middleware_failures_total",
+			"Middleware executions which failed."),
+		PostServeActionDuration: NewHistogramVec("hoverfly_post_serve_action_duration_seconds",
+			"Time taken to execute post serve actions, including their delay.", DefaultDurationBuckets, "action"),
+		PostServeActionFailures: NewCounterVec("hoverfly_post_serve_action_failures_total",
+			"Post serve action executions which failed.", "action"),
+	}
+}
+
+// SetJournalSize - sets the function giving the number of journal entries when the metrics are written
+func (this *Collector) SetJournalSize(journalSize func() int) {
+	this.mu.Lock()
+	this.journalSize = journalSize
+	this.mu.Unlock()
+}
+
+// CountRequest - counts a request served by the proxy
+func (this *Collector) CountRequest(mode, destination, method string, status int) {
+	this.Requests.Inc(mode, destination, method, strconv.Itoa(status))
+}
+
+// Write - writes every metric in the Prometheus text format
+func (this *Collector) Write(w io.Writer) error {
+	counters := []*CounterVec{
+		this.Requests,
+		this.SimulationHits,
+		this.SimulationMisses,
+		this.CacheHits,
+		this.CacheMisses,
+		this.MiddlewareFailures,
+		this.PostServeActionFailures,
+	}
+	for _, counter := range counters {
+		if err := counter.write(w); err != nil {
+			return err
+		}
+	}
+
+	histograms := []*HistogramVec{this.UpstreamDuration, this.MiddlewareDuration, this.PostServeActionDuration}
+	for _, histogram := range histograms {
+		if err := histogram.write(w); err != nil {
+			return err
+		}
+	}
+
+	cacheHits, cacheMisses := this.CacheHits.Value(), this.CacheMisses.Value()
+	cacheHitRatio := 0.0
+	if cacheHits+cacheMisses > 0 {
+		cacheHitRatio = cacheHits / (cacheHits + cacheMisses)
+	}
+	if err := writeGauge(w, "hoverfly_cache_hit_ratio", "Ratio of cache hits to cache lookups.", cacheHitRatio); err != nil {
+		return err
+	}
+
+	this.mu.Lock()
+	journalSize := this.journalSize
+	this.mu.Unlock()
+	if journalSize != nil {
+		return writeGauge(w, "hoverfly_journal_entries", "Entries held in the journal.", float64(journalSize()))
+	}
+
+	return nil
+}
+
+// CounterVec - a counter partitioned by the values of its labels
+type CounterVec struct {
+	name   string
+	help   string
+	labels []string
+	values map[string]*counterValue
+	mu     sync.Mutex
+}
+
+type counterValue struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounterVec - returns a counter with the given name, help text and label names
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		name:   name,
+		help:   help,
+		labels: labels,
+		values: map[string]*counterValue{},
+	}
+}
+
+// Inc - adds one to the counter with the given label values
+func (this *CounterVec) Inc(labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	counter, ok := this.values[key]
+	if !ok {
+		counter = &counterValue{labelValues: labelValues}
+		this.values[key] = counter
+	}
+	counter.value++
+}
+
+// Value - returns the sum of the counter across all label values
+func (this *CounterVec) Value() float64 {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	total := 0.0
+	for _, counter := range this.values {
+		total += counter.value
+	}
+	return total
+}
+
+func (this *CounterVec) write(w io.Writer) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", this.name, this.help, this.name); err != nil {
+		return err
+	}
+
+	// A counter without labels is always written so that it can be scraped before it is first incremented
+	if len(this.labels) == 0 && len(this.values) == 0 {
+		_, err := fmt.Fprintf(w, "%s 0\n", this.name)
+		return err
+	}
+
+	keys := make([]string, 0, len(this.values))
+	for key := range this.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		counter := this.values[key]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", this.name, formatLabels(this.labels, counter.labelValues), formatFloat(counter.value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HistogramVec - a histogram partitioned by the values of its labels
+type HistogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+	values  map[string]*histogramValue
+	mu      sync.Mutex
+}
+
+type histogramValue struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogramVec - returns a histogram with the given name, help text, bucket upper bounds and label names
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{
+		name:    name,
+		help:    help,
+		labels:  labels,
+		buckets: buckets,
+		values:  map[string]*histogramValue{},
+	}
+}
+
+// Observe - records a duration in the histogram with the given label values
+func (this *HistogramVec) Observe(duration time.Duration, labelValues ...string) {
+	seconds := duration.Seconds()
+	key := strings.Join(labelValues, "\xff")
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	histogram, ok := this.values[key]
+	if !ok {
+		histogram = &histogramValue{labelValues: labelValues, counts: make([]uint64, len(this.buckets))}
+		this.values[key] = histogram
+	}
+	for i, bucket := range this.buckets {
+		if seconds <= bucket {
+			histogram.counts[i]++
+		}
+	}
+	histogram.count++
+	histogram.sum += seconds
+}
+
+func (this *HistogramVec) write(w io.Writer) error {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", this.name, this.help, this.name); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(this.values))
+	for key := range this.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		histogram := this.values[key]
+		bucketLabels := append(append([]string{}, this.labels...), "le")
+		for i, bucket := range this.buckets {
+			bucketValues := append(append([]string{}, histogram.labelValues...), formatFloat(bucket))
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", this.name, formatLabels(bucketLabels, bucketValues), histogram.counts[i]); err != nil {
+				return err
+			}
+		}
+		infValues := append(append([]string{}, histogram.labelValues...), "+Inf")
+		labels := formatLabels(this.labels, histogram.labelValues)
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			this.name, formatLabels(bucketLabels, infValues), histogram.count,
+			this.name, labels, formatFloat(histogram.sum),
+			this.name, labels, histogram.count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeGauge(w io.Writer, name, help string, value float64) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n%s %s\n", name, help, name, name, formatFloat(value))
+	return err
+}
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		value := ""
+		if i < len(values) {
+			value = values[i]
+		}
+		pairs[i] = name + `="` + labelValueReplacer.Replace(value) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatFloat(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,89 @@
+package metrics_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/metrics"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Collector_Write_WritesCountersWithLabels(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCollector()
+	unit.CountRequest("simulate", "test.com", "GET", 200)
+	unit.CountRequest("simulate", "test.com", "GET", 200)
+	unit.CountRequest("spy", "test.com", "POST", 502)
+
+	var buffer bytes.Buffer
+	Expect(unit.Write(&buffer)).To(Succeed())
+
+	Expect(buffer.String()).To(ContainSubstring("# TYPE hoverfly_requests_total counter\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_requests_total{mode="simulate",destination="test.com",method="GET",status="200"} 2` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_requests_total{mode="spy",destination="test.com",method="POST",status="502"} 1` + "\n"))
+}
+
+func Test_Collector_Write_WritesUnlabelledCountersAtZero(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCollector()
+
+	var buffer bytes.Buffer
+	Expect(unit.Write(&buffer)).To(Succeed())
+
+	Expect(buffer.String()).To(ContainSubstring("hoverfly_simulation_hits_total 0\n"))
+	Expect(buffer.String()).To(ContainSubstring("hoverfly_simulation_misses_total 0\n"))
+	Expect(buffer.String()).To(ContainSubstring("hoverfly_middleware_failures_total 0\n"))
+	Expect(buffer.String()).To(ContainSubstring("hoverfly_cache_hit_ratio 0\n"))
+	Expect(buffer.String()).ToNot(ContainSubstring("hoverfly_journal_entries"))
+}
+
+func Test_Collector_Write_WritesHistogramBuckets(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCollector()
+	unit.UpstreamDuration.Observe(20*time.Millisecond, "test.com", "GET")
+	unit.UpstreamDuration.Observe(2*time.Second, "test.com", "GET")
+
+	var buffer bytes.Buffer
+	Expect(unit.Write(&buffer)).To(Succeed())
+
+	Expect(buffer.String()).To(ContainSubstring("# TYPE hoverfly_upstream_request_duration_seconds histogram\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_bucket{destination="test.com",method="GET",le="0.01"} 0` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_bucket{destination="test.com",method="GET",le="0.025"} 1` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_bucket{destination="test.com",method="GET",le="2.5"} 2` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_bucket{destination="test.com",method="GET",le="+Inf"} 2` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_sum{destination="test.com",method="GET"} 2.02` + "\n"))
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_upstream_request_duration_seconds_count{destination="test.com",method="GET"} 2` + "\n"))
+}
+
+func Test_Collector_Write_WritesCacheHitRatioAndJournalSize(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCollector()
+	unit.CacheHits.Inc()
+	unit.CacheHits.Inc()
+	unit.CacheHits.Inc()
+	unit.CacheMisses.Inc()
+	unit.SetJournalSize(func() int { return 7 })
+
+	var buffer bytes.Buffer
+	Expect(unit.Write(&buffer)).To(Succeed())
+
+	Expect(buffer.String()).To(ContainSubstring("# TYPE hoverfly_cache_hit_ratio gauge\nhoverfly_cache_hit_ratio 0.75\n"))
+	Expect(buffer.String()).To(ContainSubstring("# TYPE hoverfly_journal_entries gauge\nhoverfly_journal_entries 7\n"))
+}
+
+func Test_CounterVec_Write_EscapesLabelValues(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := metrics.NewCollector()
+	unit.PostServeActionFailures.Inc(`say "hi"\`)
+
+	var buffer bytes.Buffer
+	Expect(unit.Write(&buffer)).To(Succeed())
+
+	Expect(buffer.String()).To(ContainSubstring(`hoverfly_post_serve_action_failures_total{action="say \"hi\"\\"} 1` + "\n"))
+}
@@ -12,8 +12,10 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/SpectoLabs/hoverfly/core/fault"
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/core/interfaces"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 )
@@ -59,6 +61,7 @@ type RequestDetails struct {
 	FormData    map[string][]string
 	Headers     map[string][]string
 	rawQuery    string
+	span        *tracing.Span
 }
 
 func NewRequestDetailsFromHttpRequest(req *http.Request) (RequestDetails, error) {
@@ -212,6 +215,8 @@ type ResponseDetails struct {
 	FixedDelay       int
 	LogNormalDelay   *ResponseDetailsLogNormal
 	PostServeAction  string
+	Weight           int
+	Fault            *fault.Fault
 }
 
 func NewResponseDetailsFromResponse(data interfaces.Response) ResponseDetails {
@@ -246,6 +251,22 @@ func NewResponseDetailsFromResponse(data interfaces.Response) ResponseDetails {
 	return details
 }
 
+// NewResponseDetailsFromViewV5 also keeps the fields which only v5 simulations have
+func NewResponseDetailsFromViewV5(view v2.ResponseDetailsViewV5) ResponseDetails {
+	details := NewResponseDetailsFromResponse(view)
+	details.Weight = view.Weight
+
+	if view.Fault != nil {
+		details.Fault = &fault.Fault{
+			Type:           view.Fault.Type,
+			Bytes:          view.Fault.Bytes,
+			BytesPerSecond: view.Fault.BytesPerSecond,
+		}
+	}
+
+	return details
+}
+
 // This function will create a JSON appropriate version of ResponseDetails for the v2 API
 // If the response headers indicate that the content is encoded, or it has a non-matching
 // supported mimetype, we base64 encode it.
@@ -302,6 +323,15 @@ func (r *ResponseDetails) ConvertToResponseDetailsViewV5() v2.ResponseDetailsVie
 		TransitionsState: r.TransitionsState,
 		FixedDelay:       r.FixedDelay,
 		PostServeAction:  r.PostServeAction,
+		Weight:           r.Weight,
+	}
+
+	if r.Fault != nil {
+		view.Fault = &v2.FaultViewV5{
+			Type:           r.Fault.Type,
+			Bytes:          r.Fault.Bytes,
+			BytesPerSecond: r.Fault.BytesPerSecond,
+		}
 	}
 
 	if r.LogNormalDelay != nil {
@@ -320,6 +350,15 @@ func (this *RequestDetails) GetRawQuery() string {
 	return this.rawQuery
 }
 
+// Span returns the tracing span of the request, which is nil when tracing is disabled
+func (this *RequestDetails) Span() *tracing.Span {
+	return this.span
+}
+
+func (this *RequestDetails) SetSpan(span *tracing.Span) {
+	this.span = span
+}
+
 // Similar to req.URL.Query() but allowing compound query params like qq=country=BEL;postalCode=1234;city=SomeCity;street=SomeStreet;houseNumber=25%20a
 func parseQuery(query string) map[string][]string {
 	m := make(map[string][]string)
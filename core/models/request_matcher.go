@@ -53,6 +53,23 @@ func getValueFromMatcherView(matcher *v2.MatcherViewV5) interface{} {
 			returnValue[formField] = matchers
 		}
 		return returnValue
+	} else if matcher.Matcher == "multipart" {
+		marshalledMultipartValue, _ := json.Marshal(matcher.Value)
+		var partViews map[string]v2.MultipartPartMatcherViewV5
+		if err := json.Unmarshal(marshalledMultipartValue, &partViews); err != nil {
+			//return default value incase of any issue
+			return matcher.Value
+		}
+		returnValue := make(map[string]MultipartPartMatchers)
+		for partName, partView := range partViews {
+			returnValue[partName] = MultipartPartMatchers{
+				Value:       NewRequestFieldMatchersFromView(partView.Value),
+				Filename:    NewRequestFieldMatchersFromView(partView.Filename),
+				ContentType: NewRequestFieldMatchersFromView(partView.ContentType),
+				Headers:     NewRequestFieldMatchersFromMapView(partView.Headers),
+			}
+		}
+		return returnValue
 	} else {
 		return matcher.Value
 	}
@@ -123,6 +140,16 @@ func getValueFromRequestFieldMatcher(matcher *RequestFieldMatchers) interface{}
 			returnValue[formField] = matchersView
 		}
 		return returnValue
+	} else if matcher.Matcher == "multipart" {
+		partMatchers, ok := matcher.Value.(map[string]MultipartPartMatchers)
+		if !ok {
+			return matcher.Value
+		}
+		returnValue := make(map[string]v2.MultipartPartMatcherViewV5)
+		for partName, partMatcher := range partMatchers {
+			returnValue[partName] = partMatcher.BuildView()
+		}
+		return returnValue
 	} else {
 		return matcher.Value
 	}
@@ -142,76 +169,146 @@ func getViewFromRequestFieldMatcher(matcher *RequestFieldMatchers) *v2.MatcherVi
 	}
 }
 
+// MultipartPartMatchers match the value, filename, content type and headers of the parts of a multipart body
+// with the same name
+type MultipartPartMatchers struct {
+	Value       []RequestFieldMatchers
+	Filename    []RequestFieldMatchers
+	ContentType []RequestFieldMatchers
+	Headers     map[string][]RequestFieldMatchers
+}
+
+func (this MultipartPartMatchers) BuildView() v2.MultipartPartMatcherViewV5 {
+	view := v2.MultipartPartMatcherViewV5{
+		Value:       buildFieldMatcherViews(this.Value),
+		Filename:    buildFieldMatcherViews(this.Filename),
+		ContentType: buildFieldMatcherViews(this.ContentType),
+	}
+	for header, headerMatchers := range this.Headers {
+		if view.Headers == nil {
+			view.Headers = map[string][]v2.MatcherViewV5{}
+		}
+		view.Headers[header] = buildFieldMatcherViews(headerMatchers)
+	}
+	return view
+}
+
+func buildFieldMatcherViews(fieldMatchers []RequestFieldMatchers) []v2.MatcherViewV5 {
+	var views []v2.MatcherViewV5
+	for _, fieldMatcher := range fieldMatchers {
+		views = append(views, fieldMatcher.BuildView())
+	}
+	return views
+}
+
+// RequestMatcherResponsePair is a simulated response and the request it responds to. The id is assigned when the
+// pair is added to a simulation, unless it already has one, and a disabled pair is never matched.
 type RequestMatcherResponsePair struct {
+	Id             string
+	Disabled       bool
 	Labels         []string
 	RequestMatcher RequestMatcher
 	Response       ResponseDetails
+	Responses      []ResponseDetails
+	ResponsesMode  string
 }
 
 func NewRequestMatcherResponsePairFromView(view *v2.RequestMatcherResponsePairViewV5) *RequestMatcherResponsePair {
 
+	var responses []ResponseDetails
+	for _, responseView := range view.Responses {
+		responses = append(responses, NewResponseDetailsFromViewV5(responseView))
+	}
+
 	return &RequestMatcherResponsePair{
-		Labels: view.Labels,
-		RequestMatcher: RequestMatcher{
-			Path:          NewRequestFieldMatchersFromView(view.RequestMatcher.Path),
-			Method:        NewRequestFieldMatchersFromView(view.RequestMatcher.Method),
-			Destination:   NewRequestFieldMatchersFromView(view.RequestMatcher.Destination),
-			Scheme:        NewRequestFieldMatchersFromView(view.RequestMatcher.Scheme),
-			Body:          NewRequestFieldMatchersFromView(view.RequestMatcher.Body),
-			Headers:       NewRequestFieldMatchersFromMapView(view.RequestMatcher.Headers),
-			Query:         NewQueryRequestFieldMatchersFromMapView(view.RequestMatcher.Query),
-			RequiresState: view.RequestMatcher.RequiresState,
-		},
-		Response: NewResponseDetailsFromResponse(view.Response),
+		Id:             view.Id,
+		Disabled:       view.Disabled,
+		Labels:         view.Labels,
+		RequestMatcher: NewRequestMatcherFromView(view.RequestMatcher),
+		Response:       NewResponseDetailsFromViewV5(view.Response),
+		Responses:      responses,
+		ResponsesMode:  view.ResponsesMode,
 	}
 }
 
 func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePairViewV5 {
 
+	var responses []v2.ResponseDetailsViewV5
+	for _, response := range this.Responses {
+		responses = append(responses, response.ConvertToResponseDetailsViewV5())
+	}
+
+	return v2.RequestMatcherResponsePairViewV5{
+		Id:             this.Id,
+		Disabled:       this.Disabled,
+		Labels:         this.Labels,
+		RequestMatcher: this.RequestMatcher.BuildView(),
+		Response:       this.Response.ConvertToResponseDetailsViewV5(),
+		Responses:      responses,
+		ResponsesMode:  this.ResponsesMode,
+	}
+}
+
+func NewRequestMatcherFromView(view v2.RequestMatcherViewV5) RequestMatcher {
+
+	return RequestMatcher{
+		Path:          NewRequestFieldMatchersFromView(view.Path),
+		Method:        NewRequestFieldMatchersFromView(view.Method),
+		Destination:   NewRequestFieldMatchersFromView(view.Destination),
+		Scheme:        NewRequestFieldMatchersFromView(view.Scheme),
+		Body:          NewRequestFieldMatchersFromView(view.Body),
+		Headers:       NewRequestFieldMatchersFromMapView(view.Headers),
+		Query:         NewQueryRequestFieldMatchersFromMapView(view.Query),
+		RequiresState: view.RequiresState,
+	}
+}
+
+func (this RequestMatcher) BuildView() v2.RequestMatcherViewV5 {
+
 	var path, method, destination, scheme, body []v2.MatcherViewV5
 
-	if this.RequestMatcher.Path != nil && len(this.RequestMatcher.Path) != 0 {
+	if this.Path != nil && len(this.Path) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Path {
+		for _, matcher := range this.Path {
 			views = append(views, matcher.BuildView())
 		}
 		path = views
 	}
 
-	if this.RequestMatcher.Method != nil && len(this.RequestMatcher.Method) != 0 {
+	if this.Method != nil && len(this.Method) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Method {
+		for _, matcher := range this.Method {
 			views = append(views, matcher.BuildView())
 		}
 		method = views
 	}
 
-	if this.RequestMatcher.Destination != nil && len(this.RequestMatcher.Destination) != 0 {
+	if this.Destination != nil && len(this.Destination) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Destination {
+		for _, matcher := range this.Destination {
 			views = append(views, matcher.BuildView())
 		}
 		destination = views
 	}
 
-	if this.RequestMatcher.Scheme != nil && len(this.RequestMatcher.Scheme) != 0 {
+	if this.Scheme != nil && len(this.Scheme) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Scheme {
+		for _, matcher := range this.Scheme {
 			views = append(views, matcher.BuildView())
 		}
 		scheme = views
 	}
 
-	if this.RequestMatcher.Body != nil && len(this.RequestMatcher.Body) != 0 {
+	if this.Body != nil && len(this.Body) != 0 {
 		views := []v2.MatcherViewV5{}
-		for _, matcher := range this.RequestMatcher.Body {
+		for _, matcher := range this.Body {
 			views = append(views, matcher.BuildView())
 		}
 		body = views
 	}
 
 	headersWithMatchers := map[string][]v2.MatcherViewV5{}
-	for key, matchers := range this.RequestMatcher.Headers {
+	for key, matchers := range this.Headers {
 		views := []v2.MatcherViewV5{}
 		for _, matcher := range matchers {
 			views = append(views, matcher.BuildView())
@@ -220,9 +317,9 @@ func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePai
 	}
 
 	var queriesWithMatchers *v2.QueryMatcherViewV5
-	if this.RequestMatcher.Query != nil {
+	if this.Query != nil {
 		queriesWithMatchers = &v2.QueryMatcherViewV5{}
-		for key, matchers := range *this.RequestMatcher.Query {
+		for key, matchers := range *this.Query {
 			views := []v2.MatcherViewV5{}
 			for _, matcher := range matchers {
 				views = append(views, matcher.BuildView())
@@ -231,19 +328,15 @@ func (this *RequestMatcherResponsePair) BuildView() v2.RequestMatcherResponsePai
 		}
 	}
 
-	return v2.RequestMatcherResponsePairViewV5{
-		Labels: this.Labels,
-		RequestMatcher: v2.RequestMatcherViewV5{
-			Path:          path,
-			Method:        method,
-			Destination:   destination,
-			Scheme:        scheme,
-			Body:          body,
-			Headers:       headersWithMatchers,
-			Query:         queriesWithMatchers,
-			RequiresState: this.RequestMatcher.RequiresState,
-		},
-		Response: this.Response.ConvertToResponseDetailsViewV5(),
+	return v2.RequestMatcherViewV5{
+		Path:          path,
+		Method:        method,
+		Destination:   destination,
+		Scheme:        scheme,
+		Body:          body,
+		Headers:       headersWithMatchers,
+		Query:         queriesWithMatchers,
+		RequiresState: this.RequiresState,
 	}
 }
 
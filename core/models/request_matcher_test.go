@@ -64,6 +64,48 @@ func Test_NewRequestFieldMatchersFromView_WithFormMatchers(t *testing.T) {
 	Expect(unit[0].Value.(map[string][]models.RequestFieldMatchers)["test-key"][1].Value).To(Equal("*"))
 }
 
+func Test_NewRequestFieldMatchersFromView_WithMultipartMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewRequestFieldMatchersFromView([]v2.MatcherViewV5{
+		{
+			Matcher: "multipart",
+			Value: map[string]interface{}{
+				"file": map[string]interface{}{
+					"filename": []interface{}{
+						map[string]interface{}{"matcher": matchers.Glob, "value": "*.png"},
+					},
+					"headers": map[string]interface{}{
+						"X-Part": []interface{}{
+							map[string]interface{}{"matcher": matchers.Exact, "value": "yes"},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	Expect(unit).To(HaveLen(1))
+	Expect(unit[0].Value).To(Equal(map[string]models.MultipartPartMatchers{
+		"file": {
+			Filename: []models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "*.png"}},
+			Headers: map[string][]models.RequestFieldMatchers{
+				"X-Part": {{Matcher: matchers.Exact, Value: "yes"}},
+			},
+		},
+	}))
+
+	view := unit[0].BuildView()
+	Expect(view.Value).To(Equal(map[string]v2.MultipartPartMatcherViewV5{
+		"file": {
+			Filename: []v2.MatcherViewV5{{Matcher: matchers.Glob, Value: "*.png"}},
+			Headers: map[string][]v2.MatcherViewV5{
+				"X-Part": {{Matcher: matchers.Exact, Value: "yes"}},
+			},
+		},
+	}))
+}
+
 func Test_NewRequestFieldMatchers_BuildView(t *testing.T) {
 	RegisterTestingT(t)
 
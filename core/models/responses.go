@@ -0,0 +1,96 @@
+package models
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"strconv"
+
+	"github.com/SpectoLabs/hoverfly/core/state"
+)
+
+// Modes for choosing between the responses of a pair
+const (
+	// ResponsesSequence returns the responses in order, then keeps returning the last one
+	ResponsesSequence = "sequence"
+	// ResponsesCycle returns the responses in order, starting again after the last one
+	ResponsesCycle = "cycle"
+	// ResponsesRandom picks a response at random, in proportion to its weight
+	ResponsesRandom = "random"
+
+	// ResponsesStatePrefix starts the state keys holding the position in a sequence of responses
+	ResponsesStatePrefix = "responses:"
+)
+
+func IsValidResponsesMode(mode string) bool {
+	return mode == "" || mode == ResponsesSequence || mode == ResponsesCycle || mode == ResponsesRandom
+}
+
+func (this *RequestMatcherResponsePair) HasResponses() bool {
+	return len(this.Responses) > 0
+}
+
+// ResponsesStateKey returns the state key holding the position in the responses of the pair,
+// which is derived from its request matcher so that it is the same after exporting and importing
+func (this *RequestMatcherResponsePair) ResponsesStateKey() string {
+	requestMatcher, _ := json.Marshal(this.RequestMatcher.BuildView())
+	hash := sha1.Sum(requestMatcher)
+	return ResponsesStatePrefix + hex.EncodeToString(hash[:4])
+}
+
+// NextResponse returns the response to serve. For a pair with a sequence of responses the position
+// kept in state is moved along, and the changed state is returned so that it can be persisted.
+func (this *RequestMatcherResponsePair) NextResponse(state *state.State) (ResponseDetails, map[string]string) {
+	if !this.HasResponses() {
+		return this.Response, nil
+	}
+
+	count := len(this.Responses)
+	if this.ResponsesMode == ResponsesRandom {
+		return this.Responses[pickWeighted(this.Responses)], nil
+	}
+
+	key := this.ResponsesStateKey()
+	position, nextPosition := state.Advance(key, func(position int) int {
+		if position > count {
+			position = count
+		}
+		if position < count {
+			return position + 1
+		}
+		if this.ResponsesMode == ResponsesCycle {
+			return 1
+		}
+		return count
+	})
+	if position > count {
+		position = count
+	}
+
+	return this.Responses[position-1], map[string]string{key: strconv.Itoa(nextPosition)}
+}
+
+func pickWeighted(responses []ResponseDetails) int {
+	total := 0
+	for _, response := range responses {
+		total += weightOf(response)
+	}
+
+	pick := rand.Intn(total)
+	for i, response := range responses {
+		pick -= weightOf(response)
+		if pick < 0 {
+			return i
+		}
+	}
+	return len(responses) - 1
+}
+
+// Responses without a weight are as likely as each other. A weight of 0 is not imported, so it means no weight.
+func weightOf(response ResponseDetails) int {
+	if response.Weight == 0 {
+		return 1
+	}
+	return response.Weight
+}
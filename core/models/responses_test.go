@@ -0,0 +1,146 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	. "github.com/onsi/gomega"
+)
+
+func newPairWithResponses(mode string, responses ...models.ResponseDetails) *models.RequestMatcherResponsePair {
+	return &models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   "/flaky",
+				},
+			},
+		},
+		Responses:     responses,
+		ResponsesMode: mode,
+	}
+}
+
+func Test_IsValidResponsesMode(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(models.IsValidResponsesMode("")).To(BeTrue())
+	Expect(models.IsValidResponsesMode("sequence")).To(BeTrue())
+	Expect(models.IsValidResponsesMode("cycle")).To(BeTrue())
+	Expect(models.IsValidResponsesMode("random")).To(BeTrue())
+	Expect(models.IsValidResponsesMode("shuffle")).To(BeFalse())
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_ReturnsResponseWhenThereAreNoResponses(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &models.RequestMatcherResponsePair{
+		Response: models.ResponseDetails{Status: 200},
+	}
+
+	response, changedState := unit.NextResponse(state.NewState())
+
+	Expect(response.Status).To(Equal(200))
+	Expect(changedState).To(BeNil())
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_SequenceStopsAtLastResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newPairWithResponses("",
+		models.ResponseDetails{Status: 503},
+		models.ResponseDetails{Status: 500},
+		models.ResponseDetails{Status: 200},
+	)
+	s := state.NewState()
+
+	var statuses []int
+	for i := 0; i < 5; i++ {
+		response, _ := unit.NextResponse(s)
+		statuses = append(statuses, response.Status)
+	}
+
+	Expect(statuses).To(Equal([]int{503, 500, 200, 200, 200}))
+	Expect(s.State).To(Equal(map[string]string{unit.ResponsesStateKey(): "3"}))
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_CycleStartsAgainAfterLastResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newPairWithResponses(models.ResponsesCycle,
+		models.ResponseDetails{Status: 503},
+		models.ResponseDetails{Status: 200},
+	)
+	s := state.NewState()
+
+	var statuses []int
+	for i := 0; i < 5; i++ {
+		response, _ := unit.NextResponse(s)
+		statuses = append(statuses, response.Status)
+	}
+
+	Expect(statuses).To(Equal([]int{503, 200, 503, 200, 503}))
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_ReturnsChangedState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newPairWithResponses(models.ResponsesSequence,
+		models.ResponseDetails{Status: 503},
+		models.ResponseDetails{Status: 200},
+	)
+
+	_, changedState := unit.NextResponse(state.NewState())
+
+	Expect(changedState).To(Equal(map[string]string{unit.ResponsesStateKey(): "2"}))
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_SequenceStartsAgainWhenStateIsCleared(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newPairWithResponses(models.ResponsesSequence,
+		models.ResponseDetails{Status: 503},
+		models.ResponseDetails{Status: 200},
+	)
+	s := state.NewState()
+
+	unit.NextResponse(s)
+	unit.NextResponse(s)
+	s.SetState(map[string]string{})
+
+	response, _ := unit.NextResponse(s)
+	Expect(response.Status).To(Equal(503))
+}
+
+func Test_RequestMatcherResponsePair_NextResponse_RandomPicksByWeightWithoutState(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newPairWithResponses(models.ResponsesRandom,
+		models.ResponseDetails{Status: 503, Weight: 1},
+		models.ResponseDetails{Status: 200, Weight: 1000000},
+	)
+	s := state.NewState()
+
+	for i := 0; i < 20; i++ {
+		response, changedState := unit.NextResponse(s)
+		Expect(response.Status).To(Equal(200))
+		Expect(changedState).To(BeNil())
+	}
+	Expect(s.State).To(BeEmpty())
+}
+
+func Test_RequestMatcherResponsePair_ResponsesStateKey_IsDerivedFromRequestMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	first := newPairWithResponses("", models.ResponseDetails{Status: 200})
+	second := newPairWithResponses("", models.ResponseDetails{Status: 503})
+	other := newPairWithResponses("", models.ResponseDetails{Status: 200})
+	other.RequestMatcher.Path[0].Value = "/other"
+
+	Expect(first.ResponsesStateKey()).To(HavePrefix(models.ResponsesStatePrefix))
+	Expect(first.ResponsesStateKey()).To(Equal(second.ResponsesStateKey()))
+	Expect(first.ResponsesStateKey()).ToNot(Equal(other.ResponsesStateKey()))
+}
@@ -1,20 +1,34 @@
 package models
 
 import (
+	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/pborman/uuid"
 )
 
+// SimulationSource is a named part of a simulation, such as the pairs of one downstream service, which is
+// replaced or deleted as a whole
+type SimulationSource struct {
+	Name           string
+	MatchingPairs  []RequestMatcherResponsePair
+	WebSocketPairs []WebSocketPair
+}
+
 type Simulation struct {
 	matchingPairs           []RequestMatcherResponsePair
+	webSocketPairs          []WebSocketPair
+	sources                 []SimulationSource
 	ResponseDelays          ResponseDelays
 	ResponseDelaysLogNormal ResponseDelaysLogNormal
 	Vars                    *Variables
 	Literals                *Literals
+	CustomMatchers          *matchers.CustomMatchers
 	RWMutex                 sync.RWMutex
 }
 
@@ -22,10 +36,12 @@ func NewSimulation() *Simulation {
 
 	return &Simulation{
 		matchingPairs:           []RequestMatcherResponsePair{},
+		webSocketPairs:          []WebSocketPair{},
 		ResponseDelays:          &ResponseDelayList{},
 		ResponseDelaysLogNormal: &ResponseDelayLogNormalList{},
 		Literals:                &Literals{},
 		Vars:                    &Variables{},
+		CustomMatchers:          matchers.NewCustomMatchers(),
 	}
 }
 
@@ -40,6 +56,7 @@ func (this *Simulation) AddPair(pair *RequestMatcherResponsePair) bool {
 		}
 	}
 	if !duplicate {
+		this.assignPairId(pair)
 		this.matchingPairs = append(this.matchingPairs, *pair)
 	}
 	this.RWMutex.Unlock()
@@ -53,11 +70,19 @@ func (this *Simulation) AddPairWithOverwritingDuplicate(pair *RequestMatcherResp
 	for i, savedPair := range this.matchingPairs {
 		duplicate = reflect.DeepEqual(pair.RequestMatcher, savedPair.RequestMatcher)
 		if duplicate {
+			// The pair takes the place and, unless it has its own which no other pair has, the id of the one it
+			// overwrites
+			if pair.Id != savedPair.Id {
+				if _, _, index := this.findPair(pair.Id); pair.Id == "" || index >= 0 {
+					pair.Id = savedPair.Id
+				}
+			}
 			this.matchingPairs[i] = *pair
 			break
 		}
 	}
 	if !duplicate {
+		this.assignPairId(pair)
 		this.matchingPairs = append(this.matchingPairs, *pair)
 	}
 	this.RWMutex.Unlock()
@@ -66,6 +91,7 @@ func (this *Simulation) AddPairWithOverwritingDuplicate(pair *RequestMatcherResp
 
 func (this *Simulation) AddPairWithoutCheck(pair *RequestMatcherResponsePair) {
 	this.RWMutex.Lock()
+	this.assignPairId(pair)
 	this.matchingPairs = append(this.matchingPairs, *pair)
 	this.RWMutex.Unlock()
 }
@@ -135,26 +161,229 @@ func (this *Simulation) AddPairInSequence(pair *RequestMatcherResponsePair, stat
 		pair.RequestMatcher.RequiresState[sequenceKey] = strconv.Itoa(counter + 1)
 	}
 
+	this.assignPairId(pair)
 	this.matchingPairs = append(this.matchingPairs, *pair)
 	this.RWMutex.Unlock()
 }
 
+// GetMatchingPairs returns the pairs in the order they are matched, which is the pairs added to the simulation
+// followed by those of each source
 func (this *Simulation) GetMatchingPairs() []RequestMatcherResponsePair {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if len(this.sources) == 0 {
+		return this.matchingPairs
+	}
+
+	pairs := make([]RequestMatcherResponsePair, 0, len(this.matchingPairs))
+	pairs = append(pairs, this.matchingPairs...)
+	for _, source := range this.sources {
+		pairs = append(pairs, source.MatchingPairs...)
+	}
+	return pairs
+}
+
+// GetMatchingPairsWithoutSources returns the pairs added to the simulation rather than to one of its sources
+func (this *Simulation) GetMatchingPairsWithoutSources() []RequestMatcherResponsePair {
 	this.RWMutex.RLock()
 	pairs := this.matchingPairs
 	this.RWMutex.RUnlock()
 	return pairs
 }
 
+// Return a boolean indicates if the WebSocket pair is added or not.
+func (this *Simulation) AddWebSocketPair(pair *WebSocketPair) bool {
+	var duplicate bool
+	this.RWMutex.Lock()
+	for _, savedPair := range this.webSocketPairs {
+		duplicate = reflect.DeepEqual(pair.RequestMatcher, savedPair.RequestMatcher)
+		if duplicate {
+			break
+		}
+	}
+	if !duplicate {
+		this.webSocketPairs = append(this.webSocketPairs, *pair)
+	}
+	this.RWMutex.Unlock()
+	return !duplicate
+}
+
+func (this *Simulation) AddWebSocketPairWithOverwritingDuplicate(pair *WebSocketPair) bool {
+	var duplicate bool
+	this.RWMutex.Lock()
+	for i, savedPair := range this.webSocketPairs {
+		duplicate = reflect.DeepEqual(pair.RequestMatcher, savedPair.RequestMatcher)
+		if duplicate {
+			this.webSocketPairs[i] = *pair
+			break
+		}
+	}
+	if !duplicate {
+		this.webSocketPairs = append(this.webSocketPairs, *pair)
+	}
+	this.RWMutex.Unlock()
+	return !duplicate
+}
+
+func (this *Simulation) GetWebSocketPairs() []WebSocketPair {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	if len(this.sources) == 0 {
+		return this.webSocketPairs
+	}
+
+	pairs := make([]WebSocketPair, 0, len(this.webSocketPairs))
+	pairs = append(pairs, this.webSocketPairs...)
+	for _, source := range this.sources {
+		pairs = append(pairs, source.WebSocketPairs...)
+	}
+	return pairs
+}
+
+// GetWebSocketPairsWithoutSources returns the WebSocket pairs added to the simulation rather than to one of its
+// sources
+func (this *Simulation) GetWebSocketPairsWithoutSources() []WebSocketPair {
+	this.RWMutex.RLock()
+	pairs := this.webSocketPairs
+	this.RWMutex.RUnlock()
+	return pairs
+}
+
+// SetSource adds a source after the existing ones, or replaces the source of the same name where it is
+func (this *Simulation) SetSource(source SimulationSource) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	index := -1
+	for i, savedSource := range this.sources {
+		if savedSource.Name == source.Name {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		this.sources = append(this.sources, SimulationSource{})
+		index = len(this.sources) - 1
+	}
+
+	// The pairs are added one by one, so that each id is checked against every pair of the simulation and its
+	// sources, including those of this source already added. Any id which clashes is replaced.
+	this.sources[index] = SimulationSource{Name: source.Name, WebSocketPairs: source.WebSocketPairs}
+	for _, pair := range source.MatchingPairs {
+		this.assignPairId(&pair)
+		this.sources[index].MatchingPairs = append(this.sources[index].MatchingPairs, pair)
+	}
+}
+
+// DeleteSource removes a source, returning false if there is no source of that name
+func (this *Simulation) DeleteSource(name string) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	for i, source := range this.sources {
+		if source.Name == name {
+			this.sources = append(this.sources[:i:i], this.sources[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// GetSource returns the source of the given name
+func (this *Simulation) GetSource(name string) (SimulationSource, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	for _, source := range this.sources {
+		if source.Name == name {
+			return source, true
+		}
+	}
+	return SimulationSource{}, false
+}
+
+// GetSources returns the sources in the order they are matched
+func (this *Simulation) GetSources() []SimulationSource {
+	this.RWMutex.RLock()
+	sources := make([]SimulationSource, len(this.sources))
+	copy(sources, this.sources)
+	this.RWMutex.RUnlock()
+	return sources
+}
+
 func (this *Simulation) DeleteMatchingPairsAlongWithCustomData() {
 	var pairs []RequestMatcherResponsePair
 	this.RWMutex.Lock()
 	this.matchingPairs = pairs
+	this.webSocketPairs = nil
+	this.sources = nil
 	this.Literals = &Literals{}
 	this.Vars = &Variables{}
+	this.CustomMatchers.DeleteAll()
 	this.RWMutex.Unlock()
 }
 
+// Replace takes everything from another simulation, which should not be used afterwards, in one go, so that
+// matching never sees part of the old simulation alongside part of the new one. Alongside, if given, is run while
+// the simulation is locked, so that anything kept outside of it can be swapped at the same time.
+func (this *Simulation) Replace(simulation *Simulation, alongside func()) {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+	simulation.RWMutex.RLock()
+	defer simulation.RWMutex.RUnlock()
+
+	if alongside != nil {
+		alongside()
+	}
+
+	this.matchingPairs = simulation.matchingPairs
+	this.webSocketPairs = simulation.webSocketPairs
+	this.sources = simulation.sources
+	this.ResponseDelays = simulation.ResponseDelays
+	this.ResponseDelaysLogNormal = simulation.ResponseDelaysLogNormal
+	this.Vars = simulation.Vars
+	this.Literals = simulation.Literals
+	this.CustomMatchers.Replace(simulation.CustomMatchers)
+}
+
+func (this *Simulation) SetResponseDelays(responseDelays ResponseDelays) {
+	this.RWMutex.Lock()
+	this.ResponseDelays = responseDelays
+	this.RWMutex.Unlock()
+}
+
+func (this *Simulation) GetResponseDelays() ResponseDelays {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.ResponseDelays
+}
+
+func (this *Simulation) SetResponseDelaysLogNormal(responseDelaysLogNormal ResponseDelaysLogNormal) {
+	this.RWMutex.Lock()
+	this.ResponseDelaysLogNormal = responseDelaysLogNormal
+	this.RWMutex.Unlock()
+}
+
+func (this *Simulation) GetResponseDelaysLogNormal() ResponseDelaysLogNormal {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.ResponseDelaysLogNormal
+}
+
+func (this *Simulation) GetVariables() *Variables {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.Vars
+}
+
+func (this *Simulation) GetLiterals() *Literals {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+	return this.Literals
+}
+
 func (this *Simulation) AddVariables(variables *Variables) {
 	this.RWMutex.Lock()
 	this.Vars = variables
@@ -166,3 +395,117 @@ func (this *Simulation) AddLiterals(literals *Literals) {
 	this.Literals = literals
 	this.RWMutex.Unlock()
 }
+
+// GetPair returns the pair with the given id, and the name of the source it belongs to, which is empty for a
+// pair of the simulation itself
+func (this *Simulation) GetPair(id string) (RequestMatcherResponsePair, string, bool) {
+	this.RWMutex.RLock()
+	defer this.RWMutex.RUnlock()
+
+	pairs, source, index := this.findPair(id)
+	if index < 0 {
+		return RequestMatcherResponsePair{}, "", false
+	}
+	return pairs[index], source, true
+}
+
+// UpdatePair replaces the pair with the given id, keeping its id and its place
+func (this *Simulation) UpdatePair(id string, pair RequestMatcherResponsePair) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	pairs, _, index := this.findPair(id)
+	if index < 0 {
+		return false
+	}
+	pair.Id = id
+	pairs[index] = pair
+	return true
+}
+
+// SetPairDisabled disables or enables the pair with the given id
+func (this *Simulation) SetPairDisabled(id string, disabled bool) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	pairs, _, index := this.findPair(id)
+	if index < 0 {
+		return false
+	}
+	pairs[index].Disabled = disabled
+	return true
+}
+
+// DeletePair removes the pair with the given id
+func (this *Simulation) DeletePair(id string) bool {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	pairs, source, index := this.findPair(id)
+	if index < 0 {
+		return false
+	}
+	this.setPairs(source, append(pairs[:index:index], pairs[index+1:]...))
+	return true
+}
+
+// MovePair moves the pair with the given id to a position among the pairs of the simulation, or of the source it
+// belongs to, shifting the pairs after it
+func (this *Simulation) MovePair(id string, position int) error {
+	this.RWMutex.Lock()
+	defer this.RWMutex.Unlock()
+
+	pairs, source, index := this.findPair(id)
+	if index < 0 {
+		return fmt.Errorf("Pair %s not found", id)
+	}
+	if position < 0 || position >= len(pairs) {
+		return fmt.Errorf("Position %d is out of range, there are %d pairs", position, len(pairs))
+	}
+
+	pair := pairs[index]
+	moved := append(pairs[:index:index], pairs[index+1:]...)
+	moved = append(moved[:position:position], append([]RequestMatcherResponsePair{pair}, moved[position:]...)...)
+	this.setPairs(source, moved)
+	return nil
+}
+
+// findPair returns the pairs of the simulation, or of the source, holding the pair with the given id, along with
+// its index, which is -1 if there is no such pair
+func (this *Simulation) findPair(id string) ([]RequestMatcherResponsePair, string, int) {
+	for i, pair := range this.matchingPairs {
+		if pair.Id == id {
+			return this.matchingPairs, "", i
+		}
+	}
+	for _, source := range this.sources {
+		for i, pair := range source.MatchingPairs {
+			if pair.Id == id {
+				return source.MatchingPairs, source.Name, i
+			}
+		}
+	}
+	return nil, "", -1
+}
+
+func (this *Simulation) setPairs(sourceName string, pairs []RequestMatcherResponsePair) {
+	if sourceName == "" {
+		this.matchingPairs = pairs
+		return
+	}
+	for i, source := range this.sources {
+		if source.Name == sourceName {
+			this.sources[i].MatchingPairs = pairs
+		}
+	}
+}
+
+// assignPairId gives a pair a new id, unless it has one which no other pair has
+func (this *Simulation) assignPairId(pair *RequestMatcherResponsePair) {
+	if pair.Id != "" {
+		if _, _, index := this.findPair(pair.Id); index < 0 {
+			return
+		}
+	}
+	pair.Id = uuid.New()
+}
@@ -669,3 +669,313 @@ func Test_Simulation_DeleteMatchingPairs(t *testing.T) {
 
 	Expect(unit.GetMatchingPairs()).To(HaveLen(0))
 }
+
+func newDestinationPair(destination string) models.RequestMatcherResponsePair {
+	return models.RequestMatcherResponsePair{
+		RequestMatcher: models.RequestMatcher{
+			Destination: []models.RequestFieldMatchers{
+				{
+					Matcher: matchers.Exact,
+					Value:   destination,
+				},
+			},
+		},
+		Response: models.ResponseDetails{},
+	}
+}
+
+func Test_Simulation_GetMatchingPairs_MatchesSourcesAfterThePairsOfTheSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+	unit.SetSource(models.SimulationSource{
+		Name:          "users",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("users")},
+	})
+	pair := newDestinationPair("space")
+	unit.AddPair(&pair)
+
+	pairs := unit.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(3))
+	Expect(pairs[0].RequestMatcher.Destination[0].Value).To(Equal("space"))
+	Expect(pairs[1].RequestMatcher.Destination[0].Value).To(Equal("payments"))
+	Expect(pairs[2].RequestMatcher.Destination[0].Value).To(Equal("users"))
+
+	Expect(unit.GetMatchingPairsWithoutSources()).To(HaveLen(1))
+}
+
+func Test_Simulation_SetSource_ReplacesASourceInPlace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+	unit.SetSource(models.SimulationSource{
+		Name:          "users",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("users")},
+	})
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments-v2"), newDestinationPair("refunds")},
+	})
+
+	Expect(unit.GetSources()).To(HaveLen(2))
+	Expect(unit.GetSources()[0].Name).To(Equal("payments"))
+
+	pairs := unit.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(3))
+	Expect(pairs[0].RequestMatcher.Destination[0].Value).To(Equal("payments-v2"))
+	Expect(pairs[1].RequestMatcher.Destination[0].Value).To(Equal("refunds"))
+	Expect(pairs[2].RequestMatcher.Destination[0].Value).To(Equal("users"))
+}
+
+func Test_Simulation_DeleteSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+	unit.SetSource(models.SimulationSource{
+		Name:          "users",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("users")},
+	})
+
+	Expect(unit.DeleteSource("payments")).To(BeTrue())
+	Expect(unit.DeleteSource("payments")).To(BeFalse())
+
+	_, found := unit.GetSource("payments")
+	Expect(found).To(BeFalse())
+
+	source, found := unit.GetSource("users")
+	Expect(found).To(BeTrue())
+	Expect(source.MatchingPairs).To(HaveLen(1))
+
+	Expect(unit.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.GetMatchingPairs()[0].RequestMatcher.Destination[0].Value).To(Equal("users"))
+}
+
+func Test_Simulation_DeleteMatchingPairs_DeletesSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+
+	unit.DeleteMatchingPairsAlongWithCustomData()
+
+	Expect(unit.GetMatchingPairs()).To(HaveLen(0))
+	Expect(unit.GetSources()).To(HaveLen(0))
+}
+
+func getDestinations(pairs []models.RequestMatcherResponsePair) []interface{} {
+	var destinations []interface{}
+	for _, pair := range pairs {
+		destinations = append(destinations, pair.RequestMatcher.Destination[0].Value)
+	}
+	return destinations
+}
+
+func Test_Simulation_AddPair_GivesEachPairAnId(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	first := newDestinationPair("space")
+	first.Id = "space-pair"
+	unit.AddPair(&first)
+
+	second := newDestinationPair("moon")
+	second.Id = "space-pair"
+	unit.AddPair(&second)
+
+	third := newDestinationPair("mars")
+	unit.AddPair(&third)
+
+	pairs := unit.GetMatchingPairs()
+	Expect(pairs[0].Id).To(Equal("space-pair"))
+	Expect(pairs[1].Id).ToNot(BeEmpty())
+	Expect(pairs[1].Id).ToNot(Equal("space-pair"))
+	Expect(pairs[2].Id).ToNot(BeEmpty())
+	Expect(pairs[2].Id).ToNot(Equal(pairs[1].Id))
+}
+
+func Test_Simulation_SetSource_ReassignsIdsWhichClash(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	pair := newDestinationPair("space")
+	pair.Id = "shared"
+	unit.AddPair(&pair)
+
+	paymentsPair := newDestinationPair("payments")
+	paymentsPair.Id = "payments-pair"
+	unit.SetSource(models.SimulationSource{Name: "payments", MatchingPairs: []models.RequestMatcherResponsePair{paymentsPair}})
+
+	clashing := newDestinationPair("users")
+	clashing.Id = "shared"
+	alsoClashing := newDestinationPair("orders")
+	alsoClashing.Id = "payments-pair"
+	unit.SetSource(models.SimulationSource{Name: "users", MatchingPairs: []models.RequestMatcherResponsePair{clashing, alsoClashing}})
+
+	pairs := unit.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(4))
+	Expect(pairs[0].Id).To(Equal("shared"))
+	Expect(pairs[1].Id).To(Equal("payments-pair"))
+	Expect(pairs[2].Id).ToNot(BeElementOf("shared", "payments-pair", ""))
+	Expect(pairs[3].Id).ToNot(BeElementOf("shared", "payments-pair", "", pairs[2].Id))
+
+	// Replacing a source may keep the ids of its own pairs
+	unit.SetSource(models.SimulationSource{Name: "payments", MatchingPairs: []models.RequestMatcherResponsePair{paymentsPair}})
+	Expect(unit.GetMatchingPairs()[1].Id).To(Equal("payments-pair"))
+}
+
+func Test_Simulation_AddPairWithOverwritingDuplicate_DoesNotTakeAnIdInUse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	pair := newDestinationPair("space")
+	unit.AddPair(&pair)
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+	sourcePairId := unit.GetMatchingPairs()[1].Id
+
+	overwriting := newDestinationPair("space")
+	overwriting.Id = sourcePairId
+	unit.AddPairWithOverwritingDuplicate(&overwriting)
+
+	pairs := unit.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(2))
+	Expect(pairs[0].Id).To(Equal(pair.Id))
+	Expect(pairs[1].Id).To(Equal(sourcePairId))
+
+	overwriting.Id = "new-id"
+	unit.AddPairWithOverwritingDuplicate(&overwriting)
+	Expect(unit.GetMatchingPairs()[0].Id).To(Equal("new-id"))
+}
+
+func Test_Simulation_GetPair_FindsPairsOfSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	pair := newDestinationPair("space")
+	unit.AddPair(&pair)
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments")},
+	})
+
+	found, source, ok := unit.GetPair(pair.Id)
+	Expect(ok).To(BeTrue())
+	Expect(source).To(BeEmpty())
+	Expect(found.RequestMatcher.Destination[0].Value).To(Equal("space"))
+
+	found, source, ok = unit.GetPair(unit.GetMatchingPairs()[1].Id)
+	Expect(ok).To(BeTrue())
+	Expect(source).To(Equal("payments"))
+	Expect(found.RequestMatcher.Destination[0].Value).To(Equal("payments"))
+
+	_, _, ok = unit.GetPair("unknown")
+	Expect(ok).To(BeFalse())
+}
+
+func Test_Simulation_UpdatePair_KeepsIdAndPlace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	for _, destination := range []string{"space", "moon", "mars"} {
+		pair := newDestinationPair(destination)
+		unit.AddPair(&pair)
+	}
+	id := unit.GetMatchingPairs()[1].Id
+
+	Expect(unit.UpdatePair(id, newDestinationPair("venus"))).To(BeTrue())
+
+	pairs := unit.GetMatchingPairs()
+	Expect(getDestinations(pairs)).To(Equal([]interface{}{"space", "venus", "mars"}))
+	Expect(pairs[1].Id).To(Equal(id))
+
+	Expect(unit.UpdatePair("unknown", newDestinationPair("venus"))).To(BeFalse())
+}
+
+func Test_Simulation_SetPairDisabled(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	pair := newDestinationPair("space")
+	unit.AddPair(&pair)
+
+	Expect(unit.SetPairDisabled(pair.Id, true)).To(BeTrue())
+	Expect(unit.GetMatchingPairs()[0].Disabled).To(BeTrue())
+
+	Expect(unit.SetPairDisabled(pair.Id, false)).To(BeTrue())
+	Expect(unit.GetMatchingPairs()[0].Disabled).To(BeFalse())
+
+	Expect(unit.SetPairDisabled("unknown", true)).To(BeFalse())
+}
+
+func Test_Simulation_DeletePair_DeletesPairOfASource(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	pair := newDestinationPair("space")
+	unit.AddPair(&pair)
+	unit.SetSource(models.SimulationSource{
+		Name:          "payments",
+		MatchingPairs: []models.RequestMatcherResponsePair{newDestinationPair("payments"), newDestinationPair("refunds")},
+	})
+
+	Expect(unit.DeletePair(unit.GetMatchingPairs()[1].Id)).To(BeTrue())
+
+	Expect(getDestinations(unit.GetMatchingPairs())).To(Equal([]interface{}{"space", "refunds"}))
+	Expect(unit.GetSources()[0].MatchingPairs).To(HaveLen(1))
+
+	Expect(unit.DeletePair("unknown")).To(BeFalse())
+}
+
+func Test_Simulation_MovePair(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+
+	for _, destination := range []string{"space", "moon", "mars"} {
+		pair := newDestinationPair(destination)
+		unit.AddPair(&pair)
+	}
+	id := unit.GetMatchingPairs()[2].Id
+
+	Expect(unit.MovePair(id, 0)).To(Succeed())
+	Expect(getDestinations(unit.GetMatchingPairs())).To(Equal([]interface{}{"mars", "space", "moon"}))
+
+	Expect(unit.MovePair(id, 2)).To(Succeed())
+	Expect(getDestinations(unit.GetMatchingPairs())).To(Equal([]interface{}{"space", "moon", "mars"}))
+
+	err := unit.MovePair(id, 3)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Position 3 is out of range, there are 3 pairs"))
+
+	err = unit.MovePair("unknown", 0)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Pair unknown not found"))
+}
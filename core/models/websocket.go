@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/base64"
+	"time"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+)
+
+const (
+	WebSocketFromClient = "client"
+	WebSocketFromServer = "server"
+
+	WebSocketText   = "text"
+	WebSocketBinary = "binary"
+)
+
+// WebSocketMessage is a single message observed on a WebSocket connection
+type WebSocketMessage struct {
+	Direction string
+	Type      string
+	Data      string
+	Time      time.Time
+}
+
+// WebSocketFrame is a message sent by Hoverfly to the client when simulating
+// a WebSocket conversation. Delay is in milliseconds and is applied before the
+// frame is sent.
+type WebSocketFrame struct {
+	Type  string
+	Data  string
+	Delay int
+}
+
+// WebSocketReply holds the frames that are sent back when a client message
+// satisfies the matchers. Binary messages are matched on their base64 encoding.
+type WebSocketReply struct {
+	Matcher []RequestFieldMatchers
+	Frames  []WebSocketFrame
+}
+
+// WebSocketPair matches a WebSocket upgrade request. Frames are sent as soon as
+// the connection is established, and replies are sent in response to client messages.
+type WebSocketPair struct {
+	Labels         []string
+	RequestMatcher RequestMatcher
+	Frames         []WebSocketFrame
+	Replies        []WebSocketReply
+}
+
+func NewWebSocketPairFromView(view *v2.WebSocketPairViewV5) *WebSocketPair {
+	replies := []WebSocketReply{}
+	for _, reply := range view.Replies {
+		replies = append(replies, WebSocketReply{
+			Matcher: NewRequestFieldMatchersFromView(reply.Matcher),
+			Frames:  newWebSocketFramesFromView(reply.Frames),
+		})
+	}
+
+	return &WebSocketPair{
+		Labels:         view.Labels,
+		RequestMatcher: NewRequestMatcherFromView(view.RequestMatcher),
+		Frames:         newWebSocketFramesFromView(view.Frames),
+		Replies:        replies,
+	}
+}
+
+func (this *WebSocketPair) BuildView() v2.WebSocketPairViewV5 {
+	var replies []v2.WebSocketReplyViewV5
+	for _, reply := range this.Replies {
+		var matcherViews []v2.MatcherViewV5
+		for _, matcher := range reply.Matcher {
+			matcherViews = append(matcherViews, matcher.BuildView())
+		}
+		replies = append(replies, v2.WebSocketReplyViewV5{
+			Matcher: matcherViews,
+			Frames:  buildWebSocketFramesView(reply.Frames),
+		})
+	}
+
+	return v2.WebSocketPairViewV5{
+		Labels:         this.Labels,
+		RequestMatcher: this.RequestMatcher.BuildView(),
+		Frames:         buildWebSocketFramesView(this.Frames),
+		Replies:        replies,
+	}
+}
+
+// NewWebSocketPairFromMessages builds a simulated conversation from the messages captured on a
+// connection. Server messages sent before the first client message become the initial frames,
+// each client message becomes a reply with an exact matcher, and delays reproduce the captured timing.
+func NewWebSocketPairFromMessages(requestMatcher RequestMatcher, opened time.Time, messages []WebSocketMessage) *WebSocketPair {
+	pair := &WebSocketPair{
+		RequestMatcher: requestMatcher,
+		Frames:         []WebSocketFrame{},
+		Replies:        []WebSocketReply{},
+	}
+
+	previous := opened
+	for _, message := range messages {
+		delay := int(message.Time.Sub(previous) / time.Millisecond)
+		previous = message.Time
+
+		if message.Direction == WebSocketFromClient {
+			pair.Replies = append(pair.Replies, WebSocketReply{
+				Matcher: []RequestFieldMatchers{
+					{
+						Matcher: matchers.Exact,
+						Value:   message.MatchableData(),
+					},
+				},
+				Frames: []WebSocketFrame{},
+			})
+			continue
+		}
+
+		frame := WebSocketFrame{
+			Type:  message.Type,
+			Data:  message.Data,
+			Delay: delay,
+		}
+		if len(pair.Replies) == 0 {
+			pair.Frames = append(pair.Frames, frame)
+		} else {
+			last := &pair.Replies[len(pair.Replies)-1]
+			last.Frames = append(last.Frames, frame)
+		}
+	}
+
+	return pair
+}
+
+// MatchableData returns the message payload as it is compared against reply matchers
+func (this WebSocketMessage) MatchableData() string {
+	if this.Type == WebSocketBinary {
+		return base64.StdEncoding.EncodeToString([]byte(this.Data))
+	}
+	return this.Data
+}
+
+func (this WebSocketMessage) BuildView() v2.WebSocketMessageView {
+	data, encoded := encodeWebSocketData(this.Type, this.Data)
+	return v2.WebSocketMessageView{
+		Direction:   this.Direction,
+		Type:        this.Type,
+		Data:        data,
+		EncodedData: encoded,
+		Time:        this.Time,
+	}
+}
+
+func newWebSocketFramesFromView(views []v2.WebSocketFrameViewV5) []WebSocketFrame {
+	frames := []WebSocketFrame{}
+	for _, view := range views {
+		frameType := view.Type
+		if frameType == "" {
+			frameType = WebSocketText
+		}
+		data := view.Data
+		if view.EncodedData {
+			decoded, err := base64.StdEncoding.DecodeString(view.Data)
+			if err == nil {
+				data = string(decoded)
+			}
+		}
+		frames = append(frames, WebSocketFrame{
+			Type:  frameType,
+			Data:  data,
+			Delay: view.Delay,
+		})
+	}
+	return frames
+}
+
+func buildWebSocketFramesView(frames []WebSocketFrame) []v2.WebSocketFrameViewV5 {
+	var views []v2.WebSocketFrameViewV5
+	for _, frame := range frames {
+		data, encoded := encodeWebSocketData(frame.Type, frame.Data)
+		views = append(views, v2.WebSocketFrameViewV5{
+			Type:        frame.Type,
+			Data:        data,
+			EncodedData: encoded,
+			Delay:       frame.Delay,
+		})
+	}
+	return views
+}
+
+func encodeWebSocketData(messageType, data string) (string, bool) {
+	if messageType == WebSocketBinary {
+		return base64.StdEncoding.EncodeToString([]byte(data)), true
+	}
+	return data, false
+}
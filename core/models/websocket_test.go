@@ -0,0 +1,100 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewWebSocketPairFromMessages_SplitsConversationIntoFramesAndReplies(t *testing.T) {
+	RegisterTestingT(t)
+
+	opened := time.Now()
+	unit := models.NewWebSocketPairFromMessages(models.RequestMatcher{}, opened, []models.WebSocketMessage{
+		{Direction: models.WebSocketFromServer, Type: models.WebSocketText, Data: "welcome", Time: opened.Add(5 * time.Millisecond)},
+		{Direction: models.WebSocketFromClient, Type: models.WebSocketText, Data: "ping", Time: opened.Add(10 * time.Millisecond)},
+		{Direction: models.WebSocketFromServer, Type: models.WebSocketText, Data: "pong", Time: opened.Add(30 * time.Millisecond)},
+		{Direction: models.WebSocketFromClient, Type: models.WebSocketBinary, Data: "\x01\x02", Time: opened.Add(40 * time.Millisecond)},
+	})
+
+	Expect(unit.Frames).To(Equal([]models.WebSocketFrame{
+		{Type: models.WebSocketText, Data: "welcome", Delay: 5},
+	}))
+	Expect(unit.Replies).To(HaveLen(2))
+	Expect(unit.Replies[0].Matcher).To(Equal([]models.RequestFieldMatchers{
+		{Matcher: matchers.Exact, Value: "ping"},
+	}))
+	Expect(unit.Replies[0].Frames).To(Equal([]models.WebSocketFrame{
+		{Type: models.WebSocketText, Data: "pong", Delay: 20},
+	}))
+	Expect(unit.Replies[1].Matcher[0].Value).To(Equal("AQI="))
+	Expect(unit.Replies[1].Frames).To(BeEmpty())
+}
+
+func Test_WebSocketPair_BuildView_EncodesBinaryFrames(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.WebSocketPair{
+		Frames: []models.WebSocketFrame{
+			{Type: models.WebSocketBinary, Data: "\x01\x02\x03"},
+			{Type: models.WebSocketText, Data: "text", Delay: 10},
+		},
+	}
+
+	view := unit.BuildView()
+
+	Expect(view.Frames).To(Equal([]v2.WebSocketFrameViewV5{
+		{Type: "binary", Data: "AQID", EncodedData: true},
+		{Type: "text", Data: "text", Delay: 10},
+	}))
+}
+
+func Test_NewWebSocketPairFromView_DecodesFramesAndDefaultsToText(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewWebSocketPairFromView(&v2.WebSocketPairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/chat")},
+		},
+		Frames: []v2.WebSocketFrameViewV5{
+			{Data: "AQID", EncodedData: true, Type: "binary"},
+			{Data: "hello"},
+		},
+		Replies: []v2.WebSocketReplyViewV5{
+			{
+				Matcher: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "*")},
+				Frames:  []v2.WebSocketFrameViewV5{{Data: "reply"}},
+			},
+		},
+	})
+
+	Expect(unit.RequestMatcher.Path[0].Value).To(Equal("/chat"))
+	Expect(unit.Frames).To(Equal([]models.WebSocketFrame{
+		{Type: models.WebSocketBinary, Data: "\x01\x02\x03"},
+		{Type: models.WebSocketText, Data: "hello"},
+	}))
+	Expect(unit.Replies[0].Matcher[0].Matcher).To(Equal(matchers.Glob))
+	Expect(unit.Replies[0].Frames[0].Data).To(Equal("reply"))
+}
+
+func Test_Simulation_AddWebSocketPair_IgnoresDuplicateRequestMatcher(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := models.NewSimulation()
+	pair := &models.WebSocketPair{
+		RequestMatcher: models.RequestMatcher{
+			Path: []models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "/chat"}},
+		},
+	}
+
+	Expect(unit.AddWebSocketPair(pair)).To(BeTrue())
+	Expect(unit.AddWebSocketPair(pair)).To(BeFalse())
+	Expect(unit.GetWebSocketPairs()).To(HaveLen(1))
+
+	unit.DeleteMatchingPairsAlongWithCustomData()
+	Expect(unit.GetWebSocketPairs()).To(BeEmpty())
+}
@@ -32,6 +32,8 @@ func (this *CaptureMode) View() v2.ModeView {
 			Stateful:           this.Arguments.Stateful,
 			OverwriteDuplicate: this.Arguments.OverwriteDuplicate,
 			CaptureDelay:       this.Arguments.CaptureDelay,
+			CaptureRules:       captureRuleViews(this.Arguments.CaptureRules),
+			RedactionRules:     this.Arguments.Redactor.View(),
 		},
 	}
 }
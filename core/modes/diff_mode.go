@@ -97,6 +97,13 @@ func (this *DiffMode) Process(request *http.Request, details models.RequestDetai
 	return newProcessResult(actualResponse, actualPair.Response.FixedDelay, actualPair.Response.LogNormalDelay), nil
 }
 
+// NewDiffReport compares an expected response with an actual one in the same way as the Diff mode
+func NewDiffReport(expected *models.ResponseDetails, actual *models.ResponseDetails, headersBlacklist []string) v2.DiffReport {
+	diffMode := &DiffMode{DiffReport: v2.DiffReport{Timestamp: time.Now().Format(time.RFC3339)}}
+	diffMode.diffResponse(expected, actual, headersBlacklist)
+	return diffMode.DiffReport
+}
+
 func (this *DiffMode) diffResponse(expected *models.ResponseDetails, actual *models.ResponseDetails, headersBlacklist []string) {
 	if expected.Status != 0 && expected.Status != actual.Status {
 		this.addEntry("status", expected.Status, actual.Status)
@@ -8,12 +8,15 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/SpectoLabs/hoverfly/core/fault"
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/tracing"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 
 	"github.com/SpectoLabs/goproxy"
 	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/redaction"
 )
 
 // SimulateMode - default mode when Hoverfly looks for captured requests to respond
@@ -47,6 +50,16 @@ type ModeArguments struct {
 	OverwriteDuplicate bool
 	CaptureOnMiss      bool
 	CaptureDelay       bool
+	CaptureRules       []CaptureRule
+	Redactor           *redaction.Redactor
+}
+
+// CaptureRule turns the exact matcher captured for a field into a more general one
+type CaptureRule struct {
+	Field   string
+	Key     string
+	Matcher string
+	Value   string
 }
 
 type ProcessResult struct {
@@ -54,6 +67,7 @@ type ProcessResult struct {
 	FixedDelay                  int
 	LogNormalDelay              *models.ResponseDetailsLogNormal
 	PostServeActionInputDetails *PostServeActionInputDetails
+	Fault                       *fault.Fault
 }
 
 type PostServeActionInputDetails struct {
@@ -118,7 +132,7 @@ func ReconstructRequest(pair models.RequestResponsePair) (*http.Request, error)
 		newRequest.URL.RawQuery = pair.Request.GetRawQuery()
 	}
 
-	return newRequest, nil
+	return newRequest.WithContext(tracing.ContextWithSpan(newRequest.Context(), pair.Request.Span())), nil
 }
 
 // ReconstructResponse changes original response with details provided in Constructor Payload.Response
@@ -188,6 +202,19 @@ func GetResponseLogFields(response *models.ResponseDetails) *log.Fields {
 	}
 }
 
+func captureRuleViews(rules []CaptureRule) []v2.CaptureRuleView {
+	var views []v2.CaptureRuleView
+	for _, rule := range rules {
+		views = append(views, v2.CaptureRuleView{
+			Field:   rule.Field,
+			Key:     rule.Key,
+			Matcher: rule.Matcher,
+			Value:   rule.Value,
+		})
+	}
+	return views
+}
+
 func ReturnErrorAndLog(request *http.Request, err error, pair *models.RequestResponsePair, msg, mode string) (ProcessResult, error) {
 	log.WithFields(log.Fields{
 		"error":    err.Error(),
@@ -54,7 +54,7 @@ func (this SimulateMode) Process(request *http.Request, details models.RequestDe
 		return ReturnErrorAndLog(request, err, &pair, "There was an error when executing middleware", Simulate)
 	}
 
-	return newProcessResultWithPostServeActionInputDetails(
+	result := newProcessResultWithPostServeActionInputDetails(
 		ReconstructResponse(request, pair),
 		pair.Response.FixedDelay,
 		pair.Response.LogNormalDelay,
@@ -62,5 +62,9 @@ func (this SimulateMode) Process(request *http.Request, details models.RequestDe
 			PostServeAction: pair.Response.PostServeAction,
 			Pair:            &pair,
 		},
-	), nil
+	)
+	// Middleware does not see the fault, so it is taken from the simulated response
+	result.Fault = response.Fault
+
+	return result, nil
 }
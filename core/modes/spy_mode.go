@@ -35,6 +35,8 @@ func (this *SpyMode) View() v2.ModeView {
 			Headers:            this.Arguments.Headers,
 			OverwriteDuplicate: this.Arguments.OverwriteDuplicate,
 			CaptureDelay:       this.Arguments.CaptureDelay,
+			CaptureRules:       captureRuleViews(this.Arguments.CaptureRules),
+			RedactionRules:     this.Arguments.Redactor.View(),
 		},
 	}
 }
@@ -53,6 +55,8 @@ func (this *SpyMode) SetArguments(arguments ModeArguments) {
 		OverwriteDuplicate: arguments.OverwriteDuplicate,
 		CaptureOnMiss:      arguments.CaptureOnMiss,
 		CaptureDelay:       arguments.CaptureDelay,
+		CaptureRules:       arguments.CaptureRules,
+		Redactor:           arguments.Redactor,
 	}
 }
 
@@ -108,9 +112,12 @@ func (this SpyMode) Process(request *http.Request, details models.RequestDetails
 		return ReturnErrorAndLog(request, err, &pair, "There was an error when executing middleware", Spy)
 	}
 
-	return newProcessResult(
+	result := newProcessResult(
 		ReconstructResponse(request, pair),
 		pair.Response.FixedDelay,
 		pair.Response.LogNormalDelay,
-	), nil
+	)
+	result.Fault = response.Fault
+
+	return result, nil
 }
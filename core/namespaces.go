@@ -0,0 +1,148 @@
+package hoverfly
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+
+	"github.com/SpectoLabs/hoverfly/core/cache"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/journal"
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/resources"
+	"github.com/SpectoLabs/hoverfly/core/state"
+	"github.com/SpectoLabs/hoverfly/core/templating"
+	log "github.com/sirupsen/logrus"
+)
+
+// NamespaceHeader selects the namespace a request sent to the proxy or webserver is processed in
+const NamespaceHeader = "Hoverfly-Namespace"
+
+var namespaceName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]{0,63}$`)
+
+// Namespace returns the namespace with the name, creating it the first time it is used. Only the admin API
+// creates namespaces, so that anyone able to send traffic through the proxy cannot create an unbounded number of
+// them. A namespace has its own simulation, state, journal, cache and mode, so that test suites sharing one
+// Hoverfly do not interfere with each other. The empty name is the default namespace, which is this Hoverfly.
+func (hf *Hoverfly) Namespace(name string) (*Hoverfly, error) {
+	if name == "" {
+		return hf, nil
+	}
+	if !namespaceName.MatchString(name) {
+		return nil, fmt.Errorf("Invalid namespace %s, a namespace can only contain letters, digits, '_', '-' and '.'", name)
+	}
+
+	hf.namespacesMu.Lock()
+	defer hf.namespacesMu.Unlock()
+
+	if namespace, ok := hf.namespaces[name]; ok {
+		return namespace, nil
+	}
+
+	if hf.namespaces == nil {
+		hf.namespaces = map[string]*Hoverfly{}
+	}
+	namespace := hf.newNamespace()
+	hf.namespaces[name] = namespace
+
+	log.WithFields(log.Fields{
+		"namespace": name,
+	}).Info("Namespace created")
+
+	return namespace, nil
+}
+
+// GetNamespaces returns the names of the namespaces which have been created, in order
+func (hf *Hoverfly) GetNamespaces() v2.NamespacesView {
+	hf.namespacesMu.Lock()
+	defer hf.namespacesMu.Unlock()
+
+	names := []string{}
+	for name := range hf.namespaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return v2.NamespacesView{Namespaces: names}
+}
+
+// DeleteNamespace removes a namespace along with its simulation, state and journal
+func (hf *Hoverfly) DeleteNamespace(name string) error {
+	hf.namespacesMu.Lock()
+	defer hf.namespacesMu.Unlock()
+
+	if _, ok := hf.namespaces[name]; !ok {
+		return fmt.Errorf("Namespace %s not found", name)
+	}
+	delete(hf.namespaces, name)
+
+	return nil
+}
+
+// namespaceOf returns the namespace selected by the header of a request, removing the header so that it is
+// neither matched on nor forwarded. The namespace must already have been created with the admin API.
+func (hf *Hoverfly) namespaceOf(request *http.Request) (*Hoverfly, error) {
+	name := request.Header.Get(NamespaceHeader)
+	request.Header.Del(NamespaceHeader)
+	if name == "" {
+		return hf, nil
+	}
+
+	hf.namespacesMu.Lock()
+	defer hf.namespacesMu.Unlock()
+
+	namespace, ok := hf.namespaces[name]
+	if !ok {
+		return nil, fmt.Errorf("Namespace %s not found, a namespace is created by using it with the admin API", name)
+	}
+	return namespace, nil
+}
+
+// newNamespace creates a Hoverfly sharing the authentication, logs, HTTP client, post serve actions, gRPC
+// descriptors and settings of this one. The mode counters, metrics and tracer are shared as well, so they cover
+// the traffic of every namespace.
+func (hf *Hoverfly) newNamespace() *Hoverfly {
+	namespaceJournal := journal.NewJournal()
+	namespaceJournal.EntryLimit = hf.Journal.EntryLimit
+	namespaceJournal.BodySizeLimit = hf.Journal.BodySizeLimit
+
+	cfg := hf.Cfg.copy()
+
+	var requestCache cache.FastCache
+	if hf.CacheMatcher.RequestCache != nil {
+		if lruCache, err := cache.NewLRUCache(cfg.CacheSize); err == nil {
+			requestCache = lruCache
+		} else {
+			requestCache = cache.NewDefaultLRUCache()
+		}
+	}
+
+	namespace := &Hoverfly{
+		CacheMatcher: matching.CacheMatcher{
+			Webserver:    cfg.Webserver,
+			RequestCache: requestCache,
+		},
+		Authentication:         hf.Authentication,
+		HTTP:                   hf.HTTP,
+		Cfg:                    cfg,
+		Counter:                hf.Counter,
+		Metrics:                hf.Metrics,
+		Tracer:                 hf.Tracer,
+		version:                hf.version,
+		state:                  state.NewState(),
+		Simulation:             models.NewSimulation(),
+		Resources:              resources.NewResources(),
+		StoreLogsHook:          hf.StoreLogsHook,
+		Journal:                namespaceJournal,
+		templator:              templating.NewEnrichedTemplator(namespaceJournal),
+		PostServeActionDetails: hf.PostServeActionDetails,
+		responsesDiff:          make(map[v2.SimpleRequestDefinitionView][]v2.DiffReport),
+		grpcDescriptors:        hf.grpcDescriptors,
+	}
+	namespace.modeMap = newModeMap(namespace)
+	namespaceJournal.CustomMatchers = namespace.Simulation.CustomMatchers
+
+	return namespace
+}
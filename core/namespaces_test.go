@@ -0,0 +1,190 @@
+package hoverfly
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/go-zoo/bone"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Hoverfly_Namespace_ReturnsHoverflyForDefaultNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	namespace, err := unit.Namespace("")
+	Expect(err).To(BeNil())
+	Expect(namespace).To(BeIdenticalTo(unit))
+}
+
+func Test_Hoverfly_Namespace_CreatesNamespaceOnce(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	first, err := unit.Namespace("suite-a")
+	Expect(err).To(BeNil())
+	second, err := unit.Namespace("suite-a")
+	Expect(err).To(BeNil())
+
+	Expect(first).To(BeIdenticalTo(second))
+	Expect(first).ToNot(BeIdenticalTo(unit))
+	Expect(unit.GetNamespaces().Namespaces).To(ConsistOf("suite-a"))
+}
+
+func Test_Hoverfly_Namespace_RejectsInvalidName(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	_, err := unit.Namespace("suite/a")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Invalid namespace suite/a, a namespace can only contain letters, digits, '_', '-' and '.'"))
+	Expect(unit.GetNamespaces().Namespaces).To(BeEmpty())
+}
+
+func Test_Hoverfly_Namespace_IsIsolatedFromDefaultNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Mode: "simulate"})
+	unit.Journal.EntryLimit = 10
+	unit.SetState(map[string]string{"basket": "full"})
+
+	namespace, err := unit.Namespace("suite-a")
+	Expect(err).To(BeNil())
+
+	Expect(namespace.GetMode().Mode).To(Equal("simulate"))
+	Expect(namespace.Journal.EntryLimit).To(Equal(10))
+	Expect(namespace.GetState()).To(BeEmpty())
+
+	namespace.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{{
+				RequestMatcher: v2.RequestMatcherViewV5{
+					Path: []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "/hello"}},
+				},
+				Response: v2.ResponseDetailsViewV5{Status: 200, Body: "hello"},
+			}},
+		},
+		MetaView: v2.MetaView{SchemaVersion: "v5"},
+	})
+	Expect(namespace.SetMode("capture")).To(Succeed())
+
+	Expect(namespace.Simulation.GetMatchingPairs()).To(HaveLen(1))
+	Expect(unit.Simulation.GetMatchingPairs()).To(BeEmpty())
+	Expect(unit.GetMode().Mode).To(Equal("simulate"))
+	Expect(unit.GetState()).To(Equal(map[string]string{"basket": "full"}))
+}
+
+func Test_Hoverfly_Namespace_DoesNotShareCustomMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	namespace, _ := unit.Namespace("suite-a")
+
+	Expect(namespace.SetCustomMatcher(v2.CustomMatcherView{Name: "hmac", Remote: "http://localhost:8080/hmac"})).To(Succeed())
+
+	Expect(namespace.GetCustomMatchers().CustomMatchers).To(HaveLen(1))
+	Expect(unit.GetCustomMatchers().CustomMatchers).To(BeEmpty())
+
+	exported, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(exported.CustomMatchers).To(BeEmpty())
+}
+
+func Test_Hoverfly_DeleteNamespace_RemovesNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	namespace, _ := unit.Namespace("suite-a")
+	unit.Namespace("suite-b")
+
+	Expect(unit.DeleteNamespace("suite-a")).To(Succeed())
+	Expect(unit.GetNamespaces().Namespaces).To(Equal([]string{"suite-b"}))
+
+	recreated, _ := unit.Namespace("suite-a")
+	Expect(recreated).ToNot(BeIdenticalTo(namespace))
+}
+
+func Test_Hoverfly_DeleteNamespace_ErrorsForUnknownNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.DeleteNamespace("suite-a")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Namespace suite-a not found"))
+}
+
+func Test_Hoverfly_namespaceOf_RemovesNamespaceHeader(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	expected, _ := unit.Namespace("suite-a")
+
+	request, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
+	request.Header.Set(NamespaceHeader, "suite-a")
+
+	namespace, err := unit.namespaceOf(request)
+	Expect(err).To(BeNil())
+
+	Expect(namespace).To(BeIdenticalTo(expected))
+	Expect(request.Header).ToNot(HaveKey(NamespaceHeader))
+}
+
+func Test_Hoverfly_namespaceOf_DoesNotCreateNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	request, _ := http.NewRequest(http.MethodGet, "http://test.com", nil)
+	request.Header.Set(NamespaceHeader, "suite-a")
+
+	_, err := unit.namespaceOf(request)
+	Expect(err).To(MatchError("Namespace suite-a not found, a namespace is created by using it with the admin API"))
+	Expect(unit.GetNamespaces().Namespaces).To(BeEmpty())
+}
+
+func Test_namespacedRouter_SendsRequestsWithNamespaceParameterToNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{Mode: "simulate"})
+	router := adminApi.newNamespacedRouter(adminApi.addAdminApiRoutes(bone.New(), unit), unit)
+
+	request, _ := http.NewRequest(http.MethodPut, "/api/v2/hoverfly/mode?namespace=suite-a", bytes.NewBufferString(`{"mode": "capture"}`))
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	namespace, _ := unit.Namespace("suite-a")
+	Expect(namespace.GetMode().Mode).To(Equal("capture"))
+	Expect(unit.GetMode().Mode).To(Equal("simulate"))
+
+	request, _ = http.NewRequest(http.MethodGet, "/api/v2/namespaces", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	Expect(response.Code).To(Equal(http.StatusOK))
+
+	var namespacesView v2.NamespacesView
+	Expect(json.Unmarshal(response.Body.Bytes(), &namespacesView)).To(Succeed())
+	Expect(namespacesView.Namespaces).To(ConsistOf("suite-a"))
+}
+
+func Test_namespacedRouter_RejectsInvalidNamespace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	router := adminApi.newNamespacedRouter(adminApi.addAdminApiRoutes(bone.New(), unit), unit)
+
+	request, _ := http.NewRequest(http.MethodGet, "/api/v2/simulation?namespace=a%20b", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	Expect(response.Code).To(Equal(http.StatusBadRequest))
+}
@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// object is a mapping of an OpenAPI document, which keeps its keys in the order they were written
+// so that generated responses list their fields in the same order as the schema
+type object yaml.MapSlice
+
+func toObject(value interface{}) object {
+	if mapSlice, ok := value.(yaml.MapSlice); ok {
+		return object(mapSlice)
+	}
+	return nil
+}
+
+func (this object) lookup(key string) (interface{}, bool) {
+	for _, item := range this {
+		if fmt.Sprint(item.Key) == key {
+			return item.Value, true
+		}
+	}
+	return nil, false
+}
+
+func (this object) get(key string) interface{} {
+	value, _ := this.lookup(key)
+	return value
+}
+
+func (this object) object(key string) object {
+	return toObject(this.get(key))
+}
+
+func (this object) string(key string) string {
+	value, ok := this.get(key).(string)
+	if !ok {
+		return ""
+	}
+	return value
+}
+
+// keys returns the keys as strings, as YAML turns keys such as response codes into numbers
+func (this object) keys() []string {
+	keys := make([]string, 0, len(this))
+	for _, item := range this {
+		keys = append(keys, fmt.Sprint(item.Key))
+	}
+	return keys
+}
+
+// with returns the object with the value of the key replaced, or added after the existing keys
+func (this object) with(key string, value interface{}) object {
+	for i, item := range this {
+		if fmt.Sprint(item.Key) == key {
+			updated := append(object{}, this...)
+			updated[i].Value = value
+			return updated
+		}
+	}
+	return append(this, yaml.MapItem{Key: key, Value: value})
+}
+
+// toJson writes a value of the document as JSON, keeping the order of the keys of objects
+func toJson(value interface{}) string {
+	switch value := value.(type) {
+	case yaml.MapSlice:
+		fields := make([]string, 0, len(value))
+		for _, item := range value {
+			fields = append(fields, toJson(fmt.Sprint(item.Key))+":"+toJson(item.Value))
+		}
+		return "{" + strings.Join(fields, ",") + "}"
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, toJson(item))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	}
+
+	marshalled, err := json.Marshal(value)
+	if err != nil {
+		return "null"
+	}
+	return string(marshalled)
+}
@@ -0,0 +1,386 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	v1 "github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"gopkg.in/yaml.v2"
+)
+
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+var pathParameter = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// IsDocument returns true for an OpenAPI 3 document, in either YAML or JSON
+func IsDocument(data []byte) bool {
+	if !bytes.Contains(data, []byte("openapi")) {
+		return false
+	}
+	document, err := parse(data)
+	return err == nil && strings.HasPrefix(document.string("openapi"), "3.")
+}
+
+// NewSimulation generates a simulation from an OpenAPI 3 document, with a pair for every operation. The
+// response of a pair is the first successful response of the operation, using an example from the document
+// when there is one, or otherwise a template built from its schema.
+func NewSimulation(data []byte) (v2.SimulationViewV5, error) {
+	document, err := parse(data)
+	if err != nil {
+		return v2.SimulationViewV5{}, fmt.Errorf("invalid OpenAPI document: %s", err.Error())
+	}
+
+	version := document.string("openapi")
+	if !strings.HasPrefix(version, "3.") {
+		return v2.SimulationViewV5{}, errors.New("invalid OpenAPI document: only OpenAPI 3 is supported")
+	}
+
+	generator := &responseGenerator{components: document.object("components").object("schemas")}
+	basePath := serverBasePath(document)
+
+	pairs := []v2.RequestMatcherResponsePairViewV5{}
+	paths := document.object("paths")
+	for _, path := range paths.keys() {
+		pathItem := paths.object(path)
+		for _, method := range methods {
+			operation := pathItem.object(method)
+			if operation == nil {
+				continue
+			}
+
+			pair := v2.RequestMatcherResponsePairViewV5{
+				RequestMatcher: v2.RequestMatcherViewV5{
+					Path:   []v2.MatcherViewV5{pathMatcher(basePath + path)},
+					Method: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, strings.ToUpper(method))},
+				},
+				Response: generator.response(operation.object("responses")),
+			}
+
+			if operationId := operation.string("operationId"); operationId != "" {
+				pair.Labels = []string{operationId}
+			}
+
+			pairs = append(pairs, pair)
+		}
+	}
+
+	return v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: pairs,
+			GlobalActions: v2.GlobalActionsView{
+				Delays:          []v1.ResponseDelayView{},
+				DelaysLogNormal: []v1.ResponseDelayLogNormalView{},
+			},
+		},
+		MetaView: *v2.NewMetaView(""),
+	}, nil
+}
+
+// pathMatcher matches a path template such as /pets/{petId} with a regex, as a parameter matches a single segment
+func pathMatcher(path string) v2.MatcherViewV5 {
+	if !pathParameter.MatchString(path) {
+		return v2.NewMatcherView(matchers.Exact, path)
+	}
+
+	segments := pathParameter.Split(path, -1)
+	for i, segment := range segments {
+		segments[i] = regexp.QuoteMeta(segment)
+	}
+
+	return v2.NewMatcherView(matchers.Regex, "^"+strings.Join(segments, "[^/]+")+"$")
+}
+
+// serverBasePath returns the path of the first server, as operation paths are relative to it
+func serverBasePath(document object) string {
+	servers, _ := document.get("servers").([]interface{})
+	if len(servers) == 0 {
+		return ""
+	}
+
+	url := toObject(servers[0]).string("url")
+	if index := strings.Index(url, "://"); index >= 0 {
+		url = url[index+3:]
+		if slash := strings.Index(url, "/"); slash >= 0 {
+			url = url[slash:]
+		} else {
+			url = ""
+		}
+	}
+
+	return strings.TrimSuffix(url, "/")
+}
+
+type responseGenerator struct {
+	components object
+}
+
+func (this *responseGenerator) response(responses object) v2.ResponseDetailsViewV5 {
+	status, response := successfulResponse(responses)
+
+	view := v2.ResponseDetailsViewV5{
+		Status:  status,
+		Headers: map[string][]string{},
+	}
+
+	content := response.object("content")
+	mediaTypes := content.keys()
+	if len(mediaTypes) == 0 {
+		return view
+	}
+
+	mediaType := mediaTypes[0]
+	for _, candidate := range mediaTypes {
+		if isJson(candidate) {
+			mediaType = candidate
+			break
+		}
+	}
+	view.Headers["Content-Type"] = []string{mediaType}
+
+	media := content.object(mediaType)
+	schema := media.object("schema")
+
+	if example, ok := mediaExample(media, this.resolve(schema)); ok {
+		if isJson(mediaType) {
+			view.Body = toJson(example)
+		} else {
+			view.Body = fmt.Sprint(example)
+		}
+		return view
+	}
+
+	if schema == nil {
+		return view
+	}
+
+	if isJson(mediaType) {
+		view.Body = this.template(schema, "", "", map[string]bool{})
+	} else {
+		view.Body = scalarTemplate(this.resolve(schema), "")
+	}
+	view.Templated = true
+
+	return view
+}
+
+// successfulResponse picks the lowest 2xx response, falling back to the default response and then the first one
+func successfulResponse(responses object) (int, object) {
+	codes := responses.keys()
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		if status, err := strconv.Atoi(code); err == nil && status >= 200 && status < 300 {
+			return status, responses.object(code)
+		}
+	}
+
+	if response := responses.object("default"); response != nil {
+		return 200, response
+	}
+
+	for _, code := range codes {
+		if status, err := strconv.Atoi(code); err == nil {
+			return status, responses.object(code)
+		}
+	}
+
+	return 200, nil
+}
+
+func mediaExample(media object, schema object) (interface{}, bool) {
+	if example, ok := media.lookup("example"); ok {
+		return example, true
+	}
+
+	examples := media.object("examples")
+	for _, name := range examples.keys() {
+		if value, ok := examples.object(name).lookup("value"); ok {
+			return value, true
+		}
+	}
+
+	if example, ok := schema.lookup("example"); ok {
+		return example, true
+	}
+
+	return nil, false
+}
+
+// template writes indented JSON for a schema, where the values are template helpers generating random data.
+// The indentation also keeps the closing braces of helpers and objects apart, which raymond cannot parse.
+func (this *responseGenerator) template(schema object, name, indent string, visiting map[string]bool) string {
+	if ref := schema.string("$ref"); ref != "" {
+		if visiting[ref] {
+			return "null"
+		}
+		visiting[ref] = true
+		defer delete(visiting, ref)
+		schema = this.resolve(schema)
+	}
+
+	if example, ok := schema.lookup("example"); ok {
+		return toJson(example)
+	}
+
+	if enum, ok := schema.get("enum").([]interface{}); ok && len(enum) > 0 {
+		return toJson(enum[0])
+	}
+
+	for _, combination := range []string{"allOf", "oneOf", "anyOf"} {
+		if schemas, ok := schema.get(combination).([]interface{}); ok && len(schemas) > 0 {
+			if combination == "allOf" {
+				return this.template(this.mergeAllOf(schemas), name, indent, visiting)
+			}
+			return this.template(toObject(schemas[0]), name, indent, visiting)
+		}
+	}
+
+	switch schemaType(schema) {
+	case "object":
+		properties := schema.object("properties")
+		if len(properties) == 0 {
+			return "{}"
+		}
+		fields := []string{}
+		for _, property := range properties.keys() {
+			value := this.template(properties.object(property), property, indent+"  ", visiting)
+			fields = append(fields, indent+"  "+toJson(property)+": "+value)
+		}
+		return "{\n" + strings.Join(fields, ",\n") + "\n" + indent + "}"
+	case "array":
+		item := this.template(schema.object("items"), name, indent+"  ", visiting)
+		return "[\n" + indent + "  " + item + "\n" + indent + "]"
+	case "integer", "number", "boolean":
+		return scalarTemplate(schema, name)
+	}
+
+	return `"` + scalarTemplate(schema, name) + `"`
+}
+
+// mergeAllOf combines the properties of the schemas of an allOf into a single object schema
+func (this *responseGenerator) mergeAllOf(schemas []interface{}) object {
+	properties := object{}
+	for _, item := range schemas {
+		schema := this.resolve(toObject(item))
+		itemProperties := schema.object("properties")
+		for _, property := range itemProperties.keys() {
+			properties = properties.with(property, itemProperties.get(property))
+		}
+	}
+
+	return object{{Key: "type", Value: "object"}, {Key: "properties", Value: yaml.MapSlice(properties)}}
+}
+
+func (this *responseGenerator) resolve(schema object) object {
+	ref := schema.string("$ref")
+	if !strings.HasPrefix(ref, "#/components/schemas/") {
+		return schema
+	}
+	return this.components.object(strings.TrimPrefix(ref, "#/components/schemas/"))
+}
+
+// scalarTemplate returns a template helper for a value of the schema, picked by its type, format or name
+func scalarTemplate(schema object, name string) string {
+	switch schemaType(schema) {
+	case "integer":
+		minimum, hasMinimum := schema.lookup("minimum")
+		maximum, hasMaximum := schema.lookup("maximum")
+		if hasMinimum && hasMaximum {
+			return fmt.Sprintf("{{ randomIntegerRange %v %v }}", minimum, maximum)
+		}
+		return "{{ randomInteger }}"
+	case "number":
+		minimum, hasMinimum := schema.lookup("minimum")
+		maximum, hasMaximum := schema.lookup("maximum")
+		if hasMinimum && hasMaximum {
+			return fmt.Sprintf("{{ randomFloatRange %v %v }}", minimum, maximum)
+		}
+		return "{{ randomFloat }}"
+	case "boolean":
+		return "{{ randomBoolean }}"
+	}
+
+	switch schema.string("format") {
+	case "date-time":
+		return "{{ now '' '' }}"
+	case "date":
+		return "{{ now '' '2006-01-02' }}"
+	case "email":
+		return "{{ randomEmail }}"
+	case "uuid":
+		return "{{ randomUuid }}"
+	case "ipv4":
+		return "{{ randomIPv4 }}"
+	case "ipv6":
+		return "{{ randomIPv6 }}"
+	case "uri", "url":
+		return "{{ faker 'URL' }}"
+	case "hostname":
+		return "{{ faker 'DomainName' }}"
+	}
+
+	switch strings.ToLower(name) {
+	case "name", "fullname":
+		return "{{ faker 'Name' }}"
+	case "firstname":
+		return "{{ faker 'FirstName' }}"
+	case "lastname", "surname":
+		return "{{ faker 'LastName' }}"
+	case "email":
+		return "{{ randomEmail }}"
+	case "phone", "phonenumber":
+		return "{{ faker 'Phone' }}"
+	case "city":
+		return "{{ faker 'City' }}"
+	case "country":
+		return "{{ faker 'Country' }}"
+	case "company":
+		return "{{ faker 'Company' }}"
+	case "id", "uuid":
+		return "{{ randomUuid }}"
+	}
+
+	return "{{ randomString }}"
+}
+
+func schemaType(schema object) string {
+	switch schemaType := schema.get("type").(type) {
+	case string:
+		return schemaType
+	case []interface{}:
+		// OpenAPI 3.1 allows a list of types, such as [string, "null"]
+		for _, item := range schemaType {
+			if item != "null" {
+				return fmt.Sprint(item)
+			}
+		}
+	}
+
+	if schema.get("properties") != nil {
+		return "object"
+	}
+	if schema.get("items") != nil {
+		return "array"
+	}
+	return "string"
+}
+
+func isJson(mediaType string) bool {
+	mediaType = strings.ToLower(strings.Split(mediaType, ";")[0])
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func parse(data []byte) (object, error) {
+	var document yaml.MapSlice
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, err
+	}
+	return toObject(document), nil
+}
@@ -0,0 +1,151 @@
+package openapi
+
+import (
+	"os"
+	"testing"
+
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func readPetstore(t *testing.T) []byte {
+	data, err := os.ReadFile("testdata/petstore.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func Test_IsDocument(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(IsDocument(readPetstore(t))).To(BeTrue())
+	Expect(IsDocument([]byte(`{"openapi": "3.1.0", "paths": {}}`))).To(BeTrue())
+	Expect(IsDocument([]byte(`{"swagger": "2.0", "paths": {}}`))).To(BeFalse())
+	Expect(IsDocument([]byte(`{"data": {"pairs": []}, "meta": {"schemaVersion": "v5.3"}}`))).To(BeFalse())
+	Expect(IsDocument([]byte(`not an openapi document`))).To(BeFalse())
+}
+
+func Test_NewSimulation_ReturnsErrorForOtherDocuments(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewSimulation([]byte(`swagger: "2.0"`))
+	Expect(err).To(MatchError("invalid OpenAPI document: only OpenAPI 3 is supported"))
+
+	_, err = NewSimulation([]byte(`openapi: [`))
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_NewSimulation_CreatesPairForEachOperation(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulation(readPetstore(t))
+	Expect(err).To(BeNil())
+	Expect(simulation.SchemaVersion).To(Equal("v5.3"))
+
+	pairs := simulation.RequestResponsePairs
+	Expect(pairs).To(HaveLen(5))
+
+	Expect(pairs[0].Labels).To(Equal([]string{"listPets"}))
+	Expect(pairs[0].RequestMatcher.Method[0].Value).To(Equal("GET"))
+	Expect(pairs[0].RequestMatcher.Path[0].Matcher).To(Equal(matchers.Exact))
+	Expect(pairs[0].RequestMatcher.Path[0].Value).To(Equal("/v1/pets"))
+
+	Expect(pairs[1].RequestMatcher.Method[0].Value).To(Equal("POST"))
+
+	Expect(pairs[2].Labels).To(Equal([]string{"showPetById"}))
+	Expect(pairs[2].RequestMatcher.Path[0].Matcher).To(Equal(matchers.Regex))
+	Expect(pairs[2].RequestMatcher.Path[0].Value).To(Equal("^/v1/pets/[^/]+$"))
+
+	Expect(pairs[3].RequestMatcher.Method[0].Value).To(Equal("DELETE"))
+	Expect(pairs[4].RequestMatcher.Path[0].Value).To(Equal("/v1/health"))
+}
+
+func Test_NewSimulation_UsesExamples(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulation(readPetstore(t))
+	Expect(err).To(BeNil())
+
+	created := simulation.RequestResponsePairs[1].Response
+	Expect(created.Status).To(Equal(201))
+	Expect(created.Templated).To(BeFalse())
+	Expect(created.Headers["Content-Type"]).To(Equal([]string{"application/json"}))
+	Expect(created.Body).To(Equal(`{"id":10,"name":"Rex"}`))
+
+	health := simulation.RequestResponsePairs[4].Response
+	Expect(health.Headers["Content-Type"]).To(Equal([]string{"text/plain"}))
+	Expect(health.Body).To(Equal("OK"))
+}
+
+func Test_NewSimulation_TemplatesResponsesFromSchema(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulation(readPetstore(t))
+	Expect(err).To(BeNil())
+
+	pet := simulation.RequestResponsePairs[2].Response
+	Expect(pet.Status).To(Equal(200))
+	Expect(pet.Templated).To(BeTrue())
+	Expect(pet.Body).To(Equal(`{
+  "id": {{ randomIntegerRange 1 100 }},
+  "name": "{{ faker 'Name' }}",
+  "status": "available",
+  "createdAt": "{{ now '' '' }}",
+  "owner": {
+    "email": "{{ randomEmail }}",
+    "pets": [
+      null
+    ]
+  }
+}`))
+
+	pets := simulation.RequestResponsePairs[0].Response
+	Expect(pets.Body).To(HavePrefix("[\n  {\n    \"id\": {{ randomIntegerRange 1 100 }},"))
+}
+
+func Test_NewSimulation_ReturnsEmptyBodyWithoutContent(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulation(readPetstore(t))
+	Expect(err).To(BeNil())
+
+	deleted := simulation.RequestResponsePairs[3].Response
+	Expect(deleted.Status).To(Equal(204))
+	Expect(deleted.Body).To(BeEmpty())
+	Expect(deleted.Templated).To(BeFalse())
+}
+
+func Test_NewSimulation_MergesAllOfSchemas(t *testing.T) {
+	RegisterTestingT(t)
+
+	simulation, err := NewSimulation([]byte(`
+openapi: 3.1.0
+paths:
+  /things:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                allOf:
+                  - $ref: "#/components/schemas/Base"
+                  - type: object
+                    properties:
+                      enabled:
+                        type: boolean
+components:
+  schemas:
+    Base:
+      properties:
+        id:
+          type: string
+          format: uuid
+`))
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs[0].Response.Body).To(Equal(`{
+  "id": "{{ randomUuid }}",
+  "enabled": {{ randomBoolean }}
+}`))
+}
@@ -0,0 +1,170 @@
+package hoverfly
+
+import (
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/persistence"
+	log "github.com/sirupsen/logrus"
+)
+
+// SetPersistentStore reloads the simulation, state and template data sources kept by the store,
+// then writes every further change to them into it
+func (hf *Hoverfly) SetPersistentStore(store persistence.Store) error {
+	hf.store = nil
+
+	simulation, err := store.LoadSimulation()
+	if err != nil {
+		return err
+	}
+	if simulation != nil {
+		if err := hf.PutSimulation(*simulation).GetError(); err != nil {
+			return err
+		}
+		hf.CacheMatcher.PreloadCache(hf.Simulation)
+	}
+
+	sources, err := store.LoadSimulationSources()
+	if err != nil {
+		return err
+	}
+	for _, source := range sources {
+		if err := hf.PutSimulationSource(source.Name, source.Simulation).GetError(); err != nil {
+			return err
+		}
+	}
+
+	state, err := store.LoadState()
+	if err != nil {
+		return err
+	}
+	if len(state) > 0 {
+		hf.state.SetState(state)
+	}
+
+	dataSources, err := store.LoadDataSources()
+	if err != nil {
+		return err
+	}
+	for name, content := range dataSources {
+		if err := hf.SetCsvDataSource(name, content); err != nil {
+			return err
+		}
+	}
+
+	jsonDataSources, err := store.LoadJsonDataSources()
+	if err != nil {
+		return err
+	}
+	for _, dataSource := range jsonDataSources {
+		if err := hf.SetJsonDataSource(dataSource.Name, dataSource.Format, dataSource.Data); err != nil {
+			return err
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"pairs":           len(hf.Simulation.GetMatchingPairs()),
+		"sources":         len(sources),
+		"state":           len(state),
+		"dataSources":     len(dataSources),
+		"jsonDataSources": len(jsonDataSources),
+	}).Info("Loaded persisted data")
+
+	hf.store = store
+	return nil
+}
+
+func (hf *Hoverfly) persistPair(pair *models.RequestMatcherResponsePair) {
+	if hf.store == nil {
+		return
+	}
+	logPersistenceError(hf.store.AddPair(pair.BuildView()), "pair")
+}
+
+func (hf *Hoverfly) persistSimulation() {
+	if hf.store == nil {
+		return
+	}
+	// The sources are stored on their own, so that they can be reloaded as sources
+	simulation := hf.buildSimulationView(hf.Simulation.GetMatchingPairsWithoutSources(), hf.Simulation.GetWebSocketPairsWithoutSources(), nil, nil)
+	logPersistenceError(hf.store.SaveSimulation(simulation), "simulation")
+	hf.persistSimulationSources()
+}
+
+func (hf *Hoverfly) persistSimulationSources() {
+	if hf.store == nil {
+		return
+	}
+	var sources []persistence.SimulationSource
+	for _, source := range hf.Simulation.GetSources() {
+		sources = append(sources, persistence.SimulationSource{
+			Name:       source.Name,
+			Simulation: hf.buildSimulationSourceView(source),
+		})
+	}
+	logPersistenceError(hf.store.SaveSimulationSources(sources), "simulation sources")
+}
+
+func (hf *Hoverfly) persistState() {
+	if hf.store == nil {
+		return
+	}
+	hf.state.RWMutex.RLock()
+	state := make(map[string]string, len(hf.state.State))
+	for key, value := range hf.state.State {
+		state[key] = value
+	}
+	hf.state.RWMutex.RUnlock()
+
+	logPersistenceError(hf.store.SetState(state), "state")
+}
+
+func (hf *Hoverfly) persistStatePatch(toPatch map[string]string) {
+	if hf.store == nil || len(toPatch) == 0 {
+		return
+	}
+	logPersistenceError(hf.store.PatchState(toPatch), "state")
+}
+
+func (hf *Hoverfly) persistStateRemoval(keys []string) {
+	if hf.store == nil {
+		return
+	}
+	logPersistenceError(hf.store.RemoveState(keys), "state")
+}
+
+func (hf *Hoverfly) persistDataSource(name, content string) {
+	if hf.store == nil {
+		return
+	}
+	logPersistenceError(hf.store.SetDataSource(name, content), "templating data source")
+}
+
+func (hf *Hoverfly) persistDataSourceDeletion(name string) {
+	if hf.store == nil {
+		return
+	}
+	logPersistenceError(hf.store.DeleteDataSource(name), "templating data source")
+}
+
+func (hf *Hoverfly) persistJsonDataSource(name, format, content string) {
+	if hf.store == nil {
+		return
+	}
+	dataSource := v2.JSONDataSourceView{Name: name, Format: format, Data: content}
+	logPersistenceError(hf.store.SetJsonDataSource(dataSource), "templating data source")
+}
+
+func (hf *Hoverfly) persistJsonDataSourceDeletion(name string) {
+	if hf.store == nil {
+		return
+	}
+	logPersistenceError(hf.store.DeleteJsonDataSource(name), "templating data source")
+}
+
+func logPersistenceError(err error, what string) {
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Errorf("Failed to persist %s", what)
+	}
+}
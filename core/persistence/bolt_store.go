@@ -0,0 +1,328 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/cache"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/boltdb/bolt"
+)
+
+// DatabaseName - name of the BoltDB file created in the persist directory
+const DatabaseName = "hoverfly.db"
+
+var (
+	pairsBucket           = []byte("pairs")
+	simulationBucket      = []byte("simulation")
+	sourcesBucket         = []byte("simulationSources")
+	stateBucket           = []byte("state")
+	dataSourcesBucket     = []byte("templatingDataSources")
+	jsonDataSourcesBucket = []byte("templatingJsonDataSources")
+
+	simulationKey = []byte("simulation")
+	sourcesKey    = []byte("sources")
+)
+
+// BoltStore - Store which keeps every pair, state entry and data source as a separate BoltDB record,
+// so that each change is written on its own
+type BoltStore struct {
+	db              *bolt.DB
+	pairs           *cache.BoltCache
+	simulation      *cache.BoltCache
+	sources         *cache.BoltCache
+	state           *cache.BoltCache
+	dataSources     *cache.BoltCache
+	jsonDataSources *cache.BoltCache
+
+	mutex       sync.Mutex
+	nextPairKey int
+}
+
+// NewBoltStore - opens, or creates, the store in the given directory
+func NewBoltStore(directory string) (*BoltStore, error) {
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, err
+	}
+
+	db, err := bolt.Open(filepath.Join(directory, DatabaseName), 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s in %s: %s", DatabaseName, directory, err.Error())
+	}
+
+	store := &BoltStore{
+		db:              db,
+		pairs:           cache.NewBoltDBCache(db, pairsBucket),
+		simulation:      cache.NewBoltDBCache(db, simulationBucket),
+		sources:         cache.NewBoltDBCache(db, sourcesBucket),
+		state:           cache.NewBoltDBCache(db, stateBucket),
+		dataSources:     cache.NewBoltDBCache(db, dataSourcesBucket),
+		jsonDataSources: cache.NewBoltDBCache(db, jsonDataSourcesBucket),
+	}
+
+	keys, err := store.pairs.GetAllKeys()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	store.nextPairKey = len(keys)
+
+	return store, nil
+}
+
+func (this *BoltStore) AddPair(pair v2.RequestMatcherResponsePairViewV5) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	if err := this.putPair(pair); err != nil {
+		return err
+	}
+
+	// A simulation record marks the store as used, even if only pairs have been captured
+	if _, err := this.simulation.Get(simulationKey); err != nil {
+		return this.putSimulationWithoutPairs(v2.SimulationViewV5{})
+	}
+	return nil
+}
+
+// SaveSimulation replaces the pairs and the simulation record in a single transaction, so that a crash part way
+// through leaves the previous simulation stored
+func (this *BoltStore) SaveSimulation(simulation v2.SimulationViewV5) error {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	record, err := simulationRecord(simulation)
+	if err != nil {
+		return err
+	}
+
+	err = this.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(pairsBucket) != nil {
+			if err := tx.DeleteBucket(pairsBucket); err != nil {
+				return err
+			}
+		}
+		pairs, err := tx.CreateBucket(pairsBucket)
+		if err != nil {
+			return err
+		}
+		for i, pair := range simulation.RequestResponsePairs {
+			data, err := json.Marshal(pair)
+			if err != nil {
+				return err
+			}
+			if err := pairs.Put(pairKey(i), data); err != nil {
+				return err
+			}
+		}
+
+		bucket, err := tx.CreateBucketIfNotExists(simulationBucket)
+		if err != nil {
+			return err
+		}
+		return bucket.Put(simulationKey, record)
+	})
+	if err != nil {
+		return err
+	}
+
+	this.nextPairKey = len(simulation.RequestResponsePairs)
+	return nil
+}
+
+func (this *BoltStore) LoadSimulation() (*v2.SimulationViewV5, error) {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+
+	data, err := this.simulation.Get(simulationKey)
+	if err != nil {
+		return nil, nil
+	}
+
+	simulation := &v2.SimulationViewV5{}
+	if err := json.Unmarshal(data, simulation); err != nil {
+		return nil, err
+	}
+
+	entries, err := this.pairs.GetAllEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	simulation.RequestResponsePairs = []v2.RequestMatcherResponsePairViewV5{}
+	for _, key := range keys {
+		var pair v2.RequestMatcherResponsePairViewV5
+		if err := json.Unmarshal(entries[key], &pair); err != nil {
+			return nil, err
+		}
+		simulation.RequestResponsePairs = append(simulation.RequestResponsePairs, pair)
+	}
+
+	return simulation, nil
+}
+
+// SaveSimulationSources keeps the sources as a single record, as their order matters
+func (this *BoltStore) SaveSimulationSources(sources []SimulationSource) error {
+	data, err := json.Marshal(sources)
+	if err != nil {
+		return err
+	}
+	return this.sources.Set(sourcesKey, data)
+}
+
+func (this *BoltStore) LoadSimulationSources() ([]SimulationSource, error) {
+	data, err := this.sources.Get(sourcesKey)
+	if err != nil {
+		return nil, nil
+	}
+
+	var sources []SimulationSource
+	if err := json.Unmarshal(data, &sources); err != nil {
+		return nil, err
+	}
+	return sources, nil
+}
+
+func (this *BoltStore) SetState(state map[string]string) error {
+	if err := deleteAll(this.state); err != nil {
+		return err
+	}
+	return this.PatchState(state)
+}
+
+func (this *BoltStore) PatchState(toPatch map[string]string) error {
+	for key, value := range toPatch {
+		if err := this.state.Set([]byte(key), []byte(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *BoltStore) RemoveState(keys []string) error {
+	for _, key := range keys {
+		if err := this.state.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *BoltStore) LoadState() (map[string]string, error) {
+	return loadStrings(this.state)
+}
+
+func (this *BoltStore) SetDataSource(name, content string) error {
+	return this.dataSources.Set([]byte(name), []byte(content))
+}
+
+func (this *BoltStore) DeleteDataSource(name string) error {
+	return this.dataSources.Delete([]byte(name))
+}
+
+func (this *BoltStore) LoadDataSources() (map[string]string, error) {
+	return loadStrings(this.dataSources)
+}
+
+func (this *BoltStore) SetJsonDataSource(dataSource v2.JSONDataSourceView) error {
+	data, err := json.Marshal(dataSource)
+	if err != nil {
+		return err
+	}
+	return this.jsonDataSources.Set([]byte(dataSource.Name), data)
+}
+
+func (this *BoltStore) DeleteJsonDataSource(name string) error {
+	return this.jsonDataSources.Delete([]byte(name))
+}
+
+func (this *BoltStore) LoadJsonDataSources() ([]v2.JSONDataSourceView, error) {
+	values, err := loadStrings(this.jsonDataSources)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var dataSources []v2.JSONDataSourceView
+	for _, name := range names {
+		var dataSource v2.JSONDataSourceView
+		if err := json.Unmarshal([]byte(values[name]), &dataSource); err != nil {
+			return nil, err
+		}
+		dataSources = append(dataSources, dataSource)
+	}
+	return dataSources, nil
+}
+
+func (this *BoltStore) Close() error {
+	return this.db.Close()
+}
+
+func (this *BoltStore) putPair(pair v2.RequestMatcherResponsePairViewV5) error {
+	data, err := json.Marshal(pair)
+	if err != nil {
+		return err
+	}
+
+	if err := this.pairs.Set(pairKey(this.nextPairKey), data); err != nil {
+		return err
+	}
+	this.nextPairKey++
+	return nil
+}
+
+// pairKey is zero padded to keep the pairs in the order they were added, as BoltDB sorts keys bytewise
+func pairKey(index int) []byte {
+	return []byte(fmt.Sprintf("%016d", index))
+}
+
+func (this *BoltStore) putSimulationWithoutPairs(simulation v2.SimulationViewV5) error {
+	data, err := simulationRecord(simulation)
+	if err != nil {
+		return err
+	}
+	return this.simulation.Set(simulationKey, data)
+}
+
+// simulationRecord is the simulation without its pairs, which are stored separately
+func simulationRecord(simulation v2.SimulationViewV5) ([]byte, error) {
+	simulation.RequestResponsePairs = nil
+	return json.Marshal(simulation)
+}
+
+func deleteAll(bucket *cache.BoltCache) error {
+	count, err := bucket.RecordsCount()
+	if err != nil || count == 0 {
+		return err
+	}
+	return bucket.DeleteData()
+}
+
+func loadStrings(bucket *cache.BoltCache) (map[string]string, error) {
+	entries, err := bucket.GetAllEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{}
+	for key, value := range entries {
+		values[key] = string(value)
+	}
+	return values, nil
+}
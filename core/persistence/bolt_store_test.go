@@ -0,0 +1,197 @@
+package persistence
+
+import (
+	"testing"
+
+	v1 "github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func newPairView(path string) v2.RequestMatcherResponsePairViewV5 {
+	return v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, path)},
+		},
+		Response: v2.ResponseDetailsViewV5{
+			Status: 200,
+			Body:   "body for " + path,
+		},
+	}
+}
+
+func Test_BoltStore_LoadSimulation_ReturnsNilWhenNothingIsStored(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	simulation, err := unit.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation).To(BeNil())
+}
+
+func Test_BoltStore_AddPair_KeepsPairsInOrderAcrossReopening(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	unit, err := NewBoltStore(directory)
+	Expect(err).To(BeNil())
+
+	for _, path := range []string{"/1", "/2", "/3", "/4", "/5", "/6", "/7", "/8", "/9", "/10"} {
+		Expect(unit.AddPair(newPairView(path))).To(Succeed())
+	}
+	Expect(unit.Close()).To(Succeed())
+
+	unit, err = NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.AddPair(newPairView("/11"))).To(Succeed())
+
+	simulation, err := unit.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(11))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/1"))
+	Expect(simulation.RequestResponsePairs[9].RequestMatcher.Path[0].Value).To(Equal("/10"))
+	Expect(simulation.RequestResponsePairs[10].RequestMatcher.Path[0].Value).To(Equal("/11"))
+}
+
+func Test_BoltStore_SaveSimulation_ReplacesStoredPairs(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.AddPair(newPairView("/old"))).To(Succeed())
+	Expect(unit.SaveSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{newPairView("/new")},
+			GlobalActions: v2.GlobalActionsView{
+				Delays: []v1.ResponseDelayView{{UrlPattern: ".", Delay: 100}},
+			},
+		},
+	})).To(Succeed())
+
+	simulation, err := unit.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+	Expect(simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/new"))
+	Expect(simulation.GlobalActions.Delays).To(HaveLen(1))
+
+	Expect(unit.SaveSimulation(v2.SimulationViewV5{})).To(Succeed())
+	simulation, err = unit.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(BeEmpty())
+}
+
+func Test_BoltStore_AddPair_AppendsAfterSavedSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.SaveSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{newPairView("/1"), newPairView("/2")},
+		},
+	})).To(Succeed())
+	Expect(unit.AddPair(newPairView("/3"))).To(Succeed())
+
+	simulation, err := unit.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(3))
+	Expect(simulation.RequestResponsePairs[2].RequestMatcher.Path[0].Value).To(Equal("/3"))
+}
+
+func Test_BoltStore_State(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.SetState(map[string]string{"one": "1", "two": "2"})).To(Succeed())
+	Expect(unit.PatchState(map[string]string{"two": "II", "three": "3"})).To(Succeed())
+	Expect(unit.RemoveState([]string{"one"})).To(Succeed())
+
+	state, err := unit.LoadState()
+	Expect(err).To(BeNil())
+	Expect(state).To(Equal(map[string]string{"two": "II", "three": "3"}))
+
+	Expect(unit.SetState(map[string]string{})).To(Succeed())
+	state, err = unit.LoadState()
+	Expect(err).To(BeNil())
+	Expect(state).To(BeEmpty())
+}
+
+func Test_BoltStore_DataSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.SetDataSource("students", "id,name\n1,Test")).To(Succeed())
+	Expect(unit.SetDataSource("teachers", "id,name\n1,Teacher")).To(Succeed())
+	Expect(unit.DeleteDataSource("teachers")).To(Succeed())
+
+	dataSources, err := unit.LoadDataSources()
+	Expect(err).To(BeNil())
+	Expect(dataSources).To(Equal(map[string]string{"students": "id,name\n1,Test"}))
+}
+
+func Test_BoltStore_JsonDataSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewBoltStore(t.TempDir())
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	Expect(unit.SetJsonDataSource(v2.JSONDataSourceView{Name: "users", Format: "json", Data: `{"users": []}`})).To(Succeed())
+	Expect(unit.SetJsonDataSource(v2.JSONDataSourceView{Name: "teams", Format: "yaml", Data: "teams: []"})).To(Succeed())
+	Expect(unit.SetJsonDataSource(v2.JSONDataSourceView{Name: "roles", Format: "json", Data: `[]`})).To(Succeed())
+	Expect(unit.DeleteJsonDataSource("roles")).To(Succeed())
+
+	dataSources, err := unit.LoadJsonDataSources()
+	Expect(err).To(BeNil())
+	Expect(dataSources).To(Equal([]v2.JSONDataSourceView{
+		{Name: "teams", Format: "yaml", Data: "teams: []"},
+		{Name: "users", Format: "json", Data: `{"users": []}`},
+	}))
+}
+
+func Test_BoltStore_SaveSimulationSources_KeepsSourcesInOrderAcrossReopening(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	unit, err := NewBoltStore(directory)
+	Expect(err).To(BeNil())
+
+	sources, err := unit.LoadSimulationSources()
+	Expect(err).To(BeNil())
+	Expect(sources).To(BeEmpty())
+
+	payments := v2.SimulationViewV5{DataViewV5: v2.DataViewV5{RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{newPairView("/payments")}}}
+	accounts := v2.SimulationViewV5{DataViewV5: v2.DataViewV5{RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{newPairView("/accounts")}}}
+	Expect(unit.SaveSimulationSources([]SimulationSource{
+		{Name: "payments", Simulation: payments},
+		{Name: "accounts", Simulation: accounts},
+	})).To(Succeed())
+	Expect(unit.Close()).To(Succeed())
+
+	unit, err = NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	defer unit.Close()
+
+	sources, err = unit.LoadSimulationSources()
+	Expect(err).To(BeNil())
+	Expect(sources).To(HaveLen(2))
+	Expect(sources[0].Name).To(Equal("payments"))
+	Expect(sources[0].Simulation.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/payments"))
+	Expect(sources[1].Name).To(Equal("accounts"))
+}
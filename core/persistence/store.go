@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+)
+
+// Store keeps what Hoverfly records on disk, so that captured pairs, state and
+// template data sources can be reloaded when Hoverfly is restarted
+type Store interface {
+	// AddPair appends a single pair to the stored simulation
+	AddPair(pair v2.RequestMatcherResponsePairViewV5) error
+	// SaveSimulation replaces the stored simulation
+	SaveSimulation(simulation v2.SimulationViewV5) error
+	// LoadSimulation returns the stored simulation, or nil if nothing has been stored yet
+	LoadSimulation() (*v2.SimulationViewV5, error)
+
+	// SaveSimulationSources replaces the stored simulation sources
+	SaveSimulationSources(sources []SimulationSource) error
+	LoadSimulationSources() ([]SimulationSource, error)
+
+	SetState(state map[string]string) error
+	PatchState(toPatch map[string]string) error
+	RemoveState(keys []string) error
+	LoadState() (map[string]string, error)
+
+	SetDataSource(name, content string) error
+	DeleteDataSource(name string) error
+	LoadDataSources() (map[string]string, error)
+
+	SetJsonDataSource(dataSource v2.JSONDataSourceView) error
+	DeleteJsonDataSource(name string) error
+	LoadJsonDataSources() ([]v2.JSONDataSourceView, error)
+
+	Close() error
+}
+
+// SimulationSource is a named simulation source, which is stored apart from the simulation so that it can
+// still be replaced on its own once reloaded
+type SimulationSource struct {
+	Name       string              `json:"name"`
+	Simulation v2.SimulationViewV5 `json:"simulation"`
+}
@@ -0,0 +1,107 @@
+package hoverfly
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/persistence"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Hoverfly_SetPersistentStore_ReloadsWhatWasRecordedBeforeRestart(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	store, err := persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+
+	unit := NewHoverfly()
+	Expect(unit.SetPersistentStore(store)).To(Succeed())
+
+	Expect(unit.Save(&models.RequestDetails{
+		Method:      "GET",
+		Scheme:      "http",
+		Destination: "test.com",
+		Path:        "/captured",
+	}, &models.ResponseDetails{
+		Status: 200,
+		Body:   "captured body",
+	}, &modes.ModeArguments{})).To(Succeed())
+	unit.SetState(map[string]string{"logged-in": "true"})
+	unit.PatchState(map[string]string{"basket": "empty"})
+	Expect(unit.SetCsvDataSource("students", "id,name\n1,Test")).To(Succeed())
+	Expect(unit.SetJsonDataSource("teachers", "yaml", "teachers:\n- name: Test\n")).To(Succeed())
+	Expect(store.Close()).To(Succeed())
+
+	store, err = persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	defer store.Close()
+
+	restarted := NewHoverfly()
+	Expect(restarted.SetPersistentStore(store)).To(Succeed())
+
+	pairs := restarted.Simulation.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(1))
+	Expect(pairs[0].RequestMatcher.Path[0].Value).To(Equal("/captured"))
+	Expect(pairs[0].Response.Body).To(Equal("captured body"))
+	Expect(restarted.GetState()).To(Equal(map[string]string{"logged-in": "true", "basket": "empty"}))
+	Expect(restarted.GetAllDataSources().DataSources).To(HaveLen(1))
+	Expect(restarted.GetAllDataSources().DataSources[0].Name).To(Equal("students"))
+	Expect(restarted.GetAllDataSources().JsonDataSources).To(Equal([]v2.JSONDataSourceView{
+		{Name: "teachers", Format: "yaml", Data: "teachers:\n- name: Test\n"},
+	}))
+}
+
+func Test_Hoverfly_Shutdown_ClosesPersistentStore(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	store, err := persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+
+	unit := NewHoverfly()
+	Expect(unit.SetPersistentStore(store)).To(Succeed())
+	unit.Shutdown()
+
+	// BoltDB only lets one process open its file at a time, so reopening fails unless the store was closed
+	reopened, err := persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	reopened.Close()
+}
+
+func Test_Hoverfly_DeleteSimulation_ClearsPersistedPairs(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	store, err := persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	defer store.Close()
+
+	unit := NewHoverfly()
+	Expect(unit.SetPersistentStore(store)).To(Succeed())
+	Expect(unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{v2.NewMatcherView("exact", "/imported")},
+					},
+					Response: v2.ResponseDetailsViewV5{Status: 200},
+				},
+			},
+		},
+		MetaView: *v2.NewMetaView("test"),
+	}).GetError()).To(BeNil())
+
+	simulation, err := store.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+
+	unit.DeleteSimulation()
+
+	simulation, err = store.LoadSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.RequestResponsePairs).To(BeEmpty())
+}
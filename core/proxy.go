@@ -2,8 +2,10 @@ package hoverfly
 
 import (
 	"bytes"
+	"crypto/tls"
 	"encoding/base64"
 	"io"
+	stdlog "log"
 	"net"
 	"net/http"
 	"net/url"
@@ -17,6 +19,7 @@ import (
 	"github.com/SpectoLabs/goproxy/ext/auth"
 	"github.com/SpectoLabs/hoverfly/core/authentication"
 	"github.com/SpectoLabs/hoverfly/core/authentication/backends"
+	"github.com/SpectoLabs/hoverfly/core/fault"
 	"github.com/SpectoLabs/hoverfly/core/util"
 	log "github.com/sirupsen/logrus"
 )
@@ -51,12 +54,19 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 		}
 		r.URL.Scheme = "http"
 		r.URL.Host = r.Host
-		proxy.ServeHTTP(w, r)
+		proxyHandler(hoverfly, proxy).ServeHTTP(w, r)
 	})
 
+	mitmConnect := &goproxy.ConnectAction{
+		Action: goproxy.ConnectHijack,
+		Hijack: func(req *http.Request, client net.Conn, ctx *goproxy.ProxyCtx) {
+			hoverfly.mitmConnect(proxy, req, client, ctx)
+		},
+	}
+
 	if hoverfly.Cfg.AuthEnabled {
 		log.Info("Enabling proxy authentication")
-		proxyBasicAndBearer(proxy, "hoverfly", func(user, password string) bool {
+		proxyBasicAndBearer(proxy, "hoverfly", mitmConnect, func(user, password string) bool {
 
 			proxyUser := &backends.User{
 				Username: user,
@@ -76,16 +86,23 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 			if hoverfly.Cfg.PlainHttpTunneling && !strings.HasSuffix(host, ":443") {
 				return goproxy.HTTPMitmConnect, host
 			}
-			return goproxy.MitmConnect, host
+			return mitmConnect, host
 		}))
 
 	// processing connections
 	proxy.OnRequest(matchesFilter(hoverfly.Cfg.Destination)).DoFunc(
 		func(r *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response) {
 			startTime := time.Now()
-			resp, journalIDChannel := hoverfly.processRequest(r)
-			id, _ := hoverfly.Journal.NewEntry(r, resp, hoverfly.Cfg.Mode, startTime)
+			namespace, err := hoverfly.namespaceOf(r)
+			if err != nil {
+				return r, goproxy.NewResponse(r, goproxy.ContentTypeText, http.StatusBadRequest, err.Error())
+			}
+			ctx.UserData = namespace
+			resp, journalIDChannel := namespace.processRequest(r)
+			id, _ := namespace.Journal.NewEntry(r, resp, namespace.Cfg.GetMode(), startTime)
 			sendJournalIDToPostServeAction(journalIDChannel, id)
+			namespace.encodeGrpcResponse(r, resp)
+			declareResponseTrailers(r, resp)
 			return r, resp
 		})
 
@@ -106,7 +123,15 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	// intercepts response
 	proxy.OnResponse(matchesFilter(hoverfly.Cfg.Destination)).DoFunc(
 		func(resp *http.Response, ctx *goproxy.ProxyCtx) *http.Response {
-			hoverfly.Counter.Count(hoverfly.Cfg.GetMode())
+			status := 0
+			if resp != nil {
+				status = resp.StatusCode
+			}
+			if namespace, ok := ctx.UserData.(*Hoverfly); ok {
+				namespace.countRequest(namespace.Cfg.GetMode(), ctx.Req, status)
+			} else {
+				hoverfly.countRequest(hoverfly.Cfg.GetMode(), ctx.Req, status)
+			}
 			return resp
 		})
 
@@ -121,6 +146,120 @@ func NewProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	return proxy
 }
 
+// mitmConnect takes over a CONNECT tunnel and serves the requests sent through it, decrypting them
+// first if the client starts a TLS handshake. Unlike the goproxy MITM, it lets Hoverfly take part
+// in WebSocket conversations instead of copying the upgraded connection blindly.
+func (hf *Hoverfly) mitmConnect(proxy *goproxy.ProxyHttpServer, req *http.Request, client net.Conn, ctx *goproxy.ProxyCtx) {
+	if _, err := client.Write([]byte("HTTP/1.0 200 OK\r\n\r\n")); err != nil {
+		client.Close()
+		return
+	}
+
+	host := req.URL.Host
+	go func() {
+		conn := newPeekedConn(client)
+		firstByte, err := conn.reader.Peek(1)
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		scheme := "http"
+		var tunnelConn net.Conn = conn
+		if firstByte[0] == tlsHandshakeRecordType {
+			tlsConfig, err := goproxy.MitmConnect.TLSConfig(host, ctx)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"error": err.Error(),
+					"host":  host,
+				}).Error("Failed to create TLS configuration for CONNECT tunnel")
+				conn.Close()
+				return
+			}
+			tlsConfig = tlsConfig.Clone()
+			tlsConfig.NextProtos = []string{"h2", "http/1.1"}
+			scheme = "https"
+			tunnelConn = tls.Server(conn, tlsConfig)
+		}
+
+		server := &http.Server{
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				r.URL.Scheme = scheme
+				r.URL.Host = host
+				r.RemoteAddr = req.RemoteAddr
+				if scheme == "https" && r.ProtoMajor == 1 && !isWebSocketRequest(r) {
+					w = &chunkedResponseWriter{ResponseWriter: w, request: r}
+				}
+				proxyHandler(hf, proxy).ServeHTTP(w, r)
+			}),
+			ErrorLog:  stdlog.New(io.Discard, "", 0),
+			Protocols: newServerProtocols(),
+		}
+		server.Serve(newSingleConnListener(tunnelConn))
+	}()
+}
+
+// chunkedResponseWriter keeps the behaviour of the goproxy MITM for HTTPS responses, which are
+// always sent with chunked transfer encoding and close the tunnel once written
+type chunkedResponseWriter struct {
+	http.ResponseWriter
+	request *http.Request
+}
+
+func (w *chunkedResponseWriter) WriteHeader(statusCode int) {
+	if w.request.Method != http.MethodHead && statusCode != http.StatusNoContent {
+		w.Header().Del("Content-Length")
+	}
+	w.Header().Set("Connection", "close")
+	w.ResponseWriter.WriteHeader(statusCode)
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *chunkedResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *chunkedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// proxyHandler hands WebSocket upgrades for matching destinations to Hoverfly before they reach goproxy,
+// which would otherwise tunnel the upgraded connection without Hoverfly seeing the messages. Other
+// responses are written through a fault.ResponseWriter, so that simulated network faults can be injected.
+func proxyHandler(hoverfly *Hoverfly, proxy *goproxy.ProxyHttpServer) http.Handler {
+	filter := matchesFilter(hoverfly.Cfg.Destination)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect && r.URL.IsAbs() && isWebSocketRequest(r) && filter(r, nil) {
+			namespace, err := hoverfly.namespaceOf(r)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			namespace.serveWebSocket(w, r)
+			return
+		}
+		if r.Method == http.MethodConnect || isWebSocketRequest(r) {
+			proxy.ServeHTTP(w, r)
+			return
+		}
+		serveWithFaults(w, r, proxy.ServeHTTP)
+	})
+}
+
+// serveWithFaults writes the response through a fault.ResponseWriter, so that the fault of a
+// simulated response can be injected once the request has been processed
+func serveWithFaults(w http.ResponseWriter, r *http.Request, serve func(http.ResponseWriter, *http.Request)) {
+	faultWriter, faultRequest, created := fault.NewResponseWriter(w, r)
+	if created {
+		defer faultWriter.Finish()
+	}
+	serve(faultWriter, faultRequest)
+}
+
 func sendJournalIDToPostServeAction(journalIDChannel chan string, id string) {
 	if journalIDChannel != nil {
 		journalIDChannel <- id
@@ -136,29 +275,18 @@ func NewWebserverProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	proxy.NonproxyHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
 		r.URL.Scheme = "http"
-		resp, journalIDChannel := hoverfly.processRequest(r)
-		id, _ := hoverfly.Journal.NewEntry(r, resp, hoverfly.Cfg.Mode, startTime)
-		sendJournalIDToPostServeAction(journalIDChannel, id)
-		body, err := util.GetResponseBody(resp)
-
+		namespace, err := hoverfly.namespaceOf(r)
 		if err != nil {
-			log.Error("Error reading response body")
-			w.WriteHeader(500)
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-
-		for name, values := range resp.Header {
-			name = strings.ToLower(name)
-
-			for _, value := range values {
-				w.Header().Add(name, value)
-			}
+		if isWebSocketRequest(r) {
+			namespace.serveWebSocket(w, r)
+			return
 		}
-
-		w.WriteHeader(resp.StatusCode)
-		w.Write([]byte(body))
-
-		hoverfly.Counter.Count(hoverfly.Cfg.GetMode())
+		serveWithFaults(w, r, func(w http.ResponseWriter, r *http.Request) {
+			namespace.serveWebserverRequest(w, r, startTime)
+		})
 	})
 
 	if hoverfly.Cfg.Verbose {
@@ -184,6 +312,37 @@ func NewWebserverProxy(hoverfly *Hoverfly) *goproxy.ProxyHttpServer {
 	return proxy
 }
 
+// serveWebserverRequest processes a request sent to Hoverfly as a webserver and writes the response
+func (hf *Hoverfly) serveWebserverRequest(w http.ResponseWriter, r *http.Request, startTime time.Time) {
+	resp, journalIDChannel := hf.processRequest(r)
+	id, _ := hf.Journal.NewEntry(r, resp, hf.Cfg.Mode, startTime)
+	sendJournalIDToPostServeAction(journalIDChannel, id)
+	hf.encodeGrpcResponse(r, resp)
+	declareResponseTrailers(r, resp)
+	body, err := util.GetResponseBody(resp)
+
+	if err != nil {
+		log.Error("Error reading response body")
+		w.WriteHeader(500)
+		return
+	}
+
+	for name, values := range resp.Header {
+		if !strings.HasPrefix(name, http.TrailerPrefix) {
+			name = strings.ToLower(name)
+		}
+
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+
+	w.WriteHeader(resp.StatusCode)
+	w.Write([]byte(body))
+
+	hf.countRequest(hf.Cfg.GetMode(), r, resp.StatusCode)
+}
+
 func unauthorizedError(request *http.Request, realm, message string) *http.Response {
 	response := auth.BasicUnauthorized(request, realm)
 	response.Body = io.NopCloser(bytes.NewBuffer([]byte(message)))
@@ -192,7 +351,7 @@ func unauthorizedError(request *http.Request, realm, message string) *http.Respo
 	return response
 }
 
-func proxyBasicAndBearer(proxy *goproxy.ProxyHttpServer, realm string, basicFunc func(user, passwd string) bool, bearerFunc func(token string) bool) {
+func proxyBasicAndBearer(proxy *goproxy.ProxyHttpServer, realm string, connectAction *goproxy.ConnectAction, basicFunc func(user, passwd string) bool, bearerFunc func(token string) bool) {
 
 	proxy.OnRequest().Do(goproxy.FuncReqHandler(func(req *http.Request, ctx *goproxy.ProxyCtx) (*http.Request, *http.Response) {
 		if strings.HasSuffix(req.URL.Host, ":443") {
@@ -211,7 +370,7 @@ func proxyBasicAndBearer(proxy *goproxy.ProxyHttpServer, realm string, basicFunc
 			ctx.Resp = unauthorizedError(ctx.Req, realm, err.Error())
 			return goproxy.RejectConnect, host
 		}
-		return goproxy.MitmConnect, host
+		return connectAction, host
 	}))
 }
 
@@ -2,13 +2,17 @@ package hoverfly
 
 import (
 	"bufio"
+	"crypto/tls"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
 
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
 	. "github.com/onsi/gomega"
 )
 
@@ -392,3 +396,86 @@ func Test_matchesFilter_ShouldGetHostNameFromRequest(t *testing.T) {
 	}, nil)
 	Expect(httpResult).To(BeTrue())
 }
+
+func newFaultSimulation(fault *v2.FaultViewV5) v2.SimulationViewV5 {
+	return v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Path: []v2.MatcherViewV5{
+							v2.NewMatcherView(matchers.Exact, "/faulty"),
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   "0123456789",
+						Fault:  fault,
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_NewWebserverProxy_InjectsFaultOfSimulatedResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	unit.Cfg.Webserver = true
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(newFaultSimulation(&v2.FaultViewV5{Type: "connectionReset"})).GetError()).To(BeNil())
+
+	server := httptest.NewServer(NewWebserverProxy(unit))
+	defer server.Close()
+
+	_, err := http.Get(server.URL + "/faulty")
+	Expect(err).ToNot(BeNil())
+}
+
+func Test_NewProxy_InjectsFaultOfSimulatedResponse(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(newFaultSimulation(&v2.FaultViewV5{Type: "truncatedBody", Bytes: 4})).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}}
+
+	response, err := client.Get("http://test.com/faulty")
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+	body, err := io.ReadAll(response.Body)
+	Expect(err).To(MatchError(io.ErrUnexpectedEOF))
+	Expect(string(body)).To(Equal("0123"))
+}
+
+func Test_NewProxy_InjectsMalformedChunkedEncodingThroughConnectTunnel(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(newFaultSimulation(&v2.FaultViewV5{Type: "malformedChunkedEncoding"})).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+
+	response, err := client.Get("https://test.com/faulty")
+	Expect(err).To(BeNil())
+	Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+	_, err = io.ReadAll(response.Body)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("chunk"))
+}
@@ -0,0 +1,383 @@
+package redaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/beevik/etree"
+)
+
+const DefaultPlaceholder = "[REDACTED]"
+
+type rule struct {
+	view     v2.RedactionRuleView
+	jsonPath []interface{}
+	xpath    etree.Path
+	regex    *regexp.Regexp
+}
+
+// Redactor replaces the values selected by its rules in requests, request matchers and responses. It returns
+// redacted copies, leaving what it is given untouched.
+type Redactor struct {
+	rules []rule
+}
+
+// NewRedactor returns nil when there are no rules, which redacts nothing
+func NewRedactor(views []v2.RedactionRuleView) (*Redactor, error) {
+	if len(views) == 0 {
+		return nil, nil
+	}
+
+	redactor := &Redactor{}
+	for _, view := range views {
+		selectors := 0
+		for _, selector := range []string{view.Header, view.Query, view.JsonPath, view.XPath, view.Regex} {
+			if selector != "" {
+				selectors++
+			}
+		}
+		if selectors != 1 {
+			return nil, fmt.Errorf("Redaction rule must have exactly one of header, query, jsonPath, xpath or regex")
+		}
+
+		rule := rule{view: view}
+		var err error
+		if view.JsonPath != "" {
+			if !strings.HasPrefix(view.JsonPath, "$") {
+				return nil, fmt.Errorf("Redaction rule jsonPath %s must start with $", view.JsonPath)
+			}
+			if rule.jsonPath, err = util.SplitJsonPath(view.JsonPath); err != nil {
+				return nil, err
+			}
+			if len(rule.jsonPath) == 0 {
+				return nil, fmt.Errorf("JSONPath %s does not select a field", view.JsonPath)
+			}
+		}
+		if view.XPath != "" {
+			if rule.xpath, err = etree.CompilePath(view.XPath); err != nil {
+				return nil, fmt.Errorf("Redaction rule xpath %s is not valid: %s", view.XPath, err.Error())
+			}
+		}
+		if view.Regex != "" {
+			if rule.regex, err = regexp.Compile(view.Regex); err != nil {
+				return nil, fmt.Errorf("Redaction rule regex %s is not valid: %s", view.Regex, err.Error())
+			}
+		}
+		redactor.rules = append(redactor.rules, rule)
+	}
+	return redactor, nil
+}
+
+// View returns the rules of the redactor
+func (this *Redactor) View() []v2.RedactionRuleView {
+	if this == nil {
+		return nil
+	}
+	var views []v2.RedactionRuleView
+	for _, rule := range this.rules {
+		views = append(views, rule.view)
+	}
+	return views
+}
+
+// Request redacts the headers, query, form data and body of a request
+func (this *Redactor) Request(request models.RequestDetails) models.RequestDetails {
+	if this == nil {
+		return request
+	}
+	request.Headers = this.headers(request.Headers)
+	request.Query = this.values(request.Query, func(rule rule) string { return rule.view.Query })
+	request.FormData = this.values(request.FormData, func(rule rule) string { return rule.view.Query })
+	request.Body = this.Body(request.Body)
+	return request
+}
+
+// Response redacts the headers and body of a response
+func (this *Redactor) Response(response models.ResponseDetails) models.ResponseDetails {
+	if this == nil {
+		return response
+	}
+	response.Headers = this.headers(response.Headers)
+	response.Body = this.Body(response.Body)
+	return response
+}
+
+// RequestMatcher redacts the values of the exact and array matchers of a request matcher. Patterns such as globs
+// and regexes are left alone, as they do not hold the values of a particular request.
+func (this *Redactor) RequestMatcher(requestMatcher models.RequestMatcher) models.RequestMatcher {
+	if this == nil {
+		return requestMatcher
+	}
+
+	requestMatcher.Headers = this.mapMatchers(requestMatcher.Headers, func(rule rule, key string) bool {
+		return rule.view.Header != "" && strings.EqualFold(rule.view.Header, key)
+	})
+	if requestMatcher.Query != nil {
+		query := models.QueryRequestFieldMatchers(this.mapMatchers(*requestMatcher.Query, func(rule rule, key string) bool {
+			return rule.view.Query == key
+		}))
+		requestMatcher.Query = &query
+	}
+	requestMatcher.Body = this.bodyMatchers(requestMatcher.Body)
+	return requestMatcher
+}
+
+// Body redacts the JSON paths, XPaths and regexes of the rules in a body
+func (this *Redactor) Body(body string) string {
+	if this == nil || body == "" || !utf8.ValidString(body) {
+		return body
+	}
+
+	for _, rule := range this.rules {
+		switch {
+		case rule.jsonPath != nil:
+			body = redactJson(body, rule)
+		case rule.view.XPath != "":
+			body = redactXml(body, rule)
+		case rule.regex != nil:
+			body = redactRegex(body, rule)
+		}
+	}
+	return body
+}
+
+func (this *Redactor) headers(headers map[string][]string) map[string][]string {
+	return this.values(headers, func(rule rule) string { return rule.view.Header })
+}
+
+// values redacts a map of values, where the keys selected by the rules are not case sensitive
+func (this *Redactor) values(values map[string][]string, selector func(rule) string) map[string][]string {
+	if values == nil {
+		return nil
+	}
+
+	redacted := make(map[string][]string, len(values))
+	for key, keyValues := range values {
+		redactedValues := make([]string, len(keyValues))
+		for i, value := range keyValues {
+			for _, rule := range this.rules {
+				if selected := selector(rule); selected != "" && strings.EqualFold(selected, key) {
+					value = rule.replacement(value)
+				} else if rule.regex != nil {
+					value = redactRegex(value, rule)
+				}
+			}
+			redactedValues[i] = value
+		}
+		redacted[key] = redactedValues
+	}
+	return redacted
+}
+
+func (this *Redactor) mapMatchers(fieldMatchers map[string][]models.RequestFieldMatchers, selects func(rule, string) bool) map[string][]models.RequestFieldMatchers {
+	if fieldMatchers == nil {
+		return nil
+	}
+
+	redacted := make(map[string][]models.RequestFieldMatchers, len(fieldMatchers))
+	for key, keyMatchers := range fieldMatchers {
+		redactedMatchers := make([]models.RequestFieldMatchers, len(keyMatchers))
+		for i, matcher := range keyMatchers {
+			for _, rule := range this.rules {
+				if selects(rule, key) {
+					matcher = redactLiteralMatcher(matcher, rule.replacement)
+				} else if rule.regex != nil {
+					regexRule := rule
+					matcher = redactLiteralMatcher(matcher, func(value string) string { return redactRegex(value, regexRule) })
+				}
+			}
+			redactedMatchers[i] = matcher
+		}
+		redacted[key] = redactedMatchers
+	}
+	return redacted
+}
+
+func (this *Redactor) bodyMatchers(bodyMatchers []models.RequestFieldMatchers) []models.RequestFieldMatchers {
+	if bodyMatchers == nil {
+		return nil
+	}
+
+	redacted := make([]models.RequestFieldMatchers, len(bodyMatchers))
+	for i, matcher := range bodyMatchers {
+		redacted[i] = this.bodyMatcher(matcher)
+	}
+	return redacted
+}
+
+func (this *Redactor) bodyMatcher(matcher models.RequestFieldMatchers) models.RequestFieldMatchers {
+	switch value := matcher.Value.(type) {
+	case string:
+		switch strings.ToLower(matcher.Matcher) {
+		case "", matchers.Exact, matchers.Json, matchers.JsonPartial, matchers.Xml, matchers.XmlTemplated:
+			matcher.Value = this.Body(value)
+		}
+	case map[string][]models.RequestFieldMatchers:
+		// Form fields are selected by query rules, as they are encoded in the same way
+		matcher.Value = this.mapMatchers(value, func(rule rule, key string) bool {
+			return rule.view.Query == key
+		})
+	case map[string]models.MultipartPartMatchers:
+		parts := make(map[string]models.MultipartPartMatchers, len(value))
+		for name, part := range value {
+			part.Value = this.bodyMatchers(part.Value)
+			part.Headers = this.mapMatchers(part.Headers, func(rule rule, key string) bool {
+				return rule.view.Header != "" && strings.EqualFold(rule.view.Header, key)
+			})
+			parts[name] = part
+		}
+		matcher.Value = parts
+	}
+
+	if matcher.DoMatch != nil {
+		doMatch := this.bodyMatcher(*matcher.DoMatch)
+		matcher.DoMatch = &doMatch
+	}
+	return matcher
+}
+
+// replacement is the placeholder of the rule, or a hash of the value which is the same whenever the value is
+func (this rule) replacement(value string) string {
+	if this.view.Hash {
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])[:16]
+	}
+	if this.view.Placeholder != "" {
+		return this.view.Placeholder
+	}
+	return DefaultPlaceholder
+}
+
+// redactLiteralMatcher redacts the values held by exact and array matchers
+func redactLiteralMatcher(matcher models.RequestFieldMatchers, redact func(string) string) models.RequestFieldMatchers {
+	switch strings.ToLower(matcher.Matcher) {
+	case "", matchers.Exact:
+		if value, ok := matcher.Value.(string); ok {
+			matcher.Value = redact(value)
+		}
+	case matchers.Array:
+		switch values := matcher.Value.(type) {
+		case []string:
+			redacted := make([]string, len(values))
+			for i, value := range values {
+				redacted[i] = redact(value)
+			}
+			matcher.Value = redacted
+		case []interface{}:
+			redacted := make([]interface{}, len(values))
+			for i, value := range values {
+				if stringValue, ok := value.(string); ok {
+					redacted[i] = redact(stringValue)
+				} else {
+					redacted[i] = value
+				}
+			}
+			matcher.Value = redacted
+		}
+	}
+	return matcher
+}
+
+// redactRegex redacts each match of the regex, or only the first group of each match when the regex has groups
+func redactRegex(value string, rule rule) string {
+	matches := rule.regex.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 0 {
+		return value
+	}
+
+	var redacted strings.Builder
+	last := 0
+	for _, match := range matches {
+		start, end := match[0], match[1]
+		if len(match) > 2 && match[2] >= 0 {
+			start, end = match[2], match[3]
+		}
+		redacted.WriteString(value[last:start])
+		redacted.WriteString(rule.replacement(value[start:end]))
+		last = end
+	}
+	redacted.WriteString(value[last:])
+	return redacted.String()
+}
+
+func redactJson(body string, rule rule) string {
+	decoder := json.NewDecoder(bytes.NewBufferString(body))
+	decoder.UseNumber()
+	var data interface{}
+	if err := decoder.Decode(&data); err != nil {
+		return body
+	}
+
+	redacted, changed := replaceJsonPath(data, rule.jsonPath, rule)
+	if !changed {
+		return body
+	}
+
+	bytes, err := json.Marshal(redacted)
+	if err != nil {
+		return body
+	}
+	return string(bytes)
+}
+
+func replaceJsonPath(data interface{}, segments []interface{}, rule rule) (interface{}, bool) {
+	if len(segments) == 0 {
+		if value, ok := data.(string); ok {
+			return rule.replacement(value), true
+		}
+		value, _ := json.Marshal(data)
+		return rule.replacement(string(value)), true
+	}
+
+	changed := false
+	switch segment := segments[0].(type) {
+	case string:
+		if object, ok := data.(map[string]interface{}); ok {
+			if value, found := object[segment]; found {
+				object[segment], changed = replaceJsonPath(value, segments[1:], rule)
+			}
+		}
+	case int:
+		if array, ok := data.([]interface{}); ok {
+			for i, item := range array {
+				if segment == -1 || segment == i {
+					var itemChanged bool
+					array[i], itemChanged = replaceJsonPath(item, segments[1:], rule)
+					changed = changed || itemChanged
+				}
+			}
+		}
+	}
+	return data, changed
+}
+
+func redactXml(body string, rule rule) string {
+	document := etree.NewDocument()
+	if err := document.ReadFromString(body); err != nil || document.Root() == nil {
+		return body
+	}
+
+	elements := document.FindElementsPath(rule.xpath)
+	if len(elements) == 0 {
+		return body
+	}
+	for _, element := range elements {
+		element.SetText(rule.replacement(element.Text()))
+	}
+
+	redacted, err := document.WriteToString()
+	if err != nil {
+		return body
+	}
+	return redacted
+}
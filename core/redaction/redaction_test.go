@@ -0,0 +1,115 @@
+package redaction
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewRedactor_ReturnsNilWithoutRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	redactor, err := NewRedactor(nil)
+	Expect(err).To(BeNil())
+	Expect(redactor).To(BeNil())
+
+	Expect(redactor.Body(`{"password": "secret"}`)).To(Equal(`{"password": "secret"}`))
+	Expect(redactor.View()).To(BeNil())
+}
+
+func Test_NewRedactor_RejectsInvalidRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, rule := range []v2.RedactionRuleView{
+		{},
+		{Header: "Authorization", Query: "token"},
+		{JsonPath: "password"},
+		{JsonPath: "$.items[0"},
+		{XPath: "//password["},
+		{Regex: "[0-9"},
+	} {
+		_, err := NewRedactor([]v2.RedactionRuleView{rule})
+		Expect(err).ToNot(BeNil(), "redaction rule %v", rule)
+	}
+}
+
+func Test_Redactor_Request_RedactsHeadersQueryFormAndBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewRedactor([]v2.RedactionRuleView{
+		{Header: "authorization"},
+		{Query: "token", Placeholder: "xxx"},
+		{JsonPath: "$.user.password"},
+		{Regex: `email=([^&]+)`},
+	})
+	Expect(err).To(BeNil())
+
+	request := models.RequestDetails{
+		Headers:  map[string][]string{"Authorization": {"Bearer abc"}, "Accept": {"text/plain"}},
+		Query:    map[string][]string{"token": {"123"}, "page": {"1"}},
+		FormData: map[string][]string{"token": {"456"}},
+		Body:     `{"user": {"name": "Ben", "password": "secret"}}`,
+	}
+
+	redacted := unit.Request(request)
+
+	Expect(redacted.Headers).To(Equal(map[string][]string{"Authorization": {"[REDACTED]"}, "Accept": {"text/plain"}}))
+	Expect(redacted.Query).To(Equal(map[string][]string{"token": {"xxx"}, "page": {"1"}}))
+	Expect(redacted.FormData).To(Equal(map[string][]string{"token": {"xxx"}}))
+	Expect(redacted.Body).To(MatchJSON(`{"user": {"name": "Ben", "password": "[REDACTED]"}}`))
+
+	Expect(request.Headers["Authorization"]).To(Equal([]string{"Bearer abc"}))
+	Expect(request.Body).To(Equal(`{"user": {"name": "Ben", "password": "secret"}}`))
+
+	Expect(unit.Body("name=Ben&email=ben@example.com&age=40")).To(Equal("name=Ben&email=[REDACTED]&age=40"))
+}
+
+func Test_Redactor_Response_RedactsXmlWithStableHash(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewRedactor([]v2.RedactionRuleView{{XPath: "//card", Hash: true}})
+	Expect(err).To(BeNil())
+
+	first := unit.Response(models.ResponseDetails{Body: `<payment><card>4111</card><amount>10</amount></payment>`})
+	second := unit.Response(models.ResponseDetails{Body: `<payment><card>4111</card><amount>20</amount></payment>`})
+	third := unit.Response(models.ResponseDetails{Body: `<payment><card>5500</card><amount>10</amount></payment>`})
+
+	Expect(first.Body).To(MatchRegexp(`^<payment><card>sha256:[0-9a-f]{16}</card><amount>10</amount></payment>$`))
+	Expect(first.Body[:40]).To(Equal(second.Body[:40]))
+	Expect(first.Body).ToNot(Equal(third.Body))
+}
+
+func Test_Redactor_RequestMatcher_RedactsLiteralMatchers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit, err := NewRedactor([]v2.RedactionRuleView{
+		{Header: "Cookie"},
+		{Query: "token"},
+		{JsonPath: "$.cards[*].number"},
+	})
+	Expect(err).To(BeNil())
+
+	query := models.QueryRequestFieldMatchers{
+		"token": {{Matcher: matchers.Array, Value: []string{"a", "b"}}},
+	}
+	redacted := unit.RequestMatcher(models.RequestMatcher{
+		Headers: map[string][]models.RequestFieldMatchers{
+			"cookie":        {{Matcher: matchers.Exact, Value: "session=1"}},
+			"Authorization": {{Matcher: matchers.Glob, Value: "*"}},
+		},
+		Query: &query,
+		Body: []models.RequestFieldMatchers{
+			{Matcher: matchers.Json, Value: `{"cards": [{"number": "4111"}, {"number": "5500"}]}`},
+		},
+	})
+
+	Expect(redacted.Headers["cookie"]).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Exact, Value: "[REDACTED]"}}))
+	Expect(redacted.Headers["Authorization"]).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Glob, Value: "*"}}))
+	Expect((*redacted.Query)["token"]).To(Equal([]models.RequestFieldMatchers{{Matcher: matchers.Array, Value: []string{"[REDACTED]", "[REDACTED]"}}}))
+	Expect(redacted.Body[0].Value).To(MatchJSON(`{"cards": [{"number": "[REDACTED]"}, {"number": "[REDACTED]"}]}`))
+
+	Expect(query["token"][0].Value).To(Equal([]string{"a", "b"}))
+}
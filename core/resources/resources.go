@@ -0,0 +1,423 @@
+package resources
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+const (
+	DefaultIdField = "id"
+	DefaultLimit   = 10
+
+	pageParameter  = "_page"
+	limitParameter = "_limit"
+)
+
+type resource struct {
+	path        string
+	destination string
+	idField     string
+	seed        []map[string]interface{}
+	items       []map[string]interface{}
+}
+
+// Resources serves REST collections from in-memory stores. A POST to the path of a resource creates an item, a GET
+// lists the items and the path followed by an id gets, replaces, updates or deletes a single item.
+type Resources struct {
+	resources []*resource
+	mu        sync.RWMutex
+}
+
+func NewResources() *Resources {
+	return &Resources{}
+}
+
+// Add validates a resource and fills its store with a copy of the data. A resource with the same path and
+// destination is replaced.
+func (this *Resources) Add(view v2.ResourceViewV5) error {
+	path := strings.TrimSuffix(view.Path, "/")
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("Resource path %s must start with /", view.Path)
+	}
+
+	idField := view.IdField
+	if idField == "" {
+		idField = DefaultIdField
+	}
+
+	resource := &resource{
+		path:        path,
+		destination: view.Destination,
+		idField:     idField,
+	}
+
+	resource.items = copyItems(view.Data)
+	seen := map[string]bool{}
+	for _, item := range resource.items {
+		if _, found := item[idField]; !found {
+			continue
+		}
+		id, ok := idString(item[idField])
+		if !ok {
+			return fmt.Errorf("Resource %s has an item whose %s is not a string or number", path, idField)
+		}
+		if seen[id] {
+			return fmt.Errorf("Resource %s has more than one item with %s %s", path, idField, id)
+		}
+		seen[id] = true
+	}
+	// Items without an id are given one once every id in the data is known, so that they cannot clash
+	for _, item := range resource.items {
+		if _, found := item[idField]; !found {
+			item[idField] = resource.nextId()
+		}
+	}
+	resource.seed = resource.items
+	resource.items = copyItems(resource.seed)
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for i, existing := range this.resources {
+		if existing.path == resource.path && existing.destination == resource.destination {
+			this.resources[i] = resource
+			return nil
+		}
+	}
+	this.resources = append(this.resources, resource)
+	return nil
+}
+
+// Delete removes every resource
+func (this *Resources) Delete() {
+	this.mu.Lock()
+	this.resources = nil
+	this.mu.Unlock()
+}
+
+// Replace takes the resources of another, which is left empty, in place of these
+func (this *Resources) Replace(resources *Resources) {
+	resources.mu.Lock()
+	replacement := resources.resources
+	resources.resources = nil
+	resources.mu.Unlock()
+
+	this.mu.Lock()
+	this.resources = replacement
+	this.mu.Unlock()
+}
+
+// Reset puts the data each resource was added with back into its store
+func (this *Resources) Reset() {
+	this.mu.Lock()
+	for _, resource := range this.resources {
+		resource.items = copyItems(resource.seed)
+	}
+	this.mu.Unlock()
+}
+
+// GetDefinitions returns the resources with the data they were added with, as they are exported in a simulation
+func (this *Resources) GetDefinitions() []v2.ResourceViewV5 {
+	return this.views(func(resource *resource) []map[string]interface{} { return resource.seed })
+}
+
+// GetItems returns the resources with the data their stores hold
+func (this *Resources) GetItems() []v2.ResourceViewV5 {
+	return this.views(func(resource *resource) []map[string]interface{} { return resource.items })
+}
+
+func (this *Resources) views(data func(*resource) []map[string]interface{}) []v2.ResourceViewV5 {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	var views []v2.ResourceViewV5
+	for _, resource := range this.resources {
+		idField := resource.idField
+		if idField == DefaultIdField {
+			idField = ""
+		}
+		views = append(views, v2.ResourceViewV5{
+			Path:        resource.path,
+			Destination: resource.destination,
+			IdField:     idField,
+			Data:        copyItems(data(resource)),
+		})
+	}
+	return views
+}
+
+// Handle serves a request to the path of a resource or one of its items, returning false when there is no
+// resource for the request
+func (this *Resources) Handle(request models.RequestDetails) (*models.ResponseDetails, bool) {
+	path := request.Path
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	for _, resource := range this.resources {
+		if resource.destination != "" && resource.destination != request.Destination {
+			continue
+		}
+		if path == resource.path {
+			return resource.handleCollection(request), true
+		}
+		if id := strings.TrimPrefix(path, resource.path+"/"); id != path && !strings.Contains(id, "/") {
+			return resource.handleItem(request, id), true
+		}
+	}
+	return nil, false
+}
+
+func (this *resource) handleCollection(request models.RequestDetails) *models.ResponseDetails {
+	switch request.Method {
+	case http.MethodGet:
+		return this.list(request.Query)
+	case http.MethodPost:
+		item, err := decodeItem(request.Body)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, err.Error())
+		}
+		if _, found := item[this.idField]; !found {
+			item[this.idField] = this.nextId()
+		}
+		id, ok := idString(item[this.idField])
+		if !ok {
+			return errorResponse(http.StatusBadRequest, fmt.Sprintf("%s must be a string or number", this.idField))
+		}
+		if this.find(id) >= 0 {
+			return errorResponse(http.StatusConflict, fmt.Sprintf("%s %s already exists", this.idField, id))
+		}
+		this.items = append(this.items, item)
+
+		response := jsonResponse(http.StatusCreated, item)
+		response.Headers["Location"] = []string{this.path + "/" + id}
+		return response
+	}
+	return methodNotAllowed("GET, POST")
+}
+
+func (this *resource) handleItem(request models.RequestDetails, id string) *models.ResponseDetails {
+	index := this.find(id)
+
+	switch request.Method {
+	case http.MethodGet, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		if index < 0 {
+			return errorResponse(http.StatusNotFound, fmt.Sprintf("%s %s not found", this.idField, id))
+		}
+	default:
+		return methodNotAllowed("GET, PUT, PATCH, DELETE")
+	}
+
+	switch request.Method {
+	case http.MethodPut, http.MethodPatch:
+		item, err := decodeItem(request.Body)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, err.Error())
+		}
+		// The id comes from the path, so it cannot be changed by the body
+		itemId := this.items[index][this.idField]
+		if request.Method == http.MethodPatch {
+			patched := this.items[index]
+			for key, value := range item {
+				patched[key] = value
+			}
+			item = patched
+		}
+		item[this.idField] = itemId
+		this.items[index] = item
+	case http.MethodDelete:
+		this.items = append(this.items[:index:index], this.items[index+1:]...)
+		return &models.ResponseDetails{Status: http.StatusNoContent, Headers: map[string][]string{}}
+	}
+
+	return jsonResponse(http.StatusOK, this.items[index])
+}
+
+// list returns the items whose fields equal the query parameters, or any of them when a parameter is given more
+// than once. The _page and _limit parameters page through them, and X-Total-Count holds how many there are.
+func (this *resource) list(query map[string][]string) *models.ResponseDetails {
+	page, limit := 0, 0
+	var err error
+	if values := query[pageParameter]; len(values) > 0 {
+		if page, err = strconv.Atoi(values[0]); err != nil || page < 1 {
+			return errorResponse(http.StatusBadRequest, pageParameter+" must be a number from 1")
+		}
+		limit = DefaultLimit
+	}
+	if values := query[limitParameter]; len(values) > 0 {
+		if limit, err = strconv.Atoi(values[0]); err != nil || limit < 1 {
+			return errorResponse(http.StatusBadRequest, limitParameter+" must be a number from 1")
+		}
+		if page == 0 {
+			page = 1
+		}
+	}
+
+	fields := make([]string, 0, len(query))
+	for field := range query {
+		if field != pageParameter && field != limitParameter {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	items := []map[string]interface{}{}
+	for _, item := range this.items {
+		if matchesFilters(item, fields, query) {
+			items = append(items, item)
+		}
+	}
+	total := len(items)
+
+	if limit > 0 {
+		start := (page - 1) * limit
+		if start > len(items) {
+			start = len(items)
+		}
+		end := start + limit
+		if end > len(items) {
+			end = len(items)
+		}
+		items = items[start:end]
+	}
+
+	response := jsonResponse(http.StatusOK, items)
+	response.Headers["X-Total-Count"] = []string{strconv.Itoa(total)}
+	return response
+}
+
+func (this *resource) find(id string) int {
+	for i, item := range this.items {
+		if itemId, ok := idString(item[this.idField]); ok && itemId == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextId is one more than the highest numeric id, skipping any id which is already taken as a string
+func (this *resource) nextId() interface{} {
+	next := 1
+	for _, item := range this.items {
+		if id, ok := idString(item[this.idField]); ok {
+			if number, err := strconv.Atoi(id); err == nil && number >= next {
+				next = number + 1
+			}
+		}
+	}
+	for this.find(strconv.Itoa(next)) >= 0 {
+		next++
+	}
+	return json.Number(strconv.Itoa(next))
+}
+
+// matchesFilters compares the query parameters with the fields of an item, where a dot selects a nested field
+func matchesFilters(item map[string]interface{}, fields []string, query map[string][]string) bool {
+	for _, field := range fields {
+		var value interface{} = item
+		for _, key := range strings.Split(field, ".") {
+			object, ok := value.(map[string]interface{})
+			if !ok {
+				value = nil
+				break
+			}
+			value = object[key]
+		}
+
+		actual, ok := valueString(value)
+		if !ok {
+			return false
+		}
+		matched := false
+		for _, expected := range query[field] {
+			if actual == expected {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+func idString(id interface{}) (string, bool) {
+	switch id.(type) {
+	case string, json.Number, float64:
+		return valueString(id)
+	}
+	return "", false
+}
+
+func valueString(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case json.Number:
+		return value.String(), true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	case nil:
+		return "null", true
+	}
+	return "", false
+}
+
+func decodeItem(body string) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewBufferString(body))
+	decoder.UseNumber()
+
+	var item map[string]interface{}
+	if err := decoder.Decode(&item); err != nil || item == nil {
+		return nil, fmt.Errorf("Request body must be a JSON object")
+	}
+	return item, nil
+}
+
+// copyItems copies items deeply by encoding them, so that changing the copy leaves the original untouched
+func copyItems(items []map[string]interface{}) []map[string]interface{} {
+	copied := []map[string]interface{}{}
+	if len(items) == 0 {
+		return copied
+	}
+
+	encoded, _ := json.Marshal(items)
+	decoder := json.NewDecoder(bytes.NewBuffer(encoded))
+	decoder.UseNumber()
+	decoder.Decode(&copied)
+	return copied
+}
+
+func jsonResponse(status int, body interface{}) *models.ResponseDetails {
+	encoded, _ := json.Marshal(body)
+	return &models.ResponseDetails{
+		Status:  status,
+		Body:    string(encoded),
+		Headers: map[string][]string{"Content-Type": {"application/json"}},
+	}
+}
+
+func errorResponse(status int, message string) *models.ResponseDetails {
+	return jsonResponse(status, map[string]string{"error": message})
+}
+
+func methodNotAllowed(allow string) *models.ResponseDetails {
+	response := errorResponse(http.StatusMethodNotAllowed, "Method not allowed")
+	response.Headers["Allow"] = []string{allow}
+	return response
+}
@@ -0,0 +1,173 @@
+package resources
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newUsers() *Resources {
+	unit := NewResources()
+	Expect(unit.Add(v2.ResourceViewV5{
+		Path: "/api/users/",
+		Data: []map[string]interface{}{
+			{"id": float64(1), "name": "Ben", "team": "core", "address": map[string]interface{}{"city": "London"}},
+			{"id": float64(2), "name": "Tommy", "team": "docs", "address": map[string]interface{}{"city": "Leeds"}},
+			{"id": float64(3), "name": "Mo", "team": "core", "address": map[string]interface{}{"city": "Leeds"}},
+		},
+	})).To(Succeed())
+	return unit
+}
+
+func handle(unit *Resources, method, path, body string, query map[string][]string) *models.ResponseDetails {
+	response, found := unit.Handle(models.RequestDetails{Method: method, Path: path, Body: body, Query: query})
+	Expect(found).To(BeTrue())
+	return response
+}
+
+func Test_Resources_Add_RejectsInvalidResources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewResources()
+
+	Expect(unit.Add(v2.ResourceViewV5{Path: "api/users"})).To(MatchError("Resource path api/users must start with /"))
+	Expect(unit.Add(v2.ResourceViewV5{
+		Path: "/api/users",
+		Data: []map[string]interface{}{{"id": "a"}, {"id": "a"}},
+	})).To(MatchError("Resource /api/users has more than one item with id a"))
+	Expect(unit.Add(v2.ResourceViewV5{
+		Path: "/api/users",
+		Data: []map[string]interface{}{{"id": map[string]interface{}{}}},
+	})).To(MatchError("Resource /api/users has an item whose id is not a string or number"))
+}
+
+func Test_Resources_Add_GivesItemsWithoutAnIdOne(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewResources()
+	Expect(unit.Add(v2.ResourceViewV5{
+		Path:    "/api/orders",
+		IdField: "orderId",
+		Data:    []map[string]interface{}{{"item": "book"}, {"orderId": float64(1), "item": "pen"}},
+	})).To(Succeed())
+
+	response := handle(unit, "GET", "/api/orders", "", nil)
+	Expect(response.Body).To(MatchJSON(`[{"orderId": 2, "item": "book"}, {"orderId": 1, "item": "pen"}]`))
+}
+
+func Test_Resources_Handle_IgnoresOtherPathsAndDestinations(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newUsers()
+	Expect(unit.Add(v2.ResourceViewV5{Path: "/api/teams", Destination: "teams.com"})).To(Succeed())
+
+	for _, request := range []models.RequestDetails{
+		{Method: "GET", Path: "/api/user"},
+		{Method: "GET", Path: "/api/users/1/roles"},
+		{Method: "GET", Path: "/api/teams", Destination: "other.com"},
+	} {
+		_, found := unit.Handle(request)
+		Expect(found).To(BeFalse(), "request %v", request)
+	}
+
+	_, found := unit.Handle(models.RequestDetails{Method: "GET", Path: "/api/teams", Destination: "teams.com"})
+	Expect(found).To(BeTrue())
+}
+
+func Test_Resources_Handle_GetsItems(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newUsers()
+
+	response := handle(unit, "GET", "/api/users/2", "", nil)
+	Expect(response.Status).To(Equal(200))
+	Expect(response.Headers["Content-Type"]).To(Equal([]string{"application/json"}))
+	Expect(response.Body).To(MatchJSON(`{"id": 2, "name": "Tommy", "team": "docs", "address": {"city": "Leeds"}}`))
+
+	response = handle(unit, "GET", "/api/users/4", "", nil)
+	Expect(response.Status).To(Equal(404))
+	Expect(response.Body).To(MatchJSON(`{"error": "id 4 not found"}`))
+}
+
+func Test_Resources_Handle_ListsFilteredAndPagedItems(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newUsers()
+
+	response := handle(unit, "GET", "/api/users", "", map[string][]string{"team": {"core"}})
+	Expect(response.Body).To(MatchJSON(`[
+		{"id": 1, "name": "Ben", "team": "core", "address": {"city": "London"}},
+		{"id": 3, "name": "Mo", "team": "core", "address": {"city": "Leeds"}}
+	]`))
+	Expect(response.Headers["X-Total-Count"]).To(Equal([]string{"2"}))
+
+	response = handle(unit, "GET", "/api/users", "", map[string][]string{"address.city": {"Leeds"}, "name": {"Ben", "Mo"}})
+	Expect(response.Body).To(MatchJSON(`[{"id": 3, "name": "Mo", "team": "core", "address": {"city": "Leeds"}}]`))
+
+	response = handle(unit, "GET", "/api/users", "", map[string][]string{"_page": {"2"}, "_limit": {"2"}})
+	Expect(response.Body).To(MatchJSON(`[{"id": 3, "name": "Mo", "team": "core", "address": {"city": "Leeds"}}]`))
+	Expect(response.Headers["X-Total-Count"]).To(Equal([]string{"3"}))
+
+	response = handle(unit, "GET", "/api/users", "", map[string][]string{"_page": {"3"}, "_limit": {"2"}})
+	Expect(response.Body).To(Equal(`[]`))
+
+	response = handle(unit, "GET", "/api/users", "", map[string][]string{"_limit": {"none"}})
+	Expect(response.Status).To(Equal(400))
+}
+
+func Test_Resources_Handle_CreatesUpdatesAndDeletesItems(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newUsers()
+
+	response := handle(unit, "POST", "/api/users", `{"name": "Kate", "team": "docs"}`, nil)
+	Expect(response.Status).To(Equal(201))
+	Expect(response.Headers["Location"]).To(Equal([]string{"/api/users/4"}))
+	Expect(response.Body).To(MatchJSON(`{"id": 4, "name": "Kate", "team": "docs"}`))
+
+	response = handle(unit, "POST", "/api/users", `{"id": 4, "name": "Kate"}`, nil)
+	Expect(response.Status).To(Equal(409))
+
+	response = handle(unit, "POST", "/api/users", `["Kate"]`, nil)
+	Expect(response.Status).To(Equal(400))
+
+	response = handle(unit, "PATCH", "/api/users/4", `{"team": "core", "id": 10}`, nil)
+	Expect(response.Status).To(Equal(200))
+	Expect(response.Body).To(MatchJSON(`{"id": 4, "name": "Kate", "team": "core"}`))
+
+	response = handle(unit, "PUT", "/api/users/4", `{"name": "Katie"}`, nil)
+	Expect(response.Status).To(Equal(200))
+	Expect(response.Body).To(MatchJSON(`{"id": 4, "name": "Katie"}`))
+
+	response = handle(unit, "DELETE", "/api/users/1", "", nil)
+	Expect(response.Status).To(Equal(204))
+
+	response = handle(unit, "DELETE", "/api/users/1", "", nil)
+	Expect(response.Status).To(Equal(404))
+
+	response = handle(unit, "POST", "/api/users/2", `{}`, nil)
+	Expect(response.Status).To(Equal(405))
+	Expect(response.Headers["Allow"]).To(Equal([]string{"GET, PUT, PATCH, DELETE"}))
+
+	Expect(unit.GetItems()[0].Data).To(HaveLen(3))
+}
+
+func Test_Resources_Reset_RestoresTheData(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := newUsers()
+
+	handle(unit, "DELETE", "/api/users/1", "", nil)
+	handle(unit, "PATCH", "/api/users/2", `{"name": "Tom"}`, nil)
+	Expect(unit.GetItems()[0].Data).To(HaveLen(2))
+	Expect(unit.GetDefinitions()[0].Data).To(HaveLen(3))
+
+	unit.Reset()
+
+	response := handle(unit, "GET", "/api/users/2", "", nil)
+	Expect(response.Body).To(MatchJSON(`{"id": 2, "name": "Tommy", "team": "docs", "address": {"city": "Leeds"}}`))
+	Expect(unit.GetItems()[0].Data).To(HaveLen(3))
+	Expect(unit.GetItems()[0].Path).To(Equal("/api/users"))
+}
@@ -68,6 +68,43 @@ func (c *Configuration) SetMode(mode string) {
 	c.mu.Unlock()
 }
 
+// copy returns a configuration with the same settings, which can then be changed without affecting this one
+func (c *Configuration) copy() *Configuration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return &Configuration{
+		AdminPort:                        c.AdminPort,
+		ProxyPort:                        c.ProxyPort,
+		ListenOnHost:                     c.ListenOnHost,
+		Mode:                             c.Mode,
+		Destination:                      c.Destination,
+		Middleware:                       c.Middleware,
+		DatabasePath:                     c.DatabasePath,
+		Webserver:                        c.Webserver,
+		TLSVerification:                  c.TLSVerification,
+		UpstreamProxy:                    c.UpstreamProxy,
+		PACFile:                          append([]byte(nil), c.PACFile...),
+		Verbose:                          c.Verbose,
+		DisableCache:                     c.DisableCache,
+		CacheSize:                        c.CacheSize,
+		SecretKey:                        c.SecretKey,
+		JWTExpirationDelta:               c.JWTExpirationDelta,
+		AuthEnabled:                      c.AuthEnabled,
+		ProxyAuthorizationHeader:         c.ProxyAuthorizationHeader,
+		PlainHttpTunneling:               c.PlainHttpTunneling,
+		CORS:                             c.CORS,
+		NoImportCheck:                    c.NoImportCheck,
+		ClientAuthenticationDestination:  c.ClientAuthenticationDestination,
+		ClientAuthenticationClientCert:   c.ClientAuthenticationClientCert,
+		ClientAuthenticationClientKey:    c.ClientAuthenticationClientKey,
+		ClientAuthenticationCACert:       c.ClientAuthenticationCACert,
+		ResponsesBodyFilesPath:           c.ResponsesBodyFilesPath,
+		ResponsesBodyFilesAllowedOrigins: c.ResponsesBodyFilesAllowedOrigins,
+		EnableMiddlewareAPI:              c.EnableMiddlewareAPI,
+	}
+}
+
 func (c *Configuration) SetUpstreamProxy(upstreamProxy string) {
 	if !strings.HasPrefix(upstreamProxy, "http://") && !strings.HasPrefix(upstreamProxy, "https://") {
 		upstreamProxy = "http://" + upstreamProxy
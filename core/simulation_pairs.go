@@ -0,0 +1,121 @@
+package hoverfly
+
+import (
+	"fmt"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+// GetSimulationPairs lists the pairs of the simulation followed by those of each source, in the order they are
+// matched
+func (hf *Hoverfly) GetSimulationPairs() v2.SimulationPairsView {
+	view := v2.SimulationPairsView{Pairs: []v2.SimulationPairView{}}
+	for _, pair := range hf.Simulation.GetMatchingPairsWithoutSources() {
+		view.Pairs = append(view.Pairs, v2.SimulationPairView{RequestMatcherResponsePairViewV5: pair.BuildView()})
+	}
+	for _, source := range hf.Simulation.GetSources() {
+		for _, pair := range source.MatchingPairs {
+			view.Pairs = append(view.Pairs, v2.SimulationPairView{Source: source.Name, RequestMatcherResponsePairViewV5: pair.BuildView()})
+		}
+	}
+	return view
+}
+
+// GetSimulationPair returns the pair with the given id
+func (hf *Hoverfly) GetSimulationPair(id string) (v2.SimulationPairView, error) {
+	pair, source, found := hf.Simulation.GetPair(id)
+	if !found {
+		return v2.SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+	}
+	return v2.SimulationPairView{Source: source, RequestMatcherResponsePairViewV5: pair.BuildView()}, nil
+}
+
+// AddSimulationPair adds a pair after the other pairs of the simulation. The pair keeps its id if it has one which
+// no other pair has, otherwise it is given a new one.
+func (hf *Hoverfly) AddSimulationPair(pairView v2.RequestMatcherResponsePairViewV5) (v2.SimulationPairView, error) {
+	pair, err := hf.newSimulationPair(pairView)
+	if err != nil {
+		return v2.SimulationPairView{}, err
+	}
+
+	if hf.Cfg.NoImportCheck {
+		hf.Simulation.AddPairWithoutCheck(&pair)
+	} else if !hf.Simulation.AddPair(&pair) {
+		return v2.SimulationPairView{}, fmt.Errorf("A pair with the same request matcher already exists")
+	}
+
+	hf.state.InitializeSequences(pair.RequestMatcher.RequiresState)
+	hf.simulationPairsChanged()
+	return hf.GetSimulationPair(pair.Id)
+}
+
+// UpdateSimulationPair replaces the pair with the given id, keeping its id and its place in the matching order
+func (hf *Hoverfly) UpdateSimulationPair(id string, pairView v2.RequestMatcherResponsePairViewV5) (v2.SimulationPairView, error) {
+	if _, _, found := hf.Simulation.GetPair(id); !found {
+		return v2.SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+	}
+
+	pair, err := hf.newSimulationPair(pairView)
+	if err != nil {
+		return v2.SimulationPairView{}, err
+	}
+
+	if !hf.Simulation.UpdatePair(id, pair) {
+		return v2.SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+	}
+
+	hf.state.InitializeSequences(pair.RequestMatcher.RequiresState)
+	hf.simulationPairsChanged()
+	return hf.GetSimulationPair(id)
+}
+
+// PatchSimulationPair disables or enables the pair with the given id, and moves it to a position among the pairs of
+// the simulation or of the source it belongs to
+func (hf *Hoverfly) PatchSimulationPair(id string, patchView v2.SimulationPairPatchView) (v2.SimulationPairView, error) {
+	if _, _, found := hf.Simulation.GetPair(id); !found {
+		return v2.SimulationPairView{}, fmt.Errorf("Pair %s not found", id)
+	}
+
+	if patchView.Position != nil {
+		if err := hf.Simulation.MovePair(id, *patchView.Position); err != nil {
+			return v2.SimulationPairView{}, err
+		}
+	}
+	if patchView.Disabled != nil {
+		hf.Simulation.SetPairDisabled(id, *patchView.Disabled)
+	}
+
+	hf.simulationPairsChanged()
+	return hf.GetSimulationPair(id)
+}
+
+// DeleteSimulationPair removes the pair with the given id
+func (hf *Hoverfly) DeleteSimulationPair(id string) error {
+	if !hf.Simulation.DeletePair(id) {
+		return fmt.Errorf("Pair %s not found", id)
+	}
+
+	hf.simulationPairsChanged()
+	return nil
+}
+
+// newSimulationPair imports a pair into a scratch namespace, so that it is validated the same way as the pairs of
+// a simulation without changing this one
+func (hf *Hoverfly) newSimulationPair(pairView v2.RequestMatcherResponsePairViewV5) (models.RequestMatcherResponsePair, error) {
+	namespace := hf.newNamespace()
+	simulationView := v2.SimulationViewV5{DataViewV5: v2.DataViewV5{RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{pairView}}}
+
+	result := namespace.putOrReplaceSimulation(simulationView, true)
+	if result.GetError() != nil {
+		return models.RequestMatcherResponsePair{}, result.GetError()
+	}
+	return namespace.Simulation.GetMatchingPairs()[0], nil
+}
+
+// simulationPairsChanged drops the cached responses, which may have come from a pair that has changed, and persists
+// the simulation along with its sources
+func (hf *Hoverfly) simulationPairsChanged() {
+	hf.FlushCache()
+	hf.persistSimulation()
+}
@@ -0,0 +1,154 @@
+package hoverfly
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	. "github.com/onsi/gomega"
+)
+
+func newPairView(destination string) v2.RequestMatcherResponsePairViewV5 {
+	return newSourceSimulation(destination).RequestResponsePairs[0]
+}
+
+func Test_Hoverfly_GetSimulationPairs_ListsPairsOfTheSimulationAndItsSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+
+	pairs := unit.GetSimulationPairs().Pairs
+	Expect(pairs).To(HaveLen(2))
+	Expect(pairs[0].Id).ToNot(BeEmpty())
+	Expect(pairs[0].Source).To(BeEmpty())
+	Expect(pairs[0].Response.Body).To(Equal("test.com"))
+	Expect(pairs[1].Id).ToNot(BeEmpty())
+	Expect(pairs[1].Source).To(Equal("payments"))
+	Expect(pairs[1].Response.Body).To(Equal("payments.com"))
+}
+
+func Test_Hoverfly_AddSimulationPair_AddsPairAfterTheOthers(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+
+	added, err := unit.AddSimulationPair(newPairView("other.com"))
+	Expect(err).To(BeNil())
+	Expect(added.Id).ToNot(BeEmpty())
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"test.com", "other.com"}))
+
+	_, err = unit.AddSimulationPair(newPairView("other.com"))
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("A pair with the same request matcher already exists"))
+}
+
+func Test_Hoverfly_AddSimulationPair_RejectsAnInvalidPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	pairView := newPairView("test.com")
+	pairView.ResponsesMode = "shuffle"
+
+	_, err := unit.AddSimulationPair(pairView)
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("invalid responsesMode shuffle"))
+	Expect(getSimulationDestinations(unit)).To(BeEmpty())
+}
+
+func Test_Hoverfly_UpdateSimulationPair_KeepsIdAndPlace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com", "other.com")).GetError()).To(BeNil())
+	id := unit.GetSimulationPairs().Pairs[0].Id
+
+	updated, err := unit.UpdateSimulationPair(id, newPairView("updated.com"))
+	Expect(err).To(BeNil())
+	Expect(updated.Id).To(Equal(id))
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"updated.com", "other.com"}))
+
+	_, err = unit.UpdateSimulationPair("unknown", newPairView("updated.com"))
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Pair unknown not found"))
+}
+
+func Test_Hoverfly_PatchSimulationPair_DisablesAndMovesPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com", "other.com")).GetError()).To(BeNil())
+	id := unit.GetSimulationPairs().Pairs[1].Id
+
+	disabled := true
+	position := 0
+	patched, err := unit.PatchSimulationPair(id, v2.SimulationPairPatchView{Disabled: &disabled, Position: &position})
+	Expect(err).To(BeNil())
+	Expect(patched.Disabled).To(BeTrue())
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"other.com", "test.com"}))
+
+	position = 2
+	_, err = unit.PatchSimulationPair(id, v2.SimulationPairPatchView{Position: &position})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Position 2 is out of range, there are 2 pairs"))
+}
+
+func Test_Hoverfly_PatchSimulationPair_DisabledPairIsNotMatched(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+	id := unit.GetSimulationPairs().Pairs[0].Id
+
+	request := models.RequestDetails{Destination: "test.com"}
+
+	_, matchErr := unit.GetResponse(request)
+	Expect(matchErr).To(BeNil())
+
+	disabled := true
+	_, err := unit.PatchSimulationPair(id, v2.SimulationPairPatchView{Disabled: &disabled})
+	Expect(err).To(BeNil())
+
+	_, matchErr = unit.GetResponse(request)
+	Expect(matchErr).ToNot(BeNil())
+}
+
+func Test_Hoverfly_DeleteSimulationPair(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com", "other.com")).GetError()).To(BeNil())
+
+	Expect(unit.DeleteSimulationPair(unit.GetSimulationPairs().Pairs[0].Id)).To(Succeed())
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"other.com"}))
+
+	err := unit.DeleteSimulationPair("unknown")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Pair unknown not found"))
+}
+
+func Test_Hoverfly_GetSimulation_KeepsPairIdsWhenReimported(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+	exported, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+
+	Expect(unit.ReplaceSimulation(exported).GetError()).To(BeNil())
+
+	Expect(unit.GetSimulationPairs().Pairs[0].Id).To(Equal(exported.RequestResponsePairs[0].Id))
+}
@@ -0,0 +1,107 @@
+package hoverfly
+
+import (
+	"fmt"
+
+	v1 "github.com/SpectoLabs/hoverfly/core/handlers/v1"
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/models"
+)
+
+// GetSimulationSources lists the named simulation sources in the order they are matched
+func (hf *Hoverfly) GetSimulationSources() v2.SimulationSourcesView {
+	view := v2.SimulationSourcesView{Sources: []v2.SimulationSourceView{}}
+	for _, source := range hf.Simulation.GetSources() {
+		view.Sources = append(view.Sources, v2.SimulationSourceView{
+			Name:           source.Name,
+			Pairs:          len(source.MatchingPairs),
+			WebSocketPairs: len(source.WebSocketPairs),
+		})
+	}
+	return view
+}
+
+// GetSimulationSource returns the simulation of a named source
+func (hf *Hoverfly) GetSimulationSource(name string) (v2.SimulationViewV5, error) {
+	source, found := hf.Simulation.GetSource(name)
+	if !found {
+		return v2.SimulationViewV5{}, fmt.Errorf("Simulation source %s not found", name)
+	}
+	return hf.buildSimulationSourceView(source), nil
+}
+
+// PutSimulationSource adds a named source after the existing ones, or replaces the source of that name keeping
+// its place in the matching order. A source only has pairs and WebSocket pairs, as the global actions, literals,
+// variables, resources and custom matchers apply to the whole simulation.
+func (hf *Hoverfly) PutSimulationSource(name string, simulationView v2.SimulationViewV5) v2.SimulationImportResult {
+	result := v2.SimulationImportResult{}
+	if name == "" {
+		result.SetError(fmt.Errorf("Simulation source name is required"))
+		return result
+	}
+	if err := validateSimulationSource(simulationView); err != nil {
+		result.SetError(err)
+		return result
+	}
+
+	// The pairs are imported into a scratch namespace first, so that an invalid pair leaves the source as it was.
+	// Their ids are only unique within the scratch namespace, so SetSource checks them again.
+	namespace := hf.newNamespace()
+	result = namespace.putOrReplaceSimulation(simulationView, true)
+	if result.GetError() != nil {
+		return result
+	}
+
+	source := models.SimulationSource{
+		Name:           name,
+		MatchingPairs:  namespace.Simulation.GetMatchingPairs(),
+		WebSocketPairs: namespace.Simulation.GetWebSocketPairs(),
+	}
+
+	initialStates := map[string]string{}
+	for _, pair := range source.MatchingPairs {
+		for key, value := range pair.RequestMatcher.RequiresState {
+			initialStates[key] = value
+		}
+	}
+	hf.state.InitializeSequences(initialStates)
+
+	hf.Simulation.SetSource(source)
+	hf.FlushCache()
+	hf.persistSimulationSources()
+
+	return result
+}
+
+// DeleteSimulationSource removes a named source along with its pairs
+func (hf *Hoverfly) DeleteSimulationSource(name string) error {
+	if !hf.Simulation.DeleteSource(name) {
+		return fmt.Errorf("Simulation source %s not found", name)
+	}
+
+	hf.FlushCache()
+	hf.persistSimulationSources()
+	return nil
+}
+
+func validateSimulationSource(simulationView v2.SimulationViewV5) error {
+	if len(simulationView.GlobalActions.Delays) > 0 || len(simulationView.GlobalActions.DelaysLogNormal) > 0 ||
+		len(simulationView.GlobalLiterals) > 0 || len(simulationView.GlobalVariables) > 0 ||
+		len(simulationView.Resources) > 0 || len(simulationView.CustomMatchers) > 0 {
+		return fmt.Errorf("A simulation source can only have pairs and webSocketPairs, set globalActions, literals, variables, resources and customMatchers on the simulation")
+	}
+	return nil
+}
+
+func (hf *Hoverfly) buildSimulationSourceView(source models.SimulationSource) v2.SimulationViewV5 {
+	pairViews := make([]v2.RequestMatcherResponsePairViewV5, 0, len(source.MatchingPairs))
+	for _, pair := range source.MatchingPairs {
+		pairViews = append(pairViews, pair.BuildView())
+	}
+
+	simulationView := v2.BuildSimulationView(pairViews, v1.ResponseDelayPayloadView{}, v1.ResponseDelayLogNormalPayloadView{}, nil, nil, hf.version)
+	for _, pair := range source.WebSocketPairs {
+		simulationView.WebSocketPairs = append(simulationView.WebSocketPairs, pair.BuildView())
+	}
+	return simulationView
+}
@@ -0,0 +1,179 @@
+package hoverfly
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/persistence"
+	. "github.com/onsi/gomega"
+)
+
+func newSourceSimulation(destinations ...string) v2.SimulationViewV5 {
+	simulation := v2.SimulationViewV5{}
+	for _, destination := range destinations {
+		simulation.RequestResponsePairs = append(simulation.RequestResponsePairs, v2.RequestMatcherResponsePairViewV5{
+			RequestMatcher: v2.RequestMatcherViewV5{
+				Destination: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, destination)},
+			},
+			Response: v2.ResponseDetailsViewV5{
+				Status: 200,
+				Body:   destination,
+			},
+		})
+	}
+	return simulation
+}
+
+func getSimulationDestinations(unit *Hoverfly) []string {
+	simulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+
+	var destinations []string
+	for _, pair := range simulation.RequestResponsePairs {
+		destinations = append(destinations, pair.RequestMatcher.Destination[0].Value.(string))
+	}
+	return destinations
+}
+
+func Test_Hoverfly_PutSimulationSource_MatchesSourcesInOrderAfterTheSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("users", newSourceSimulation("users.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"test.com", "payments.com", "users.com"}))
+	Expect(unit.GetSimulationSources()).To(Equal(v2.SimulationSourcesView{Sources: []v2.SimulationSourceView{
+		{Name: "payments", Pairs: 1},
+		{Name: "users", Pairs: 1},
+	}}))
+}
+
+func Test_Hoverfly_PutSimulationSource_ReplacesOnlyThatSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("users", newSourceSimulation("users.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments-v2.com", "refunds.com")).GetError()).To(BeNil())
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"payments-v2.com", "refunds.com", "users.com"}))
+
+	source, err := unit.GetSimulationSource("payments")
+	Expect(err).To(BeNil())
+	Expect(source.RequestResponsePairs).To(HaveLen(2))
+	Expect(source.RequestResponsePairs[1].Response.Body).To(Equal("refunds.com"))
+}
+
+func Test_Hoverfly_PutSimulationSource_ReassignsPairIdsUsedByTheSimulation(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	simulation := newSourceSimulation("test.com")
+	simulation.RequestResponsePairs[0].Id = "shared"
+	Expect(unit.PutSimulation(simulation).GetError()).To(BeNil())
+
+	source := newSourceSimulation("payments.com")
+	source.RequestResponsePairs[0].Id = "shared"
+	Expect(unit.PutSimulationSource("payments", source).GetError()).To(BeNil())
+
+	pairs := unit.Simulation.GetMatchingPairs()
+	Expect(pairs).To(HaveLen(2))
+	Expect(pairs[0].Id).To(Equal("shared"))
+	Expect(pairs[1].Id).ToNot(BeElementOf("shared", ""))
+
+	pair, err := unit.GetSimulationPair("shared")
+	Expect(err).To(BeNil())
+	Expect(pair.Response.Body).To(Equal("test.com"))
+}
+
+func Test_Hoverfly_PutSimulationSource_KeepsSourceWhenANewPairIsInvalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+
+	invalid := newSourceSimulation("payments-v2.com", "refunds.com")
+	invalid.RequestResponsePairs[1].ResponsesMode = "shuffle"
+
+	result := unit.PutSimulationSource("payments", invalid)
+	Expect(result.GetError()).ToNot(BeNil())
+	Expect(result.GetError().Error()).To(ContainSubstring("invalid responsesMode shuffle"))
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"payments.com"}))
+}
+
+func Test_Hoverfly_PutSimulationSource_RejectsGlobalSections(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	simulation := newSourceSimulation("payments.com")
+	simulation.GlobalLiterals = []v2.GlobalLiteralViewV5{{Name: "currency", Value: "GBP"}}
+
+	result := unit.PutSimulationSource("payments", simulation)
+	Expect(result.GetError()).ToNot(BeNil())
+	Expect(result.GetError().Error()).To(ContainSubstring("A simulation source can only have pairs and webSocketPairs"))
+	Expect(unit.GetSimulationSources().Sources).To(BeEmpty())
+}
+
+func Test_Hoverfly_DeleteSimulationSource_DeletesOnlyThatSource(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("users", newSourceSimulation("users.com")).GetError()).To(BeNil())
+
+	Expect(unit.DeleteSimulationSource("payments")).To(Succeed())
+
+	Expect(getSimulationDestinations(unit)).To(Equal([]string{"users.com"}))
+
+	err := unit.DeleteSimulationSource("payments")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Simulation source payments not found"))
+}
+
+func Test_Hoverfly_DeleteSimulation_DeletesSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+
+	unit.DeleteSimulation()
+
+	Expect(getSimulationDestinations(unit)).To(BeEmpty())
+	Expect(unit.GetSimulationSources().Sources).To(BeEmpty())
+}
+
+func Test_Hoverfly_SetPersistentStore_ReloadsSimulationSources(t *testing.T) {
+	RegisterTestingT(t)
+
+	directory := t.TempDir()
+	store, err := persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+
+	unit := NewHoverfly()
+	Expect(unit.SetPersistentStore(store)).To(Succeed())
+
+	Expect(unit.PutSimulation(newSourceSimulation("test.com")).GetError()).To(BeNil())
+	Expect(unit.PutSimulationSource("payments", newSourceSimulation("payments.com")).GetError()).To(BeNil())
+	Expect(store.Close()).To(Succeed())
+
+	store, err = persistence.NewBoltStore(directory)
+	Expect(err).To(BeNil())
+	defer store.Close()
+
+	restarted := NewHoverfly()
+	Expect(restarted.SetPersistentStore(store)).To(Succeed())
+
+	Expect(getSimulationDestinations(restarted)).To(Equal([]string{"test.com", "payments.com"}))
+	Expect(restarted.GetSimulationSources().Sources).To(Equal([]v2.SimulationSourceView{{Name: "payments", Pairs: 1}}))
+}
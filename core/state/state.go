@@ -2,6 +2,7 @@ package state
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 )
@@ -59,6 +60,23 @@ func (s *State) RemoveState(toRemove []string) {
 	s.RWMutex.Unlock()
 }
 
+// Advance moves the position kept under the key, which starts at 1, to the position returned by next.
+// It returns the position before it was moved along with the new one.
+func (s *State) Advance(key string, next func(position int) int) (int, int) {
+	s.RWMutex.Lock()
+	defer s.RWMutex.Unlock()
+
+	position, err := strconv.Atoi(s.State[key])
+	if err != nil || position < 1 {
+		position = 1
+	}
+
+	nextPosition := next(position)
+	s.State[key] = strconv.Itoa(nextPosition)
+
+	return position, nextPosition
+}
+
 func (s *State) GetNewSequenceKey() string {
 	returnKey := ""
 	i := 1
@@ -150,3 +150,28 @@ func Test_State_GetNewSequenceKey_ReturnsFirstFreeInSequence(t *testing.T) {
 	})
 	Expect(s.GetNewSequenceKey()).To(Equal("sequence:4"))
 }
+
+func Test_State_Advance_StartsAtOneAndStoresNextPosition(t *testing.T) {
+	RegisterTestingT(t)
+
+	s := state.NewState()
+
+	position, nextPosition := s.Advance("responses:1", func(position int) int { return position + 1 })
+	Expect(position).To(Equal(1))
+	Expect(nextPosition).To(Equal(2))
+	Expect(s.State["responses:1"]).To(Equal("2"))
+
+	position, nextPosition = s.Advance("responses:1", func(position int) int { return position + 1 })
+	Expect(position).To(Equal(2))
+	Expect(nextPosition).To(Equal(3))
+}
+
+func Test_State_Advance_RestartsFromOneWhenPositionIsInvalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	s := state.NewState()
+	s.SetState(map[string]string{"responses:1": "not a number"})
+
+	position, _ := s.Advance("responses:1", func(position int) int { return position })
+	Expect(position).To(Equal(1))
+}
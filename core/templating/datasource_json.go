@@ -0,0 +1,243 @@
+package templating
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"gopkg.in/yaml.v2"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	JsonFormat = "json"
+	YamlFormat = "yaml"
+)
+
+// JsonDataSource holds nested data for templates to query with JSONPath. YAML is read into the same structure,
+// so both formats are queried and changed in the same way.
+type JsonDataSource struct {
+	Name   string
+	Format string
+	Data   interface{}
+	mu     sync.Mutex
+}
+
+func NewJsonDataSource(name, format, content string) (*JsonDataSource, error) {
+	if format == "" {
+		format = JsonFormat
+	}
+
+	var data interface{}
+	switch strings.ToLower(format) {
+	case JsonFormat:
+		if err := json.Unmarshal([]byte(content), &data); err != nil {
+			return nil, fmt.Errorf("Data source %s is not valid JSON: %s", name, err.Error())
+		}
+	case YamlFormat:
+		var yamlData interface{}
+		if err := yaml.Unmarshal([]byte(content), &yamlData); err != nil {
+			return nil, fmt.Errorf("Data source %s is not valid YAML: %s", name, err.Error())
+		}
+		data = fromYaml(yamlData)
+	default:
+		return nil, fmt.Errorf("Data source format must be json or yaml")
+	}
+
+	return &JsonDataSource{Name: name, Format: strings.ToLower(format), Data: data}, nil
+}
+
+func (dataSource *JsonDataSource) GetDataSourceView() (v2.JSONDataSourceView, error) {
+	dataSource.mu.Lock()
+	defer dataSource.mu.Unlock()
+
+	var content []byte
+	var err error
+	if dataSource.Format == YamlFormat {
+		content, err = yaml.Marshal(dataSource.Data)
+	} else {
+		content, err = json.Marshal(dataSource.Data)
+	}
+	if err != nil {
+		return v2.JSONDataSourceView{}, err
+	}
+	return v2.JSONDataSourceView{Name: dataSource.Name, Format: dataSource.Format, Data: string(content)}, nil
+}
+
+// query returns every value the JSONPath selects
+func (dataSource *JsonDataSource) query(path string) ([]interface{}, error) {
+	jsonPath := jsonpath.New("")
+	if err := jsonPath.Parse(util.PrepareJsonPathQuery(path)); err != nil {
+		return nil, err
+	}
+
+	results, err := jsonPath.FindResults(dataSource.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []interface{}
+	for _, result := range results {
+		for _, value := range result {
+			if value.IsValid() && value.CanInterface() {
+				values = append(values, value.Interface())
+			} else {
+				values = append(values, nil)
+			}
+		}
+	}
+	return values, nil
+}
+
+// update replaces each value the path selects with what the function returns, counting the values replaced. A
+// missing field at the end of the path is passed to the function as nil, so that it can be added.
+func (dataSource *JsonDataSource) update(path string, replace func(interface{}) (interface{}, bool)) (int, error) {
+	segments, err := util.SplitJsonPath(path)
+	if err != nil {
+		return 0, err
+	}
+
+	data, updated := updateJsonValue(dataSource.Data, segments, replace)
+	dataSource.Data = data
+	return updated, nil
+}
+
+func updateJsonValue(data interface{}, segments []interface{}, replace func(interface{}) (interface{}, bool)) (interface{}, int) {
+	if len(segments) == 0 {
+		replaced, ok := replace(data)
+		if !ok {
+			return data, 0
+		}
+		return replaced, 1
+	}
+
+	updated := 0
+	switch segment := segments[0].(type) {
+	case string:
+		object, ok := data.(map[string]interface{})
+		if !ok {
+			return data, 0
+		}
+		value, found := object[segment]
+		if !found && len(segments) > 1 {
+			return data, 0
+		}
+		var replaced interface{}
+		replaced, updated = updateJsonValue(value, segments[1:], replace)
+		if updated > 0 {
+			object[segment] = replaced
+		}
+	case int:
+		array, ok := data.([]interface{})
+		if !ok {
+			return data, 0
+		}
+		for i, item := range array {
+			if segment == -1 || segment == i {
+				var itemUpdated int
+				array[i], itemUpdated = updateJsonValue(item, segments[1:], replace)
+				updated += itemUpdated
+			}
+		}
+	}
+	return data, updated
+}
+
+// parseJsonValue reads a value given to a helper, which is JSON when it can be parsed as such or a string otherwise
+func parseJsonValue(value interface{}) interface{} {
+	switch value := value.(type) {
+	case string:
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(value), &parsed); err == nil {
+			return parsed
+		}
+		return value
+	case int:
+		return float64(value)
+	case []interface{}, map[string]interface{}, float64, bool, nil:
+		return value
+	}
+
+	// Values from other helpers, such as Request.Body, are normalised by a round trip through JSON
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	var parsed interface{}
+	json.Unmarshal(encoded, &parsed)
+	return parsed
+}
+
+// copyJsonValues copies values deeply by encoding them, so that changing the data leaves the copy untouched
+func copyJsonValues(values []interface{}) []interface{} {
+	copied := []interface{}{}
+	if len(values) == 0 {
+		return copied
+	}
+
+	encoded, err := json.Marshal(values)
+	if err != nil {
+		return copied
+	}
+	json.Unmarshal(encoded, &copied)
+	return copied
+}
+
+// jsonValueString is how a value is written into a template, or compared with a value given to a helper
+func jsonValueString(value interface{}) string {
+	switch value := value.(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case nil:
+		return ""
+	}
+
+	buffer := new(bytes.Buffer)
+	encoder := json.NewEncoder(buffer)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return strings.TrimSuffix(buffer.String(), "\n")
+}
+
+// fromYaml turns the maps read from YAML, which can have keys of any type, into the maps read from JSON
+func fromYaml(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[interface{}]interface{}:
+		object := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			object[fmt.Sprint(key)] = fromYaml(item)
+		}
+		return object
+	case []interface{}:
+		array := make([]interface{}, len(value))
+		for i, item := range value {
+			array[i] = fromYaml(item)
+		}
+		return array
+	}
+
+	// Numbers are held as float64, as they are when read from JSON
+	if number := reflect.ValueOf(value); number.IsValid() {
+		switch number.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(number.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			return float64(number.Uint())
+		case reflect.Float32:
+			return number.Float()
+		}
+	}
+	return value
+}
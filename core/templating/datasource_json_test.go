@@ -0,0 +1,49 @@
+package templating
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	. "github.com/onsi/gomega"
+)
+
+func Test_NewJsonDataSource_ReadsJsonAndYamlIntoTheSameData(t *testing.T) {
+	RegisterTestingT(t)
+
+	jsonDataSource, err := NewJsonDataSource("users", "", `{"users": [{"id": 1, "name": "Ben", "active": true}]}`)
+	Expect(err).To(BeNil())
+	Expect(jsonDataSource.Format).To(Equal("json"))
+
+	yamlDataSource, err := NewJsonDataSource("users", "YAML", "users:\n- id: 1\n  name: Ben\n  active: true\n")
+	Expect(err).To(BeNil())
+	Expect(yamlDataSource.Format).To(Equal("yaml"))
+
+	Expect(yamlDataSource.Data).To(Equal(jsonDataSource.Data))
+}
+
+func Test_NewJsonDataSource_RejectsInvalidContentOrFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := NewJsonDataSource("users", "json", `{"users": [}`)
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(HavePrefix("Data source users is not valid JSON"))
+
+	_, err = NewJsonDataSource("users", "yaml", "users: [")
+	Expect(err).NotTo(BeNil())
+	Expect(err.Error()).To(HavePrefix("Data source users is not valid YAML"))
+
+	_, err = NewJsonDataSource("users", "xml", "<users/>")
+	Expect(err).To(MatchError("Data source format must be json or yaml"))
+}
+
+func Test_JsonDataSource_GetDataSourceViewWritesTheFormatItWasReadFrom(t *testing.T) {
+	RegisterTestingT(t)
+
+	dataSource, err := NewJsonDataSource("users", "json", `{"users": [{"id": 1}]}`)
+	Expect(err).To(BeNil())
+	Expect(dataSource.GetDataSourceView()).To(Equal(v2.JSONDataSourceView{Name: "users", Format: "json", Data: `{"users":[{"id":1}]}`}))
+
+	dataSource, err = NewJsonDataSource("users", "yaml", "users:\n- id: 1\n")
+	Expect(err).To(BeNil())
+	Expect(dataSource.GetDataSourceView()).To(Equal(v2.JSONDataSourceView{Name: "users", Format: "yaml", Data: "users:\n- id: 1\n"}))
+}
@@ -2,7 +2,10 @@ package templating
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 
 	log "github.com/sirupsen/logrus"
@@ -16,142 +19,712 @@ type Condition struct {
 	Column   string
 	Operator string
 	Value    string
+	// Values holds the list of an IN or NOT IN condition
+	Values []string
+	// Groups holds the alternatives of an OR condition, which matches when every condition of any group matches
+	Groups [][]Condition
+}
+
+// Aggregate represents COUNT, SUM, AVG, MIN or MAX in the columns of a SELECT query
+type Aggregate struct {
+	Function string
+	Column   string // "*" for COUNT(*)
+	Name     string
+}
+
+// Join represents a data source joined to the rows of a SELECT query
+type Join struct {
+	DataSourceName string
+	Alias          string
+	Left           bool
+	LeftColumn     string
+	RightColumn    string
+	data           [][]string
+}
+
+// OrderBy represents a single column in the ORDER BY clause
+type OrderBy struct {
+	Column     string
+	Descending bool
 }
 
 // SQLStatement represents a simple SQL-like query
 type SQLStatement struct {
-	Type           string // "SELECT", "UPDATE", or "DELETE"
+	Type           string // "SELECT", "INSERT", "UPDATE", or "DELETE"
 	Columns        []string
 	Conditions     []Condition
 	SetClauses     map[string]string // For UPDATE queries
 	DataSourceName string
+	Alias          string
+	Distinct       bool
+	Aliases        map[string]string // Names given to selected columns
+	Aggregates     []Aggregate
+	Joins          []Join
+	GroupBy        []string
+	OrderBy        []OrderBy
+	Limit          int // 0 when there is no LIMIT
+	Offset         int
+	InsertRows     [][]string // For INSERT queries, with a value for every column of the data source
 }
 
 func parseSqlCommand(query string, datasource *TemplateDataSource) (SQLStatement, error) {
-	query = strings.TrimSpace(query)
-	var commandType string
-	if strings.HasPrefix(strings.ToUpper(query), "SELECT") {
-		commandType = "SELECT"
-	} else if strings.HasPrefix(strings.ToUpper(query), "UPDATE") {
-		commandType = "UPDATE"
-	} else if strings.HasPrefix(strings.ToUpper(query), "DELETE") {
-		commandType = "DELETE"
-	} else {
-		return SQLStatement{}, errors.New("invalid query type")
+	parser, err := newSqlParser(strings.TrimSpace(query))
+	if err != nil {
+		return SQLStatement{}, err
 	}
 
-	var selectRegex *regexp.Regexp
-	var matches []string
-	var columnsPart, dataSourceName, wherePart string
+	switch {
+	case parser.acceptKeyword("SELECT"):
+		return parser.parseSelect(datasource)
+	case parser.acceptKeyword("INSERT"):
+		return parser.parseInsert(datasource)
+	case parser.acceptKeyword("UPDATE"):
+		return parser.parseUpdate(datasource)
+	case parser.acceptKeyword("DELETE"):
+		return parser.parseDelete(datasource)
+	}
+	return SQLStatement{}, errors.New("invalid query type")
+}
 
-	switch commandType {
-	case "SELECT":
-		selectRegex = regexp.MustCompile(`(?i)^SELECT\s+(.+)\s+FROM\s+([\w-]+)\s*(?:WHERE\s+(.+))?$`)
-		matches = selectRegex.FindStringSubmatch(query)
-		if len(matches) < 3 {
+type sqlParser struct {
+	tokens   []sqlToken
+	position int
+}
 
-			return SQLStatement{}, errors.New("invalid query format")
+func newSqlParser(text string) (*sqlParser, error) {
+	tokens, err := tokenizeSql(text)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlParser{tokens: tokens}, nil
+}
+
+func (p *sqlParser) peek() sqlToken {
+	return p.peekAt(0)
+}
+
+func (p *sqlParser) peekAt(offset int) sqlToken {
+	if p.position+offset < len(p.tokens) {
+		return p.tokens[p.position+offset]
+	}
+	return sqlToken{kind: sqlEnd}
+}
+
+func (p *sqlParser) next() sqlToken {
+	token := p.peek()
+	if token.kind != sqlEnd {
+		p.position++
+	}
+	return token
+}
+
+func (p *sqlParser) describe() string {
+	if token := p.peek(); token.kind != sqlEnd {
+		return "'" + token.text + "'"
+	}
+	return "end of query"
+}
 
+func isSqlKeyword(token sqlToken, keyword string) bool {
+	return token.kind == sqlIdentifier && !token.quoted && strings.EqualFold(token.text, keyword)
+}
+
+// acceptKeyword moves past the keywords when they come next
+func (p *sqlParser) acceptKeyword(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if !isSqlKeyword(p.peekAt(i), keyword) {
+			return false
 		}
-		columnsPart = matches[1]
-		dataSourceName = matches[2]
-		source, exits := datasource.GetDataSource(dataSourceName)
-		if !exits {
-			return SQLStatement{}, errors.New("data source does not exist")
+	}
+	p.position += len(keywords)
+	return true
+}
+
+func (p *sqlParser) expectKeyword(keywords ...string) error {
+	if !p.acceptKeyword(keywords...) {
+		return fmt.Errorf("expected %s but found %s", strings.Join(keywords, " "), p.describe())
+	}
+	return nil
+}
+
+func (p *sqlParser) acceptSymbol(symbols ...string) (string, bool) {
+	token := p.peek()
+	if token.kind == sqlSymbol && stringExists(symbols, token.text) {
+		p.next()
+		return token.text, true
+	}
+	return "", false
+}
+
+func (p *sqlParser) expectSymbol(symbol string) error {
+	if _, ok := p.acceptSymbol(symbol); !ok {
+		return fmt.Errorf("expected %s but found %s", symbol, p.describe())
+	}
+	return nil
+}
+
+func (p *sqlParser) isIdentifier() bool {
+	token := p.peek()
+	return token.kind == sqlIdentifier && (token.quoted || !sqlKeywords[strings.ToUpper(token.text)])
+}
+
+func (p *sqlParser) identifier(what string) (string, error) {
+	if !p.isIdentifier() {
+		return "", fmt.Errorf("expected %s but found %s", what, p.describe())
+	}
+	return p.next().text, nil
+}
+
+func (p *sqlParser) literal() (string, error) {
+	if token := p.peek(); token.kind == sqlString || token.kind == sqlNumber {
+		return p.next().text, nil
+	}
+	return "", fmt.Errorf("expected a value but found %s", p.describe())
+}
+
+func (p *sqlParser) count(clause string) (int, error) {
+	token := p.next()
+	number, err := strconv.Atoi(token.text)
+	if token.kind != sqlNumber || err != nil || number < 0 {
+		return 0, fmt.Errorf("%s must be a whole number", clause)
+	}
+	return number, nil
+}
+
+func (p *sqlParser) end() error {
+	p.acceptSymbol(";")
+	if p.peek().kind != sqlEnd {
+		return fmt.Errorf("unexpected %s", p.describe())
+	}
+	return nil
+}
+
+// sqlScope holds the data sources a query reads from, to resolve the columns it names
+type sqlScope struct {
+	sources []sqlScopeSource
+}
+
+type sqlScopeSource struct {
+	name      string
+	qualifier string
+	headers   []string
+}
+
+// resolve returns the key of a column in the rows of the query, and the name of the column in its data source.
+// Rows are keyed by column name when there is one data source, and by qualifier and column name when there are joins.
+func (s sqlScope) resolve(column string) (string, string, error) {
+	var found []sqlScopeSource
+	header := column
+	for _, source := range s.sources {
+		if stringExists(source.headers, column) {
+			found = append(found, source)
+		}
+	}
+	if dot := strings.LastIndex(column, "."); len(found) == 0 && dot > 0 {
+		qualifier := column[:dot]
+		header = column[dot+1:]
+		for _, source := range s.sources {
+			if (source.qualifier == qualifier || source.name == qualifier) && stringExists(source.headers, header) {
+				found = []sqlScopeSource{source}
+				break
+			}
 		}
+	}
+
+	switch len(found) {
+	case 0:
+		return "", "", errors.New("invalid column provided: " + column)
+	case 1:
+		return s.key(found[0], header), header, nil
+	}
+	return "", "", errors.New("ambiguous column provided: " + column)
+}
 
-		if len(matches) == 4 {
-			wherePart = matches[3]
+func (s sqlScope) key(source sqlScopeSource, header string) string {
+	if len(s.sources) > 1 {
+		return source.qualifier + "." + header
+	}
+	return header
+}
+
+// isUnique reports whether only one data source has the column
+func (s sqlScope) isUnique(header string) bool {
+	found := 0
+	for _, source := range s.sources {
+		if stringExists(source.headers, header) {
+			found++
 		}
+	}
+	return found == 1
+}
 
-		headers := source.Data[0]
-		columns, err := parseColumns(columnsPart, headers)
+func (s sqlScope) resolveConditions(conditions []Condition) ([]Condition, error) {
+	for i, condition := range conditions {
+		if condition.Operator == "OR" {
+			for j, group := range condition.Groups {
+				resolved, err := s.resolveConditions(group)
+				if err != nil {
+					return nil, err
+				}
+				conditions[i].Groups[j] = resolved
+			}
+			continue
+		}
+		key, _, err := s.resolve(condition.Column)
 		if err != nil {
-			return SQLStatement{}, err
+			return nil, err
+		}
+		conditions[i].Column = key
+	}
+	return conditions, nil
+}
+
+// parseDataSource reads the name of a data source, and its alias when one is allowed
+func (p *sqlParser) parseDataSource(datasource *TemplateDataSource, allowAlias bool) (sqlScopeSource, error) {
+	name, err := p.identifier("data source name")
+	if err != nil {
+		return sqlScopeSource{}, err
+	}
+	source, exists := datasource.GetDataSource(name)
+	if !exists {
+		return sqlScopeSource{}, errors.New("data source does not exist")
+	}
+
+	source.mu.Lock()
+	defer source.mu.Unlock()
+	if len(source.Data) == 0 {
+		return sqlScopeSource{}, errors.New("data source " + name + " has no columns")
+	}
+	scopeSource := sqlScopeSource{name: name, qualifier: name, headers: append([]string{}, source.Data[0]...)}
+
+	if allowAlias {
+		if p.acceptKeyword("AS") || p.isIdentifier() {
+			if scopeSource.qualifier, err = p.identifier("alias"); err != nil {
+				return sqlScopeSource{}, err
+			}
 		}
+	}
+	return scopeSource, nil
+}
+
+type sqlSelectItem struct {
+	column   string // "*" for every column
+	function string // set for an aggregate
+	alias    string
+}
+
+var sqlAggregateFunctions = []string{"COUNT", "SUM", "AVG", "MIN", "MAX"}
+
+func (p *sqlParser) parseSelect(datasource *TemplateDataSource) (SQLStatement, error) {
+	statement := SQLStatement{Type: "SELECT", Distinct: p.acceptKeyword("DISTINCT")}
+
+	items, err := p.parseSelectItems()
+	if err != nil {
+		return SQLStatement{}, err
+	}
+	if err := p.expectKeyword("FROM"); err != nil {
+		return SQLStatement{}, err
+	}
 
-		conditions, err := parseConditions(wherePart)
+	source, err := p.parseDataSource(datasource, true)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+	statement.DataSourceName = source.name
+	if source.qualifier != source.name {
+		statement.Alias = source.qualifier
+	}
+	scope := sqlScope{sources: []sqlScopeSource{source}}
+
+	for {
+		left := false
+		if p.acceptKeyword("LEFT") {
+			p.acceptKeyword("OUTER")
+			left = true
+		} else {
+			p.acceptKeyword("INNER")
+		}
+		if !p.acceptKeyword("JOIN") {
+			if left {
+				return SQLStatement{}, fmt.Errorf("expected JOIN but found %s", p.describe())
+			}
+			break
+		}
+
+		joined, err := p.parseDataSource(datasource, true)
 		if err != nil {
 			return SQLStatement{}, err
 		}
+		join := Join{DataSourceName: joined.name, Left: left}
+		if joined.qualifier != joined.name {
+			join.Alias = joined.qualifier
+		}
+		if err := p.expectKeyword("ON"); err != nil {
+			return SQLStatement{}, err
+		}
+		if join.LeftColumn, err = p.identifier("column"); err != nil {
+			return SQLStatement{}, err
+		}
+		if _, ok := p.acceptSymbol("=", "=="); !ok {
+			return SQLStatement{}, fmt.Errorf("expected = but found %s", p.describe())
+		}
+		if join.RightColumn, err = p.identifier("column"); err != nil {
+			return SQLStatement{}, err
+		}
+		scope.sources = append(scope.sources, joined)
+		statement.Joins = append(statement.Joins, join)
+	}
 
-		return SQLStatement{
-			Type:           commandType,
-			Columns:        columns,
-			Conditions:     conditions,
-			DataSourceName: dataSourceName,
-		}, nil
-	case "UPDATE":
-		selectRegex = regexp.MustCompile(`(?i)^UPDATE\s+([\w-]+)\s+SET\s+(.+?)(?:\s+WHERE\s+(.+))?$`)
-		matches = selectRegex.FindStringSubmatch(query)
-		if len(matches) < 3 {
-			return SQLStatement{}, errors.New("invalid UPDATE query format")
-		}
-		dataSourceName = matches[1]
-		source, exits := datasource.GetDataSource(dataSourceName)
-		if !exits {
-			return SQLStatement{}, errors.New("data source does not exist")
-		}
-		setPart := matches[2]
-		if len(matches) == 4 {
-			wherePart = matches[3]
-		}
-		headers := source.Data[0]
-		setClauses, err := parseSetClauses(setPart, headers)
-		if err != nil {
+	// Columns are resolved once every data source is known, as a join can make a column name ambiguous
+	for i, join := range statement.Joins {
+		if statement.Joins[i].LeftColumn, _, err = scope.resolve(join.LeftColumn); err != nil {
 			return SQLStatement{}, err
 		}
-		conditions, err := parseConditions(wherePart)
-		if err != nil {
+		if statement.Joins[i].RightColumn, _, err = scope.resolve(join.RightColumn); err != nil {
+			return SQLStatement{}, err
+		}
+	}
+
+	if err := p.parseWhere(&statement, scope); err != nil {
+		return SQLStatement{}, err
+	}
+
+	if p.acceptKeyword("GROUP", "BY") {
+		for {
+			column, err := p.identifier("column")
+			if err != nil {
+				return SQLStatement{}, err
+			}
+			key, _, err := scope.resolve(column)
+			if err != nil {
+				return SQLStatement{}, err
+			}
+			statement.GroupBy = append(statement.GroupBy, key)
+			if _, ok := p.acceptSymbol(","); !ok {
+				break
+			}
+		}
+	}
+
+	var orderBy []OrderBy
+	if p.acceptKeyword("ORDER", "BY") {
+		for {
+			column, err := p.identifier("column")
+			if err != nil {
+				return SQLStatement{}, err
+			}
+			descending := p.acceptKeyword("DESC")
+			if !descending {
+				p.acceptKeyword("ASC")
+			}
+			orderBy = append(orderBy, OrderBy{Column: column, Descending: descending})
+			if _, ok := p.acceptSymbol(","); !ok {
+				break
+			}
+		}
+	}
+
+	if p.acceptKeyword("LIMIT") {
+		if statement.Limit, err = p.count("LIMIT"); err != nil {
 			return SQLStatement{}, err
 		}
-		return SQLStatement{
-			Type:           "UPDATE",
-			Conditions:     conditions,
-			SetClauses:     setClauses,
-			DataSourceName: dataSourceName,
-		}, nil
+		if statement.Limit == 0 {
+			return SQLStatement{}, errors.New("LIMIT must be greater than 0")
+		}
+	}
+	if p.acceptKeyword("OFFSET") {
+		if statement.Offset, err = p.count("OFFSET"); err != nil {
+			return SQLStatement{}, err
+		}
+	}
+	if err := p.end(); err != nil {
+		return SQLStatement{}, err
+	}
+
+	if err := statement.resolveSelectItems(items, scope); err != nil {
+		return SQLStatement{}, err
+	}
+	if err := statement.resolveOrderBy(orderBy, scope); err != nil {
+		return SQLStatement{}, err
+	}
+	return statement, nil
+}
+
+func (p *sqlParser) parseSelectItems() ([]sqlSelectItem, error) {
+	var items []sqlSelectItem
+	for {
+		var item sqlSelectItem
+		if _, ok := p.acceptSymbol("*"); ok {
+			item.column = "*"
+		} else {
+			name, err := p.identifier("column")
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.acceptSymbol("("); ok {
+				item.function = strings.ToUpper(name)
+				if !stringExists(sqlAggregateFunctions, item.function) {
+					return nil, errors.New("unsupported function: " + name)
+				}
+				if _, ok := p.acceptSymbol("*"); ok {
+					if item.function != "COUNT" {
+						return nil, errors.New(item.function + "(*) is not supported")
+					}
+					item.column = "*"
+				} else if item.column, err = p.identifier("column"); err != nil {
+					return nil, err
+				}
+				if err := p.expectSymbol(")"); err != nil {
+					return nil, err
+				}
+			} else {
+				item.column = name
+			}
+
+			if p.acceptKeyword("AS") || p.isIdentifier() {
+				if item.alias, err = p.identifier("alias"); err != nil {
+					return nil, err
+				}
+			}
+		}
+		items = append(items, item)
+		if _, ok := p.acceptSymbol(","); !ok {
+			return items, nil
+		}
+	}
+}
+
+func (statement *SQLStatement) resolveSelectItems(items []sqlSelectItem, scope sqlScope) error {
+	setAlias := func(key, alias string) {
+		if statement.Aliases == nil {
+			statement.Aliases = map[string]string{}
+		}
+		statement.Aliases[key] = alias
+	}
+
+	for _, item := range items {
+		switch {
+		case item.function != "":
+			aggregate := Aggregate{Function: item.function, Column: item.column, Name: item.alias}
+			header := ""
+			if item.column != "*" {
+				var err error
+				if aggregate.Column, header, err = scope.resolve(item.column); err != nil {
+					return err
+				}
+			}
+			if aggregate.Name == "" {
+				aggregate.Name = strings.ToLower(aggregate.Function)
+				if header != "" {
+					aggregate.Name += "_" + header
+				}
+			}
+			statement.Aggregates = append(statement.Aggregates, aggregate)
+		case item.column == "*":
+			for _, source := range scope.sources {
+				for _, header := range source.headers {
+					key := scope.key(source, header)
+					statement.Columns = append(statement.Columns, key)
+					if key != header && scope.isUnique(header) {
+						setAlias(key, header)
+					}
+				}
+			}
+		default:
+			key, header, err := scope.resolve(item.column)
+			if err != nil {
+				return err
+			}
+			statement.Columns = append(statement.Columns, key)
+			if item.alias != "" {
+				setAlias(key, item.alias)
+			} else if key != header {
+				setAlias(key, header)
+			}
+		}
+	}
+
+	if len(statement.Aggregates) > 0 || len(statement.GroupBy) > 0 {
+		for _, column := range statement.Columns {
+			if !stringExists(statement.GroupBy, column) {
+				return errors.New("column " + column + " must be in GROUP BY to be selected")
+			}
+		}
+	}
+	return nil
+}
+
+// resolveOrderBy sorts by the names given to selected columns and aggregates first, then by the columns of the
+// data sources
+func (statement *SQLStatement) resolveOrderBy(orderBy []OrderBy, scope sqlScope) error {
+	names := []string{}
+	for _, column := range statement.Columns {
+		names = append(names, statement.outputName(column))
+	}
+	for _, aggregate := range statement.Aggregates {
+		names = append(names, aggregate.Name)
+	}
 
-	case "DELETE":
-		selectRegex = regexp.MustCompile(`(?i)^DELETE\s+FROM\s+([\w-]+)\s*(?:WHERE\s+(.+))?$`)
-		matches = selectRegex.FindStringSubmatch(query)
-		if len(matches) < 2 {
-			return SQLStatement{}, errors.New("invalid DELETE query format")
+	for _, order := range orderBy {
+		if !stringExists(names, order.Column) {
+			key, _, err := scope.resolve(order.Column)
+			if err != nil {
+				return err
+			}
+			order.Column = key
 		}
-		dataSourceName = matches[1]
-		if _, exits := datasource.GetDataSource(dataSourceName); !exits {
-			return SQLStatement{}, errors.New("data source does not exist")
+		statement.OrderBy = append(statement.OrderBy, order)
+	}
+	return nil
+}
+
+func (statement SQLStatement) outputName(column string) string {
+	if alias, ok := statement.Aliases[column]; ok {
+		return alias
+	}
+	return column
+}
+
+func (p *sqlParser) parseWhere(statement *SQLStatement, scope sqlScope) error {
+	if !p.acceptKeyword("WHERE") {
+		return nil
+	}
+	conditions, err := p.parseOr()
+	if err != nil {
+		return err
+	}
+	statement.Conditions, err = scope.resolveConditions(conditions)
+	return err
+}
+
+func (p *sqlParser) parseInsert(datasource *TemplateDataSource) (SQLStatement, error) {
+	if err := p.expectKeyword("INTO"); err != nil {
+		return SQLStatement{}, err
+	}
+	source, err := p.parseDataSource(datasource, false)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+
+	columns := source.headers
+	if _, ok := p.acceptSymbol("("); ok {
+		columns = nil
+		for {
+			column, err := p.identifier("column")
+			if err != nil {
+				return SQLStatement{}, err
+			}
+			if !stringExists(source.headers, column) {
+				return SQLStatement{}, errors.New("invalid column provided: " + column)
+			}
+			columns = append(columns, column)
+			if _, ok := p.acceptSymbol(","); !ok {
+				break
+			}
 		}
-		if len(matches) == 3 {
-			wherePart = matches[2]
+		if err := p.expectSymbol(")"); err != nil {
+			return SQLStatement{}, err
 		}
-		conditions, err := parseConditions(wherePart)
+	}
+
+	if err := p.expectKeyword("VALUES"); err != nil {
+		return SQLStatement{}, err
+	}
+	var rows [][]string
+	for {
+		values, err := p.parseValueList()
 		if err != nil {
 			return SQLStatement{}, err
 		}
-		return SQLStatement{
-			Type:           "DELETE",
-			Conditions:     conditions,
-			DataSourceName: dataSourceName,
-		}, nil
+		if len(values) != len(columns) {
+			return SQLStatement{}, fmt.Errorf("expected %d values but found %d", len(columns), len(values))
+		}
+		// Columns which are not given a value are left empty
+		row := make([]string, len(source.headers))
+		for i, column := range columns {
+			row[indexOf(source.headers, column)] = values[i]
+		}
+		rows = append(rows, row)
+		if _, ok := p.acceptSymbol(","); !ok {
+			break
+		}
+	}
+	if err := p.end(); err != nil {
+		return SQLStatement{}, err
+	}
+
+	return SQLStatement{
+		Type:           "INSERT",
+		DataSourceName: source.name,
+		InsertRows:     rows,
+	}, nil
+}
+
+func (p *sqlParser) parseUpdate(datasource *TemplateDataSource) (SQLStatement, error) {
+	source, err := p.parseDataSource(datasource, false)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+	if err := p.expectKeyword("SET"); err != nil {
+		return SQLStatement{}, err
+	}
+	setClauses, err := p.parseAssignments(source.headers)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+
+	statement := SQLStatement{
+		Type:           "UPDATE",
+		SetClauses:     setClauses,
+		DataSourceName: source.name,
+	}
+	if err := p.parseWhere(&statement, sqlScope{sources: []sqlScopeSource{source}}); err != nil {
+		return SQLStatement{}, err
 	}
-	return SQLStatement{}, errors.New("invalid query format")
+	if err := p.end(); err != nil {
+		return SQLStatement{}, err
+	}
+	return statement, nil
 }
 
-// parseColumns determines the columns to select based on the query part and headers
-func parseColumns(columnsPart string, headers []string) ([]string, error) {
-	columnsPart = strings.TrimSpace(columnsPart)
-	if columnsPart == "*" {
-		return headers, nil
+func (p *sqlParser) parseDelete(datasource *TemplateDataSource) (SQLStatement, error) {
+	if err := p.expectKeyword("FROM"); err != nil {
+		return SQLStatement{}, err
+	}
+	source, err := p.parseDataSource(datasource, false)
+	if err != nil {
+		return SQLStatement{}, err
+	}
+
+	statement := SQLStatement{
+		Type:           "DELETE",
+		DataSourceName: source.name,
+	}
+	if err := p.parseWhere(&statement, sqlScope{sources: []sqlScopeSource{source}}); err != nil {
+		return SQLStatement{}, err
+	}
+	if err := p.end(); err != nil {
+		return SQLStatement{}, err
 	}
-	columns := strings.Split(columnsPart, ",")
-	for i, column := range columns {
-		if !stringExists(headers, strings.TrimSpace(column)) {
-			return nil, errors.New("invalid column provided: " + strings.TrimSpace(column))
+	return statement, nil
+}
+
+func (p *sqlParser) parseValueList() ([]string, error) {
+	if err := p.expectSymbol("("); err != nil {
+		return nil, err
+	}
+	var values []string
+	for {
+		value, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if _, ok := p.acceptSymbol(","); !ok {
+			break
 		}
-		columns[i] = strings.TrimSpace(column)
 	}
-	return columns, nil
+	return values, p.expectSymbol(")")
 }
 
 func stringExists(slice []string, target string) bool {
@@ -185,99 +758,458 @@ func trimQuotes(s string) string {
 
 // parseSetClauses parses the SET part of an UPDATE query
 func parseSetClauses(setPart string, headers []string) (map[string]string, error) {
-	setClauses := make(map[string]string)
-	parts, err := splitOnCommasOutsideQuotes(setPart)
+	parser, err := newSqlParser(setPart)
+	if err != nil {
+		return nil, err
+	}
+	setClauses, err := parser.parseAssignments(headers)
 	if err != nil {
 		return nil, err
 	}
+	return setClauses, parser.end()
+}
 
-	for _, part := range parts {
-		keyValue := strings.SplitN(strings.TrimSpace(part), "=", 2)
-		if len(keyValue) != 2 {
-			return nil, errors.New("invalid SET clause: " + part)
+func (p *sqlParser) parseAssignments(headers []string) (map[string]string, error) {
+	setClauses := make(map[string]string)
+	for {
+		key, err := p.identifier("column")
+		if err != nil {
+			return nil, err
 		}
-		key := strings.TrimSpace(keyValue[0])
-		value := strings.TrimSpace(keyValue[1])
-
 		if !stringExists(headers, key) {
 			return nil, errors.New("invalid column provided: " + key)
 		}
-		setClauses[key] = trimQuotes(value)
+		if _, ok := p.acceptSymbol("=", "=="); !ok {
+			return nil, errors.New("invalid SET clause for " + key)
+		}
+		if setClauses[key], err = p.literal(); err != nil {
+			return nil, err
+		}
+		if _, ok := p.acceptSymbol(","); !ok {
+			return setClauses, nil
+		}
 	}
-	return setClauses, nil
 }
 
-// splitOnCommasOutsideQuotes splits a string by commas only if the commas are outside of quotes
-func splitOnCommasOutsideQuotes(s string) ([]string, error) {
-	var parts []string
-	var sb strings.Builder
-	var inQuotes bool
-	var quoteChar rune
+// parseConditions parses the WHERE part of the query into a slice of Conditions and returns an error if any issues are found.
+func parseConditions(wherePart string) ([]Condition, error) {
+	parser, err := newSqlParser(wherePart)
+	if err != nil {
+		return nil, err
+	}
+	if parser.peek().kind == sqlEnd {
+		return []Condition{}, nil
+	}
+	conditions, err := parser.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return conditions, parser.end()
+}
 
-	for _, r := range s {
-		switch {
-		case r == '\'' || r == '"':
-			if inQuotes {
-				if r == quoteChar {
-					inQuotes = false
-				}
-			} else {
-				inQuotes = true
-				quoteChar = r
-			}
-			sb.WriteRune(r)
-		case r == ',' && !inQuotes:
-			parts = append(parts, sb.String())
-			sb.Reset()
-		default:
-			sb.WriteRune(r)
+// parseOr reads conditions separated by OR, which become a single OR condition when there is more than one group.
+// AND binds more tightly than OR, and parentheses group conditions as usual.
+func (p *sqlParser) parseOr() ([]Condition, error) {
+	var groups [][]Condition
+	for {
+		group, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+		if !p.acceptKeyword("OR") {
+			break
 		}
 	}
-
-	// Add the last part
-	parts = append(parts, sb.String())
-
-	return parts, nil
+	if len(groups) == 1 {
+		return groups[0], nil
+	}
+	return []Condition{{Operator: "OR", Groups: groups}}, nil
 }
 
-// parseConditions parses the WHERE part of the query into a slice of Conditions and returns an error if any issues are found.
-func parseConditions(wherePart string) ([]Condition, error) {
+func (p *sqlParser) parseAnd() ([]Condition, error) {
 	conditions := []Condition{}
+	for {
+		if _, ok := p.acceptSymbol("("); ok {
+			group, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectSymbol(")"); err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, group...)
+		} else {
+			condition, err := p.parseComparison()
+			if err != nil {
+				return nil, err
+			}
+			if condition != nil {
+				conditions = append(conditions, *condition)
+			}
+		}
+		if !p.acceptKeyword("AND") {
+			return conditions, nil
+		}
+	}
+}
 
-	conditionRegex := regexp.MustCompile(`(\w+)\s*(==|=|!=|<=|>=|<|>)\s*'([^']*)'`)
-	conditionMatches := conditionRegex.FindAllStringSubmatch(wherePart, -1)
+var mirroredSqlOperators = map[string]string{"<": ">", "<=": ">=", ">": "<", ">=": "<="}
 
-	if len(conditionMatches) == 0 {
-		return conditions, nil
+// parseComparison reads a single condition. A comparison between two values is worked out straight away, giving no
+// condition when it is true and a condition which never matches when it is false.
+func (p *sqlParser) parseComparison() (*Condition, error) {
+	left, leftIsColumn, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	condition := Condition{Column: left}
+	negated := p.acceptKeyword("NOT")
+	switch {
+	case p.acceptKeyword("LIKE"):
+		condition.Operator = "LIKE"
+		if condition.Value, err = p.literal(); err != nil {
+			return nil, err
+		}
+	case p.acceptKeyword("IN"):
+		condition.Operator = "IN"
+		if condition.Values, err = p.parseValueList(); err != nil {
+			return nil, err
+		}
+	case !negated && p.acceptKeyword("BETWEEN"):
+		from, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectKeyword("AND"); err != nil {
+			return nil, err
+		}
+		to, err := p.literal()
+		if err != nil {
+			return nil, err
+		}
+		condition = Condition{Operator: "OR", Groups: [][]Condition{{
+			{Column: left, Operator: ">=", Value: from},
+			{Column: left, Operator: "<=", Value: to},
+		}}}
+	case negated:
+		return nil, fmt.Errorf("expected LIKE or IN after NOT but found %s", p.describe())
+	default:
+		operator, ok := p.acceptSymbol("==", "=", "!=", "<>", "<=", ">=", "<", ">")
+		if !ok {
+			return nil, fmt.Errorf("expected an operator but found %s", p.describe())
+		}
+		if operator == "<>" {
+			operator = "!="
+		}
+		right, rightIsColumn, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		if leftIsColumn && rightIsColumn {
+			return nil, errors.New("columns can only be compared with each other in a JOIN")
+		}
+		condition.Operator = operator
+		condition.Value = right
+		if rightIsColumn {
+			condition.Column = right
+			condition.Value = left
+			if mirrored, ok := mirroredSqlOperators[operator]; ok {
+				condition.Operator = mirrored
+			}
+			leftIsColumn = true
+		}
+	}
+	if negated {
+		condition.Operator = "NOT " + condition.Operator
 	}
 
-	for _, match := range conditionMatches {
-		if len(match) != 4 {
-			return nil, errors.New("invalid condition format")
+	if !leftIsColumn {
+		if condition.Operator == "OR" || strings.HasSuffix(condition.Operator, "LIKE") || strings.HasSuffix(condition.Operator, "IN") {
+			return nil, fmt.Errorf("expected a column but found '%s'", left)
+		}
+		if compareCondition(left, condition) {
+			return nil, nil
 		}
-		conditions = append(conditions, Condition{
-			Column:   match[1],
-			Operator: match[2],
-			Value:    match[3],
-		})
+		return &Condition{Operator: "OR"}, nil
 	}
+	return &condition, nil
+}
 
-	return conditions, nil
+func (p *sqlParser) parseOperand() (string, bool, error) {
+	if p.isIdentifier() {
+		return p.next().text, true, nil
+	}
+	value, err := p.literal()
+	return value, false, err
 }
 
 // ExecuteSelectQuery executes a SELECT query and returns the results as a slice of RowMaps
 func executeSqlSelectQuery(data *[][]string, query SQLStatement) []RowMap {
-	headers := (*data)[0] // First row as header
+	filtered := []RowMap{}
+	for _, row := range joinRows(*data, query) {
+		if matchesConditions(row, query.Conditions) {
+			filtered = append(filtered, row)
+		}
+	}
+
+	var rows []sqlResultRow
+	if len(query.Aggregates) > 0 || len(query.GroupBy) > 0 {
+		rows = groupRows(filtered, query)
+	} else {
+		for _, row := range filtered {
+			rows = append(rows, sqlResultRow{source: row, output: projectRow(row, query.Columns, query.Aliases)})
+		}
+	}
+	if query.Distinct {
+		rows = distinctRows(rows)
+	}
+	sortRows(rows, query.OrderBy)
+
+	start := query.Offset
+	if start > len(rows) {
+		start = len(rows)
+	}
+	end := len(rows)
+	if query.Limit > 0 && start+query.Limit < end {
+		end = start + query.Limit
+	}
+
 	results := []RowMap{}
-	for _, row := range (*data)[1:] {
-		rowMap := mapRow(headers, row)
-		if matchesConditions(rowMap, query.Conditions) {
-			results = append(results, projectRow(rowMap, query.Columns))
+	for _, row := range rows[start:end] {
+		results = append(results, row.output)
+	}
+	return results
+}
+
+// sqlResultRow keeps the row a result was selected from, so that results can be sorted by columns not selected
+type sqlResultRow struct {
+	source RowMap
+	output RowMap
+}
+
+func (row sqlResultRow) value(column string) string {
+	if value, ok := row.output[column]; ok {
+		return value
+	}
+	return row.source[column]
+}
+
+func (query SQLStatement) qualifier() string {
+	if query.Alias != "" {
+		return query.Alias
+	}
+	return query.DataSourceName
+}
+
+func (join Join) qualifier() string {
+	if join.Alias != "" {
+		return join.Alias
+	}
+	return join.DataSourceName
+}
+
+// joinRows maps the rows of the data source, joined to the rows of any other data sources in the query
+func joinRows(data [][]string, query SQLStatement) []RowMap {
+	if len(query.Joins) == 0 {
+		return sourceRows(data, "")
+	}
+
+	rows := sourceRows(data, query.qualifier()+".")
+	for _, join := range query.Joins {
+		joinedRows := sourceRows(join.data, join.qualifier()+".")
+		var next []RowMap
+		for _, row := range rows {
+			matched := false
+			for _, joinedRow := range joinedRows {
+				combined := combineRows(row, joinedRow)
+				if combined[join.LeftColumn] == combined[join.RightColumn] {
+					next = append(next, combined)
+					matched = true
+				}
+			}
+			// A LEFT JOIN keeps rows without a match, leaving the columns of the joined data source empty
+			if !matched && join.Left && len(join.data) > 0 {
+				empty := RowMap{}
+				for _, header := range join.data[0] {
+					empty[join.qualifier()+"."+header] = ""
+				}
+				next = append(next, combineRows(row, empty))
+			}
+		}
+		rows = next
+	}
+	return rows
+}
+
+func sourceRows(data [][]string, prefix string) []RowMap {
+	rows := []RowMap{}
+	if len(data) == 0 {
+		return rows
+	}
+	headers := data[0]
+	for _, row := range data[1:] {
+		rowMap := make(RowMap)
+		for i, header := range headers {
+			if i < len(row) {
+				rowMap[prefix+header] = row[i]
+			} else {
+				rowMap[prefix+header] = ""
+			}
+		}
+		rows = append(rows, rowMap)
+	}
+	return rows
+}
+
+func combineRows(row, other RowMap) RowMap {
+	combined := make(RowMap, len(row)+len(other))
+	for key, value := range row {
+		combined[key] = value
+	}
+	for key, value := range other {
+		combined[key] = value
+	}
+	return combined
+}
+
+// groupRows gives a result for each group of rows with the same values in the GROUP BY columns. Aggregates without
+// GROUP BY give a single result, even when no rows match.
+func groupRows(rows []RowMap, query SQLStatement) []sqlResultRow {
+	var keys []string
+	groups := map[string][]RowMap{}
+	for _, row := range rows {
+		values := make([]string, len(query.GroupBy))
+		for i, column := range query.GroupBy {
+			values[i] = row[column]
+		}
+		key := strings.Join(values, "\x00")
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], row)
+	}
+	if len(query.GroupBy) == 0 && len(keys) == 0 {
+		keys = append(keys, "")
+	}
+
+	var results []sqlResultRow
+	for _, key := range keys {
+		group := groups[key]
+		source := RowMap{}
+		if len(group) > 0 {
+			source = group[0]
+		}
+		output := projectRow(source, query.Columns, query.Aliases)
+		for _, aggregate := range query.Aggregates {
+			output[aggregate.Name] = aggregateRows(group, aggregate)
 		}
+		results = append(results, sqlResultRow{source: source, output: output})
 	}
 	return results
 }
 
+// aggregateRows skips empty values, and values which are not numbers when adding them up
+func aggregateRows(rows []RowMap, aggregate Aggregate) string {
+	if aggregate.Column == "*" {
+		return strconv.Itoa(len(rows))
+	}
+
+	count, numbers := 0, 0
+	sum := 0.0
+	extreme := ""
+	for _, row := range rows {
+		value := row[aggregate.Column]
+		if value == "" {
+			continue
+		}
+		count++
+		if number, err := strconv.ParseFloat(value, 64); err == nil {
+			numbers++
+			sum += number
+		}
+		if count == 1 ||
+			(aggregate.Function == "MIN" && compareSqlValues(value, extreme) < 0) ||
+			(aggregate.Function == "MAX" && compareSqlValues(value, extreme) > 0) {
+			extreme = value
+		}
+	}
+
+	switch aggregate.Function {
+	case "COUNT":
+		return strconv.Itoa(count)
+	case "SUM":
+		return strconv.FormatFloat(sum, 'f', -1, 64)
+	case "AVG":
+		if numbers == 0 {
+			return ""
+		}
+		return strconv.FormatFloat(sum/float64(numbers), 'f', -1, 64)
+	}
+	return extreme
+}
+
+func distinctRows(rows []sqlResultRow) []sqlResultRow {
+	seen := map[string]bool{}
+	var distinct []sqlResultRow
+	for _, row := range rows {
+		columns := make([]string, 0, len(row.output))
+		for column := range row.output {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		var key strings.Builder
+		for _, column := range columns {
+			key.WriteString(column + "\x00" + row.output[column] + "\x00")
+		}
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			distinct = append(distinct, row)
+		}
+	}
+	return distinct
+}
+
+func sortRows(rows []sqlResultRow, orderBy []OrderBy) {
+	if len(orderBy) == 0 {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, order := range orderBy {
+			comparison := compareSqlValues(rows[i].value(order.Column), rows[j].value(order.Column))
+			if comparison != 0 {
+				return (comparison < 0) != order.Descending
+			}
+		}
+		return false
+	})
+}
+
+// compareSqlValues compares values as numbers when they both are, or as text otherwise
+func compareSqlValues(value1, value2 string) int {
+	number1, err1 := strconv.ParseFloat(value1, 64)
+	number2, err2 := strconv.ParseFloat(value2, 64)
+	if err1 == nil && err2 == nil {
+		switch {
+		case number1 < number2:
+			return -1
+		case number1 > number2:
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(value1, value2)
+}
+
+// executeSqlInsertCommand executes an INSERT query and appends the rows to the data
+func executeSqlInsertCommand(data *[][]string, query SQLStatement) int {
+	for _, row := range query.InsertRows {
+		*data = append(*data, append([]string{}, row...))
+	}
+	return len(query.InsertRows)
+}
+
 // ExecuteUpdateQuery executes an UPDATE query and modifies the data in-place
 func executeSqlUpdateCommand(data *[][]string, query SQLStatement) int {
 	if len(*data) < 2 {
@@ -352,20 +1284,25 @@ func indexOf(headers []string, column string) int {
 func mapRow(headers, row []string) RowMap {
 	rowMap := make(RowMap)
 	for i, header := range headers {
-		rowMap[header] = row[i]
+		if i < len(row) {
+			rowMap[header] = row[i]
+		} else {
+			rowMap[header] = ""
+		}
 	}
 	return rowMap
 }
 
-// projectRow filters the row based on the selected columns
-func projectRow(row RowMap, columns []string) RowMap {
-	if len(columns) == 0 {
-		return row
-	}
+// projectRow filters the row based on the selected columns, naming them after their aliases
+func projectRow(row RowMap, columns []string, aliases map[string]string) RowMap {
 	projected := make(RowMap)
 	for _, col := range columns {
 		if val, ok := row[col]; ok {
-			projected[col] = val
+			if alias, ok := aliases[col]; ok {
+				projected[alias] = val
+			} else {
+				projected[col] = val
+			}
 		}
 	}
 	return projected
@@ -374,36 +1311,71 @@ func projectRow(row RowMap, columns []string) RowMap {
 // matchesConditions checks if a row matches all given conditions
 func matchesConditions(row RowMap, conditions []Condition) bool {
 	for _, condition := range conditions {
-		val, ok := row[condition.Column]
-		if !ok {
-			return false
-		}
-		switch condition.Operator {
-		case "==", "=":
-			if val != condition.Value {
-				return false
-			}
-		case "!=":
-			if val == condition.Value {
-				return false
-			}
-		case "<":
-			if isGreaterThanOrEqual(val, condition.Value) {
-				return false
-			}
-		case "<=":
-			if isGreaterThan(val, condition.Value) {
-				return false
-			}
-		case ">":
-			if isLessThanOrEqual(val, condition.Value) {
-				return false
+		if condition.Operator == "OR" {
+			matched := false
+			for _, group := range condition.Groups {
+				if matchesConditions(row, group) {
+					matched = true
+					break
+				}
 			}
-		case ">=":
-			if isLessThan(val, condition.Value) {
+			if !matched {
 				return false
 			}
+			continue
+		}
+
+		val, ok := row[condition.Column]
+		if !ok || !compareCondition(val, condition) {
+			return false
 		}
 	}
 	return true
 }
+
+// compareCondition compares a value with a condition. Equality compares text, while the other comparisons
+// compare numbers when both values are numbers.
+func compareCondition(val string, condition Condition) bool {
+	switch condition.Operator {
+	case "==", "=":
+		return val == condition.Value
+	case "!=":
+		return val != condition.Value
+	case "<":
+		return compareSqlValues(val, condition.Value) < 0
+	case "<=":
+		return compareSqlValues(val, condition.Value) <= 0
+	case ">":
+		return compareSqlValues(val, condition.Value) > 0
+	case ">=":
+		return compareSqlValues(val, condition.Value) >= 0
+	case "LIKE":
+		return likeMatches(val, condition.Value)
+	case "NOT LIKE":
+		return !likeMatches(val, condition.Value)
+	case "IN":
+		return stringExists(condition.Values, val)
+	case "NOT IN":
+		return !stringExists(condition.Values, val)
+	}
+	return false
+}
+
+// likeMatches matches a value with a LIKE pattern ignoring case, where % matches any text and _ any one character
+func likeMatches(val, pattern string) bool {
+	var expression strings.Builder
+	expression.WriteString("(?is)^")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			expression.WriteString(".*")
+		case '_':
+			expression.WriteString(".")
+		default:
+			expression.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expression.WriteString("$")
+	matched, err := regexp.MatchString(expression.String(), val)
+	return err == nil && matched
+}
@@ -0,0 +1,100 @@
+package templating
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type sqlTokenKind int
+
+const (
+	sqlIdentifier sqlTokenKind = iota
+	sqlString
+	sqlNumber
+	sqlSymbol
+	sqlEnd
+)
+
+type sqlToken struct {
+	kind sqlTokenKind
+	text string
+	// quoted identifiers are never read as keywords
+	quoted bool
+}
+
+// sqlKeywords are only read as the names of data sources, columns or aliases when quoted with backticks
+var sqlKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "FROM": true, "WHERE": true, "AND": true, "OR": true, "NOT": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "OUTER": true, "ON": true, "GROUP": true, "ORDER": true, "BY": true,
+	"ASC": true, "DESC": true, "LIMIT": true, "OFFSET": true, "AS": true, "LIKE": true, "IN": true, "BETWEEN": true,
+	"INSERT": true, "INTO": true, "VALUES": true, "UPDATE": true, "SET": true, "DELETE": true,
+}
+
+// tokenizeSql splits a statement into identifiers, quoted strings, numbers and symbols. Identifiers can contain
+// hyphens, as data source names often do, and dots, which separate a data source from one of its columns.
+func tokenizeSql(query string) ([]sqlToken, error) {
+	var tokens []sqlToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"' || r == '`':
+			var sb strings.Builder
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == r {
+					// A quote is escaped by doubling it
+					if i+1 < len(runes) && runes[i+1] == r {
+						sb.WriteRune(r)
+						i++
+						continue
+					}
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, errors.New("unterminated quoted value")
+			}
+			kind := sqlString
+			if r == '`' {
+				kind = sqlIdentifier
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: sb.String(), quoted: true})
+		case unicode.IsDigit(r) || (r == '-' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i++; i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.'); i++ {
+			}
+			tokens = append(tokens, sqlToken{kind: sqlNumber, text: string(runes[start:i])})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i++; i < len(runes) && isSqlIdentifierRune(runes[i]); i++ {
+			}
+			tokens = append(tokens, sqlToken{kind: sqlIdentifier, text: string(runes[start:i])})
+		default:
+			symbol := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<>", "<=", ">=":
+					symbol = two
+				}
+			}
+			if !strings.Contains("(),*=<>!;", symbol[:1]) || symbol == "!" {
+				return nil, errors.New("unexpected character: " + symbol)
+			}
+			tokens = append(tokens, sqlToken{kind: sqlSymbol, text: symbol})
+			i += len(symbol)
+		}
+	}
+	return append(tokens, sqlToken{kind: sqlEnd}), nil
+}
+
+func isSqlIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
@@ -137,9 +137,9 @@ func TestParseSetClauses_InvalidInput(t *testing.T) {
 func TestParseConditions_ValidInput(t *testing.T) {
 	wherePart := "id == '1' AND name != 'John' AND age >= '30'"
 	expected := []Condition{
-		{"id", "==", "1"},
-		{"name", "!=", "John"},
-		{"age", ">=", "30"},
+		{Column: "id", Operator: "==", Value: "1"},
+		{Column: "name", Operator: "!=", Value: "John"},
+		{Column: "age", Operator: ">=", Value: "30"},
 	}
 
 	conditions, err := parseConditions(wherePart)
@@ -171,7 +171,7 @@ func TestParseConditions_EmptyInput(t *testing.T) {
 func TestParseConditions_SingleCondition(t *testing.T) {
 	wherePart := "name == 'Alice'"
 	expected := []Condition{
-		{"name", "==", "Alice"},
+		{Column: "name", Operator: "==", Value: "Alice"},
 	}
 
 	conditions, err := parseConditions(wherePart)
@@ -188,8 +188,8 @@ func TestParseConditions_SingleCondition(t *testing.T) {
 func TestParseConditions_MultipleConditions(t *testing.T) {
 	wherePart := "id == '1' AND age < '40'"
 	expected := []Condition{
-		{"id", "==", "1"},
-		{"age", "<", "40"},
+		{Column: "id", Operator: "==", Value: "1"},
+		{Column: "age", Operator: "<", Value: "40"},
 	}
 
 	conditions, err := parseConditions(wherePart)
@@ -323,3 +323,143 @@ func TestExecuteSqlDeleteCommand_RowCountResult(t *testing.T) {
 		t.Errorf("expected %v, got %v", expected, result)
 	}
 }
+
+func newSqlTestDataSource() *TemplateDataSource {
+	templateDataSource := NewTemplateDataSource()
+	templateDataSource.dataSources = map[string]*DataSource{
+		"pets": {
+			SourceType: "csv",
+			Name:       "pets",
+			Data: [][]string{
+				{"id", "category", "name", "ownerId"},
+				{"1", "cats", "Tom", "1"},
+				{"2", "dogs", "Rex", "2"},
+				{"9", "cats", "Felix", "1"},
+				{"10", "birds", "Tweety", ""},
+				{"11", "dogs", "Fido", "2"},
+			},
+		},
+		"owners": {
+			SourceType: "csv",
+			Name:       "owners",
+			Data: [][]string{
+				{"id", "name"},
+				{"1", "Ann"},
+				{"2", "Bob"},
+			},
+		},
+	}
+	return templateDataSource
+}
+
+func TestExecuteSqlSelectQuery_FullSql(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected []RowMap
+	}{
+		{
+			query:    "SELECT name FROM pets ORDER BY id DESC LIMIT 2 OFFSET 1",
+			expected: []RowMap{{"name": "Tweety"}, {"name": "Felix"}},
+		},
+		{
+			query:    "SELECT name FROM pets WHERE category = 'birds' OR (category = 'dogs' AND name != 'Rex')",
+			expected: []RowMap{{"name": "Tweety"}, {"name": "Fido"}},
+		},
+		{
+			query:    "SELECT name FROM pets WHERE name LIKE 'f%' AND id NOT IN ('11', '12')",
+			expected: []RowMap{{"name": "Felix"}},
+		},
+		{
+			query:    "SELECT name FROM pets WHERE id BETWEEN 2 AND 9 AND name NOT LIKE '_ex'",
+			expected: []RowMap{{"name": "Felix"}},
+		},
+		{
+			query:    "SELECT COUNT(*) AS total, SUM(id), MAX(name) FROM pets WHERE category IN ('cats', 'dogs')",
+			expected: []RowMap{{"total": "4", "sum_id": "23", "max_name": "Tom"}},
+		},
+		{
+			query:    "SELECT category, COUNT(*) AS total FROM pets GROUP BY category ORDER BY total DESC, category",
+			expected: []RowMap{{"category": "cats", "total": "2"}, {"category": "dogs", "total": "2"}, {"category": "birds", "total": "1"}},
+		},
+		{
+			query:    "SELECT DISTINCT category FROM pets ORDER BY category",
+			expected: []RowMap{{"category": "birds"}, {"category": "cats"}, {"category": "dogs"}},
+		},
+		{
+			query:    "SELECT p.name, o.name AS owner FROM pets p JOIN owners o ON p.ownerId = o.id WHERE o.name = 'Bob' ORDER BY p.id",
+			expected: []RowMap{{"name": "Rex", "owner": "Bob"}, {"name": "Fido", "owner": "Bob"}},
+		},
+		{
+			query:    "SELECT pets.name, owners.name AS owner FROM pets LEFT JOIN owners ON ownerId = owners.id WHERE category = 'birds'",
+			expected: []RowMap{{"name": "Tweety", "owner": ""}},
+		},
+		{
+			query:    "SELECT * FROM pets WHERE 1 = 2",
+			expected: []RowMap{},
+		},
+	}
+
+	for _, test := range tests {
+		templateDataSource := newSqlTestDataSource()
+		command, err := parseSqlCommand(test.query, templateDataSource)
+		if err != nil {
+			t.Errorf("unexpected error for query %s: %v", test.query, err)
+			continue
+		}
+		for i, join := range command.Joins {
+			command.Joins[i].data = templateDataSource.dataSources[join.DataSourceName].Data
+		}
+
+		result := executeSqlSelectQuery(&templateDataSource.dataSources["pets"].Data, command)
+		if !reflect.DeepEqual(result, test.expected) {
+			t.Errorf("expected %v, got %v for query: %s", test.expected, result, test.query)
+		}
+	}
+}
+
+func TestExecuteSqlInsertCommand(t *testing.T) {
+	templateDataSource := newSqlTestDataSource()
+
+	command, err := parseSqlCommand("INSERT INTO owners (name, id) VALUES ('Cat', 3), ('Dan', '4');", templateDataSource)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	result := executeSqlInsertCommand(&templateDataSource.dataSources["owners"].Data, command)
+	if result != 2 {
+		t.Errorf("expected 2, got %v", result)
+	}
+
+	expected := [][]string{{"id", "name"}, {"1", "Ann"}, {"2", "Bob"}, {"3", "Cat"}, {"4", "Dan"}}
+	if !reflect.DeepEqual(templateDataSource.dataSources["owners"].Data, expected) {
+		t.Errorf("expected %v, got %v", expected, templateDataSource.dataSources["owners"].Data)
+	}
+}
+
+func TestParseCommand_InvalidFullSql(t *testing.T) {
+	tests := map[string]string{
+		"SELECT name FROM pets JOIN owners ON ownerId = owners.id":  "ambiguous column provided: name",
+		"SELECT name FROM pets WHERE colour = 'black'":              "invalid column provided: colour",
+		"SELECT name, COUNT(*) FROM pets":                           "column name must be in GROUP BY to be selected",
+		"SELECT name FROM pets LIMIT 0":                             "LIMIT must be greater than 0",
+		"SELECT name FROM pets WHERE name = 'Tom' extra":            "unexpected 'extra'",
+		"SELECT name FROM pets WHERE name = id":                     "columns can only be compared with each other in a JOIN",
+		"INSERT INTO owners (id, name) VALUES ('3')":                "expected 2 values but found 1",
+		"UPDATE owners SET name = 'Cat' WHERE name = 'Ann' OR":      "expected a value but found end of query",
+		"DELETE FROM owners WHERE name = 'Ann":                      "unterminated quoted value",
+		"SELECT MEDIAN(id) FROM pets":                               "unsupported function: MEDIAN",
+		"SELECT name FROM pets WHERE name NOT = 'Tom'":              "expected LIKE or IN after NOT but found '='",
+		"SELECT name FROM pets ORDER BY colour":                     "invalid column provided: colour",
+		"SELECT name FROM pets LEFT owners ON ownerId = owners.id":  "expected JOIN but found 'owners'",
+		"SELECT name FROM pets JOIN vets ON pets.id = vets.petId":   "data source does not exist",
+		"SELECT name FROM pets WHERE 'Tom' LIKE 'T%'":               "expected a column but found 'Tom'",
+		"SELECT p.name FROM pets p JOIN owners o ON p.ownerId = id": "ambiguous column provided: id",
+	}
+
+	for query, expected := range tests {
+		_, err := parseSqlCommand(query, newSqlTestDataSource())
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected error %s, got %v for query: %s", expected, err, query)
+		}
+	}
+}
@@ -5,14 +5,16 @@ import (
 )
 
 type TemplateDataSource struct {
-	dataSources map[string]*DataSource
-	rwMutex     sync.RWMutex
+	dataSources     map[string]*DataSource
+	jsonDataSources map[string]*JsonDataSource
+	rwMutex         sync.RWMutex
 }
 
 func NewTemplateDataSource() *TemplateDataSource {
 
 	return &TemplateDataSource{
-		dataSources: make(map[string]*DataSource),
+		dataSources:     make(map[string]*DataSource),
+		jsonDataSources: make(map[string]*JsonDataSource),
 	}
 }
 
@@ -47,3 +49,35 @@ func (t *TemplateDataSource) GetDataSource(name string) (*DataSource, bool) {
 	source, exits := t.dataSources[name]
 	return source, exits
 }
+
+func (t *TemplateDataSource) SetJsonDataSource(dataSourceName string, dataSource *JsonDataSource) {
+
+	t.rwMutex.Lock()
+	defer t.rwMutex.Unlock()
+
+	t.jsonDataSources[dataSourceName] = dataSource
+}
+
+func (t *TemplateDataSource) DeleteJsonDataSource(dataSourceName string) {
+
+	t.rwMutex.Lock()
+	defer t.rwMutex.Unlock()
+
+	delete(t.jsonDataSources, dataSourceName)
+}
+
+func (t *TemplateDataSource) GetAllJsonDataSources() map[string]*JsonDataSource {
+
+	t.rwMutex.RLock()
+	defer t.rwMutex.RUnlock()
+
+	return t.jsonDataSources
+}
+
+func (t *TemplateDataSource) GetJsonDataSource(name string) (*JsonDataSource, bool) {
+	t.rwMutex.RLock()
+	defer t.rwMutex.RUnlock()
+
+	source, exists := t.jsonDataSources[name]
+	return source, exists
+}
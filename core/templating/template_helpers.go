@@ -7,6 +7,7 @@ import (
 	"math"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -442,21 +443,42 @@ func (t templateHelpers) csvSqlCommand(commandString string) []RowMap {
 		return []RowMap{}
 	}
 
-	// Find the data source by name
-	source, exists := t.TemplateDataSource.GetDataSource(command.DataSourceName)
-	if !exists {
-		log.Error("Could not find datasource " + command.DataSourceName)
-		return []RowMap{}
+	// Find the data sources by name, locking them in order of their names so that commands cannot deadlock
+	names := []string{command.DataSourceName}
+	for _, join := range command.Joins {
+		if !stringExists(names, join.DataSourceName) {
+			names = append(names, join.DataSourceName)
+		}
 	}
+	sort.Strings(names)
 
-	source.mu.Lock()
-	defer source.mu.Unlock()
+	sources := make(map[string]*DataSource)
+	for _, name := range names {
+		source, exists := t.TemplateDataSource.GetDataSource(name)
+		if !exists {
+			log.Error("Could not find datasource " + name)
+			return []RowMap{}
+		}
+		sources[name] = source
+	}
+	for _, name := range names {
+		sources[name].mu.Lock()
+		defer sources[name].mu.Unlock()
+	}
+	for i, join := range command.Joins {
+		command.Joins[i].data = sources[join.DataSourceName].Data
+	}
+	source := sources[command.DataSourceName]
 
 	var results []RowMap
 
 	switch command.Type {
 	case "SELECT":
 		results = executeSqlSelectQuery(&source.Data, command)
+	case "INSERT":
+		rowsAffected := executeSqlInsertCommand(&source.Data, command)
+		log.Debug(strconv.Itoa(rowsAffected) + " rows affected by " + commandString)
+		return nil
 	case "UPDATE":
 		rowsAffected := executeSqlUpdateCommand(&source.Data, command)
 		log.Debug(strconv.Itoa(rowsAffected) + " rows affected by " + commandString)
@@ -764,3 +786,153 @@ func getHeaderIndex(data [][]string, headerName string) (int, error) {
 	}
 	return -1, fmt.Errorf("search field %s does not found", headerName)
 }
+
+func (t templateHelpers) jsonData(dataSourceName, path string) string {
+	values, ok := t.queryJsonDataSource(dataSourceName, path)
+	if !ok || len(values) == 0 {
+		return ""
+	}
+	if len(values) == 1 {
+		return jsonValueString(values[0])
+	}
+	return jsonValueString(values)
+}
+
+func (t templateHelpers) jsonDataAsArray(dataSourceName, path string) []interface{} {
+	values, ok := t.queryJsonDataSource(dataSourceName, path)
+	if !ok {
+		return []interface{}{}
+	}
+	return jsonItems(values)
+}
+
+func (t templateHelpers) jsonDataMatchingItems(dataSourceName, path, searchFieldName, searchFieldValue string) []interface{} {
+	values, ok := t.queryJsonDataSource(dataSourceName, path)
+	if !ok {
+		return []interface{}{}
+	}
+
+	result := []interface{}{}
+	for _, item := range jsonItems(values) {
+		if jsonItemMatches(item, searchFieldName, searchFieldValue) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (t templateHelpers) jsonDataCount(dataSourceName, path string) string {
+	values, ok := t.queryJsonDataSource(dataSourceName, path)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%d", len(jsonItems(values)))
+}
+
+func (t templateHelpers) jsonDataAddItem(dataSourceName, path string, item interface{}) string {
+	newItem := parseJsonValue(item)
+	t.updateJsonDataSource(dataSourceName, path, func(value interface{}) (interface{}, bool) {
+		switch array := value.(type) {
+		case []interface{}:
+			return append(array, newItem), true
+		case nil:
+			return []interface{}{newItem}, true
+		}
+		return value, false
+	})
+	return ""
+}
+
+func (t templateHelpers) jsonDataSet(dataSourceName, path string, value interface{}) string {
+	newValue := parseJsonValue(value)
+	t.updateJsonDataSource(dataSourceName, path, func(interface{}) (interface{}, bool) {
+		return newValue, true
+	})
+	return ""
+}
+
+func (t templateHelpers) jsonDataDeleteItems(dataSourceName, path, searchFieldName, searchFieldValue string, output bool) string {
+	itemsDeleted := 0
+	t.updateJsonDataSource(dataSourceName, path, func(value interface{}) (interface{}, bool) {
+		array, ok := value.([]interface{})
+		if !ok {
+			return value, false
+		}
+		filtered := []interface{}{}
+		for _, item := range array {
+			if jsonItemMatches(item, searchFieldName, searchFieldValue) {
+				itemsDeleted++
+			} else {
+				filtered = append(filtered, item)
+			}
+		}
+		return filtered, true
+	})
+	if output {
+		return fmt.Sprintf("%d", itemsDeleted)
+	}
+	return ""
+}
+
+// queryJsonDataSource returns a copy of the values so they can be read after another request has changed the data
+func (t templateHelpers) queryJsonDataSource(dataSourceName, path string) ([]interface{}, bool) {
+	source, exists := t.TemplateDataSource.GetJsonDataSource(dataSourceName)
+	if !exists {
+		log.Error("could not find datasource " + dataSourceName)
+		return nil, false
+	}
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	values, err := source.query(path)
+	if err != nil {
+		log.Error("could not query datasource " + dataSourceName + ": " + err.Error())
+		return nil, false
+	}
+	return copyJsonValues(values), true
+}
+
+func (t templateHelpers) updateJsonDataSource(dataSourceName, path string, replace func(interface{}) (interface{}, bool)) {
+	source, exists := t.TemplateDataSource.GetJsonDataSource(dataSourceName)
+	if !exists {
+		log.Error("could not find datasource " + dataSourceName)
+		return
+	}
+	source.mu.Lock()
+	defer source.mu.Unlock()
+
+	updated, err := source.update(path, replace)
+	if err != nil {
+		log.Error("could not update datasource " + dataSourceName + ": " + err.Error())
+	} else if updated == 0 {
+		log.Error("could not find " + path + " in datasource " + dataSourceName)
+	}
+}
+
+// jsonItems returns the items of the array a path selects, or the values selected when there is more than one
+func jsonItems(values []interface{}) []interface{} {
+	if len(values) == 1 {
+		if array, ok := values[0].([]interface{}); ok {
+			return array
+		}
+	}
+	if values == nil {
+		return []interface{}{}
+	}
+	return values
+}
+
+// jsonItemMatches compares a field of an item with a value, where a dot selects a nested field
+func jsonItemMatches(item interface{}, searchFieldName, searchFieldValue string) bool {
+	value := item
+	for _, key := range strings.Split(searchFieldName, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		if value, ok = object[key]; !ok {
+			return false
+		}
+	}
+	return jsonValueString(value) == searchFieldValue
+}
@@ -101,6 +101,13 @@ func NewEnrichedTemplator(journal *journal.Journal) *Templator {
 	helperMethodMap["csvDeleteRows"] = t.csvDeleteRows
 	helperMethodMap["csvCountRows"] = t.csvCountRows
 	helperMethodMap["csvSqlCommand"] = t.csvSqlCommand
+	helperMethodMap["jsonData"] = t.jsonData
+	helperMethodMap["jsonDataAsArray"] = t.jsonDataAsArray
+	helperMethodMap["jsonDataMatchingItems"] = t.jsonDataMatchingItems
+	helperMethodMap["jsonDataCount"] = t.jsonDataCount
+	helperMethodMap["jsonDataAddItem"] = t.jsonDataAddItem
+	helperMethodMap["jsonDataSet"] = t.jsonDataSet
+	helperMethodMap["jsonDataDeleteItems"] = t.jsonDataDeleteItems
 	helperMethodMap["journal"] = t.parseJournalBasedOnIndex
 	helperMethodMap["hasJournalKey"] = t.hasJournalKey
 	helperMethodMap["setStatusCode"] = t.setStatusCode
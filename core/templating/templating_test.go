@@ -277,6 +277,31 @@ func Test_ApplyTemplate_CsvSQL_Delete(t *testing.T) {
 	Expect(template).To(Equal(`1,Test1,55;2,Test2,56;`)) // Test3 entry should be deleted
 }
 
+func Test_ApplyTemplate_CsvSQL_InsertAndPaginate(t *testing.T) {
+	RegisterTestingT(t)
+
+	templator := initiateTemplator()
+
+	// First, insert a row
+	_, err := renderTemplate(&models.RequestDetails{}, make(map[string]string), `{{csvSqlCommand "INSERT INTO test-csv2 (id, name, marks) VALUES ('3', 'Test4', '60')"}}`, templator)
+	Expect(err).To(BeNil())
+
+	// Then, sort and page through the rows
+	template, err := renderTemplate(&models.RequestDetails{}, make(map[string]string), `{{#each (csvSqlCommand "SELECT name FROM test-csv2 WHERE marks > 55 ORDER BY marks DESC LIMIT 2 OFFSET 1")}}{{this.name}};{{/each}}`, templator)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`Test4;Test2;`))
+}
+
+func Test_ApplyTemplate_CsvSQL_JoinAndCount(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{#each (csvSqlCommand "SELECT one.name, COUNT(*) AS rows FROM test-csv1 one JOIN test-csv2 two ON one.id = two.id GROUP BY one.name ORDER BY one.name")}}{{this.name}}={{this.rows}};{{/each}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`Test1=1;Test2=1;`))
+}
+
 func Test_ApplyTemplate_CsvSQL_InvalidQuery(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -703,6 +728,37 @@ func Test_ApplyTemplate_Request_Body_Jsonpath_From_Xml(t *testing.T) {
 	Expect(template).To(Equal("Johnny"))
 }
 
+func Test_ApplyTemplate_Request_Body_Multipart(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{
+		Body: "--xyz\r\n" +
+			"Content-Disposition: form-data; name=\"name\"\r\n\r\n" +
+			"Ben\r\n" +
+			"--xyz\r\n" +
+			"Content-Disposition: form-data; name=\"avatar\"; filename=\"ben.png\"\r\n" +
+			"Content-Type: image/png\r\n\r\n" +
+			"png\r\n" +
+			"--xyz--\r\n",
+	}, make(map[string]string), `{{ Request.Body 'multipart' 'name' }} {{ Request.Body 'multipart' 'avatar.filename' }} {{ Request.Body 'multipart' 'avatar.contentType' }}`)
+
+	Expect(err).To(BeNil())
+
+	Expect(template).To(Equal("Ben ben.png image/png"))
+}
+
+func Test_ApplyTemplate_Request_Body_GraphQL(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{
+		Body: `{"query": "query GetUser($id: ID!) { user(id: $id) { name } }", "variables": {"id": "42"}}`,
+	}, make(map[string]string), `{{ Request.Body 'graphql' 'operationName' }} {{ Request.Body 'graphql' '$.id' }}`)
+
+	Expect(err).To(BeNil())
+
+	Expect(template).To(Equal("GetUser 42"))
+}
+
 func Test_ApplyTemplate_ReplaceStringInQueryParams(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -1017,6 +1073,69 @@ func toInterfaceSlice(arguments []string) []interface{} {
 	return argumentsArray
 }
 
+func Test_ApplyTemplate_JsonData(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{jsonData 'test-json' '$.team'}} {{jsonData 'test-json' '$.users[1].id'}} {{{jsonData 'test-json' '$.users[0].address'}}} {{{jsonData 'test-json' '$.users[*].name'}}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`core 2 {"city":"London"} ["Ben","Tommy"]`))
+}
+
+func Test_ApplyTemplate_JsonDataMissingDataSourceOrPath(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{jsonData 'test-json99' '$.team'}}{{jsonData 'test-json' '$.teams'}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(``))
+}
+
+func Test_ApplyTemplate_JsonDataAsArray(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{#each (jsonDataAsArray 'test-json' '$.users')}}{{this.name}} from {{this.address.city}};{{/each}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`Ben from London;Tommy from Leeds;`))
+}
+
+func Test_ApplyTemplate_JsonDataMatchingItems(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{#each (jsonDataMatchingItems 'test-json' '$.users' 'address.city' 'Leeds')}}{{this.id}}:{{this.name}}{{/each}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`2:Tommy`))
+}
+
+func Test_ApplyTemplate_JsonDataCount(t *testing.T) {
+	RegisterTestingT(t)
+
+	template, err := ApplyTemplate(&models.RequestDetails{}, make(map[string]string), `{{jsonDataCount 'test-json' '$.users'}}`)
+
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`2`))
+}
+
+func Test_ApplyTemplate_JsonDataAddItemSetAndDeleteItems(t *testing.T) {
+	RegisterTestingT(t)
+
+	templator := initiateTemplator()
+
+	template, err := renderTemplate(&models.RequestDetails{Body: `{"id": 3, "name": "Mo"}`}, make(map[string]string), `{{jsonDataAddItem 'test-json' '$.users' (Request.Body 'jsonpath' '$')}}{{jsonDataCount 'test-json' '$.users'}}`, templator)
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`3`))
+
+	template, err = renderTemplate(&models.RequestDetails{}, make(map[string]string), `{{jsonDataSet 'test-json' '$.users[2].address' '{"city": "Leeds"}'}}{{jsonDataSet 'test-json' '$.team' 'docs'}}{{jsonData 'test-json' '$.team'}} {{jsonData 'test-json' '$.users[2].address.city'}}`, templator)
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`docs Leeds`))
+
+	template, err = renderTemplate(&models.RequestDetails{}, make(map[string]string), `{{jsonDataDeleteItems 'test-json' '$.users' 'address.city' 'Leeds' true}} {{jsonData 'test-json' '$.users[*].name'}}`, templator)
+	Expect(err).To(BeNil())
+	Expect(template).To(Equal(`2 Ben`))
+}
+
 func ApplyTemplate(requestDetails *models.RequestDetails, state map[string]string, responseBody string) (string, error) {
 
 	templator := initiateTemplator()
@@ -1037,5 +1156,7 @@ func initiateTemplator() *templating.Templator {
 	dataSource2, _ := templating.NewCsvDataSource("test-csv2", "id,name,marks\n1,Test1,55\n2,Test2,56\n5553686208582,Test3,66\n")
 	templator.TemplateHelper.TemplateDataSource.SetDataSource("test-csv1", dataSource1)
 	templator.TemplateHelper.TemplateDataSource.SetDataSource("test-csv2", dataSource2)
+	jsonDataSource, _ := templating.NewJsonDataSource("test-json", "json", `{"team": "core", "users": [{"id": 1, "name": "Ben", "address": {"city": "London"}}, {"id": 2, "name": "Tommy", "address": {"city": "Leeds"}}]}`)
+	templator.TemplateHelper.TemplateDataSource.SetJsonDataSource("test-json", jsonDataSource)
 	return templator
 }
@@ -0,0 +1,282 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	maxQueuedSpans = 2048
+	maxBatchSize   = 256
+)
+
+// Tracer - exports the spans of proxied requests to an OpenTelemetry collector, using OTLP over HTTP with JSON
+// encoding. Spans are sent in batches from a background goroutine, and are dropped if the collector cannot keep up.
+type Tracer struct {
+	Endpoint    string
+	ServiceName string
+
+	flushInterval time.Duration
+	client        *http.Client
+	spans         chan *Span
+	stop          chan struct{}
+	stopped       chan struct{}
+	stopOnce      sync.Once
+}
+
+// NewTracer - returns a tracer exporting to the OTLP endpoint of a collector, such as http://localhost:4318
+func NewTracer(endpoint string) *Tracer {
+	return newTracer(endpoint, 2*time.Second)
+}
+
+func newTracer(endpoint string, flushInterval time.Duration) *Tracer {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+
+	tracer := &Tracer{
+		Endpoint:      endpoint,
+		ServiceName:   "hoverfly",
+		flushInterval: flushInterval,
+		client:        &http.Client{Timeout: 10 * time.Second},
+		spans:         make(chan *Span, maxQueuedSpans),
+		stop:          make(chan struct{}),
+		stopped:       make(chan struct{}),
+	}
+	go tracer.run()
+
+	return tracer
+}
+
+func (this *Tracer) export(span *Span) {
+	select {
+	case this.spans <- span:
+	default:
+		log.WithField("span", span.Name).Debug("Dropped span as the trace export queue is full")
+	}
+}
+
+// Shutdown - stops exporting and sends the spans which are still queued, so that the traces of the last
+// requests are not lost when Hoverfly exits
+func (this *Tracer) Shutdown() {
+	if this == nil {
+		return
+	}
+
+	this.stopOnce.Do(func() {
+		close(this.stop)
+	})
+	<-this.stopped
+}
+
+func (this *Tracer) run() {
+	defer close(this.stopped)
+
+	var batch []*Span
+	ticker := time.NewTicker(this.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case span := <-this.spans:
+			batch = append(batch, span)
+			if len(batch) < maxBatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-this.stop:
+			this.flush(batch)
+			return
+		}
+
+		this.sendBatch(batch)
+		batch = nil
+	}
+}
+
+func (this *Tracer) flush(batch []*Span) {
+	for {
+		select {
+		case span := <-this.spans:
+			batch = append(batch, span)
+			if len(batch) < maxBatchSize {
+				continue
+			}
+		default:
+			if len(batch) > 0 {
+				this.sendBatch(batch)
+			}
+			return
+		}
+
+		this.sendBatch(batch)
+		batch = nil
+	}
+}
+
+func (this *Tracer) sendBatch(batch []*Span) {
+	if err := this.send(batch); err != nil {
+		log.WithFields(log.Fields{
+			"error":    err.Error(),
+			"endpoint": this.Endpoint,
+		}).Warn("Failed to export traces")
+	}
+}
+
+func (this *Tracer) send(spans []*Span) error {
+	body, err := json.Marshal(newExportRequest(this.ServiceName, spans))
+	if err != nil {
+		return err
+	}
+
+	response, err := this.client.Post(this.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	response.Body.Close()
+
+	if response.StatusCode/100 != 2 {
+		return fmt.Errorf("collector responded with %s", response.Status)
+	}
+	return nil
+}
+
+// The views below are the parts of the OTLP JSON encoding that Hoverfly uses
+
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope      `json:"scope"`
+	Spans []spanView `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type spanView struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              SpanKind   `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+func newExportRequest(serviceName string, spans []*Span) exportRequest {
+	views := make([]spanView, len(spans))
+	for i, span := range spans {
+		views[i] = newSpanView(span)
+	}
+
+	return exportRequest{
+		ResourceSpans: []resourceSpans{{
+			Resource: resource{
+				Attributes: []keyValue{{Key: "service.name", Value: newAnyValue(serviceName)}},
+			},
+			ScopeSpans: []scopeSpans{{
+				Scope: scope{Name: "hoverfly"},
+				Spans: views,
+			}},
+		}},
+	}
+}
+
+func newSpanView(span *Span) spanView {
+	span.mu.Lock()
+	defer span.mu.Unlock()
+
+	view := spanView{
+		TraceID:           hex.EncodeToString(span.TraceID[:]),
+		SpanID:            hex.EncodeToString(span.SpanID[:]),
+		Name:              span.Name,
+		Kind:              span.Kind,
+		StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+		EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+	}
+	if span.ParentSpanID != [8]byte{} {
+		view.ParentSpanID = hex.EncodeToString(span.ParentSpanID[:])
+	}
+	if span.Error != "" {
+		view.Status = status{Code: 2, Message: span.Error}
+	}
+
+	keys := make([]string, 0, len(span.Attributes))
+	for key := range span.Attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		view.Attributes = append(view.Attributes, keyValue{Key: key, Value: newAnyValue(span.Attributes[key])})
+	}
+
+	return view
+}
+
+func newAnyValue(value interface{}) anyValue {
+	switch v := value.(type) {
+	case bool:
+		return anyValue{BoolValue: &v}
+	case int:
+		intValue := strconv.Itoa(v)
+		return anyValue{IntValue: &intValue}
+	case int64:
+		intValue := strconv.FormatInt(v, 10)
+		return anyValue{IntValue: &intValue}
+	case float64:
+		return anyValue{DoubleValue: &v}
+	case string:
+		return anyValue{StringValue: &v}
+	default:
+		stringValue := ""
+		if b, err := json.Marshal(v); err == nil {
+			stringValue = string(b)
+		}
+		return anyValue{StringValue: &stringValue}
+	}
+}
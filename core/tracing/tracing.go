@@ -0,0 +1,181 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceparentHeader is the W3C Trace Context header carrying the trace and parent span of a request
+const TraceparentHeader = "traceparent"
+
+// SpanKind tells a collector whether a span serves a request, makes one or is internal to Hoverfly
+type SpanKind int
+
+const (
+	KindInternal SpanKind = 1
+	KindServer   SpanKind = 2
+	KindClient   SpanKind = 3
+)
+
+// Span - a timed operation of a trace. Every method is safe to call on a nil span, which is what requests get
+// when tracing is disabled.
+type Span struct {
+	TraceID      [16]byte
+	SpanID       [8]byte
+	ParentSpanID [8]byte
+	Name         string
+	Kind         SpanKind
+	Start        time.Time
+	End          time.Time
+	Attributes   map[string]interface{}
+	Error        string
+	Sampled      bool
+
+	tracer *Tracer
+	mu     sync.Mutex
+}
+
+// StartSpan - starts a server span for an incoming request, continuing the trace of its traceparent header if it
+// has a valid one. It returns nil when the tracer is nil.
+func (this *Tracer) StartSpan(name string, header http.Header) *Span {
+	if this == nil {
+		return nil
+	}
+
+	span := &Span{
+		Name:       name,
+		Kind:       KindServer,
+		Start:      time.Now(),
+		Attributes: map[string]interface{}{},
+		Sampled:    true,
+		tracer:     this,
+	}
+
+	if traceID, parentSpanID, sampled, ok := ParseTraceparent(header.Get(TraceparentHeader)); ok {
+		span.TraceID = traceID
+		span.ParentSpanID = parentSpanID
+		span.Sampled = sampled
+	} else {
+		rand.Read(span.TraceID[:])
+	}
+	rand.Read(span.SpanID[:])
+
+	return span
+}
+
+// StartChild - starts a span within this one
+func (this *Span) StartChild(name string, kind SpanKind) *Span {
+	if this == nil {
+		return nil
+	}
+
+	child := &Span{
+		TraceID:      this.TraceID,
+		ParentSpanID: this.SpanID,
+		Name:         name,
+		Kind:         kind,
+		Start:        time.Now(),
+		Attributes:   map[string]interface{}{},
+		Sampled:      this.Sampled,
+		tracer:       this.tracer,
+	}
+	rand.Read(child.SpanID[:])
+
+	return child
+}
+
+// SetAttribute - sets an attribute, which can be a string, bool, int or float64
+func (this *Span) SetAttribute(key string, value interface{}) {
+	if this == nil {
+		return
+	}
+
+	this.mu.Lock()
+	this.Attributes[key] = value
+	this.mu.Unlock()
+}
+
+// SetError - marks the span as failed when the error is not nil
+func (this *Span) SetError(err error) {
+	if this == nil || err == nil {
+		return
+	}
+
+	this.mu.Lock()
+	this.Error = err.Error()
+	this.mu.Unlock()
+}
+
+// Finish - ends the span and hands it to the tracer to be exported
+func (this *Span) Finish() {
+	if this == nil {
+		return
+	}
+
+	this.mu.Lock()
+	this.End = time.Now()
+	this.mu.Unlock()
+
+	if this.Sampled {
+		this.tracer.export(this)
+	}
+}
+
+// Traceparent - returns the traceparent header value which makes this span the parent of a request
+func (this *Span) Traceparent() string {
+	if this == nil {
+		return ""
+	}
+
+	flags := "00"
+	if this.Sampled {
+		flags = "01"
+	}
+	return "00-" + hex.EncodeToString(this.TraceID[:]) + "-" + hex.EncodeToString(this.SpanID[:]) + "-" + flags
+}
+
+// ParseTraceparent - reads the trace id, parent span id and sampled flag of a version 00 traceparent header
+func ParseTraceparent(value string) ([16]byte, [8]byte, bool, bool) {
+	var traceID [16]byte
+	var spanID [8]byte
+
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, spanID, false, false
+	}
+
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == [16]byte{} {
+		return traceID, spanID, false, false
+	}
+	if _, err := hex.Decode(spanID[:], []byte(parts[2])); err != nil || spanID == [8]byte{} {
+		return traceID, spanID, false, false
+	}
+
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return traceID, spanID, false, false
+	}
+
+	return traceID, spanID, flags[0]&1 == 1, true
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpan - returns a context carrying the span, or the context itself when the span is nil
+func ContextWithSpan(ctx context.Context, span *Span) context.Context {
+	if span == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, spanContextKey{}, span)
+}
+
+// SpanFromContext - returns the span carried by the context, or nil if it has none
+func SpanFromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanContextKey{}).(*Span)
+	return span
+}
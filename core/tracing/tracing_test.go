@@ -0,0 +1,213 @@
+package tracing
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ParseTraceparent_ReadsValidHeader(t *testing.T) {
+	RegisterTestingT(t)
+
+	traceID, spanID, sampled, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	Expect(ok).To(BeTrue())
+	Expect(sampled).To(BeTrue())
+	Expect(traceID[:]).To(Equal([]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36}))
+	Expect(spanID[:]).To(Equal([]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}))
+}
+
+func Test_ParseTraceparent_ReadsSampledFlag(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, _, sampled, ok := ParseTraceparent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+
+	Expect(ok).To(BeTrue())
+	Expect(sampled).To(BeFalse())
+}
+
+func Test_ParseTraceparent_RejectsInvalidHeaders(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, value := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	} {
+		_, _, _, ok := ParseTraceparent(value)
+		Expect(ok).To(BeFalse(), value)
+	}
+}
+
+func Test_Tracer_StartSpan_ContinuesIncomingTrace(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &Tracer{spans: make(chan *Span, 1)}
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	span := unit.StartSpan("GET", header)
+
+	Expect(span.Traceparent()).To(HavePrefix("00-4bf92f3577b34da6a3ce929d0e0e4736-"))
+	Expect(span.Traceparent()).ToNot(ContainSubstring("00f067aa0ba902b7"))
+	Expect(span.ParentSpanID[:]).To(Equal([]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}))
+	Expect(span.Kind).To(Equal(KindServer))
+}
+
+func Test_Tracer_StartSpan_StartsNewTraceWithoutTraceparent(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &Tracer{spans: make(chan *Span, 1)}
+
+	span := unit.StartSpan("GET", http.Header{})
+
+	Expect(span.TraceID).ToNot(Equal([16]byte{}))
+	Expect(span.ParentSpanID).To(Equal([8]byte{}))
+	Expect(span.Sampled).To(BeTrue())
+	Expect(span.Traceparent()).To(HaveSuffix("-01"))
+}
+
+func Test_Span_StartChild_SharesTraceAndSampling(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := &Tracer{spans: make(chan *Span, 1)}
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00")
+	span := unit.StartSpan("GET", header)
+
+	child := span.StartChild("matching", KindInternal)
+
+	Expect(child.TraceID).To(Equal(span.TraceID))
+	Expect(child.ParentSpanID).To(Equal(span.SpanID))
+	Expect(child.SpanID).ToNot(Equal(span.SpanID))
+	Expect(child.Sampled).To(BeFalse())
+
+	child.Finish()
+	Expect(unit.spans).To(BeEmpty())
+}
+
+func Test_Span_MethodsCanBeCalledOnNilSpan(t *testing.T) {
+	RegisterTestingT(t)
+
+	var tracer *Tracer
+	span := tracer.StartSpan("GET", http.Header{})
+
+	Expect(span).To(BeNil())
+	Expect(span.StartChild("matching", KindInternal)).To(BeNil())
+	Expect(span.Traceparent()).To(Equal(""))
+	span.SetAttribute("key", "value")
+	span.SetError(errors.New("error"))
+	span.Finish()
+}
+
+func Test_ContextWithSpan_CarriesSpan(t *testing.T) {
+	RegisterTestingT(t)
+
+	span := &Span{Name: "GET"}
+	request := httptest.NewRequest("GET", "http://test.com", nil)
+
+	Expect(SpanFromContext(request.Context())).To(BeNil())
+	Expect(SpanFromContext(ContextWithSpan(request.Context(), span))).To(Equal(span))
+	Expect(ContextWithSpan(request.Context(), nil)).To(Equal(request.Context()))
+}
+
+func Test_Tracer_ExportsFinishedSpansAsOtlpJson(t *testing.T) {
+	RegisterTestingT(t)
+
+	requests := make(chan exportRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Expect(r.URL.Path).To(Equal("/v1/traces"))
+		Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+
+		body, _ := io.ReadAll(r.Body)
+		var request exportRequest
+		Expect(json.Unmarshal(body, &request)).To(Succeed())
+		requests <- request
+	}))
+	defer collector.Close()
+
+	unit := newTracer(collector.URL, 10*time.Millisecond)
+	header := http.Header{}
+	header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	span := unit.StartSpan("GET", header)
+	span.SetAttribute("http.response.status_code", 200)
+	span.SetAttribute("hoverfly.mode", "simulate")
+	child := span.StartChild("upstream request", KindClient)
+	child.SetError(errors.New("connection refused"))
+	child.Finish()
+	span.Finish()
+
+	var request exportRequest
+	Eventually(requests, time.Second).Should(Receive(&request))
+
+	Expect(request.ResourceSpans).To(HaveLen(1))
+	Expect(*request.ResourceSpans[0].Resource.Attributes[0].Value.StringValue).To(Equal("hoverfly"))
+
+	spans := request.ResourceSpans[0].ScopeSpans[0].Spans
+	Expect(spans).To(HaveLen(2))
+
+	Expect(spans[0].Name).To(Equal("upstream request"))
+	Expect(spans[0].Kind).To(Equal(KindClient))
+	Expect(spans[0].TraceID).To(Equal("4bf92f3577b34da6a3ce929d0e0e4736"))
+	Expect(spans[0].ParentSpanID).To(Equal(spans[1].SpanID))
+	Expect(spans[0].Status).To(Equal(status{Code: 2, Message: "connection refused"}))
+
+	Expect(spans[1].Name).To(Equal("GET"))
+	Expect(spans[1].Kind).To(Equal(KindServer))
+	Expect(spans[1].ParentSpanID).To(Equal("00f067aa0ba902b7"))
+	Expect(spans[1].Attributes).To(HaveLen(2))
+	Expect(spans[1].Attributes[0].Key).To(Equal("hoverfly.mode"))
+	Expect(*spans[1].Attributes[0].Value.StringValue).To(Equal("simulate"))
+	Expect(spans[1].Attributes[1].Key).To(Equal("http.response.status_code"))
+	Expect(*spans[1].Attributes[1].Value.IntValue).To(Equal("200"))
+}
+
+func Test_Tracer_Shutdown_ExportsQueuedSpans(t *testing.T) {
+	RegisterTestingT(t)
+
+	requests := make(chan exportRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var request exportRequest
+		Expect(json.Unmarshal(body, &request)).To(Succeed())
+		requests <- request
+	}))
+	defer collector.Close()
+
+	unit := newTracer(collector.URL, time.Hour)
+	unit.StartSpan("GET", http.Header{}).Finish()
+	unit.StartSpan("POST", http.Header{}).Finish()
+
+	unit.Shutdown()
+
+	var request exportRequest
+	Expect(requests).To(Receive(&request))
+	Expect(request.ResourceSpans[0].ScopeSpans[0].Spans).To(HaveLen(2))
+
+	unit.Shutdown()
+}
+
+func Test_Tracer_Shutdown_CanBeCalledOnNilTracer(t *testing.T) {
+	RegisterTestingT(t)
+
+	var unit *Tracer
+	Expect(unit.Shutdown).ToNot(Panic())
+}
+
+func Test_NewTracer_AddsTracesPathToEndpoint(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(NewTracer("http://localhost:4318").Endpoint).To(Equal("http://localhost:4318/v1/traces"))
+	Expect(NewTracer("http://localhost:4318/").Endpoint).To(Equal("http://localhost:4318/v1/traces"))
+	Expect(NewTracer("http://localhost:4318/v1/traces").Endpoint).To(Equal("http://localhost:4318/v1/traces"))
+}
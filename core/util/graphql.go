@@ -0,0 +1,692 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphQLRequest is the query, operation name and variables sent in the body of a GraphQL request
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLOperation is an operation of a GraphQL document in a normalised form, so that two operations which only
+// differ in whitespace, comments, the order of fields, arguments or variables, or in how fragments are used have
+// the same String
+type GraphQLOperation struct {
+	Type       string
+	Name       string
+	Variables  string
+	Directives string
+	Selections []GraphQLSelection
+}
+
+// GraphQLSelection is a field or inline fragment selected by an operation. The key holds the alias, name,
+// arguments and directives of a field, or the type condition and directives of a fragment.
+type GraphQLSelection struct {
+	Key        string
+	Selections []GraphQLSelection
+}
+
+// ParseGraphQLRequest reads a GraphQL request from a body holding either JSON with the query, or the query on
+// its own as sent with the application/graphql content type
+func ParseGraphQLRequest(body string) (GraphQLRequest, bool) {
+	var request GraphQLRequest
+	if err := json.Unmarshal([]byte(body), &request); err == nil {
+		return request, request.Query != ""
+	}
+
+	if _, err := parseGraphQLDocument(body); err != nil {
+		return GraphQLRequest{}, false
+	}
+	return GraphQLRequest{Query: body}, true
+}
+
+// fetchFromGraphQLBody returns the operation name or query of a GraphQL request, or runs a JSONPath query
+// against its variables
+func fetchFromGraphQLBody(query, body string) interface{} {
+	request, ok := ParseGraphQLRequest(body)
+	if !ok {
+		return ""
+	}
+
+	switch query {
+	case "operationName":
+		if request.OperationName == "" {
+			if operation, err := ParseGraphQLOperation(request.Query, ""); err == nil {
+				return operation.Name
+			}
+		}
+		return request.OperationName
+	case "query":
+		return request.Query
+	}
+
+	variables, err := json.Marshal(request.Variables)
+	if err != nil {
+		return ""
+	}
+	return jsonPath(query, string(variables))
+}
+
+// ParseGraphQLOperation parses a GraphQL document and returns the operation with the name, or the only
+// operation when no name is given, with any fragments it spreads expanded
+func ParseGraphQLOperation(query, operationName string) (*GraphQLOperation, error) {
+	document, err := parseGraphQLDocument(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var operation *graphQLOperationDefinition
+	for _, candidate := range document.operations {
+		if operationName == "" && len(document.operations) == 1 || candidate.name == operationName && operationName != "" {
+			operation = candidate
+			break
+		}
+	}
+	if operation == nil {
+		if operationName == "" {
+			return nil, errors.New("GraphQL document has more than one operation but no operation name was given")
+		}
+		return nil, fmt.Errorf("GraphQL document has no operation named %s", operationName)
+	}
+
+	selections, err := document.expand(operation.selections, map[string]bool{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &GraphQLOperation{
+		Type:       operation.operationType,
+		Name:       operation.name,
+		Variables:  operation.variables,
+		Directives: operation.directives,
+		Selections: selections,
+	}, nil
+}
+
+func (this GraphQLOperation) String() string {
+	var builder strings.Builder
+	builder.WriteString(this.Type)
+	if this.Name != "" {
+		builder.WriteString(" " + this.Name)
+	}
+	if this.Variables != "" {
+		builder.WriteString("(" + this.Variables + ")")
+	}
+	builder.WriteString(this.Directives)
+	writeGraphQLSelections(&builder, this.Selections)
+	return builder.String()
+}
+
+func writeGraphQLSelections(builder *strings.Builder, selections []GraphQLSelection) {
+	if len(selections) == 0 {
+		return
+	}
+	builder.WriteString("{")
+	for i, selection := range selections {
+		if i > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(selection.Key)
+		writeGraphQLSelections(builder, selection.Selections)
+	}
+	builder.WriteString("}")
+}
+
+func (this GraphQLSelection) String() string {
+	var builder strings.Builder
+	builder.WriteString(this.Key)
+	writeGraphQLSelections(&builder, this.Selections)
+	return builder.String()
+}
+
+type graphQLDocument struct {
+	operations []*graphQLOperationDefinition
+	fragments  map[string]*graphQLFragmentDefinition
+}
+
+type graphQLOperationDefinition struct {
+	operationType string
+	name          string
+	variables     string
+	directives    string
+	selections    []graphQLSelectionNode
+}
+
+type graphQLFragmentDefinition struct {
+	typeCondition string
+	directives    string
+	selections    []graphQLSelectionNode
+}
+
+// graphQLSelectionNode is a selection as written in the document, where a fragment spread is yet to be expanded
+type graphQLSelectionNode struct {
+	key            string
+	fragmentSpread string
+	selections     []graphQLSelectionNode
+}
+
+// expand replaces fragment spreads with inline fragments of the same type and sorts the selections
+func (this *graphQLDocument) expand(nodes []graphQLSelectionNode, spreading map[string]bool) ([]GraphQLSelection, error) {
+	var selections []GraphQLSelection
+	for _, node := range nodes {
+		key := node.key
+		children := node.selections
+
+		if node.fragmentSpread != "" {
+			fragment, ok := this.fragments[node.fragmentSpread]
+			if !ok {
+				return nil, fmt.Errorf("GraphQL fragment %s is not defined", node.fragmentSpread)
+			}
+			if spreading[node.fragmentSpread] {
+				return nil, fmt.Errorf("GraphQL fragment %s spreads itself", node.fragmentSpread)
+			}
+			key = "...on " + fragment.typeCondition + node.key + fragment.directives
+			children = fragment.selections
+			spreading[node.fragmentSpread] = true
+		}
+
+		expanded, err := this.expand(children, spreading)
+		if err != nil {
+			return nil, err
+		}
+		delete(spreading, node.fragmentSpread)
+
+		selections = append(selections, GraphQLSelection{Key: key, Selections: expanded})
+	}
+
+	sort.SliceStable(selections, func(i, j int) bool {
+		if selections[i].Key != selections[j].Key {
+			return selections[i].Key < selections[j].Key
+		}
+		return selections[i].String() < selections[j].String()
+	})
+	return selections, nil
+}
+
+const (
+	graphQLPunctuator = iota
+	graphQLName
+	graphQLNumber
+	graphQLString
+	graphQLEnd
+)
+
+type graphQLToken struct {
+	kind  int
+	value string
+}
+
+type graphQLParser struct {
+	tokens   []graphQLToken
+	position int
+}
+
+func parseGraphQLDocument(query string) (*graphQLDocument, error) {
+	tokens, err := lexGraphQL(query)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &graphQLParser{tokens: tokens}
+	document := &graphQLDocument{fragments: map[string]*graphQLFragmentDefinition{}}
+
+	for !parser.peekKind(graphQLEnd) {
+		switch {
+		case parser.peek("{"):
+			selections, err := parser.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			document.operations = append(document.operations, &graphQLOperationDefinition{operationType: "query", selections: selections})
+		case parser.peek("query"), parser.peek("mutation"), parser.peek("subscription"):
+			operation, err := parser.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			document.operations = append(document.operations, operation)
+		case parser.peek("fragment"):
+			name, fragment, err := parser.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			document.fragments[name] = fragment
+		default:
+			return nil, parser.unexpected()
+		}
+	}
+
+	if len(document.operations) == 0 {
+		return nil, errors.New("GraphQL document has no operations")
+	}
+	return document, nil
+}
+
+func (this *graphQLParser) peek(value string) bool {
+	token := this.tokens[this.position]
+	return token.kind != graphQLString && token.kind != graphQLEnd && token.value == value
+}
+
+func (this *graphQLParser) peekKind(kind int) bool {
+	return this.tokens[this.position].kind == kind
+}
+
+func (this *graphQLParser) next() graphQLToken {
+	token := this.tokens[this.position]
+	if token.kind != graphQLEnd {
+		this.position++
+	}
+	return token
+}
+
+func (this *graphQLParser) expect(value string) error {
+	if !this.peek(value) {
+		return this.unexpected()
+	}
+	this.next()
+	return nil
+}
+
+func (this *graphQLParser) expectName() (string, error) {
+	if !this.peekKind(graphQLName) {
+		return "", this.unexpected()
+	}
+	return this.next().value, nil
+}
+
+func (this *graphQLParser) unexpected() error {
+	token := this.tokens[this.position]
+	if token.kind == graphQLEnd {
+		return errors.New("GraphQL document ended unexpectedly")
+	}
+	return fmt.Errorf("GraphQL document has unexpected %s", token.value)
+}
+
+func (this *graphQLParser) parseOperation() (*graphQLOperationDefinition, error) {
+	operation := &graphQLOperationDefinition{operationType: this.next().value}
+
+	if this.peekKind(graphQLName) {
+		operation.name = this.next().value
+	}
+
+	if this.peek("(") {
+		variables, err := this.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		operation.variables = variables
+	}
+
+	directives, err := this.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	operation.directives = directives
+
+	operation.selections, err = this.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	return operation, nil
+}
+
+func (this *graphQLParser) parseFragment() (string, *graphQLFragmentDefinition, error) {
+	this.next()
+	name, err := this.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := this.expect("on"); err != nil {
+		return "", nil, err
+	}
+	typeCondition, err := this.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+	directives, err := this.parseDirectives()
+	if err != nil {
+		return "", nil, err
+	}
+	selections, err := this.parseSelectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+
+	return name, &graphQLFragmentDefinition{typeCondition: typeCondition, directives: directives, selections: selections}, nil
+}
+
+func (this *graphQLParser) parseVariableDefinitions() (string, error) {
+	this.next()
+
+	var definitions []string
+	for !this.peek(")") {
+		if err := this.expect("$"); err != nil {
+			return "", err
+		}
+		name, err := this.expectName()
+		if err != nil {
+			return "", err
+		}
+		if err := this.expect(":"); err != nil {
+			return "", err
+		}
+		variableType, err := this.parseType()
+		if err != nil {
+			return "", err
+		}
+		definition := "$" + name + ":" + variableType
+		if this.peek("=") {
+			this.next()
+			defaultValue, err := this.parseValue()
+			if err != nil {
+				return "", err
+			}
+			definition += "=" + defaultValue
+		}
+		directives, err := this.parseDirectives()
+		if err != nil {
+			return "", err
+		}
+		definitions = append(definitions, definition+directives)
+	}
+	this.next()
+
+	sort.Strings(definitions)
+	return strings.Join(definitions, ","), nil
+}
+
+func (this *graphQLParser) parseType() (string, error) {
+	var graphQLType string
+	if this.peek("[") {
+		this.next()
+		itemType, err := this.parseType()
+		if err != nil {
+			return "", err
+		}
+		if err := this.expect("]"); err != nil {
+			return "", err
+		}
+		graphQLType = "[" + itemType + "]"
+	} else {
+		name, err := this.expectName()
+		if err != nil {
+			return "", err
+		}
+		graphQLType = name
+	}
+
+	if this.peek("!") {
+		this.next()
+		graphQLType += "!"
+	}
+	return graphQLType, nil
+}
+
+func (this *graphQLParser) parseDirectives() (string, error) {
+	var directives strings.Builder
+	for this.peek("@") {
+		this.next()
+		name, err := this.expectName()
+		if err != nil {
+			return "", err
+		}
+		directives.WriteString("@" + name)
+		if this.peek("(") {
+			arguments, err := this.parseArguments()
+			if err != nil {
+				return "", err
+			}
+			directives.WriteString(arguments)
+		}
+	}
+	return directives.String(), nil
+}
+
+func (this *graphQLParser) parseArguments() (string, error) {
+	this.next()
+
+	var arguments []string
+	for !this.peek(")") {
+		name, err := this.expectName()
+		if err != nil {
+			return "", err
+		}
+		if err := this.expect(":"); err != nil {
+			return "", err
+		}
+		value, err := this.parseValue()
+		if err != nil {
+			return "", err
+		}
+		arguments = append(arguments, name+":"+value)
+	}
+	this.next()
+
+	sort.Strings(arguments)
+	return "(" + strings.Join(arguments, ",") + ")", nil
+}
+
+func (this *graphQLParser) parseValue() (string, error) {
+	switch {
+	case this.peek("$"):
+		this.next()
+		name, err := this.expectName()
+		if err != nil {
+			return "", err
+		}
+		return "$" + name, nil
+	case this.peek("["):
+		this.next()
+		var values []string
+		for !this.peek("]") {
+			value, err := this.parseValue()
+			if err != nil {
+				return "", err
+			}
+			values = append(values, value)
+		}
+		this.next()
+		return "[" + strings.Join(values, ",") + "]", nil
+	case this.peek("{"):
+		this.next()
+		var fields []string
+		for !this.peek("}") {
+			name, err := this.expectName()
+			if err != nil {
+				return "", err
+			}
+			if err := this.expect(":"); err != nil {
+				return "", err
+			}
+			value, err := this.parseValue()
+			if err != nil {
+				return "", err
+			}
+			fields = append(fields, name+":"+value)
+		}
+		this.next()
+		sort.Strings(fields)
+		return "{" + strings.Join(fields, ",") + "}", nil
+	case this.peekKind(graphQLName), this.peekKind(graphQLNumber), this.peekKind(graphQLString):
+		return this.next().value, nil
+	}
+	return "", this.unexpected()
+}
+
+func (this *graphQLParser) parseSelectionSet() ([]graphQLSelectionNode, error) {
+	if err := this.expect("{"); err != nil {
+		return nil, err
+	}
+
+	var selections []graphQLSelectionNode
+	for !this.peek("}") {
+		selection, err := this.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+	this.next()
+
+	if len(selections) == 0 {
+		return nil, errors.New("GraphQL document has an empty selection set")
+	}
+	return selections, nil
+}
+
+func (this *graphQLParser) parseSelection() (graphQLSelectionNode, error) {
+	if this.peek("...") {
+		this.next()
+
+		if this.peekKind(graphQLName) && !this.peek("on") {
+			name := this.next().value
+			directives, err := this.parseDirectives()
+			if err != nil {
+				return graphQLSelectionNode{}, err
+			}
+			return graphQLSelectionNode{key: directives, fragmentSpread: name}, nil
+		}
+
+		key := "..."
+		if this.peek("on") {
+			this.next()
+			typeCondition, err := this.expectName()
+			if err != nil {
+				return graphQLSelectionNode{}, err
+			}
+			key += "on " + typeCondition
+		}
+		directives, err := this.parseDirectives()
+		if err != nil {
+			return graphQLSelectionNode{}, err
+		}
+		selections, err := this.parseSelectionSet()
+		if err != nil {
+			return graphQLSelectionNode{}, err
+		}
+		return graphQLSelectionNode{key: key + directives, selections: selections}, nil
+	}
+
+	name, err := this.expectName()
+	if err != nil {
+		return graphQLSelectionNode{}, err
+	}
+	key := name
+	if this.peek(":") {
+		this.next()
+		fieldName, err := this.expectName()
+		if err != nil {
+			return graphQLSelectionNode{}, err
+		}
+		// An alias which is the same as the field name changes nothing
+		if fieldName != name {
+			key = name + ":" + fieldName
+		} else {
+			key = fieldName
+		}
+	}
+
+	if this.peek("(") {
+		arguments, err := this.parseArguments()
+		if err != nil {
+			return graphQLSelectionNode{}, err
+		}
+		key += arguments
+	}
+
+	directives, err := this.parseDirectives()
+	if err != nil {
+		return graphQLSelectionNode{}, err
+	}
+	key += directives
+
+	var selections []graphQLSelectionNode
+	if this.peek("{") {
+		selections, err = this.parseSelectionSet()
+		if err != nil {
+			return graphQLSelectionNode{}, err
+		}
+	}
+
+	return graphQLSelectionNode{key: key, selections: selections}, nil
+}
+
+// lexGraphQL splits a GraphQL document into tokens, dropping whitespace, commas and comments, which are
+// insignificant
+func lexGraphQL(query string) ([]graphQLToken, error) {
+	var tokens []graphQLToken
+	position := 0
+
+	for position < len(query) {
+		character := query[position]
+		switch {
+		case character == ' ' || character == '\t' || character == '\n' || character == '\r' || character == ',':
+			position++
+		case strings.HasPrefix(query[position:], "\uFEFF"):
+			position += len("\uFEFF")
+		case character == '#':
+			for position < len(query) && query[position] != '\n' && query[position] != '\r' {
+				position++
+			}
+		case strings.HasPrefix(query[position:], "..."):
+			tokens = append(tokens, graphQLToken{graphQLPunctuator, "..."})
+			position += 3
+		case strings.IndexByte("!$&():=@[]{}|", character) >= 0:
+			tokens = append(tokens, graphQLToken{graphQLPunctuator, string(character)})
+			position++
+		case character == '_' || isASCIILetter(character):
+			start := position
+			for position < len(query) && (query[position] == '_' || isASCIILetter(query[position]) || isASCIIDigit(query[position])) {
+				position++
+			}
+			tokens = append(tokens, graphQLToken{graphQLName, query[start:position]})
+		case character == '-' || isASCIIDigit(character):
+			start := position
+			position++
+			for position < len(query) && (isASCIIDigit(query[position]) || strings.IndexByte(".eE+-", query[position]) >= 0) {
+				position++
+			}
+			tokens = append(tokens, graphQLToken{graphQLNumber, query[start:position]})
+		case strings.HasPrefix(query[position:], `"""`):
+			end := strings.Index(query[position+3:], `"""`)
+			if end < 0 {
+				return nil, errors.New("GraphQL document has an unterminated block string")
+			}
+			value := query[position+3 : position+3+end]
+			tokens = append(tokens, graphQLToken{graphQLString, fmt.Sprintf("%q", strings.TrimSpace(value))})
+			position += end + 6
+		case character == '"':
+			start := position
+			position++
+			for position < len(query) && query[position] != '"' && query[position] != '\n' {
+				if query[position] == '\\' {
+					position++
+				}
+				position++
+			}
+			if position >= len(query) || query[position] != '"' {
+				return nil, errors.New("GraphQL document has an unterminated string")
+			}
+			position++
+			tokens = append(tokens, graphQLToken{graphQLString, query[start:position]})
+		default:
+			return nil, fmt.Errorf("GraphQL document has unexpected character %q", character)
+		}
+	}
+
+	return append(tokens, graphQLToken{kind: graphQLEnd}), nil
+}
+
+func isASCIILetter(character byte) bool {
+	return character >= 'a' && character <= 'z' || character >= 'A' && character <= 'Z'
+}
+
+func isASCIIDigit(character byte) bool {
+	return character >= '0' && character <= '9'
+}
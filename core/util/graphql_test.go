@@ -0,0 +1,99 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ParseGraphQLOperation_NormalisesWhitespaceCommentsAndOrder(t *testing.T) {
+	RegisterTestingT(t)
+
+	first, err := ParseGraphQLOperation(`
+		# Fetch a user
+		query GetUser($id: ID!, $withPosts: Boolean = false) {
+			user(id: $id, active: true) {
+				name
+				id
+				posts @include(if: $withPosts) { title }
+			}
+		}`, "")
+	Expect(err).To(BeNil())
+
+	second, err := ParseGraphQLOperation(`query GetUser($withPosts:Boolean=false,$id:ID!){user(active:true,id:$id){id,posts@include(if:$withPosts){title},name}}`, "")
+	Expect(err).To(BeNil())
+
+	Expect(first.String()).To(Equal(second.String()))
+	Expect(first.Name).To(Equal("GetUser"))
+	Expect(first.Type).To(Equal("query"))
+}
+
+func Test_ParseGraphQLOperation_ExpandsFragments(t *testing.T) {
+	RegisterTestingT(t)
+
+	withFragment, err := ParseGraphQLOperation(`query { user { ...UserFields } } fragment UserFields on User { id name }`, "")
+	Expect(err).To(BeNil())
+
+	inline, err := ParseGraphQLOperation(`query { user { ... on User { name id } } }`, "")
+	Expect(err).To(BeNil())
+
+	Expect(withFragment.String()).To(Equal(inline.String()))
+}
+
+func Test_ParseGraphQLOperation_SelectsOperationByName(t *testing.T) {
+	RegisterTestingT(t)
+
+	document := `query A { a } mutation B { b }`
+
+	operation, err := ParseGraphQLOperation(document, "B")
+	Expect(err).To(BeNil())
+	Expect(operation.String()).To(Equal("mutation B{b}"))
+
+	_, err = ParseGraphQLOperation(document, "")
+	Expect(err).To(MatchError("GraphQL document has more than one operation but no operation name was given"))
+
+	_, err = ParseGraphQLOperation(document, "C")
+	Expect(err).To(MatchError("GraphQL document has no operation named C"))
+}
+
+func Test_ParseGraphQLOperation_ErrorsOnInvalidDocument(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := ParseGraphQLOperation(`query { user { id }`, "")
+	Expect(err).To(MatchError("GraphQL document ended unexpectedly"))
+
+	_, err = ParseGraphQLOperation(`hello`, "")
+	Expect(err).To(MatchError("GraphQL document has unexpected hello"))
+
+	_, err = ParseGraphQLOperation(`query { ...Missing }`, "")
+	Expect(err).To(MatchError("GraphQL fragment Missing is not defined"))
+
+	_, err = ParseGraphQLOperation(`query { user(name: "unterminated) { id } }`, "")
+	Expect(err).To(MatchError("GraphQL document has an unterminated string"))
+}
+
+func Test_ParseGraphQLRequest_ReadsJsonAndRawBodies(t *testing.T) {
+	RegisterTestingT(t)
+
+	request, ok := ParseGraphQLRequest(`{"query": "query GetUser { user { id } }", "operationName": "GetUser", "variables": {"id": "1"}}`)
+	Expect(ok).To(BeTrue())
+	Expect(request.Query).To(Equal("query GetUser { user { id } }"))
+	Expect(request.OperationName).To(Equal("GetUser"))
+	Expect(request.Variables).To(Equal(map[string]interface{}{"id": "1"}))
+
+	request, ok = ParseGraphQLRequest(`{ user { id } }`)
+	Expect(ok).To(BeTrue())
+	Expect(request.Query).To(Equal(`{ user { id } }`))
+
+	_, ok = ParseGraphQLRequest(`{"name": "not graphql"}`)
+	Expect(ok).To(BeFalse())
+}
+
+func Test_FetchFromRequestBody_GraphQL(t *testing.T) {
+	RegisterTestingT(t)
+
+	body := `{"query": "query GetUser($id: ID!) { user(id: $id) { id } }", "variables": {"id": "42"}}`
+
+	Expect(FetchFromRequestBody("graphql", "operationName", body)).To(Equal("GetUser"))
+	Expect(FetchFromRequestBody("graphql", "$.id", body)).To(Equal("42"))
+}
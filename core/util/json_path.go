@@ -0,0 +1,66 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SplitJsonPath splits a JSONPath such as $.items[*].id into its keys and indexes, with -1 standing for
+// every item of an array. The path can also be written as a template, such as {.items[*].id}, or without the $.
+// The root has no segments. Filters and recursive descent are not supported, as they do not name what they select.
+func SplitJsonPath(path string) ([]interface{}, error) {
+	rest := strings.TrimSpace(path)
+	if strings.HasPrefix(rest, "{") && strings.HasSuffix(rest, "}") {
+		rest = rest[1 : len(rest)-1]
+	}
+	rest = strings.TrimPrefix(rest, "$")
+	if rest != "" && !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[") {
+		rest = "." + rest
+	}
+
+	segments := []interface{}{}
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "."):
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("JSONPath %s has an empty key", path)
+			}
+			if rest[:end] == "*" {
+				return nil, fmt.Errorf("JSONPath %s is not supported", path)
+			}
+			segments = append(segments, rest[:end])
+			rest = rest[end:]
+		case strings.HasPrefix(rest, "["):
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("JSONPath %s has an unclosed [", path)
+			}
+			index := rest[1:end]
+			if quoted := strings.Trim(index, `'"`); quoted != index {
+				if quoted == "" {
+					return nil, fmt.Errorf("JSONPath %s has an empty key", path)
+				}
+				segments = append(segments, quoted)
+			} else if index == "*" {
+				segments = append(segments, -1)
+			} else if number, err := strconv.Atoi(index); err == nil && number >= 0 {
+				segments = append(segments, number)
+			} else if index == "" {
+				return nil, fmt.Errorf("JSONPath %s has an empty index", path)
+			} else {
+				return nil, fmt.Errorf("JSONPath %s is not supported", path)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("JSONPath %s is not supported", path)
+		}
+	}
+
+	return segments, nil
+}
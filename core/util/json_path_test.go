@@ -0,0 +1,33 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_SplitJsonPath_ReadsKeysAndIndexes(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(SplitJsonPath("$.users[0].address.city")).To(Equal([]interface{}{"users", 0, "address", "city"}))
+	Expect(SplitJsonPath("$.items[*]['first.name']")).To(Equal([]interface{}{"items", -1, "first.name"}))
+	Expect(SplitJsonPath("$")).To(Equal([]interface{}{}))
+}
+
+func Test_SplitJsonPath_ReadsTemplatesAndPathsWithoutRoot(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(SplitJsonPath("{.users[*].tags[1]}")).To(Equal([]interface{}{"users", -1, "tags", 1}))
+	Expect(SplitJsonPath("{$.users}")).To(Equal([]interface{}{"users"}))
+	Expect(SplitJsonPath("users[0].name")).To(Equal([]interface{}{"users", 0, "name"}))
+	Expect(SplitJsonPath("[0]")).To(Equal([]interface{}{0}))
+}
+
+func Test_SplitJsonPath_RejectsPathsWhichDoNotNameWhatTheySelect(t *testing.T) {
+	RegisterTestingT(t)
+
+	for _, path := range []string{"$..name", "$.users[?(@.id==1)]", "$.users.*", "$.users[-1]", "$.users[", "$.users[]"} {
+		_, err := SplitJsonPath(path)
+		Expect(err).NotTo(BeNil(), path)
+	}
+}
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"io"
+	"mime"
+	"mime/multipart"
+	"strings"
+)
+
+// MultipartPart is one part of a multipart/form-data body
+type MultipartPart struct {
+	Name        string
+	Filename    string
+	ContentType string
+	Headers     map[string][]string
+	Value       string
+}
+
+// ParseMultipartBody splits a multipart/form-data body into its parts. The boundary is read from the content type,
+// or from the first line of the body when the content type is not known.
+func ParseMultipartBody(contentType, body string) ([]MultipartPart, error) {
+	boundary := ""
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		boundary = params["boundary"]
+	}
+	if boundary == "" {
+		boundary = inferMultipartBoundary(body)
+	}
+
+	reader := multipart.NewReader(strings.NewReader(body), boundary)
+
+	var parts []MultipartPart
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			return parts, nil
+		}
+		if err != nil {
+			return parts, err
+		}
+
+		value, err := io.ReadAll(part)
+		if err != nil {
+			return parts, err
+		}
+
+		parts = append(parts, MultipartPart{
+			Name:        part.FormName(),
+			Filename:    part.FileName(),
+			ContentType: part.Header.Get("Content-Type"),
+			Headers:     part.Header,
+			Value:       string(value),
+		})
+		part.Close()
+	}
+}
+
+// FetchFromMultipartBody returns the value of the part with the name. A query of the name followed by ".filename"
+// or ".contentType" returns that detail of the part instead.
+func FetchFromMultipartBody(query, body string) string {
+	parts, _ := ParseMultipartBody("", body)
+
+	for _, part := range parts {
+		if part.Name == query {
+			return part.Value
+		}
+	}
+
+	separator := strings.LastIndex(query, ".")
+	if separator < 0 {
+		return ""
+	}
+	name, detail := query[:separator], query[separator+1:]
+	for _, part := range parts {
+		if part.Name != name {
+			continue
+		}
+		switch detail {
+		case "filename":
+			return part.Filename
+		case "contentType":
+			return part.ContentType
+		}
+	}
+	return ""
+}
+
+func inferMultipartBoundary(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.HasPrefix(line, "--") {
+			return strings.TrimPrefix(line, "--")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,59 @@
+package util
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+const testMultipartBody = "--xyz\r\n" +
+	"Content-Disposition: form-data; name=\"name\"\r\n\r\n" +
+	"Ben\r\n" +
+	"--xyz\r\n" +
+	"Content-Disposition: form-data; name=\"avatar\"; filename=\"ben.png\"\r\n" +
+	"Content-Type: image/png\r\n\r\n" +
+	"png\r\n" +
+	"--xyz--\r\n"
+
+func Test_ParseMultipartBody_ReadsBoundaryFromContentType(t *testing.T) {
+	RegisterTestingT(t)
+
+	parts, err := ParseMultipartBody("multipart/form-data; boundary=xyz", testMultipartBody)
+	Expect(err).To(BeNil())
+	Expect(parts).To(HaveLen(2))
+
+	Expect(parts[0].Name).To(Equal("name"))
+	Expect(parts[0].Value).To(Equal("Ben"))
+	Expect(parts[0].Filename).To(Equal(""))
+
+	Expect(parts[1].Name).To(Equal("avatar"))
+	Expect(parts[1].Value).To(Equal("png"))
+	Expect(parts[1].Filename).To(Equal("ben.png"))
+	Expect(parts[1].ContentType).To(Equal("image/png"))
+	Expect(parts[1].Headers).To(HaveKeyWithValue("Content-Type", []string{"image/png"}))
+}
+
+func Test_ParseMultipartBody_InfersBoundaryFromBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	parts, err := ParseMultipartBody("", testMultipartBody)
+	Expect(err).To(BeNil())
+	Expect(parts).To(HaveLen(2))
+}
+
+func Test_FetchFromMultipartBody(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(FetchFromMultipartBody("name", testMultipartBody)).To(Equal("Ben"))
+	Expect(FetchFromMultipartBody("avatar.filename", testMultipartBody)).To(Equal("ben.png"))
+	Expect(FetchFromMultipartBody("avatar.contentType", testMultipartBody)).To(Equal("image/png"))
+	Expect(FetchFromMultipartBody("missing", testMultipartBody)).To(Equal(""))
+}
+
+func Test_GetContentTypeFromHeaders_Multipart(t *testing.T) {
+	RegisterTestingT(t)
+
+	Expect(GetContentTypeFromHeaders(map[string][]string{
+		"Content-Type": {"multipart/form-data; boundary=xyz"},
+	})).To(Equal("multipart"))
+}
@@ -168,6 +168,12 @@ func GetContentTypeFromHeaders(headers map[string][]string) string {
 	}
 
 	for _, v := range headers["Content-Type"] {
+		if strings.HasPrefix(strings.ToLower(v), "multipart/form-data") {
+			return "multipart"
+		}
+		if strings.TrimSpace(strings.Split(strings.ToLower(v), ";")[0]) == "application/graphql" {
+			return "graphql"
+		}
 		if regexp.MustCompile("[/+]json$").MatchString(v) {
 			return "json"
 		}
@@ -381,6 +387,10 @@ func FetchFromRequestBody(queryType, query, toMatch string) interface{} {
 		return jsonPath(query, toMatch)
 	} else if queryType == "xpath" {
 		return xPath(query, toMatch)
+	} else if queryType == "multipart" {
+		return FetchFromMultipartBody(query, toMatch)
+	} else if queryType == "graphql" {
+		return fetchFromGraphQLBody(query, toMatch)
 	} else if queryType == "jsonpathfromxml" {
 		xmlReader := strings.NewReader(toMatch)
 		jsonBytes, err := xj.Convert(xmlReader)
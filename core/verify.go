@@ -0,0 +1,247 @@
+package hoverfly
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	log "github.com/sirupsen/logrus"
+)
+
+// VerifySimulation replays a request for each pair of the simulation against the real destination and
+// reports how the real responses differ from the simulated ones. Pairs with a request matcher which no
+// concrete request can be worked out from are skipped.
+func (hf *Hoverfly) VerifySimulation(diffFilterView v2.DiffFilterView) v2.VerificationView {
+	verification := v2.VerificationView{
+		Passed:  true,
+		Results: []v2.PairVerificationView{},
+	}
+
+	for _, pair := range hf.Simulation.GetMatchingPairs() {
+		result := hf.verifyPair(pair, diffFilterView)
+		if !result.Passed && result.Skipped == "" {
+			verification.Passed = false
+		}
+		verification.Results = append(verification.Results, result)
+	}
+
+	return verification
+}
+
+func (hf *Hoverfly) verifyPair(pair models.RequestMatcherResponsePair, diffFilterView v2.DiffFilterView) v2.PairVerificationView {
+	result := v2.PairVerificationView{Labels: pair.Labels}
+
+	if pair.Disabled {
+		result.Skipped = "pair is disabled"
+		return result
+	}
+
+	requestDetails, err := newRequestDetailsFromMatcher(pair.RequestMatcher)
+	if err != nil {
+		result.Skipped = err.Error()
+		return result
+	}
+
+	request, err := modes.ReconstructRequest(models.RequestResponsePair{Request: requestDetails})
+	if err != nil {
+		result.Skipped = err.Error()
+		return result
+	}
+
+	result.Request = v2.SimpleRequestDefinitionView{
+		Method: request.Method,
+		Host:   request.URL.Host,
+		Path:   request.URL.Path,
+		Query:  request.URL.RawQuery,
+	}
+
+	response, _, err := hf.DoRequest(request)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"url":   request.URL.String(),
+		}).Warn("Failed to verify simulation against the real destination")
+		result.Error = err.Error()
+		return result
+	}
+	defer response.Body.Close()
+
+	body, _ := util.GetResponseBody(response)
+	actual := &models.ResponseDetails{
+		Status:  response.StatusCode,
+		Body:    body,
+		Headers: util.GetResponseHeaders(response),
+	}
+
+	expected := pair.Response
+	if pair.HasResponses() {
+		expected = pair.Responses[0]
+	}
+
+	diffReport := modes.NewDiffReport(&expected, actual, diffFilterView.ExcludedHeaders)
+
+	var diffEntries []v2.DiffReportEntry
+	for _, diffEntry := range diffReport.DiffEntries {
+		// The body of a templated response is only known once rendered, so it cannot be compared
+		if expected.Templated && (diffEntry.Field == "body" || strings.HasPrefix(diffEntry.Field, "body/")) {
+			continue
+		}
+		if !needsToExcludeDiffEntry(&diffEntry, &diffFilterView) {
+			diffEntries = append(diffEntries, diffEntry)
+		}
+	}
+
+	result.Passed = len(diffEntries) == 0
+	if !result.Passed {
+		diffReport.DiffEntries = diffEntries
+		result.DiffReport = &diffReport
+	}
+
+	return result
+}
+
+// newRequestDetailsFromMatcher works out a request which the request matcher matches
+func newRequestDetailsFromMatcher(requestMatcher models.RequestMatcher) (models.RequestDetails, error) {
+	requestDetails := models.RequestDetails{
+		Method:  "GET",
+		Scheme:  "http",
+		Path:    "/",
+		Query:   map[string][]string{},
+		Headers: map[string][]string{},
+	}
+
+	if len(requestMatcher.Destination) == 0 {
+		return requestDetails, fmt.Errorf("request has no destination matcher")
+	}
+
+	fields := []struct {
+		name     string
+		matchers []models.RequestFieldMatchers
+		value    *string
+	}{
+		{"method", requestMatcher.Method, &requestDetails.Method},
+		{"scheme", requestMatcher.Scheme, &requestDetails.Scheme},
+		{"destination", requestMatcher.Destination, &requestDetails.Destination},
+		{"path", requestMatcher.Path, &requestDetails.Path},
+	}
+	for _, field := range fields {
+		if len(field.matchers) == 0 {
+			continue
+		}
+		value, ok := matchers.ExampleValue(field.matchers[0].Matcher, field.matchers[0].Value)
+		if !ok {
+			return requestDetails, fmt.Errorf("no %s can be worked out from the %s matcher", field.name, field.matchers[0].Matcher)
+		}
+		*field.value = value
+	}
+
+	if requestMatcher.Query != nil {
+		for key, fieldMatchers := range *requestMatcher.Query {
+			values, err := exampleValues("query "+key, fieldMatchers)
+			if err != nil {
+				return requestDetails, err
+			}
+			requestDetails.Query[key] = values
+		}
+	}
+
+	for key, fieldMatchers := range requestMatcher.Headers {
+		values, err := exampleValues("header "+key, fieldMatchers)
+		if err != nil {
+			return requestDetails, err
+		}
+		requestDetails.Headers[key] = values
+	}
+
+	if len(requestMatcher.Body) > 0 {
+		body := requestMatcher.Body[0]
+		if form, ok := body.Value.(map[string][]models.RequestFieldMatchers); ok && body.Matcher == "form" {
+			formData := url.Values{}
+			for key, fieldMatchers := range form {
+				values, err := exampleValues("form field "+key, fieldMatchers)
+				if err != nil {
+					return requestDetails, err
+				}
+				formData[key] = values
+			}
+			requestDetails.Body = formData.Encode()
+			if _, ok := requestDetails.Headers["Content-Type"]; !ok {
+				requestDetails.Headers["Content-Type"] = []string{"application/x-www-form-urlencoded"}
+			}
+		} else if parts, ok := body.Value.(map[string]models.MultipartPartMatchers); ok && body.Matcher == "multipart" {
+			multipartBody, contentType, err := exampleMultipartBody(parts)
+			if err != nil {
+				return requestDetails, err
+			}
+			requestDetails.Body = multipartBody
+			requestDetails.Headers["Content-Type"] = []string{contentType}
+		} else {
+			value, ok := matchers.ExampleValue(body.Matcher, body.Value)
+			if !ok {
+				return requestDetails, fmt.Errorf("no body can be worked out from the %s matcher", body.Matcher)
+			}
+			requestDetails.Body = value
+		}
+	}
+
+	return requestDetails, nil
+}
+
+func exampleValues(name string, fieldMatchers []models.RequestFieldMatchers) ([]string, error) {
+	if len(fieldMatchers) == 0 {
+		return []string{""}, nil
+	}
+	values, ok := matchers.ExampleValues(fieldMatchers[0].Matcher, fieldMatchers[0].Value)
+	if !ok {
+		return nil, fmt.Errorf("no %s can be worked out from the %s matcher", name, fieldMatchers[0].Matcher)
+	}
+	return values, nil
+}
+
+// exampleMultipartBody writes a multipart body with a part for each part matcher, returning it with the content
+// type holding its boundary
+func exampleMultipartBody(parts map[string]models.MultipartPartMatchers) (string, string, error) {
+	var buffer bytes.Buffer
+	writer := multipart.NewWriter(&buffer)
+
+	for name, partMatchers := range parts {
+		values, err := exampleValues("multipart part "+name, partMatchers.Value)
+		if err != nil {
+			return "", "", err
+		}
+		header := textproto.MIMEHeader{}
+		disposition := fmt.Sprintf(`form-data; name="%s"`, name)
+		if len(partMatchers.Filename) > 0 {
+			filenames, err := exampleValues("filename of multipart part "+name, partMatchers.Filename)
+			if err != nil {
+				return "", "", err
+			}
+			disposition += fmt.Sprintf(`; filename="%s"`, filenames[0])
+		}
+		header.Set("Content-Disposition", disposition)
+		if len(partMatchers.ContentType) > 0 {
+			contentTypes, err := exampleValues("content type of multipart part "+name, partMatchers.ContentType)
+			if err != nil {
+				return "", "", err
+			}
+			header.Set("Content-Type", contentTypes[0])
+		}
+
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return "", "", err
+		}
+		part.Write([]byte(values[0]))
+	}
+	writer.Close()
+
+	return buffer.String(), writer.FormDataContentType(), nil
+}
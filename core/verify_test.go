@@ -0,0 +1,134 @@
+package hoverfly
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func newVerifiedServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/users/1":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"id": 1, "name": "real", "team": "%s"}`, r.URL.Query().Get("team"))
+		case "/health":
+			fmt.Fprint(w, "ok")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newVerifiedPair(host string, path v2.MatcherViewV5, body string) v2.RequestMatcherResponsePairViewV5 {
+	return v2.RequestMatcherResponsePairViewV5{
+		RequestMatcher: v2.RequestMatcherViewV5{
+			Destination: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, host)},
+			Path:        []v2.MatcherViewV5{path},
+			Query: &v2.QueryMatcherViewV5{
+				"team": []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "qa*")},
+			},
+		},
+		Response: v2.ResponseDetailsViewV5{
+			Status: 200,
+			Body:   body,
+		},
+	}
+}
+
+func Test_Hoverfly_VerifySimulation_ReportsDifferencesFromRealService(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newVerifiedServer()
+	defer server.Close()
+	host, _ := url.Parse(server.URL)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				newVerifiedPair(host.Host, v2.NewMatcherView(matchers.Exact, "/users/1"), `{"id": 1, "name": "stub", "team": "qax"}`),
+				newVerifiedPair(host.Host, v2.NewMatcherView(matchers.Regex, "^/health(/.*)?$"), "ok"),
+				newVerifiedPair(host.Host, v2.NewMatcherView(matchers.Negation, "/users"), ""),
+			},
+		},
+		MetaView: v2.MetaView{SchemaVersion: "v5"},
+	})
+
+	verification := unit.VerifySimulation(v2.DiffFilterView{})
+
+	Expect(verification.Passed).To(BeFalse())
+	Expect(verification.Results).To(HaveLen(3))
+
+	Expect(verification.Results[0].Passed).To(BeFalse())
+	Expect(verification.Results[0].Request).To(Equal(v2.SimpleRequestDefinitionView{
+		Method: "GET",
+		Host:   host.Host,
+		Path:   "/users/1",
+		Query:  "team=qax",
+	}))
+	Expect(verification.Results[0].DiffReport.DiffEntries).To(ConsistOf(v2.DiffReportEntry{
+		Field:    "body/name",
+		Expected: "stub",
+		Actual:   "real",
+	}))
+
+	Expect(verification.Results[1].Passed).To(BeTrue())
+	Expect(verification.Results[1].Request.Path).To(Equal("/health"))
+	Expect(verification.Results[1].DiffReport).To(BeNil())
+
+	Expect(verification.Results[2].Passed).To(BeFalse())
+	Expect(verification.Results[2].Skipped).To(Equal("no path can be worked out from the negate matcher"))
+}
+
+func Test_Hoverfly_VerifySimulation_ExcludesFilteredFields(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newVerifiedServer()
+	defer server.Close()
+	host, _ := url.Parse(server.URL)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				newVerifiedPair(host.Host, v2.NewMatcherView(matchers.Exact, "/users/1"), `{"id": 1, "name": "stub", "team": "qax"}`),
+			},
+		},
+		MetaView: v2.MetaView{SchemaVersion: "v5"},
+	})
+
+	verification := unit.VerifySimulation(v2.DiffFilterView{ExcludedResponseFields: []string{"$.name"}})
+
+	Expect(verification.Passed).To(BeTrue())
+	Expect(verification.Results[0].Passed).To(BeTrue())
+}
+
+func Test_Hoverfly_VerifySimulation_FailsWhenRealServiceIsUnavailable(t *testing.T) {
+	RegisterTestingT(t)
+
+	server := newVerifiedServer()
+	host, _ := url.Parse(server.URL)
+	server.Close()
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+	unit.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				newVerifiedPair(host.Host, v2.NewMatcherView(matchers.Exact, "/health"), "ok"),
+			},
+		},
+		MetaView: v2.MetaView{SchemaVersion: "v5"},
+	})
+
+	verification := unit.VerifySimulation(v2.DiffFilterView{})
+
+	Expect(verification.Passed).To(BeFalse())
+	Expect(verification.Results[0].Error).To(ContainSubstring("connection refused"))
+}
@@ -0,0 +1,229 @@
+package hoverfly
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/openapi"
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+)
+
+// watchDebounce is how long the watcher waits after a change before reloading, as editors often write a file in
+// several steps
+const watchDebounce = 200 * time.Millisecond
+
+// simulationWatch holds the paths watched for simulation changes and the outcome of the last reload
+type simulationWatch struct {
+	paths      []string
+	files      []string
+	lastLoaded time.Time
+	err        error
+	mu         sync.Mutex
+}
+
+// WatchSimulations loads the simulation from the given files and directories, then reloads it whenever one of
+// them changes. A directory provides every simulation file directly inside it. The simulations of all the files are
+// combined and replace the whole simulation. A change which leaves any file invalid is reported and the last good
+// simulation is kept.
+func (hf *Hoverfly) WatchSimulations(paths []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("Failed to watch simulations: %s", err.Error())
+	}
+
+	// Editors often save a file by replacing it, which ends a watch on the file itself, so the directories
+	// holding the files are watched instead
+	watchedDirs := map[string]bool{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			watcher.Close()
+			return fmt.Errorf("Failed to watch simulations: %s", err.Error())
+		}
+
+		dir := path
+		if !info.IsDir() {
+			dir = filepath.Dir(path)
+		}
+		if watchedDirs[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("Failed to watch simulations in %s: %s", dir, err.Error())
+		}
+		watchedDirs[dir] = true
+	}
+
+	hf.watch = &simulationWatch{paths: paths}
+	hf.reloadWatchedSimulations()
+
+	go hf.watchSimulations(watcher)
+
+	return nil
+}
+
+func (hf *Hoverfly) watchSimulations(watcher *fsnotify.Watcher) {
+	defer watcher.Close()
+
+	var reload <-chan time.Time
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) || !hf.isWatchedSimulationFile(event.Name) {
+				continue
+			}
+			reload = time.After(watchDebounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Error("Error watching simulations")
+		case <-reload:
+			reload = nil
+			hf.reloadWatchedSimulations()
+		}
+	}
+}
+
+// isWatchedSimulationFile tells whether a file is one of the watched files, or a simulation file in a watched
+// directory
+func (hf *Hoverfly) isWatchedSimulationFile(name string) bool {
+	for _, path := range hf.watch.paths {
+		if filepath.Clean(name) == filepath.Clean(path) {
+			return true
+		}
+		if filepath.Dir(name) == filepath.Clean(path) && isSimulationFileName(name) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSimulationFileName(name string) bool {
+	ext := filepath.Ext(name)
+	return !strings.HasPrefix(filepath.Base(name), ".") && (ext == ".json" || ext == ".yaml" || ext == ".yml")
+}
+
+// reloadWatchedSimulations reads every watched file, and replaces the simulation if they are all valid. The
+// replacement is imported off to the side, so an invalid file leaves the simulation as it was.
+func (hf *Hoverfly) reloadWatchedSimulations() {
+	files, simulation, err := hf.readWatchedSimulations()
+	if err == nil {
+		result := hf.ReplaceSimulation(simulation)
+		err = result.GetError()
+		for _, warning := range result.WarningMessages {
+			log.Warn(warning.Message)
+		}
+	}
+
+	hf.watch.mu.Lock()
+	hf.watch.files = files
+	hf.watch.err = err
+	if err == nil {
+		hf.watch.lastLoaded = time.Now()
+	}
+	hf.watch.mu.Unlock()
+
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"files": files,
+		}).Error("Failed to reload watched simulations, keeping the last good simulation")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"files": files,
+	}).Info("Reloaded watched simulations")
+}
+
+// readWatchedSimulations combines the simulations of the watched files into one
+func (hf *Hoverfly) readWatchedSimulations() ([]string, v2.SimulationViewV5, error) {
+	var files []string
+	for _, path := range hf.watch.paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return files, v2.SimulationViewV5{}, err
+		}
+		if !info.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return files, v2.SimulationViewV5{}, err
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isSimulationFileName(entry.Name()) {
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	sort.Strings(files)
+
+	combined := v2.SimulationViewV5{}
+	for _, file := range files {
+		body, err := os.ReadFile(file)
+		if err != nil {
+			return files, v2.SimulationViewV5{}, err
+		}
+
+		var simulation v2.SimulationViewV5
+		if openapi.IsDocument(body) {
+			simulation, err = openapi.NewSimulation(body)
+		} else {
+			simulation, err = v2.NewSimulationViewFromRequestBody(body)
+		}
+		if err != nil {
+			return files, v2.SimulationViewV5{}, fmt.Errorf("%s: %s", file, err.Error())
+		}
+
+		combined.RequestResponsePairs = append(combined.RequestResponsePairs, simulation.RequestResponsePairs...)
+		combined.GlobalActions.Delays = append(combined.GlobalActions.Delays, simulation.GlobalActions.Delays...)
+		combined.GlobalActions.DelaysLogNormal = append(combined.GlobalActions.DelaysLogNormal, simulation.GlobalActions.DelaysLogNormal...)
+		combined.GlobalLiterals = append(combined.GlobalLiterals, simulation.GlobalLiterals...)
+		combined.GlobalVariables = append(combined.GlobalVariables, simulation.GlobalVariables...)
+		combined.WebSocketPairs = append(combined.WebSocketPairs, simulation.WebSocketPairs...)
+		combined.CustomMatchers = append(combined.CustomMatchers, simulation.CustomMatchers...)
+		combined.Resources = append(combined.Resources, simulation.Resources...)
+	}
+
+	return files, combined, nil
+}
+
+// GetWatch returns the paths watched for simulation changes and the outcome of the last reload, or nil when no
+// paths are watched
+func (hf *Hoverfly) GetWatch() *v2.WatchView {
+	if hf.watch == nil {
+		return nil
+	}
+
+	hf.watch.mu.Lock()
+	defer hf.watch.mu.Unlock()
+
+	view := &v2.WatchView{
+		Paths: hf.watch.paths,
+		Files: hf.watch.files,
+	}
+	if !hf.watch.lastLoaded.IsZero() {
+		view.LastLoaded = hf.watch.lastLoaded.Format(time.RFC3339)
+	}
+	if hf.watch.err != nil {
+		view.Error = hf.watch.err.Error()
+	}
+	return view
+}
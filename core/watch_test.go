@@ -0,0 +1,120 @@
+package hoverfly
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func watchedSimulation(path, body string) string {
+	return fmt.Sprintf(`{
+	"data": {
+		"pairs": [{
+			"request": {"path": [{"matcher": "exact", "value": "%s"}]},
+			"response": {"status": 200, "body": "%s"}
+		}]
+	},
+	"meta": {"schemaVersion": "v5.2"}
+}`, path, body)
+}
+
+func watchedResponseBodies(unit *Hoverfly) func() []string {
+	return func() []string {
+		simulation, err := unit.GetSimulation()
+		Expect(err).To(BeNil())
+
+		var bodies []string
+		for _, pair := range simulation.RequestResponsePairs {
+			bodies = append(bodies, pair.Response.Body)
+		}
+		return bodies
+	}
+}
+
+func Test_Hoverfly_WatchSimulations_LoadsEverySimulationFileInADirectory(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir := t.TempDir()
+	Expect(os.WriteFile(filepath.Join(dir, "b.json"), []byte(watchedSimulation("/b", "b")), 0644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "a.json"), []byte(watchedSimulation("/a", "a")), 0644)).To(Succeed())
+	Expect(os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a simulation"), 0644)).To(Succeed())
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.WatchSimulations([]string{dir})).To(Succeed())
+
+	Expect(watchedResponseBodies(unit)()).To(Equal([]string{"a", "b"}))
+
+	watch := unit.GetWatch()
+	Expect(watch.Paths).To(Equal([]string{dir}))
+	Expect(watch.Files).To(Equal([]string{filepath.Join(dir, "a.json"), filepath.Join(dir, "b.json")}))
+	Expect(watch.LastLoaded).ToNot(BeEmpty())
+	Expect(watch.Error).To(BeEmpty())
+}
+
+func Test_Hoverfly_WatchSimulations_ReloadsChangedFiles(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "simulation.json")
+	Expect(os.WriteFile(file, []byte(watchedSimulation("/a", "before")), 0644)).To(Succeed())
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.WatchSimulations([]string{file})).To(Succeed())
+	Expect(watchedResponseBodies(unit)()).To(Equal([]string{"before"}))
+
+	Expect(os.WriteFile(file, []byte(watchedSimulation("/a", "after")), 0644)).To(Succeed())
+
+	Eventually(watchedResponseBodies(unit), 5*time.Second).Should(Equal([]string{"after"}))
+}
+
+func Test_Hoverfly_WatchSimulations_KeepsLastGoodSimulationWhenAFileIsInvalid(t *testing.T) {
+	RegisterTestingT(t)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "simulation.json")
+	Expect(os.WriteFile(file, []byte(watchedSimulation("/a", "good")), 0644)).To(Succeed())
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.WatchSimulations([]string{dir})).To(Succeed())
+
+	Expect(os.WriteFile(file, []byte(`{"data": {`), 0644)).To(Succeed())
+
+	Eventually(func() string {
+		return unit.GetWatch().Error
+	}, 5*time.Second).Should(ContainSubstring("simulation.json: Invalid JSON"))
+	Expect(watchedResponseBodies(unit)()).To(Equal([]string{"good"}))
+
+	Expect(os.WriteFile(file, []byte(watchedSimulation("/a", "fixed")), 0644)).To(Succeed())
+
+	Eventually(func() string {
+		return unit.GetWatch().Error
+	}, 5*time.Second).Should(BeEmpty())
+	Expect(watchedResponseBodies(unit)()).To(Equal([]string{"fixed"}))
+}
+
+func Test_Hoverfly_WatchSimulations_ErrorsWhenAPathDoesNotExist(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	err := unit.WatchSimulations([]string{filepath.Join(t.TempDir(), "missing.json")})
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(ContainSubstring("Failed to watch simulations"))
+	Expect(unit.GetWatch()).To(BeNil())
+}
+
+func Test_Hoverfly_GetWatch_ReturnsNilWhenNotWatching(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverflyWithConfiguration(&Configuration{})
+
+	Expect(unit.GetWatch()).To(BeNil())
+}
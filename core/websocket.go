@@ -0,0 +1,360 @@
+package hoverfly
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/SpectoLabs/hoverfly/core/matching"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/SpectoLabs/hoverfly/core/modes"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+// Headers which belong to the WebSocket handshake or to the hop between client and proxy,
+// and so must not be forwarded when dialling the destination
+var webSocketHandshakeHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Sec-Websocket-Extensions",
+	"Sec-Websocket-Key",
+	"Sec-Websocket-Protocol",
+	"Sec-Websocket-Version",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+func isWebSocketRequest(r *http.Request) bool {
+	return websocket.IsWebSocketUpgrade(r)
+}
+
+// serveWebSocket handles a WebSocket upgrade request. In simulate and spy mode the conversation is
+// served from a matching WebSocket pair, otherwise it is forwarded to the destination and, when
+// capturing, stored as a WebSocket pair once the connection is closed.
+func (hf *Hoverfly) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	startTime := time.Now()
+	mode := hf.Cfg.GetMode()
+
+	requestDetails, err := models.NewRequestDetailsFromHttpRequest(r)
+	if err != nil {
+		hf.writeWebSocketError(w, r, modes.ErrorResponse(r, err, "Could not interpret HTTP request").Response, mode, startTime)
+		return
+	}
+
+	var captureArguments *modes.ModeArguments
+	switch mode {
+	case modes.Simulate, modes.Spy:
+		pair, matchErr := matching.WebSocketMatch(requestDetails, hf.Cfg.Webserver, hf.Simulation, hf.state)
+		if matchErr == nil {
+			hf.simulateWebSocket(w, r, pair, mode, startTime)
+			return
+		}
+
+		if mode == modes.Simulate {
+			log.WithFields(log.Fields{
+				"destination": requestDetails.Destination,
+				"path":        requestDetails.Path,
+			}).Warn("Failed to find matching WebSocket pair")
+			hf.writeWebSocketError(w, r, modes.ErrorResponse(r, matchErr, "There was an error when matching").Response, mode, startTime)
+			return
+		}
+
+		if spyMode, ok := hf.modeMap[modes.Spy].(*modes.SpyMode); ok && spyMode.Arguments.CaptureOnMiss {
+			captureArguments = &spyMode.Arguments
+		}
+	case modes.Capture:
+		if captureMode, ok := hf.modeMap[modes.Capture].(*modes.CaptureMode); ok {
+			captureArguments = &captureMode.Arguments
+		}
+	}
+
+	hf.forwardWebSocket(w, r, requestDetails, captureArguments, mode, startTime)
+}
+
+func (hf *Hoverfly) simulateWebSocket(w http.ResponseWriter, r *http.Request, pair *models.WebSocketPair, mode string, startTime time.Time) {
+	upgrader := newWebSocketUpgrader(websocket.Subprotocols(r))
+	clientConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Error("Failed to upgrade WebSocket connection")
+		return
+	}
+
+	recorder := &webSocketRecorder{}
+	sender := newWebSocketSender(clientConn, recorder)
+	sender.send(pair.Frames)
+
+	for {
+		messageType, data, err := clientConn.ReadMessage()
+		if err != nil {
+			break
+		}
+		message := recorder.record(models.WebSocketFromClient, messageType, data)
+		if reply := matching.WebSocketReplyMatch(pair.Replies, message, hf.Simulation.CustomMatchers); reply != nil {
+			sender.send(reply.Frames)
+		}
+	}
+
+	sender.stop()
+	clientConn.Close()
+
+	hf.Journal.NewWebSocketEntry(r, newWebSocketUpgradeResponse(r, upgrader.Subprotocols), recorder.messages(), mode, startTime)
+	hf.countRequest(mode, r, http.StatusSwitchingProtocols)
+}
+
+func (hf *Hoverfly) forwardWebSocket(w http.ResponseWriter, r *http.Request, requestDetails models.RequestDetails, captureArguments *modes.ModeArguments, mode string, startTime time.Time) {
+	dialer := &websocket.Dialer{
+		HandshakeTimeout: 45 * time.Second,
+		Subprotocols:     websocket.Subprotocols(r),
+	}
+
+	client, err := GetHttpClient(hf, r.Host)
+	if err != nil {
+		hf.writeWebSocketError(w, r, modes.ErrorResponse(r, err, "There was an error when forwarding the request to the intended destination").Response, mode, startTime)
+		return
+	}
+	if transport, ok := client.Transport.(*http.Transport); ok {
+		dialer.Proxy = transport.Proxy
+		dialer.TLSClientConfig = transport.TLSClientConfig
+	}
+
+	target := *r.URL
+	if target.Host == "" {
+		target.Host = r.Host
+	}
+	if target.Scheme == "https" {
+		target.Scheme = "wss"
+	} else {
+		target.Scheme = "ws"
+	}
+
+	header := r.Header.Clone()
+	for _, name := range webSocketHandshakeHeaders {
+		header.Del(name)
+	}
+	if ProxyAuthorizationHeader != "" {
+		header.Del(ProxyAuthorizationHeader)
+	}
+
+	serverConn, serverResponse, err := dialer.Dial(target.String(), header)
+	if err != nil {
+		if serverResponse != nil {
+			hf.writeWebSocketError(w, r, serverResponse, mode, startTime)
+		} else {
+			hf.writeWebSocketError(w, r, modes.ErrorResponse(r, err, "There was an error when forwarding the request to the intended destination").Response, mode, startTime)
+		}
+		return
+	}
+	defer serverConn.Close()
+
+	var subprotocols []string
+	if serverConn.Subprotocol() != "" {
+		subprotocols = []string{serverConn.Subprotocol()}
+	}
+	responseHeader := http.Header{}
+	for _, cookie := range serverResponse.Header.Values("Set-Cookie") {
+		responseHeader.Add("Set-Cookie", cookie)
+	}
+
+	upgrader := newWebSocketUpgrader(subprotocols)
+	clientConn, err := upgrader.Upgrade(w, r, responseHeader)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Error("Failed to upgrade WebSocket connection")
+		return
+	}
+	defer clientConn.Close()
+
+	opened := time.Now()
+	recorder := &webSocketRecorder{}
+	errc := make(chan error, 2)
+	go relayWebSocket(clientConn, serverConn, models.WebSocketFromClient, recorder, errc)
+	go relayWebSocket(serverConn, clientConn, models.WebSocketFromServer, recorder, errc)
+
+	<-errc
+	clientConn.Close()
+	serverConn.Close()
+	<-errc
+
+	messages := recorder.messages()
+	if captureArguments != nil {
+		hf.SaveWebSocket(&requestDetails, opened, messages, captureArguments)
+	}
+
+	hf.Journal.NewWebSocketEntry(r, newWebSocketUpgradeResponse(r, subprotocols), messages, mode, startTime)
+	hf.countRequest(mode, r, http.StatusSwitchingProtocols)
+}
+
+// relayWebSocket copies messages from one connection to the other until either side
+// fails, passing the close code on to the other side when the source closes
+func relayWebSocket(src, dst *websocket.Conn, direction string, recorder *webSocketRecorder, errc chan error) {
+	for {
+		messageType, data, err := src.ReadMessage()
+		if err != nil {
+			closeMessage := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+			if closeErr, ok := err.(*websocket.CloseError); ok && closeErr.Code != websocket.CloseNoStatusReceived {
+				closeMessage = websocket.FormatCloseMessage(closeErr.Code, closeErr.Text)
+			}
+			dst.WriteControl(websocket.CloseMessage, closeMessage, time.Now().Add(time.Second))
+			errc <- err
+			return
+		}
+
+		recorder.record(direction, messageType, data)
+		if err := dst.WriteMessage(messageType, data); err != nil {
+			errc <- err
+			return
+		}
+	}
+}
+
+func (hf *Hoverfly) writeWebSocketError(w http.ResponseWriter, r *http.Request, resp *http.Response, mode string, startTime time.Time) {
+	body, _ := util.GetResponseBody(resp)
+	resp.Body = io.NopCloser(bytes.NewBufferString(body))
+
+	for name, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+	w.Write([]byte(body))
+
+	hf.Journal.NewEntry(r, resp, mode, startTime)
+	hf.countRequest(mode, r, resp.StatusCode)
+}
+
+func newWebSocketUpgrader(subprotocols []string) *websocket.Upgrader {
+	if len(subprotocols) > 1 {
+		subprotocols = subprotocols[:1]
+	}
+	return &websocket.Upgrader{
+		Subprotocols: subprotocols,
+		CheckOrigin: func(r *http.Request) bool {
+			return true
+		},
+	}
+}
+
+// newWebSocketUpgradeResponse describes the handshake response for the journal
+func newWebSocketUpgradeResponse(r *http.Request, subprotocols []string) *http.Response {
+	header := http.Header{}
+	header.Set("Upgrade", "websocket")
+	header.Set("Connection", "Upgrade")
+	if len(subprotocols) > 0 {
+		header.Set("Sec-WebSocket-Protocol", subprotocols[0])
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusSwitchingProtocols,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewBufferString("")),
+		Request:    r,
+	}
+}
+
+type webSocketRecorder struct {
+	mutex    sync.Mutex
+	recorded []models.WebSocketMessage
+}
+
+func (this *webSocketRecorder) record(direction string, messageType int, data []byte) models.WebSocketMessage {
+	message := models.WebSocketMessage{
+		Direction: direction,
+		Type:      models.WebSocketText,
+		Data:      string(data),
+		Time:      time.Now(),
+	}
+	if messageType == websocket.BinaryMessage {
+		message.Type = models.WebSocketBinary
+	}
+
+	this.mutex.Lock()
+	this.recorded = append(this.recorded, message)
+	this.mutex.Unlock()
+
+	return message
+}
+
+func (this *webSocketRecorder) messages() []models.WebSocketMessage {
+	this.mutex.Lock()
+	defer this.mutex.Unlock()
+	return this.recorded
+}
+
+// webSocketSender writes simulated frames to the client one at a time, so that
+// the delays of queued frames add up and frames are never written concurrently
+type webSocketSender struct {
+	conn     *websocket.Conn
+	recorder *webSocketRecorder
+	queue    chan []models.WebSocketFrame
+	closed   chan struct{}
+	done     chan struct{}
+}
+
+func newWebSocketSender(conn *websocket.Conn, recorder *webSocketRecorder) *webSocketSender {
+	sender := &webSocketSender{
+		conn:     conn,
+		recorder: recorder,
+		queue:    make(chan []models.WebSocketFrame, 64),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go sender.run()
+	return sender
+}
+
+func (this *webSocketSender) send(frames []models.WebSocketFrame) {
+	if len(frames) == 0 {
+		return
+	}
+	select {
+	case this.queue <- frames:
+	case <-this.closed:
+	}
+}
+
+func (this *webSocketSender) stop() {
+	close(this.closed)
+	<-this.done
+}
+
+func (this *webSocketSender) run() {
+	defer close(this.done)
+	for {
+		select {
+		case frames := <-this.queue:
+			for _, frame := range frames {
+				if frame.Delay > 0 {
+					select {
+					case <-time.After(time.Duration(frame.Delay) * time.Millisecond):
+					case <-this.closed:
+						return
+					}
+				}
+
+				messageType := websocket.TextMessage
+				if frame.Type == models.WebSocketBinary {
+					messageType = websocket.BinaryMessage
+				}
+				if err := this.conn.WriteMessage(messageType, []byte(frame.Data)); err != nil {
+					this.conn.Close()
+					return
+				}
+				this.recorder.record(models.WebSocketFromServer, messageType, []byte(frame.Data))
+			}
+		case <-this.closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package hoverfly
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/models"
+	"github.com/gorilla/websocket"
+	. "github.com/onsi/gomega"
+)
+
+var chatWebSocketSimulation = v2.SimulationViewV5{
+	DataViewV5: v2.DataViewV5{
+		RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{},
+		WebSocketPairs: []v2.WebSocketPairViewV5{
+			{
+				RequestMatcher: v2.RequestMatcherViewV5{
+					Path: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "/chat")},
+				},
+				Frames: []v2.WebSocketFrameViewV5{
+					{Data: "welcome"},
+				},
+				Replies: []v2.WebSocketReplyViewV5{
+					{
+						Matcher: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, "ping")},
+						Frames: []v2.WebSocketFrameViewV5{
+							{Data: "pong", Delay: 10},
+						},
+					},
+					{
+						Matcher: []v2.MatcherViewV5{v2.NewMatcherView(matchers.Glob, "*")},
+						Frames: []v2.WebSocketFrameViewV5{
+							{Type: "binary", Data: "AQID", EncodedData: true},
+						},
+					},
+				},
+			},
+		},
+	},
+	MetaView: *v2.NewMetaView("test"),
+}
+
+func newWebSocketProxyDialer(proxyServer *httptest.Server) *websocket.Dialer {
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	return &websocket.Dialer{
+		Proxy:           http.ProxyURL(proxyURL),
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}
+}
+
+func Test_Hoverfly_SimulatesWebSocketThroughProxy(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(chatWebSocketSimulation).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	conn, _, err := newWebSocketProxyDialer(proxyServer).Dial("ws://example.com/chat", nil)
+	Expect(err).To(BeNil())
+
+	_, message, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(message)).To(Equal("welcome"))
+
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte("ping"))).To(Succeed())
+	messageType, message, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(messageType).To(Equal(websocket.TextMessage))
+	Expect(string(message)).To(Equal("pong"))
+
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte("anything"))).To(Succeed())
+	messageType, message, err = conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(messageType).To(Equal(websocket.BinaryMessage))
+	Expect(message).To(Equal([]byte{1, 2, 3}))
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	conn.Close()
+
+	Eventually(func() int {
+		journal, _ := unit.Journal.GetEntries(0, 25, nil, nil, "")
+		return len(journal.Journal)
+	}).Should(Equal(1))
+
+	journal, _ := unit.Journal.GetEntries(0, 25, nil, nil, "")
+	Expect(journal.Journal[0].Response.Status).To(Equal(http.StatusSwitchingProtocols))
+	Expect(journal.Journal[0].WebSocketMessages).To(HaveLen(5))
+	Expect(journal.Journal[0].WebSocketMessages[0].Direction).To(Equal("server"))
+	Expect(journal.Journal[0].WebSocketMessages[0].Data).To(Equal("welcome"))
+	Expect(journal.Journal[0].WebSocketMessages[1].Direction).To(Equal("client"))
+	Expect(journal.Journal[0].WebSocketMessages[1].Data).To(Equal("ping"))
+	Expect(journal.Journal[0].WebSocketMessages[4].Data).To(Equal("AQID"))
+	Expect(journal.Journal[0].WebSocketMessages[4].EncodedData).To(BeTrue())
+}
+
+func Test_Hoverfly_SimulatesWebSocketThroughProxyOverTLS(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(chatWebSocketSimulation).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	conn, _, err := newWebSocketProxyDialer(proxyServer).Dial("wss://example.com/chat", nil)
+	Expect(err).To(BeNil())
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(message)).To(Equal("welcome"))
+}
+
+func Test_Hoverfly_ReturnsBadGatewayWhenNoWebSocketPairMatches(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "simulate"})).To(Succeed())
+	Expect(unit.PutSimulation(chatWebSocketSimulation).GetError()).To(BeNil())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	_, response, err := newWebSocketProxyDialer(proxyServer).Dial("ws://example.com/other", nil)
+	Expect(err).To(Equal(websocket.ErrBadHandshake))
+	Expect(response.StatusCode).To(Equal(http.StatusBadGateway))
+}
+
+func Test_Hoverfly_CapturesWebSocketConversation(t *testing.T) {
+	RegisterTestingT(t)
+
+	upgrader := websocket.Upgrader{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+		for {
+			messageType, message, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			conn.WriteMessage(messageType, message)
+		}
+	}))
+	defer upstream.Close()
+
+	unit := NewHoverfly()
+	Expect(unit.SetModeWithArguments(v2.ModeView{Mode: "capture"})).To(Succeed())
+
+	proxyServer := httptest.NewServer(proxyHandler(unit, NewProxy(unit)))
+	defer proxyServer.Close()
+
+	upstreamURL, _ := url.Parse(upstream.URL)
+	conn, _, err := newWebSocketProxyDialer(proxyServer).Dial("ws://"+upstreamURL.Host+"/echo", nil)
+	Expect(err).To(BeNil())
+
+	_, message, err := conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(message)).To(Equal("hello"))
+
+	Expect(conn.WriteMessage(websocket.TextMessage, []byte("echo me"))).To(Succeed())
+	_, message, err = conn.ReadMessage()
+	Expect(err).To(BeNil())
+	Expect(string(message)).To(Equal("echo me"))
+
+	conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+	conn.Close()
+
+	Eventually(func() []models.WebSocketPair {
+		return unit.Simulation.GetWebSocketPairs()
+	}, time.Second).Should(HaveLen(1))
+
+	pair := unit.Simulation.GetWebSocketPairs()[0]
+	Expect(pair.RequestMatcher.Path[0].Value).To(Equal("/echo"))
+	Expect(pair.RequestMatcher.Destination[0].Value).To(Equal(upstreamURL.Host))
+	Expect(pair.Frames).To(HaveLen(1))
+	Expect(pair.Frames[0].Data).To(Equal("hello"))
+	Expect(pair.Replies).To(HaveLen(1))
+	Expect(pair.Replies[0].Matcher[0].Value).To(Equal("echo me"))
+	Expect(pair.Replies[0].Frames[0].Data).To(Equal("echo me"))
+
+	simulation, err := unit.GetSimulation()
+	Expect(err).To(BeNil())
+	Expect(simulation.WebSocketPairs).To(HaveLen(1))
+}
@@ -0,0 +1,135 @@
+package api_test
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers"
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/api/v2/journal/verify", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	Context("With journal enabled", func() {
+
+		BeforeEach(func() {
+			hoverfly = functional_tests.NewHoverfly()
+			hoverfly.Start()
+
+			hoverfly.Proxy(sling.New().Get("http://hoverfly.io/orders"))
+			hoverfly.Proxy(sling.New().Get("http://hoverfly.io/orders"))
+			hoverfly.Proxy(sling.New().Post("http://hoverfly.io/basket"))
+		})
+
+		AfterEach(func() {
+			hoverfly.Stop()
+		})
+
+		It("should pass when the expected number of requests were received", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal/verify")
+			req.Body(bytes.NewBufferString(`{
+				"request": {
+					"method": [{"matcher": "exact", "value": "GET"}],
+					"path": [{"matcher": "exact", "value": "/orders"}]
+				},
+				"exactly": 2
+			}`))
+			res := functional_tests.DoRequest(req)
+
+			Expect(res.StatusCode).To(Equal(200))
+
+			var resultView v2.RequestVerificationResultView
+			functional_tests.UnmarshalFromResponse(res, &resultView)
+
+			Expect(resultView.Passed).To(BeTrue())
+			Expect(resultView.Count).To(Equal(2))
+		})
+
+		It("should fail with the closest misses when too few requests were received", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal/verify")
+			req.Body(bytes.NewBufferString(`{
+				"request": {
+					"method": [{"matcher": "exact", "value": "POST"}],
+					"path": [{"matcher": "exact", "value": "/orders"}]
+				}
+			}`))
+			res := functional_tests.DoRequest(req)
+
+			Expect(res.StatusCode).To(Equal(200))
+
+			var resultView v2.RequestVerificationResultView
+			functional_tests.UnmarshalFromResponse(res, &resultView)
+
+			Expect(resultView.Passed).To(BeFalse())
+			Expect(resultView.Message).To(Equal("Expected at least 1 matching request but received 0 matching requests"))
+			Expect(resultView.ClosestMisses).To(HaveLen(3))
+			Expect(resultView.ClosestMisses[0].MissedFields).To(ConsistOf("method"))
+			Expect(resultView.ClosestMisses[2].MissedFields).To(ConsistOf("path"))
+		})
+
+		It("should check the order of requests", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal/verify")
+			req.Body(bytes.NewBufferString(`{
+				"inOrder": [
+					{"path": [{"matcher": "exact", "value": "/basket"}]},
+					{"path": [{"matcher": "exact", "value": "/orders"}]}
+				]
+			}`))
+			res := functional_tests.DoRequest(req)
+
+			Expect(res.StatusCode).To(Equal(200))
+
+			var resultView v2.RequestVerificationResultView
+			functional_tests.UnmarshalFromResponse(res, &resultView)
+
+			Expect(resultView.Passed).To(BeFalse())
+			Expect(resultView.Message).To(Equal("Expected 2 requests in order but request 2 was not received after request 1"))
+		})
+
+		It("should error when the expectation is invalid", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal/verify")
+			req.Body(bytes.NewBufferString(`{"request": {}, "atLeast": 3, "atMost": 1}`))
+			res := functional_tests.DoRequest(req)
+
+			Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+
+			var errorView handlers.ErrorView
+			functional_tests.UnmarshalFromResponse(res, &errorView)
+
+			Expect(errorView.Error).To(Equal(`"atLeast" cannot be greater than "atMost"`))
+		})
+	})
+
+	Context("With journal disabled", func() {
+
+		BeforeEach(func() {
+			hoverfly = functional_tests.NewHoverfly()
+			hoverfly.Start("-journal-size=0")
+		})
+
+		AfterEach(func() {
+			hoverfly.Stop()
+		})
+
+		It("should return an error", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal/verify")
+			req.Body(bytes.NewBufferString(`{"request": {}}`))
+			res := functional_tests.DoRequest(req)
+
+			Expect(res.StatusCode).To(Equal(http.StatusInternalServerError))
+
+			var errorView handlers.ErrorView
+			functional_tests.UnmarshalFromResponse(res, &errorView)
+
+			Expect(errorView.Error).To(Equal("Journal disabled"))
+		})
+	})
+})
@@ -0,0 +1,57 @@
+package api_test
+
+import (
+	"io/ioutil"
+
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/metrics", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	Context("GET", func() {
+
+		It("Should get the metrics in the Prometheus text format", func() {
+			req := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/metrics")
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+			Expect(res.Header.Get("Content-Type")).To(Equal("text/plain; version=0.0.4; charset=utf-8"))
+
+			body, err := ioutil.ReadAll(res.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring("# TYPE hoverfly_requests_total counter\n"))
+			Expect(string(body)).To(ContainSubstring("hoverfly_simulation_hits_total 0\n"))
+			Expect(string(body)).To(ContainSubstring("hoverfly_journal_entries 0\n"))
+		})
+
+		It("Should count the requests and simulation misses when a request has been made", func() {
+			proxyReq := sling.New().Get("http://www.google.com")
+			hoverfly.Proxy(proxyReq)
+
+			req := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/metrics")
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+
+			body, err := ioutil.ReadAll(res.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(ContainSubstring(`hoverfly_requests_total{mode="simulate",destination="www.google.com",method="GET",status="502"} 1` + "\n"))
+			Expect(string(body)).To(ContainSubstring("hoverfly_simulation_misses_total 1\n"))
+			Expect(string(body)).To(ContainSubstring("hoverfly_journal_entries 1\n"))
+		})
+	})
+})
@@ -0,0 +1,101 @@
+package api_test
+
+import (
+	"bytes"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/api/v2/simulation/match", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+		hoverfly.ImportSimulation(`{
+			"data": {
+				"pairs": [
+					{
+						"request": {
+							"path": [{"matcher": "exact", "value": "/orders"}],
+							"headers": {"Accept": [{"matcher": "exact", "value": "text/html"}]}
+						},
+						"response": {"status": 200, "transitionsState": {"orders": "listed"}}
+					},
+					{
+						"request": {
+							"path": [{"matcher": "glob", "value": "/order*"}]
+						},
+						"response": {"status": 200}
+					}
+				]
+			},
+			"meta": {"schemaVersion": "v5"}
+		}`)
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	Context("POST", func() {
+
+		It("Should explain how every pair matched the request", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/match")
+			req.Body(bytes.NewBufferString(`{
+				"method": "GET",
+				"destination": "hoverfly.io",
+				"path": "/orders",
+				"headers": {"Accept": ["application/json"]}
+			}`))
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+
+			var explanationView v2.MatchExplanationView
+			functional_tests.UnmarshalFromResponse(res, &explanationView)
+
+			Expect(explanationView.Strategy).To(Equal("strongest"))
+			Expect(explanationView.Matched).To(BeTrue())
+			Expect(explanationView.Candidates).To(HaveLen(2))
+
+			Expect(explanationView.Candidates[0].Matched).To(BeFalse())
+			Expect(explanationView.Candidates[0].MissedFields).To(ConsistOf("headers"))
+
+			Expect(explanationView.Candidates[1].Matched).To(BeTrue())
+			Expect(explanationView.Candidates[1].Selected).To(BeTrue())
+		})
+
+		It("Should not add the request to the journal or change the state", func() {
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/match")
+			req.Body(bytes.NewBufferString(`{
+				"method": "GET",
+				"destination": "hoverfly.io",
+				"path": "/orders",
+				"headers": {"Accept": ["text/html"]}
+			}`))
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+
+			journalReq := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal")
+			journalRes := functional_tests.DoRequest(journalReq)
+
+			var journalView v2.JournalView
+			functional_tests.UnmarshalFromResponse(journalRes, &journalView)
+			Expect(journalView.Journal).To(BeEmpty())
+
+			stateReq := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/state")
+			stateRes := functional_tests.DoRequest(stateReq)
+
+			var stateView v2.StateView
+			functional_tests.UnmarshalFromResponse(stateRes, &stateView)
+			Expect(stateView.State).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,109 @@
+package api_test
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/api/v2/simulation/pairs", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	pairsUrl := func() string {
+		return "http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/pairs"
+	}
+
+	getPairs := func() []v2.SimulationPairView {
+		res := functional_tests.DoRequest(sling.New().Get(pairsUrl()))
+		Expect(res.StatusCode).To(Equal(200))
+
+		pairsView := &v2.SimulationPairsView{}
+		functional_tests.UnmarshalFromResponse(res, pairsView)
+		return pairsView.Pairs
+	}
+
+	proxiedBody := func(url string) string {
+		res := hoverfly.Proxy(sling.New().Get(url))
+		body, err := ioutil.ReadAll(res.Body)
+		Expect(err).To(BeNil())
+		return string(body)
+	}
+
+	It("should add, update, disable, move and delete individual pairs", func() {
+		hoverfly.ImportSimulation(simulationSource("test.com", "first"))
+
+		req := sling.New().Post(pairsUrl()).Body(bytes.NewBufferString(`{
+			"id": "catch-all",
+			"request": {"destination": [{"matcher": "glob", "value": "*"}]},
+			"response": {"status": 200, "body": "catch all"}
+		}`))
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		pairs := getPairs()
+		Expect(pairs).To(HaveLen(2))
+		Expect(pairs[1].Id).To(Equal("catch-all"))
+		firstId := pairs[0].Id
+
+		req = sling.New().Put(pairsUrl() + "/" + firstId).Body(bytes.NewBufferString(`{
+			"request": {"destination": [{"matcher": "exact", "value": "test.com"}]},
+			"response": {"status": 200, "body": "updated"}
+		}`))
+		res = functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		Expect(proxiedBody("http://test.com")).To(Equal("updated"))
+
+		req = sling.New().Patch(pairsUrl() + "/" + firstId).Body(bytes.NewBufferString(`{"disabled": true}`))
+		res = functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		Expect(proxiedBody("http://test.com")).To(Equal("catch all"))
+
+		req = sling.New().Patch(pairsUrl() + "/" + firstId).Body(bytes.NewBufferString(`{"disabled": false, "position": 1}`))
+		res = functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		pairs = getPairs()
+		Expect(pairs[0].Id).To(Equal("catch-all"))
+		Expect(pairs[1].Id).To(Equal(firstId))
+		Expect(pairs[1].Disabled).To(BeFalse())
+
+		req = sling.New().Delete(pairsUrl() + "/catch-all")
+		res = functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		Expect(proxiedBody("http://test.com")).To(Equal("updated"))
+		Expect(getPairs()).To(HaveLen(1))
+		Expect(getPairs()[0].Id).To(Equal(firstId))
+	})
+
+	It("should keep pair ids in the simulation", func() {
+		hoverfly.ImportSimulation(simulationSource("test.com", "first"))
+
+		simulation := hoverfly.ExportSimulation()
+		Expect(simulation.RequestResponsePairs[0].Id).To(Equal(getPairs()[0].Id))
+	})
+
+	It("should return not found for an unknown pair", func() {
+		res := functional_tests.DoRequest(sling.New().Delete(pairsUrl() + "/unknown"))
+		Expect(res.StatusCode).To(Equal(404))
+	})
+})
@@ -0,0 +1,99 @@
+package api_test
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func simulationSource(destination, body string) string {
+	return fmt.Sprintf(`{
+	"data": {
+		"pairs": [{
+			"request": {"destination": [{"matcher": "exact", "value": "%s"}]},
+			"response": {"status": 200, "body": "%s"}
+		}]
+	},
+	"meta": {"schemaVersion": "v5.2"}
+}`, destination, body)
+}
+
+var _ = Describe("/api/v2/simulation/sources", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	putSource := func(name, simulation string) {
+		req := sling.New().Put("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/sources/" + name).Body(bytes.NewBufferString(simulation))
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+	}
+
+	proxiedBody := func(url string) string {
+		res := hoverfly.Proxy(sling.New().Get(url))
+		body, err := ioutil.ReadAll(res.Body)
+		Expect(err).To(BeNil())
+		return string(body)
+	}
+
+	It("should simulate each source, and replace and delete them independently", func() {
+		putSource("payments", simulationSource("payments.com", "paid"))
+		putSource("users", simulationSource("users.com", "user"))
+
+		Expect(proxiedBody("http://payments.com")).To(Equal("paid"))
+		Expect(proxiedBody("http://users.com")).To(Equal("user"))
+
+		putSource("payments", simulationSource("payments.com", "refunded"))
+
+		Expect(proxiedBody("http://payments.com")).To(Equal("refunded"))
+		Expect(proxiedBody("http://users.com")).To(Equal("user"))
+
+		req := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/sources")
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		sourcesView := &v2.SimulationSourcesView{}
+		functional_tests.UnmarshalFromResponse(res, sourcesView)
+		Expect(sourcesView.Sources).To(Equal([]v2.SimulationSourceView{
+			{Name: "payments", Pairs: 1},
+			{Name: "users", Pairs: 1},
+		}))
+
+		req = sling.New().Delete("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/sources/payments")
+		res = functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(200))
+
+		Expect(hoverfly.Proxy(sling.New().Get("http://payments.com")).StatusCode).To(Equal(502))
+		Expect(proxiedBody("http://users.com")).To(Equal("user"))
+	})
+
+	It("should include the sources in the simulation", func() {
+		putSource("payments", simulationSource("payments.com", "paid"))
+
+		simulation := hoverfly.ExportSimulation()
+		Expect(simulation.RequestResponsePairs).To(HaveLen(1))
+		Expect(simulation.RequestResponsePairs[0].Response.Body).To(Equal("paid"))
+	})
+
+	It("should return not found for an unknown source", func() {
+		req := sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation/sources/payments")
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(404))
+	})
+})
@@ -0,0 +1,86 @@
+package api_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/api/v2/verify", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+		hoverfly.SetMode("capture")
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	Context("POST", func() {
+
+		It("Should pass when the real service still responds as simulated", func() {
+			fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"version": 1}`)
+			}))
+			defer fakeServer.Close()
+
+			resp := hoverfly.Proxy(sling.New().Get(fakeServer.URL + "/api?test=one"))
+			Expect(resp.StatusCode).To(Equal(200))
+
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/verify")
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+
+			var verification v2.VerificationView
+			functional_tests.UnmarshalFromResponse(res, &verification)
+
+			Expect(verification.Passed).To(BeTrue())
+			Expect(verification.Results).To(HaveLen(1))
+			Expect(verification.Results[0].Request.Path).To(Equal("/api"))
+			Expect(verification.Results[0].Request.Query).To(Equal("test=one"))
+		})
+
+		It("Should report differences when the real service has changed", func() {
+			version := 1
+			fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"version": %d}`, version)
+			}))
+			defer fakeServer.Close()
+
+			resp := hoverfly.Proxy(sling.New().Get(fakeServer.URL + "/api"))
+			Expect(resp.StatusCode).To(Equal(200))
+
+			version = 2
+
+			req := sling.New().Post("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/verify").
+				BodyJSON(v2.DiffFilterView{ExcludedHeaders: []string{"Date"}})
+			res := functional_tests.DoRequest(req)
+			Expect(res.StatusCode).To(Equal(200))
+
+			var verification v2.VerificationView
+			functional_tests.UnmarshalFromResponse(res, &verification)
+
+			Expect(verification.Passed).To(BeFalse())
+			Expect(verification.Results).To(HaveLen(1))
+			Expect(verification.Results[0].DiffReport.DiffEntries).To(ConsistOf(v2.DiffReportEntry{
+				Field:    "body/version",
+				Expected: "1",
+				Actual:   "2",
+			}))
+		})
+	})
+})
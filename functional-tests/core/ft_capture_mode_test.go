@@ -544,6 +544,87 @@ var _ = Describe("When I run Hoverfly", func() {
 		})
 	})
 
+	Context("When running in capture mode with capture rules", func() {
+
+		BeforeEach(func() {
+			hoverfly.SetModeWithArgs("capture", v2.ModeArgumentsView{
+				CaptureRules: []v2.CaptureRuleView{
+					{Field: "path", Value: "/users/{id}"},
+					{Field: "body", Key: "$.requestId"},
+				},
+			})
+		})
+
+		It("Should capture generalised matchers which replay for other requests", func() {
+
+			fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write([]byte("user"))
+			}))
+
+			defer fakeServer.Close()
+
+			resp := hoverfly.Proxy(sling.New().Post(fakeServer.URL+"/users/1").Add("Content-Type", "application/json").Body(bytes.NewBufferString(`{"requestId": "a1", "name": "Ben"}`)))
+			Expect(resp.StatusCode).To(Equal(200))
+
+			payload := hoverfly.ExportSimulation()
+			Expect(payload.RequestResponsePairs).To(HaveLen(1))
+			Expect(payload.RequestResponsePairs[0].RequestMatcher.Path[0].Matcher).To(Equal(matchers.Glob))
+			Expect(payload.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/users/*"))
+			Expect(payload.RequestResponsePairs[0].RequestMatcher.Body[0].Matcher).To(Equal(matchers.JsonPartial))
+			Expect(payload.RequestResponsePairs[0].RequestMatcher.Body[0].Value).To(MatchJSON(`{"name": "Ben"}`))
+
+			hoverfly.SetMode("simulate")
+
+			resp = hoverfly.Proxy(sling.New().Post(fakeServer.URL+"/users/2").Add("Content-Type", "application/json").Body(bytes.NewBufferString(`{"requestId": "b2", "name": "Ben"}`)))
+			Expect(resp.StatusCode).To(Equal(200))
+
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(Equal("user"))
+		})
+	})
+
+	Context("When running in capture mode with redaction rules", func() {
+
+		BeforeEach(func() {
+			hoverfly.SetModeWithArgs("capture", v2.ModeArgumentsView{
+				Headers: []string{"Authorization"},
+				RedactionRules: []v2.RedactionRuleView{
+					{Header: "Authorization"},
+					{JsonPath: "$.token"},
+				},
+			})
+		})
+
+		It("Should redact the captured request and response", func() {
+
+			fakeServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(`{"token": "abc123", "expires": 60}`))
+			}))
+
+			defer fakeServer.Close()
+
+			resp := hoverfly.Proxy(sling.New().Get(fakeServer.URL).Add("Authorization", "Bearer secret"))
+			Expect(resp.StatusCode).To(Equal(200))
+
+			body, err := io.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(Equal(`{"token": "abc123", "expires": 60}`))
+
+			payload := hoverfly.ExportSimulation()
+			Expect(payload.RequestResponsePairs).To(HaveLen(1))
+			Expect(payload.RequestResponsePairs[0].RequestMatcher.Headers["Authorization"][0].Value).To(Equal("[REDACTED]"))
+			Expect(payload.RequestResponsePairs[0].Response.Body).To(MatchJSON(`{"token": "[REDACTED]", "expires": 60}`))
+
+			journalRes := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal"))
+			journalBody, err := io.ReadAll(journalRes.Body)
+			Expect(err).To(BeNil())
+			Expect(string(journalBody)).ToNot(ContainSubstring("secret"))
+			Expect(string(journalBody)).ToNot(ContainSubstring("abc123"))
+		})
+	})
+
 	Context("When running in capture mode with stateful capturing enabled", func() {
 
 		BeforeEach(func() {
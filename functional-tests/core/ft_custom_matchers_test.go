@@ -0,0 +1,76 @@
+package hoverfly_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("When using custom matchers", func() {
+
+	var (
+		hoverfly      *functional_tests.Hoverfly
+		remoteMatcher *httptest.Server
+	)
+
+	BeforeEach(func() {
+		remoteMatcher = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var request struct {
+				Value  string `json:"value"`
+				Actual string `json:"actual"`
+			}
+			json.NewDecoder(r.Body).Decode(&request)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"matched": strings.EqualFold(request.Value, request.Actual),
+			})
+		}))
+
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+		remoteMatcher.Close()
+	})
+
+	It("should match with a remote custom matcher from the simulation", func() {
+		simulation := `{
+			"data": {
+				"customMatchers": [{"name": "case-insensitive", "remote": "` + remoteMatcher.URL + `"}],
+				"pairs": [{
+					"request": {"path": [{"matcher": "case-insensitive", "value": "/HELLO"}]},
+					"response": {"status": 200, "body": "matched"}
+				}]
+			},
+			"meta": {"schemaVersion": "v5.3"}
+		}`
+		req := sling.New().Put("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation").Body(bytes.NewBufferString(simulation))
+		Expect(functional_tests.DoRequest(req).StatusCode).To(Equal(http.StatusOK))
+		hoverfly.SetMode("simulate")
+
+		resp := hoverfly.Proxy(sling.New().Get("http://test-server.com/hello"))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).To(BeNil())
+		Expect(string(body)).To(Equal("matched"))
+
+		resp = hoverfly.Proxy(sling.New().Get("http://test-server.com/goodbye"))
+		Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+	})
+
+	It("should refuse a local custom matcher without the middleware API enabled", func() {
+		req := sling.New().Put("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/hoverfly/custom-matchers").
+			Body(bytes.NewBufferString(`{"name": "local", "binary": "sh", "script": "echo"}`))
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(http.StatusBadRequest))
+	})
+})
@@ -0,0 +1,142 @@
+package hoverfly_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/SpectoLabs/hoverfly/functional-tests/testdata"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("When using namespaces", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	importNamespacedSimulation := func(namespace, simulation string) {
+		req := sling.New().Put("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/simulation?namespace=" + namespace).
+			Body(bytes.NewBufferString(simulation))
+		res := functional_tests.DoRequest(req)
+		Expect(res.StatusCode).To(Equal(http.StatusOK))
+	}
+
+	Context("as a proxy", func() {
+
+		BeforeEach(func() {
+			hoverfly = functional_tests.NewHoverfly()
+			hoverfly.Start()
+			importNamespacedSimulation("suite-a", testdata.JsonGetAndPost)
+		})
+
+		AfterEach(func() {
+			hoverfly.Stop()
+		})
+
+		It("should simulate from the namespace selected by the header", func() {
+			resp := hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite-a"))
+			Expect(resp.StatusCode).To(Equal(201))
+
+			body, err := ioutil.ReadAll(resp.Body)
+			Expect(err).To(BeNil())
+			Expect(string(body)).To(Equal("body1"))
+		})
+
+		It("should not share the simulation with other namespaces", func() {
+			resp := hoverfly.Proxy(sling.New().Get("http://destination1/path1"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+
+			importNamespacedSimulation("suite-b", testdata.JsonPayload)
+			resp = hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite-b"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		})
+
+		It("should reject a namespace which has not been created with the admin API", func() {
+			resp := hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite-b"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+
+			var namespacesView v2.NamespacesView
+			res := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/namespaces"))
+			functional_tests.UnmarshalFromResponse(res, &namespacesView)
+			Expect(namespacesView.Namespaces).To(Equal([]string{"suite-a"}))
+		})
+
+		It("should keep a journal for each namespace", func() {
+			hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite-a"))
+			hoverfly.Proxy(sling.New().Get("http://destination1/path1"))
+
+			var journalView v2.JournalView
+			res := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal?namespace=suite-a"))
+			functional_tests.UnmarshalFromResponse(res, &journalView)
+
+			Expect(journalView.Journal).To(HaveLen(1))
+			Expect(journalView.Journal[0].Response.Status).To(Equal(201))
+			Expect(journalView.Journal[0].Request.Headers).ToNot(HaveKey("Hoverfly-Namespace"))
+
+			res = functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/journal"))
+			functional_tests.UnmarshalFromResponse(res, &journalView)
+
+			Expect(journalView.Journal).To(HaveLen(1))
+			Expect(journalView.Journal[0].Response.Status).To(Equal(http.StatusBadGateway))
+		})
+
+		It("should set the mode of a namespace on its own", func() {
+			req := sling.New().Put("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/hoverfly/mode?namespace=suite-a").
+				Body(bytes.NewBufferString(`{"mode": "capture"}`))
+			Expect(functional_tests.DoRequest(req).StatusCode).To(Equal(http.StatusOK))
+
+			var modeView v2.ModeView
+			res := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/hoverfly/mode?namespace=suite-a"))
+			functional_tests.UnmarshalFromResponse(res, &modeView)
+			Expect(modeView.Mode).To(Equal("capture"))
+
+			Expect(hoverfly.GetMode().Mode).To(Equal("simulate"))
+		})
+
+		It("should list and delete namespaces", func() {
+			var namespacesView v2.NamespacesView
+			res := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/namespaces"))
+			functional_tests.UnmarshalFromResponse(res, &namespacesView)
+			Expect(namespacesView.Namespaces).To(Equal([]string{"suite-a"}))
+
+			res = functional_tests.DoRequest(sling.New().Delete("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/namespaces/suite-a"))
+			Expect(res.StatusCode).To(Equal(http.StatusOK))
+			functional_tests.UnmarshalFromResponse(res, &namespacesView)
+			Expect(namespacesView.Namespaces).To(BeEmpty())
+
+			resp := hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite-a"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+
+		It("should reject an invalid namespace", func() {
+			resp := hoverfly.Proxy(sling.New().Get("http://destination1/path1").Set("Hoverfly-Namespace", "suite a"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("as a webserver", func() {
+
+		BeforeEach(func() {
+			hoverfly = functional_tests.NewHoverfly()
+			hoverfly.Start("-webserver")
+			importNamespacedSimulation("suite-a", testdata.JsonGetAndPost)
+		})
+
+		AfterEach(func() {
+			hoverfly.Stop()
+		})
+
+		It("should simulate from the namespace selected by the header", func() {
+			resp := functional_tests.DoRequest(sling.New().Get("http://localhost:"+hoverfly.GetProxyPort()+"/path1").Set("Hoverfly-Namespace", "suite-a"))
+			Expect(resp.StatusCode).To(Equal(201))
+
+			resp = functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetProxyPort() + "/path1"))
+			Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		})
+	})
+})
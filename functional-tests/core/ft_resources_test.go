@@ -0,0 +1,82 @@
+package hoverfly_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("When simulating resources", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+	)
+
+	BeforeEach(func() {
+		hoverfly = functional_tests.NewHoverfly()
+		hoverfly.Start()
+		hoverfly.ImportSimulation(`{
+			"data": {
+				"pairs": [],
+				"resources": [{
+					"path": "/api/users",
+					"destination": "test-server.com",
+					"data": [
+						{"id": 1, "name": "Ben", "team": "core"},
+						{"id": 2, "name": "Tommy", "team": "docs"}
+					]
+				}]
+			},
+			"meta": {"schemaVersion": "v5.3"}
+		}`)
+		hoverfly.SetMode("simulate")
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+	})
+
+	It("should keep the items created, updated and deleted across requests", func() {
+		resp := hoverfly.Proxy(sling.New().Post("http://test-server.com/api/users").Body(bytes.NewBufferString(`{"name": "Kate", "team": "core"}`)))
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(resp.Header.Get("Location")).To(Equal("/api/users/3"))
+
+		resp = hoverfly.Proxy(sling.New().Patch("http://test-server.com/api/users/2").Body(bytes.NewBufferString(`{"team": "core"}`)))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp = hoverfly.Proxy(sling.New().Delete("http://test-server.com/api/users/1"))
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		resp = hoverfly.Proxy(sling.New().Get("http://test-server.com/api/users/1"))
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+
+		resp = hoverfly.Proxy(sling.New().Get("http://test-server.com/api/users?team=core&_limit=1&_page=2"))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("X-Total-Count")).To(Equal("2"))
+		body, _ := ioutil.ReadAll(resp.Body)
+		Expect(string(body)).To(MatchJSON(`[{"id": 3, "name": "Kate", "team": "core"}]`))
+	})
+
+	It("should inspect and reset the resources over the admin API", func() {
+		resp := hoverfly.Proxy(sling.New().Delete("http://test-server.com/api/users/1"))
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+
+		resp = functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/resources"))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, _ := ioutil.ReadAll(resp.Body)
+		Expect(string(body)).To(MatchJSON(`{"resources": [{"path": "/api/users", "destination": "test-server.com", "data": [{"id": 2, "name": "Tommy", "team": "docs"}]}]}`))
+
+		resp = functional_tests.DoRequest(sling.New().Delete("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/resources"))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp = hoverfly.Proxy(sling.New().Get("http://test-server.com/api/users/1"))
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		body, _ = ioutil.ReadAll(resp.Body)
+		Expect(string(body)).To(MatchJSON(`{"id": 1, "name": "Ben", "team": "core"}`))
+	})
+})
@@ -36,7 +36,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.V5JsonPayload), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 	})
 
@@ -54,7 +54,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.Delays), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -65,7 +65,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.ClosestMissProof), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -76,7 +76,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.ExactMatch), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -87,7 +87,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.GlobMatch), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -98,7 +98,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.XmlMatch), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -109,7 +109,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.XpathMatch), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 	})
 
@@ -127,7 +127,7 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.QueryMatchers), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 
 		It("should upgrade it to the latest simulation", func() {
@@ -138,7 +138,15 @@ var _ = Describe("Running Hoverfly with older simulations", func() {
 
 			functional_tests.Unmarshal([]byte(testdata.HeaderMatchers), &simulation)
 
-			Expect(upgradedSimulation.DataViewV5).To(Equal(simulation.DataViewV5))
+			Expect(withoutPairIds(upgradedSimulation.DataViewV5)).To(Equal(simulation.DataViewV5))
 		})
 	})
 })
+
+// withoutPairIds clears the ids Hoverfly gives to pairs imported without one
+func withoutPairIds(data v2.DataViewV5) v2.DataViewV5 {
+	for i := range data.RequestResponsePairs {
+		data.RequestResponsePairs[i].Id = ""
+	}
+	return data
+}
@@ -36,5 +36,21 @@ var _ = Describe("Manage template data source in hoverfly", func() {
 				Expect(templateDataSourceView.DataSources[0].Data).To(Equal("Id,Name,Marks\n1,Test1,45\n2,Test2,55\n3,Test3,67\n4,Test4,89\n*,NA,ABSENT\n"))
 			})
 		})
+
+		Context("hoverfly with json template data source", func() {
+
+			BeforeEach(func() {
+				hoverfly.Start("-templating-data-source", "test-json testdata/test-student-data.json")
+			})
+
+			It("Should return json template data sources", func() {
+				templateDataSourceView := hoverfly.GetAllDataSources()
+				Expect(templateDataSourceView.DataSources).To(BeEmpty())
+				Expect(templateDataSourceView.JsonDataSources).To(HaveLen(1))
+				Expect(templateDataSourceView.JsonDataSources[0].Name).To(Equal("test-json"))
+				Expect(templateDataSourceView.JsonDataSources[0].Format).To(Equal("json"))
+				Expect(templateDataSourceView.JsonDataSources[0].Data).To(MatchJSON(`{"students": [{"id": 1, "name": "Test1", "marks": 45}, {"id": 2, "name": "Test2", "marks": 55}]}`))
+			})
+		})
 	})
 })
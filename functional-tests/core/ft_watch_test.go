@@ -0,0 +1,103 @@
+package hoverfly_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/functional-tests"
+	"github.com/dghubble/sling"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func watchedSimulation(body string) string {
+	return fmt.Sprintf(`{
+	"data": {
+		"pairs": [{
+			"request": {"destination": [{"matcher": "exact", "value": "test-server.com"}]},
+			"response": {"status": 200, "body": "%s"}
+		}]
+	},
+	"meta": {"schemaVersion": "v5.2"}
+}`, body)
+}
+
+var _ = Describe("When I run Hoverfly", func() {
+
+	var (
+		hoverfly *functional_tests.Hoverfly
+		dir      string
+		file     string
+	)
+
+	proxiedBody := func() string {
+		response := hoverfly.Proxy(sling.New().Get("http://test-server.com"))
+		body, err := ioutil.ReadAll(response.Body)
+		Expect(err).To(BeNil())
+		return string(body)
+	}
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "hoverfly-watch")
+		Expect(err).To(BeNil())
+		file = filepath.Join(dir, "simulation.json")
+		Expect(ioutil.WriteFile(file, []byte(watchedSimulation("first")), 0644)).To(Succeed())
+
+		hoverfly = functional_tests.NewHoverfly()
+	})
+
+	AfterEach(func() {
+		hoverfly.Stop()
+		os.RemoveAll(dir)
+	})
+
+	Context("with -watch", func() {
+
+		BeforeEach(func() {
+			hoverfly.Start("-watch", dir)
+		})
+
+		It("should load the simulation and reload it when it changes", func() {
+			Expect(proxiedBody()).To(Equal("first"))
+
+			Expect(ioutil.WriteFile(file, []byte(watchedSimulation("second")), 0644)).To(Succeed())
+
+			Eventually(proxiedBody, 5*time.Second).Should(Equal("second"))
+		})
+
+		It("should keep the last good simulation and report the error when a file is invalid", func() {
+			Expect(ioutil.WriteFile(file, []byte(`{"data": `), 0644)).To(Succeed())
+
+			Eventually(func() string {
+				view := &v2.HoverflyView{}
+				response := functional_tests.DoRequest(sling.New().Get("http://localhost:" + hoverfly.GetAdminPort() + "/api/v2/hoverfly"))
+				functional_tests.UnmarshalFromResponse(response, view)
+				Expect(view.Watch).ToNot(BeNil())
+				Expect(view.Watch.Paths).To(Equal([]string{dir}))
+				return view.Watch.Error
+			}, 5*time.Second).Should(ContainSubstring("Invalid JSON"))
+
+			Expect(proxiedBody()).To(Equal("first"))
+		})
+	})
+})
+
+var _ = Describe("When I run Hoverfly with -watch and -import", func() {
+
+	It("should refuse to start, as each reload would discard the imported simulation", func() {
+		dir, err := ioutil.TempDir("", "hoverfly-watch")
+		Expect(err).To(BeNil())
+		defer os.RemoveAll(dir)
+
+		output, err := exec.Command(functional_tests.BuildBinaryPath(), "-watch", dir, "-import", filepath.Join(dir, "simulation.json")).CombinedOutput()
+
+		Expect(err).ToNot(BeNil())
+		Expect(string(output)).To(ContainSubstring("-watch cannot be used with -import"))
+	})
+})
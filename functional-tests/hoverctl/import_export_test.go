@@ -8,6 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 
@@ -28,6 +31,7 @@ var _ = Describe("When I use hoverctl", func() {
 			{
 				"data": {
 					"pairs": [{
+						"id": "create-booking",
 						"labels": ["create", "bookings"],
 						"response": {
 							"status": 201,
@@ -95,6 +99,7 @@ var _ = Describe("When I use hoverctl", func() {
 			{
 				"data": {
 					"pairs": [{
+						"id": "create-booking",
 						"labels": ["create", "bookings"],
 						"response": {
 							"status": 201,
@@ -187,7 +192,7 @@ var _ = Describe("When I use hoverctl", func() {
 				}
 			}`
 
-		hoverflySimulation = `"pairs":[{"labels":["create","bookings"],"request":{"path":[{"matcher":"exact","value":"/api/bookings"}],"method":[{"matcher":"exact","value":"POST"}],"destination":[{"matcher":"exact","value":"www.my-test.com"}],"scheme":[{"matcher":"exact","value":"http"}],"body":[{"matcher":"exact","value":"{\"flightId\": \"1\"}"}],"headers":{"Content-Type":[{"matcher":"exact","value":"application/json"}]}},"response":{"status":201,"body":"","encodedBody":false,"headers":{"Location":["http://localhost/api/bookings/1"]},"templated":false}}],"globalActions":{"delays":[],"delaysLogNormal":[]}}`
+		hoverflySimulation = `"pairs":[{"id":"create-booking","labels":["create","bookings"],"request":{"path":[{"matcher":"exact","value":"/api/bookings"}],"method":[{"matcher":"exact","value":"POST"}],"destination":[{"matcher":"exact","value":"www.my-test.com"}],"scheme":[{"matcher":"exact","value":"http"}],"body":[{"matcher":"exact","value":"{\"flightId\": \"1\"}"}],"headers":{"Content-Type":[{"matcher":"exact","value":"application/json"}]}},"response":{"status":201,"body":"","encodedBody":false,"headers":{"Location":["http://localhost/api/bookings/1"]},"templated":false}}],"globalActions":{"delays":[],"delaysLogNormal":[]}}`
 
 		hoverflyMeta = `"meta":{"schemaVersion":"v5.3","hoverflyVersion":"v\d+.\d+.\d+(-rc.\d)*","timeExported":`
 	)
@@ -206,6 +211,60 @@ var _ = Describe("When I use hoverctl", func() {
 		})
 
 		Describe("Exporting simulation with bodyFile", func() {
+			importLargeBodySimulation := func() string {
+				largeBody := `{"bookings": [` + strings.Repeat(`{"id": 1}, `, 20) + `{"id": 2}]}`
+
+				hoverfly.ImportSimulation(`{
+	"data": {
+		"pairs": [
+			{
+				"request": {
+					"path": [
+						{
+							"matcher": "exact",
+							"value": "/api/v1/bookings"
+						}
+					]
+				},
+				"response": {
+					"status": 200,
+					"body": ` + strconv.Quote(largeBody) + `,
+					"headers": {
+						"Content-Type": ["application/json"]
+					}
+				}
+			},
+			{
+				"request": {
+					"path": [
+						{
+							"matcher": "exact",
+							"value": "/api/v1/health"
+						}
+					]
+				},
+				"response": {
+					"status": 200,
+					"body": "ok"
+				}
+			}
+		]
+	},
+	"meta": {
+		"schemaVersion": "v5.2"
+	}
+}`)
+				return largeBody
+			}
+
+			restartWithBodyFilesPath := func(bodyFilesPath string) {
+				hoverfly.Stop()
+				hoverfly = functional_tests.NewHoverfly()
+				hoverfly.Start("-response-body-files-path", bodyFilesPath)
+
+				functional_tests.Run(hoverctlBinary, "targets", "update", "local", "--admin-port", hoverfly.GetAdminPort())
+			}
+
 			It("can export bodyFile fields", func() {
 				fileName := functional_tests.GenerateFileName()
 				bodyFileName := functional_tests.GenerateFileName()
@@ -299,6 +358,69 @@ var _ = Describe("When I use hoverctl", func() {
 				Expect(view.DataViewV5.RequestResponsePairs[0].Response.Body).To(BeEmpty())
 				Expect(view.DataViewV5.RequestResponsePairs[0].Response.BodyFile).To(Equal(bodyFileName))
 			})
+
+			It("can export large bodies to body files which are read on import", func() {
+				fileName := functional_tests.GenerateFileName()
+				largeBody := importLargeBodySimulation()
+
+				output := functional_tests.Run(hoverctlBinary, "export", fileName, "--body-file-threshold", "100")
+				Expect(output).To(ContainSubstring("Successfully exported simulation to " + fileName))
+
+				data, err := ioutil.ReadFile(fileName)
+				Expect(err).To(BeNil())
+
+				var view v2.SimulationViewV5
+				functional_tests.Unmarshal(data, &view)
+
+				bodyFile := view.DataViewV5.RequestResponsePairs[0].Response.BodyFile
+				Expect(bodyFile).To(MatchRegexp(`^bodies/[0-9a-f]{64}\.json$`))
+				Expect(view.DataViewV5.RequestResponsePairs[0].Response.Body).To(BeEmpty())
+				Expect(view.DataViewV5.RequestResponsePairs[1].Response.BodyFile).To(BeEmpty())
+				Expect(view.DataViewV5.RequestResponsePairs[1].Response.Body).To(Equal("ok"))
+
+				data, err = ioutil.ReadFile(filepath.Join("testdata-gen", bodyFile))
+				Expect(err).To(BeNil())
+				Expect(string(data)).To(Equal(largeBody))
+
+				restartWithBodyFilesPath(filepath.Join(workingDirectory, "testdata-gen"))
+
+				output = functional_tests.Run(hoverctlBinary, "import", fileName)
+				Expect(output).To(ContainSubstring("Successfully imported simulation from " + fileName))
+
+				Expect(hoverfly.ExportSimulation().RequestResponsePairs[0].Response.Body).To(Equal(largeBody))
+			})
+
+			It("can export large bodies next to a simulation at an absolute path", func() {
+				directory, err := ioutil.TempDir("", "hoverctl-export")
+				Expect(err).To(BeNil())
+				defer os.RemoveAll(directory)
+
+				fileName := filepath.Join(directory, "simulation.json")
+				largeBody := importLargeBodySimulation()
+
+				output := functional_tests.Run(hoverctlBinary, "export", fileName, "--body-file-threshold", "100")
+				Expect(output).To(ContainSubstring("Successfully exported simulation to " + fileName))
+
+				data, err := ioutil.ReadFile(fileName)
+				Expect(err).To(BeNil())
+
+				var view v2.SimulationViewV5
+				functional_tests.Unmarshal(data, &view)
+
+				bodyFile := view.DataViewV5.RequestResponsePairs[0].Response.BodyFile
+				Expect(bodyFile).To(MatchRegexp(`^bodies/[0-9a-f]{64}\.json$`))
+
+				data, err = ioutil.ReadFile(filepath.Join(directory, bodyFile))
+				Expect(err).To(BeNil())
+				Expect(string(data)).To(Equal(largeBody))
+
+				restartWithBodyFilesPath(directory)
+
+				output = functional_tests.Run(hoverctlBinary, "import", fileName)
+				Expect(output).To(ContainSubstring("Successfully imported simulation from " + fileName))
+
+				Expect(hoverfly.ExportSimulation().RequestResponsePairs[0].Response.Body).To(Equal(largeBody))
+			})
 		})
 
 		Describe("Managing Hoverflies data using the CLI", func() {
@@ -377,6 +499,38 @@ var _ = Describe("When I use hoverctl", func() {
 				Expect(string(bytes)).To(MatchRegexp(hoverflyMeta))
 			})
 
+			It("can import a HAR file", func() {
+
+				fileName := functional_tests.GenerateFileName()
+				err := ioutil.WriteFile(fileName, []byte(`{
+					"log": {
+						"version": "1.2",
+						"entries": [{
+							"request": {"method": "GET", "url": "http://www.har-test.com/api/bookings?page=1", "headers": []},
+							"response": {
+								"status": 200,
+								"headers": [{"name": "Content-Type", "value": "text/plain"}],
+								"content": {"size": 5, "mimeType": "text/plain", "text": "aGVsbG8=", "encoding": "base64"}
+							}
+						}]
+					}
+				}`), 0644)
+				Expect(err).To(BeNil())
+
+				output := functional_tests.Run(hoverctlBinary, "import", fileName, "--format", "har")
+
+				Expect(output).To(ContainSubstring("Successfully imported simulation from " + fileName))
+
+				simulation := hoverfly.GetSimulation()
+				view := v2.SimulationViewV5{}
+				Expect(json.NewDecoder(simulation).Decode(&view)).To(Succeed())
+
+				Expect(view.RequestResponsePairs).To(HaveLen(1))
+				Expect(view.RequestResponsePairs[0].RequestMatcher.Destination[0].Value).To(Equal("www.har-test.com"))
+				Expect(view.RequestResponsePairs[0].RequestMatcher.Path[0].Value).To(Equal("/api/bookings"))
+				Expect(view.RequestResponsePairs[0].Response.Body).To(Equal("hello"))
+			})
+
 			// TODO: Fix this test
 			// It("cannot import incorrect json / missing meta", func() {
 			// 	hoverfly.ImportSimulation(v3HoverflyData)
@@ -91,4 +91,45 @@ var _ = Describe("When I use hoverctl", func() {
 		})
 
 	})
+	Describe("set json templating-data-source", func() {
+
+		BeforeEach(func() {
+			hoverfly = functional_tests.NewHoverfly()
+			hoverfly.Start()
+			functional_tests.Run(hoverctlBinary, "targets", "update", "local", "--admin-port", hoverfly.GetAdminPort())
+		})
+
+		AfterEach(func() {
+			hoverfly.Stop()
+		})
+
+		It("should set, get and delete json and yaml data sources", func() {
+			output := functional_tests.Run(hoverctlBinary, "templating-data-source", "set", "--json", "--name", "test-json", "--filePath", "testdata/test-student-data.json")
+			Expect(output).To(ContainSubstring("Success"))
+
+			output = functional_tests.Run(hoverctlBinary, "templating-data-source", "set", "--json", "--name", "test-yaml", "--filePath", "testdata/test-student-data.yaml")
+			Expect(output).To(ContainSubstring("Success"))
+
+			output = functional_tests.Run(hoverctlBinary, "templating-data-source", "get-all")
+			Expect(output).To(ContainSubstring("test-json"))
+			Expect(output).To(ContainSubstring(`{"students":[{"id":1,"marks":45,"name":"Test1"}`))
+			Expect(output).To(ContainSubstring("test-yaml"))
+			Expect(output).To(ContainSubstring("yaml"))
+
+			output = functional_tests.Run(hoverctlBinary, "templating-data-source", "delete", "--json", "--name", "test-json")
+			Expect(output).To(ContainSubstring("Success"))
+
+			output = functional_tests.Run(hoverctlBinary, "templating-data-source", "get-all")
+			Expect(output).NotTo(ContainSubstring("test-json"))
+			Expect(output).To(ContainSubstring("test-yaml"))
+		})
+
+		It("should fail to set a json data source which is not valid json", func() {
+			output := functional_tests.Run(hoverctlBinary, "templating-data-source", "set", "--json", "--name", "test-json", "--filePath", "testdata/test-student-data.csv")
+
+			Expect(output).To(ContainSubstring("Could not set json data source"))
+			Expect(output).To(ContainSubstring("is not valid JSON"))
+		})
+
+	})
 })
@@ -3,13 +3,18 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
 )
 
 var urlPattern string
+var redactHeaders, redactQueries, redactJsonPaths, redactXPaths, redactRegexes []string
+var redactHash bool
+var bodyFileThreshold int
 var exportCmd = &cobra.Command{
 	Use:   "export [path to simulation]",
 	Short: "Export a simulation from Hoverfly",
@@ -23,20 +28,34 @@ will be written to the file path provided.
 
 		checkArgAndExit(args, "You have not provided a path to simulation", "export")
 
-		simulationView, err := wrapper.ExportSimulation(*target, urlPattern)
-		handleIfError(err)
+		var redactionRules []v2.RedactionRuleView
+		for _, header := range redactHeaders {
+			redactionRules = append(redactionRules, v2.RedactionRuleView{Header: header, Hash: redactHash})
+		}
+		for _, query := range redactQueries {
+			redactionRules = append(redactionRules, v2.RedactionRuleView{Query: query, Hash: redactHash})
+		}
+		for _, jsonPath := range redactJsonPaths {
+			redactionRules = append(redactionRules, v2.RedactionRuleView{JsonPath: jsonPath, Hash: redactHash})
+		}
+		for _, xpath := range redactXPaths {
+			redactionRules = append(redactionRules, v2.RedactionRuleView{XPath: xpath, Hash: redactHash})
+		}
+		for _, regex := range redactRegexes {
+			redactionRules = append(redactionRules, v2.RedactionRuleView{Regex: regex, Hash: redactHash})
+		}
 
-		for i, pair := range simulationView.DataViewV5.RequestResponsePairs {
-			bodyFile := pair.Response.GetBodyFile()
-			if len(bodyFile) == 0 {
-				continue
-			}
+		simulationView, err := wrapper.ExportSimulation(*target, urlPattern, redactionRules)
+		handleIfError(err)
 
-			if err := configuration.WriteFile(bodyFile, []byte(pair.Response.GetBody())); err != nil {
-				handleIfError(err)
-			}
+		// Body files the simulation already references are relative to the directory hoverctl runs in
+		handleIfError(writeBodyFiles(&simulationView, ""))
 
-			simulationView.DataViewV5.RequestResponsePairs[i].Response.Body = ""
+		if bodyFileThreshold >= 0 {
+			// Body files for large bodies are relative to the simulation, so it can be imported from anywhere with
+			// -response-body-files-path set to its directory
+			v2.ReferenceBodyFiles(&simulationView, bodyFileThreshold, v2.BodyFilesDirectory)
+			handleIfError(writeBodyFiles(&simulationView, filepath.Dir(args[0])))
 		}
 
 		simulationData, err := json.MarshalIndent(simulationView, "", "\t")
@@ -49,8 +68,50 @@ will be written to the file path provided.
 	},
 }
 
+// writeBodyFiles writes the bodies of the responses with a bodyFile to that file in the directory, and removes them
+// from the simulation
+func writeBodyFiles(simulationView *v2.SimulationViewV5, directory string) error {
+	for i := range simulationView.DataViewV5.RequestResponsePairs {
+		pair := &simulationView.DataViewV5.RequestResponsePairs[i]
+		if err := writeBodyFile(&pair.Response, directory); err != nil {
+			return err
+		}
+		for j := range pair.Responses {
+			if err := writeBodyFile(&pair.Responses[j], directory); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func writeBodyFile(response *v2.ResponseDetailsViewV5, directory string) error {
+	bodyFile := response.GetBodyFile()
+	if len(bodyFile) == 0 {
+		return nil
+	}
+	// Bodies already written relative to the directory hoverctl runs in have been removed, so are not written again
+	if directory != "" && len(response.GetBody()) == 0 {
+		return nil
+	}
+
+	if err := configuration.WriteFile(filepath.Join(directory, filepath.FromSlash(bodyFile)), []byte(response.GetBody())); err != nil {
+		return err
+	}
+
+	response.Body = ""
+	return nil
+}
+
 func init() {
 	RootCmd.AddCommand(exportCmd)
 
 	exportCmd.Flags().StringVar(&urlPattern, "url-pattern", "", "Export simulation for the urls that matches a pattern, eg. foo.com/api/v(.+)")
+	exportCmd.Flags().StringArrayVar(&redactHeaders, "redact-header", []string{}, "Redact the values of a request or response header, eg. Authorization")
+	exportCmd.Flags().StringArrayVar(&redactQueries, "redact-query", []string{}, "Redact the values of a query parameter or form field, eg. apiKey")
+	exportCmd.Flags().StringArrayVar(&redactJsonPaths, "redact-json-path", []string{}, "Redact a field of JSON bodies, eg. $.user.password")
+	exportCmd.Flags().StringArrayVar(&redactXPaths, "redact-xpath", []string{}, "Redact the text of XML elements, eg. //password")
+	exportCmd.Flags().StringArrayVar(&redactRegexes, "redact-regex", []string{}, "Redact matches of a regex in bodies, headers and queries, or its first group")
+	exportCmd.Flags().BoolVar(&redactHash, "redact-hash", false, "Replace redacted values with a hash of the value rather than [REDACTED]")
+	exportCmd.Flags().IntVar(&bodyFileThreshold, "body-file-threshold", -1, "Write response bodies larger than this many bytes to files in a bodies directory next to the simulation, referenced relative to the simulation")
 }
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 
+	"github.com/SpectoLabs/hoverfly/core/openapi"
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
 )
 
+var importFormat string
+
 // importCmd represents the import command
 var importCmd = &cobra.Command{
 	Use:   "import [path to simulation]",
@@ -18,6 +22,14 @@ simulation data will be replaced. An absolute or
 relative path to a Hoverfly simulation JSON file
 must be provided. To add multiple simulations,
 use "hoverctl simulation add [paths]" instead.
+
+An OpenAPI 3 document in YAML or JSON can be given
+instead of a simulation, in which case a simulation
+is generated with a pair for each operation.
+
+Use "--format har" to import an HTTP Archive (HAR),
+such as one saved from browser devtools, with a pair
+for each of its entries.
 	`,
 
 	Run: func(cmd *cobra.Command, args []string) {
@@ -27,7 +39,15 @@ use "hoverctl simulation add [paths]" instead.
 		simulationData, err := configuration.ReadFile(args[0])
 		handleIfError(err)
 
-		err = wrapper.ImportSimulation(*target, string(simulationData))
+		if importFormat == "" && openapi.IsDocument(simulationData) {
+			simulation, err := openapi.NewSimulation(simulationData)
+			handleIfError(err)
+
+			simulationData, err = json.Marshal(simulation)
+			handleIfError(err)
+		}
+
+		err = wrapper.ImportSimulation(*target, string(simulationData), importFormat)
 		handleIfError(err)
 
 		fmt.Println("Successfully imported simulation from", args[0])
@@ -36,4 +56,6 @@ use "hoverctl simulation add [paths]" instead.
 
 func init() {
 	RootCmd.AddCommand(importCmd)
+
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Format of the file to import, either json or har. Defaults to a simulation in JSON")
 }
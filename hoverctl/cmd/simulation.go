@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
@@ -29,6 +33,11 @@ warning message.
 
 You may provide an absolute or relative path to each 
 simulation file.
+
+With --name, the simulation is added as a named source 
+instead, replacing any source of the same name. Sources 
+are matched after the rest of the simulation, in the 
+order they were first added.
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 
@@ -36,6 +45,20 @@ simulation file.
 
 		checkArgAndExit(args, "You have not provided a path to simulation", "simulation add")
 
+		if name, _ := cmd.Flags().GetString("name"); name != "" {
+			if len(args) > 1 {
+				handleIfError(fmt.Errorf("Only one simulation can be added as a named source"))
+			}
+
+			simulationData, err := configuration.ReadFile(args[0])
+			handleIfError(err)
+
+			err = wrapper.PutSimulationSource(*target, name, string(simulationData))
+			handleIfError(err)
+			fmt.Println("Successfully added simulation source", name, "from", args[0])
+			return
+		}
+
 		for _, arg := range args {
 
 			simulationData, err := configuration.ReadFile(arg)
@@ -49,7 +72,269 @@ simulation file.
 	},
 }
 
+var listSimulationSourcesCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the named simulation sources",
+	Long: `
+Lists the named simulation sources in Hoverfly, in the 
+order they are matched, with the number of pairs in each.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		sourcesView, err := wrapper.GetSimulationSources(*target)
+		handleIfError(err)
+
+		if len(sourcesView.Sources) == 0 {
+			fmt.Println("Hoverfly has no simulation sources")
+			return
+		}
+
+		data := [][]string{{"Name", "Pairs", "WebSocket pairs"}}
+		for _, source := range sourcesView.Sources {
+			data = append(data, []string{source.Name, strconv.Itoa(source.Pairs), strconv.Itoa(source.WebSocketPairs)})
+		}
+		drawTable(data, true)
+	},
+}
+
+var deleteSimulationSourceCmd = &cobra.Command{
+	Use:   "delete [source name]",
+	Short: "Delete a named simulation source",
+	Long: `
+Deletes a named simulation source along with its pairs, 
+leaving the rest of the simulation as it is.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		checkArgAndExit(args, "You have not provided the name of a simulation source", "simulation delete")
+
+		err := wrapper.DeleteSimulationSource(*target, args[0])
+		handleIfError(err)
+		fmt.Println("Successfully deleted simulation source", args[0])
+	},
+}
+
+var simulationPairCmd = &cobra.Command{
+	Use:   "pair",
+	Short: "Manage the individual pairs of the simulation",
+	Long: `
+This allows you to manage the individual pairs of the 
+simulation in Hoverfly, including those of named sources. 
+Each pair has an id, which is listed by "hoverctl 
+simulation pair list".
+	`,
+}
+
+var listSimulationPairsCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the pairs of the simulation",
+	Long: `
+Lists the pairs of the simulation in the order they are 
+matched, followed by those of each named source.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		pairsView, err := wrapper.GetSimulationPairs(*target)
+		handleIfError(err)
+
+		if len(pairsView.Pairs) == 0 {
+			fmt.Println("Hoverfly has no pairs")
+			return
+		}
+
+		data := [][]string{{"Id", "Source", "Method", "Destination", "Path", "Status", "Enabled"}}
+		for _, pair := range pairsView.Pairs {
+			data = append(data, []string{
+				pair.Id,
+				pair.Source,
+				matcherValues(pair.RequestMatcher.Method),
+				matcherValues(pair.RequestMatcher.Destination),
+				matcherValues(pair.RequestMatcher.Path),
+				strconv.Itoa(pair.Response.Status),
+				strconv.FormatBool(!pair.Disabled),
+			})
+		}
+		drawTable(data, true)
+	},
+}
+
+var getSimulationPairCmd = &cobra.Command{
+	Use:   "get [pair id]",
+	Short: "Print a pair of the simulation",
+	Long: `
+Prints a pair of the simulation as JSON.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		checkArgAndExit(args, "You have not provided the id of a pair", "simulation pair get")
+
+		pairView, err := wrapper.GetSimulationPair(*target, args[0])
+		handleIfError(err)
+
+		pairData, err := json.MarshalIndent(pairView, "", "\t")
+		handleIfError(err)
+		fmt.Println(string(pairData))
+	},
+}
+
+var addSimulationPairCmd = &cobra.Command{
+	Use:   "add [path to pair]",
+	Short: "Add a pair to the simulation",
+	Long: `
+Adds a pair, read from a JSON file in the same format as 
+a pair of a simulation, after the other pairs of the 
+simulation.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		checkArgAndExit(args, "You have not provided a path to a pair", "simulation pair add")
+
+		pairData, err := configuration.ReadFile(args[0])
+		handleIfError(err)
+
+		pairView, err := wrapper.AddSimulationPair(*target, string(pairData))
+		handleIfError(err)
+		fmt.Println("Successfully added pair", pairView.Id, "from", args[0])
+	},
+}
+
+var updateSimulationPairCmd = &cobra.Command{
+	Use:   "update [pair id] [path to pair]",
+	Short: "Replace a pair of the simulation",
+	Long: `
+Replaces a pair with one read from a JSON file, keeping 
+its id and its place in the matching order.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		if len(args) < 2 {
+			checkArgAndExit(nil, "You have not provided the id of a pair and a path to a pair", "simulation pair update")
+		}
+
+		pairData, err := configuration.ReadFile(args[1])
+		handleIfError(err)
+
+		_, err = wrapper.UpdateSimulationPair(*target, args[0], string(pairData))
+		handleIfError(err)
+		fmt.Println("Successfully updated pair", args[0], "from", args[1])
+	},
+}
+
+var deleteSimulationPairCmd = &cobra.Command{
+	Use:   "delete [pair id]",
+	Short: "Delete a pair of the simulation",
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		checkArgAndExit(args, "You have not provided the id of a pair", "simulation pair delete")
+
+		err := wrapper.DeleteSimulationPair(*target, args[0])
+		handleIfError(err)
+		fmt.Println("Successfully deleted pair", args[0])
+	},
+}
+
+var enableSimulationPairCmd = &cobra.Command{
+	Use:   "enable [pair id]",
+	Short: "Enable a disabled pair of the simulation",
+	Run: func(cmd *cobra.Command, args []string) {
+		setSimulationPairDisabled(args, false)
+	},
+}
+
+var disableSimulationPairCmd = &cobra.Command{
+	Use:   "disable [pair id]",
+	Short: "Disable a pair of the simulation",
+	Long: `
+Disables a pair, so that it is not matched, without 
+removing it from the simulation.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		setSimulationPairDisabled(args, true)
+	},
+}
+
+var moveSimulationPairCmd = &cobra.Command{
+	Use:   "move [pair id] [position]",
+	Short: "Move a pair of the simulation",
+	Long: `
+Moves a pair to a position, starting from 0, among the 
+pairs of the simulation, or of the named source it 
+belongs to, changing the order in which they are matched.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		checkTargetAndExit(target)
+
+		if len(args) < 2 {
+			checkArgAndExit(nil, "You have not provided the id of a pair and a position", "simulation pair move")
+		}
+
+		position, err := strconv.Atoi(args[1])
+		if err != nil {
+			handleIfError(fmt.Errorf("Position %s is not a number", args[1]))
+		}
+
+		_, err = wrapper.PatchSimulationPair(*target, args[0], v2.SimulationPairPatchView{Position: &position})
+		handleIfError(err)
+		fmt.Println("Successfully moved pair", args[0], "to position", position)
+	},
+}
+
+func setSimulationPairDisabled(args []string, disabled bool) {
+	checkTargetAndExit(target)
+
+	command := "simulation pair enable"
+	if disabled {
+		command = "simulation pair disable"
+	}
+	checkArgAndExit(args, "You have not provided the id of a pair", command)
+
+	_, err := wrapper.PatchSimulationPair(*target, args[0], v2.SimulationPairPatchView{Disabled: &disabled})
+	handleIfError(err)
+
+	if disabled {
+		fmt.Println("Successfully disabled pair", args[0])
+	} else {
+		fmt.Println("Successfully enabled pair", args[0])
+	}
+}
+
+func matcherValues(matcherViews []v2.MatcherViewV5) string {
+	var values []string
+	for _, matcherView := range matcherViews {
+		values = append(values, fmt.Sprint(matcherView.Value))
+	}
+	return strings.Join(values, ", ")
+}
+
 func init() {
 	RootCmd.AddCommand(simulationCmd)
 	simulationCmd.AddCommand(addSimulationCmd)
+	simulationCmd.AddCommand(listSimulationSourcesCmd)
+	simulationCmd.AddCommand(deleteSimulationSourceCmd)
+	simulationCmd.AddCommand(simulationPairCmd)
+	simulationPairCmd.AddCommand(listSimulationPairsCmd)
+	simulationPairCmd.AddCommand(getSimulationPairCmd)
+	simulationPairCmd.AddCommand(addSimulationPairCmd)
+	simulationPairCmd.AddCommand(updateSimulationPairCmd)
+	simulationPairCmd.AddCommand(deleteSimulationPairCmd)
+	simulationPairCmd.AddCommand(enableSimulationPairCmd)
+	simulationPairCmd.AddCommand(disableSimulationPairCmd)
+	simulationPairCmd.AddCommand(moveSimulationPairCmd)
+
+	addSimulationCmd.Flags().String("name", "", "Add the simulation as a named source, replacing the source of the same name")
 }
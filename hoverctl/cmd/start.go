@@ -77,6 +77,7 @@ hoverctl configuration file.
 		target.NoImportCheck, _ = cmd.Flags().GetBool("no-import-check")
 
 		target.Simulations, _ = cmd.Flags().GetStringSlice("import")
+		target.Watch, _ = cmd.Flags().GetStringSlice("watch")
 
 		// Feature flags
 		target.EnableMiddlewareAPI, _ = cmd.Flags().GetBool("enable-middleware-api")
@@ -198,6 +199,7 @@ func init() {
 	startCmd.Flags().String("password", "", "Password to authenticate Hoverfly")
 
 	startCmd.Flags().StringSlice("import", []string{}, "Simulations to import")
+	startCmd.Flags().StringSlice("watch", []string{}, "Simulation files or directories to load and reload whenever they change")
 
 	startCmd.Flags().StringSlice("logs-output", []string{}, "Locations for log output, \"console\"(default) or \"file\"")
 	startCmd.Flags().String("logs-file", "", "Log file name. Use \"hoverfly-<target name>.log\" if not provided")
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
 	"github.com/spf13/cobra"
@@ -67,6 +68,13 @@ mode and middleware set.
 				fmt.Println("Hoverfly is using local middleware with the command " + middleware.Binary + " and the script:\n" + middleware.Script)
 			}
 		}
+		if hoverflyInfo.Watch != nil {
+			fmt.Println("")
+			fmt.Println("Hoverfly is watching simulations in:\n" + strings.Join(hoverflyInfo.Watch.Paths, "\n"))
+			if hoverflyInfo.Watch.Error != "" {
+				fmt.Println("The last reload failed, so the last good simulation is kept:\n" + hoverflyInfo.Watch.Error)
+			}
+		}
 	},
 }
 
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
 	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
@@ -10,12 +12,14 @@ import (
 )
 
 var name, filePath string
+var jsonDataSource bool
 
 var templateCsvDataSourceCommand = &cobra.Command{
 	Use:   "templating-data-source",
 	Short: "Manage the templating data source for Hoverfly",
 	Long: `
-		This allows you to manage templating data source for Hoverfly. Only CSV datasource is supported as of now  
+		This allows you to manage templating data source for Hoverfly. CSV data sources are set by default,
+		and JSON or YAML data sources are set with --json
 	`,
 }
 
@@ -36,19 +40,25 @@ var templateCsvDataSourceGetCommand = &cobra.Command{
 
 var templateCsvDataSourceSetCommand = &cobra.Command{
 	Use:   "set",
-	Short: "Set csv templating data source for Hoverfly",
+	Short: "Set csv, json or yaml templating data source for Hoverfly",
 	Long: `
 Hoverfly Templating CSV DataSource can be set using the following flags: 
 	 --name --filePath
+Add --json to set a JSON DataSource instead, which is read as YAML when the file
+ends with .yaml or .yml
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		checkTargetAndExit(target)
 		if name == "" || filePath == "" {
-			fmt.Println("data source name and file path are compulsory to set csv templating data source")
+			fmt.Println("data source name and file path are compulsory to set templating data source")
 		} else {
 			data, err := configuration.ReadFile(filePath)
 			handleIfError(err)
-			err = wrapper.SetCsvTemplateDataSource(name, string(data), *target)
+			if jsonDataSource {
+				err = wrapper.SetJsonTemplateDataSource(name, getJsonDataSourceFormat(filePath), string(data), *target)
+			} else {
+				err = wrapper.SetCsvTemplateDataSource(name, string(data), *target)
+			}
 			handleIfError(err)
 			fmt.Println("Success")
 		}
@@ -57,17 +67,23 @@ Hoverfly Templating CSV DataSource can be set using the following flags:
 
 var templateCsvDataSourceDeleteCommand = &cobra.Command{
 	Use:   "delete",
-	Short: "Delete csv templating data source for Hoverfly",
+	Short: "Delete csv, json or yaml templating data source for Hoverfly",
 	Long: `
 Hoverfly CSV templating datasource can be deleted using the following flags: 
 	 --name
+Add --json to delete a JSON or YAML DataSource instead
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		checkTargetAndExit(target)
 		if name == "" {
-			fmt.Println("csv datasource name to be deleted not provided")
+			fmt.Println("datasource name to be deleted not provided")
 		} else {
-			err := wrapper.DeleteCsvDataSource(name, *target)
+			var err error
+			if jsonDataSource {
+				err = wrapper.DeleteJsonDataSource(name, *target)
+			} else {
+				err = wrapper.DeleteCsvDataSource(name, *target)
+			}
 			handleIfError(err)
 			fmt.Println("Success")
 		}
@@ -83,16 +99,31 @@ func init() {
 	templateCsvDataSourceSetCommand.PersistentFlags().StringVar(&name, "name", "", "Datasource Name to be set")
 	templateCsvDataSourceSetCommand.PersistentFlags().StringVar(&filePath, "filePath", "",
 		"An absolute or relative path to a csv file that Hoverfly will use for templating")
+	templateCsvDataSourceSetCommand.PersistentFlags().BoolVar(&jsonDataSource, "json", false,
+		"Set a JSON data source, or a YAML one if the file ends with .yaml or .yml, which templates query with JSONPath")
 	templateCsvDataSourceDeleteCommand.PersistentFlags().StringVar(&name, "name", "", "Datasource Name to be set")
+	templateCsvDataSourceDeleteCommand.PersistentFlags().BoolVar(&jsonDataSource, "json", false, "Delete a JSON or YAML data source")
 
 }
 
 func getTemplatingDataSourceTabularData(templatingDataSourceView v2.TemplateDataSourceView) [][]string {
 
-	templateDataSourceDetails := [][]string{{"CSV DataSource Name", "Content"}}
+	templateDataSourceDetails := [][]string{{"DataSource Name", "Type", "Content"}}
 	for _, csvDataSourceView := range templatingDataSourceView.DataSources {
-		csvDataSource := []string{csvDataSourceView.Name, getContentShorthand(csvDataSourceView.Data)}
+		csvDataSource := []string{csvDataSourceView.Name, "csv", getContentShorthand(csvDataSourceView.Data)}
 		templateDataSourceDetails = append(templateDataSourceDetails, csvDataSource)
 	}
+	for _, jsonDataSourceView := range templatingDataSourceView.JsonDataSources {
+		jsonDataSource := []string{jsonDataSourceView.Name, jsonDataSourceView.Format, getContentShorthand(jsonDataSourceView.Data)}
+		templateDataSourceDetails = append(templateDataSourceDetails, jsonDataSource)
+	}
 	return templateDataSourceDetails
 }
+
+func getJsonDataSourceFormat(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
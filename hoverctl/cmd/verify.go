@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
+	"github.com/spf13/cobra"
+)
+
+var verifyExcludedHeaders []string
+var verifyExcludedResponseFields []string
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify the simulation against the real services",
+	Long: `
+Replays a request for each pair of the simulation
+against the real service and reports where the real
+response differs from the simulated one. Pairs with
+a request no concrete request can be worked out from,
+such as one using a jsonpath matcher, are skipped.
+
+Exits with a non-zero status when any pair fails, so
+that it can be used in CI to find stubs which have
+drifted from the real API.
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		verification, err := wrapper.VerifySimulation(*target, v2.DiffFilterView{
+			ExcludedHeaders:        verifyExcludedHeaders,
+			ExcludedResponseFields: verifyExcludedResponseFields,
+		})
+		handleIfError(err)
+
+		passed, failed, skipped := 0, 0, 0
+		for index, result := range verification.Results {
+			request := fmt.Sprintf("%s %s%s", result.Request.Method, result.Request.Host, result.Request.Path)
+			if result.Request.Query != "" {
+				request += "?" + result.Request.Query
+			}
+
+			switch {
+			case result.Skipped != "":
+				skipped++
+				fmt.Printf("%d. SKIPPED %s\n", index+1, result.Skipped)
+			case result.Passed:
+				passed++
+				fmt.Printf("%d. PASSED %s\n", index+1, request)
+			default:
+				failed++
+				fmt.Printf("%d. FAILED %s\n", index+1, request)
+				if result.Error != "" {
+					fmt.Printf("\n%s\n", result.Error)
+				}
+				if result.DiffReport != nil {
+					fmt.Printf("\n%s", diffReportMessage(*result.DiffReport))
+				}
+			}
+		}
+
+		fmt.Printf("\n%d passed, %d failed, %d skipped\n", passed, failed, skipped)
+
+		if !verification.Passed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringSliceVar(&verifyExcludedHeaders, "exclude-header", []string{}, "Response header to leave out of the comparison, can be repeated")
+	verifyCmd.Flags().StringSliceVar(&verifyExcludedResponseFields, "exclude-response-field", []string{}, "JSON path of a response body field to leave out of the comparison, eg. $.createdAt, can be repeated")
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	"github.com/SpectoLabs/hoverfly/core/util"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+	"github.com/SpectoLabs/hoverfly/hoverctl/wrapper"
+	"github.com/spf13/cobra"
+)
+
+var verifyRequestsMethod string
+var verifyRequestsDestination string
+var verifyRequestsPath string
+var verifyRequestsExactly int
+var verifyRequestsAtLeast int
+var verifyRequestsAtMost int
+var verifyRequestsNever bool
+
+var verifyRequestsCmd = &cobra.Command{
+	Use:   "verify-requests [path to verification]",
+	Short: "Verify the requests Hoverfly has received",
+	Long: `
+Checks the requests in the journal of Hoverfly against
+an expectation. The request can be given with the
+--method, --destination and --path flags, which match
+exactly, or as a JSON file holding a request matcher:
+
+  {"request": {"path": [{"matcher": "glob", "value": "/orders/*"}]}, "atLeast": 2}
+
+A file can also hold "inOrder", a list of request
+matchers which must have been received in that order.
+
+Without --exactly, --at-least, --at-most or --never,
+at least one matching request is expected. When too
+few requests match, the journal entries which came
+closest are shown with the fields they missed on.
+
+Exits with a non-zero status when the expectation
+is not met.
+	`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		checkTargetAndExit(target)
+
+		var verification v2.RequestVerificationView
+		if len(args) > 0 {
+			data, err := configuration.ReadFile(args[0])
+			handleIfError(err)
+			handleIfError(json.Unmarshal(data, &verification))
+		}
+
+		if verifyRequestsMethod != "" || verifyRequestsDestination != "" || verifyRequestsPath != "" {
+			verification.Request = &v2.RequestMatcherViewV5{}
+			if verifyRequestsMethod != "" {
+				verification.Request.Method = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, strings.ToUpper(verifyRequestsMethod))}
+			}
+			if verifyRequestsDestination != "" {
+				verification.Request.Destination = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, verifyRequestsDestination)}
+			}
+			if verifyRequestsPath != "" {
+				verification.Request.Path = []v2.MatcherViewV5{v2.NewMatcherView(matchers.Exact, verifyRequestsPath)}
+			}
+		}
+
+		if cmd.Flags().Changed("exactly") {
+			verification.Exactly = &verifyRequestsExactly
+		}
+		if cmd.Flags().Changed("at-least") {
+			verification.AtLeast = &verifyRequestsAtLeast
+		}
+		if cmd.Flags().Changed("at-most") {
+			verification.AtMost = &verifyRequestsAtMost
+		}
+		if verifyRequestsNever {
+			verification.Never = true
+		}
+
+		result, err := wrapper.VerifyRequests(*target, verification)
+		handleIfError(err)
+
+		if result.Passed {
+			fmt.Println("PASSED", result.Message)
+			return
+		}
+
+		fmt.Println("FAILED", result.Message)
+		if len(result.ClosestMisses) > 0 {
+			fmt.Println("\nClosest requests received:")
+			for index, miss := range result.ClosestMisses {
+				request := miss.Request
+				fmt.Printf("%d. %s %s://%s%s", index+1, util.PointerToString(request.Method), util.PointerToString(request.Scheme),
+					util.PointerToString(request.Destination), util.PointerToString(request.Path))
+				if query := util.PointerToString(request.Query); query != "" {
+					fmt.Printf("?%s", query)
+				}
+				fmt.Printf(" at %s, missed on %s\n", miss.TimeStarted, strings.Join(miss.MissedFields, ", "))
+			}
+		}
+
+		os.Exit(1)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(verifyRequestsCmd)
+
+	verifyRequestsCmd.Flags().StringVar(&verifyRequestsMethod, "method", "", "Method of the expected request")
+	verifyRequestsCmd.Flags().StringVar(&verifyRequestsDestination, "destination", "", "Destination of the expected request, eg. hoverfly.io")
+	verifyRequestsCmd.Flags().StringVar(&verifyRequestsPath, "path", "", "Path of the expected request")
+	verifyRequestsCmd.Flags().IntVar(&verifyRequestsExactly, "exactly", 0, "Number of matching requests expected")
+	verifyRequestsCmd.Flags().IntVar(&verifyRequestsAtLeast, "at-least", 0, "Fewest matching requests expected")
+	verifyRequestsCmd.Flags().IntVar(&verifyRequestsAtMost, "at-most", 0, "Most matching requests expected")
+	verifyRequestsCmd.Flags().BoolVar(&verifyRequestsNever, "never", false, "Expect no matching requests")
+}
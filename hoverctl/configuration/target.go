@@ -43,6 +43,7 @@ type Target struct {
 	LogLevel string
 
 	Simulations []string `yaml:",omitempty"`
+	Watch       []string `yaml:",omitempty"`
 
 	LogOutput []string `yaml:",omitempty"`
 	LogFile   string   `yaml:",omitempty"`
@@ -174,6 +175,10 @@ func (this Target) BuildFlags() Flags {
 		}
 	}
 
+	for _, val := range this.Watch {
+		flags = append(flags, "-watch="+val)
+	}
+
 	// Feature flags
 	if this.EnableMiddlewareAPI {
 		flags = append(flags, "-enable-middleware-api")
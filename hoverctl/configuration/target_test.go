@@ -238,6 +238,18 @@ func Test_Target_BuildFlags_SetImportFlags(t *testing.T) {
 	Expect(unit.BuildFlags()[1]).To(Equal("-import=bar.json"))
 }
 
+func Test_Target_BuildFlags_SetWatchFlags(t *testing.T) {
+	RegisterTestingT(t)
+
+	unit := Target{
+		Watch: []string{"simulations", "foo.json"},
+	}
+
+	Expect(unit.BuildFlags()).To(HaveLen(2))
+	Expect(unit.BuildFlags()[0]).To(Equal("-watch=simulations"))
+	Expect(unit.BuildFlags()[1]).To(Equal("-watch=foo.json"))
+}
+
 func Test_Target_BuildFlags_AddSkipImportCheckFlagWhenTrue(t *testing.T) {
 	RegisterTestingT(t)
 
@@ -20,18 +20,23 @@ import (
 )
 
 const (
-	v2ApiSimulation               = "/api/v2/simulation"
-	v2ApiMode                     = "/api/v2/hoverfly/mode"
-	v2ApiDestination              = "/api/v2/hoverfly/destination"
-	v2ApiState                    = "/api/v2/state"
-	v2ApiMiddleware               = "/api/v2/hoverfly/middleware"
-	v2ApiPostServeAction          = "/api/v2/hoverfly/post-serve-action"
-	v2ApiTemplateDataSourceAction = "/api/v2/hoverfly/templating-data-source/csv"
-	v2ApiPac                      = "/api/v2/hoverfly/pac"
-	v2ApiCache                    = "/api/v2/cache"
-	v2ApiLogs                     = "/api/v2/logs"
-	v2ApiHoverfly                 = "/api/v2/hoverfly"
-	v2ApiDiff                     = "/api/v2/diff"
+	v2ApiSimulation                   = "/api/v2/simulation"
+	v2ApiSimulationSources            = "/api/v2/simulation/sources"
+	v2ApiSimulationPairs              = "/api/v2/simulation/pairs"
+	v2ApiMode                         = "/api/v2/hoverfly/mode"
+	v2ApiDestination                  = "/api/v2/hoverfly/destination"
+	v2ApiState                        = "/api/v2/state"
+	v2ApiMiddleware                   = "/api/v2/hoverfly/middleware"
+	v2ApiPostServeAction              = "/api/v2/hoverfly/post-serve-action"
+	v2ApiTemplateDataSourceAction     = "/api/v2/hoverfly/templating-data-source/csv"
+	v2ApiJsonTemplateDataSourceAction = "/api/v2/hoverfly/templating-data-source/json"
+	v2ApiPac                          = "/api/v2/hoverfly/pac"
+	v2ApiCache                        = "/api/v2/cache"
+	v2ApiLogs                         = "/api/v2/logs"
+	v2ApiHoverfly                     = "/api/v2/hoverfly"
+	v2ApiDiff                         = "/api/v2/diff"
+	v2ApiVerify                       = "/api/v2/verify"
+	v2ApiJournalVerify                = "/api/v2/journal/verify"
 
 	v2ApiShutdown = "/api/v2/shutdown"
 	v2ApiHealth   = "/api/health"
@@ -10,11 +10,18 @@ import (
 	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
 )
 
-func ExportSimulation(target configuration.Target, urlPattern string) (v2.SimulationViewV5, error) {
+func ExportSimulation(target configuration.Target, urlPattern string, redactionRules []v2.RedactionRuleView) (v2.SimulationViewV5, error) {
 	view := v2.SimulationViewV5{}
-	requestUrl := v2ApiSimulation
+	query := url.Values{}
 	if len(urlPattern) > 0 {
-		requestUrl = fmt.Sprintf("%s?urlPattern=%s", requestUrl, url.QueryEscape(urlPattern))
+		query.Set("urlPattern", urlPattern)
+	}
+	for _, rule := range redactionRules {
+		addRedactionRuleToQuery(query, rule)
+	}
+	requestUrl := v2ApiSimulation
+	if len(query) > 0 {
+		requestUrl = fmt.Sprintf("%s?%s", requestUrl, query.Encode())
 	}
 	response, err := doRequest(target, "GET", requestUrl, "", nil)
 	if err != nil {
@@ -32,8 +39,30 @@ func ExportSimulation(target configuration.Target, urlPattern string) (v2.Simula
 	return view, err
 }
 
-func ImportSimulation(target configuration.Target, simulationData string) error {
-	response, err := doRequest(target, "PUT", v2ApiSimulation, simulationData, nil)
+func addRedactionRuleToQuery(query url.Values, rule v2.RedactionRuleView) {
+	switch {
+	case rule.Header != "":
+		query.Add("redactHeader", rule.Header)
+	case rule.Query != "":
+		query.Add("redactQuery", rule.Query)
+	case rule.JsonPath != "":
+		query.Add("redactJsonPath", rule.JsonPath)
+	case rule.XPath != "":
+		query.Add("redactXPath", rule.XPath)
+	case rule.Regex != "":
+		query.Add("redactRegex", rule.Regex)
+	}
+	if rule.Hash {
+		query.Set("redactHash", "true")
+	}
+}
+
+func ImportSimulation(target configuration.Target, simulationData string, format string) error {
+	requestUrl := v2ApiSimulation
+	if len(format) > 0 {
+		requestUrl = fmt.Sprintf("%s?format=%s", requestUrl, url.QueryEscape(format))
+	}
+	response, err := doRequest(target, "PUT", requestUrl, simulationData, nil)
 	if err != nil {
 		return err
 	}
@@ -96,3 +125,136 @@ func DeleteSimulations(target configuration.Target) error {
 
 	return nil
 }
+
+// PutSimulationSource adds the simulation to Hoverfly as a named source, replacing the source of that name if
+// there is one
+func PutSimulationSource(target configuration.Target, name, simulationData string) error {
+	response, err := doRequest(target, "PUT", v2ApiSimulationSources+"/"+url.PathEscape(name), simulationData, nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not add simulation source")
+	if err != nil {
+		return err
+	}
+
+	responseBytes, _ := io.ReadAll(response.Body)
+
+	result := &v2.SimulationImportResult{}
+	json.Unmarshal(responseBytes, result)
+
+	for _, warning := range result.WarningMessages {
+		fmt.Println(warning.Message)
+		fmt.Println(warning.DocsLink + "\n")
+	}
+
+	return nil
+}
+
+func GetSimulationSources(target configuration.Target) (v2.SimulationSourcesView, error) {
+	view := v2.SimulationSourcesView{}
+	response, err := doRequest(target, "GET", v2ApiSimulationSources, "", nil)
+	if err != nil {
+		return view, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not retrieve simulation sources")
+	if err != nil {
+		return view, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&view)
+	return view, err
+}
+
+func DeleteSimulationSource(target configuration.Target, name string) error {
+	response, err := doRequest(target, "DELETE", v2ApiSimulationSources+"/"+url.PathEscape(name), "", nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return handleResponseError(response, "Could not delete simulation source")
+}
+
+func GetSimulationPairs(target configuration.Target) (v2.SimulationPairsView, error) {
+	view := v2.SimulationPairsView{}
+	response, err := doRequest(target, "GET", v2ApiSimulationPairs, "", nil)
+	if err != nil {
+		return view, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not retrieve pairs")
+	if err != nil {
+		return view, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&view)
+	return view, err
+}
+
+func GetSimulationPair(target configuration.Target, id string) (v2.SimulationPairView, error) {
+	return doSimulationPairRequest(target, "GET", id, "", "Could not retrieve pair")
+}
+
+// AddSimulationPair adds a pair, given as JSON, after the other pairs of the simulation
+func AddSimulationPair(target configuration.Target, pairData string) (v2.SimulationPairView, error) {
+	return doSimulationPairRequest(target, "POST", "", pairData, "Could not add pair")
+}
+
+// UpdateSimulationPair replaces a pair with one given as JSON, keeping its id and its place in the matching order
+func UpdateSimulationPair(target configuration.Target, id, pairData string) (v2.SimulationPairView, error) {
+	return doSimulationPairRequest(target, "PUT", id, pairData, "Could not update pair")
+}
+
+// PatchSimulationPair disables, enables or moves a pair
+func PatchSimulationPair(target configuration.Target, id string, patchView v2.SimulationPairPatchView) (v2.SimulationPairView, error) {
+	patchData, err := json.Marshal(patchView)
+	if err != nil {
+		return v2.SimulationPairView{}, err
+	}
+	return doSimulationPairRequest(target, "PATCH", id, string(patchData), "Could not update pair")
+}
+
+func DeleteSimulationPair(target configuration.Target, id string) error {
+	response, err := doRequest(target, "DELETE", v2ApiSimulationPairs+"/"+url.PathEscape(id), "", nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	return handleResponseError(response, "Could not delete pair")
+}
+
+func doSimulationPairRequest(target configuration.Target, method, id, body, errorMessage string) (v2.SimulationPairView, error) {
+	view := v2.SimulationPairView{}
+
+	requestUrl := v2ApiSimulationPairs
+	if id != "" {
+		requestUrl += "/" + url.PathEscape(id)
+	}
+
+	response, err := doRequest(target, method, requestUrl, body, nil)
+	if err != nil {
+		return view, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, errorMessage)
+	if err != nil {
+		return view, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&view)
+	return view, err
+}
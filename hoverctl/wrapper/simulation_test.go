@@ -50,7 +50,7 @@ func Test_ExportSimulation_GetsModeFromHoverfly(t *testing.T) {
 	hoverfly.ReplaceSimulation(simulationList)
 	simulationList.RequestResponsePairs[0].Response.Body = responseBody
 
-	view, err := ExportSimulation(target, "")
+	view, err := ExportSimulation(target, "", nil)
 	Expect(err).To(BeNil())
 	Expect(view).To(Equal(simulationList))
 }
@@ -104,7 +104,76 @@ func Test_ExportSimulation_WithUrlPattern(t *testing.T) {
 	hoverfly.ReplaceSimulation(simulationList)
 	simulationList.RequestResponsePairs[0].Response.Body = responseBody
 
-	view, err := ExportSimulation(target, "test-(.+).com")
+	view, err := ExportSimulation(target, "test-(.+).com", nil)
+	Expect(err).To(BeNil())
+	Expect(view).To(Equal(simulationList))
+}
+
+func Test_ExportSimulation_WithRedactionRules(t *testing.T) {
+	RegisterTestingT(t)
+
+	responseBody := `{"simulation": true}`
+	simulationList := v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "GET",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation",
+							},
+						},
+						Query: &v2.QueryMatcherViewV5{
+							"redactHeader": []v2.MatcherViewV5{
+								{
+									Matcher: matchers.Exact,
+									Value:   "Authorization",
+								},
+							},
+							"redactJsonPath": []v2.MatcherViewV5{
+								{
+									Matcher: matchers.Exact,
+									Value:   "$.password",
+								},
+							},
+							"redactHash": []v2.MatcherViewV5{
+								{
+									Matcher: matchers.Exact,
+									Value:   "true",
+								},
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   responseBody,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	}
+
+	simulationListBytes, err := json.Marshal(simulationList)
+	Expect(err).To(BeNil())
+
+	simulationList.RequestResponsePairs[0].Response.Body = string(simulationListBytes[:])
+	hoverfly.ReplaceSimulation(simulationList)
+	simulationList.RequestResponsePairs[0].Response.Body = responseBody
+
+	view, err := ExportSimulation(target, "", []v2.RedactionRuleView{
+		{Header: "Authorization", Hash: true},
+		{JsonPath: "$.password"},
+	})
 	Expect(err).To(BeNil())
 	Expect(view).To(Equal(simulationList))
 }
@@ -112,7 +181,7 @@ func Test_ExportSimulation_WithUrlPattern(t *testing.T) {
 func Test_ExportSimulation_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
 	RegisterTestingT(t)
 
-	_, err := ExportSimulation(inaccessibleTarget, "")
+	_, err := ExportSimulation(inaccessibleTarget, "", nil)
 
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
@@ -152,7 +221,7 @@ func Test_ExportSimulation_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
 		},
 	})
 
-	_, err := ExportSimulation(target, "")
+	_, err := ExportSimulation(target, "", nil)
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(Equal("Could not retrieve simulation\n\ntest error"))
 }
@@ -197,14 +266,59 @@ func Test_ImportSimulation_SendsCorrectHTTPRequest(t *testing.T) {
 		},
 	})
 
-	err := ImportSimulation(target, `{"simulation": true}`)
+	err := ImportSimulation(target, `{"simulation": true}`, "")
+	Expect(err).To(BeNil())
+}
+
+func Test_ImportSimulation_SendsFormat(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "PUT",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation",
+							},
+						},
+						Query: &v2.QueryMatcherViewV5{
+							"format": []v2.MatcherViewV5{
+								{
+									Matcher: matchers.Exact,
+									Value:   "har",
+								},
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	err := ImportSimulation(target, `{"log": {"entries": []}}`, "har")
 	Expect(err).To(BeNil())
 }
 
 func Test_ImportSimulation_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
 	RegisterTestingT(t)
 
-	err := ImportSimulation(inaccessibleTarget, "")
+	err := ImportSimulation(inaccessibleTarget, "", "")
 
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
@@ -244,7 +358,7 @@ func Test_ImportSimulation_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
 		},
 	})
 
-	err := ImportSimulation(target, "")
+	err := ImportSimulation(target, "", "")
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(Equal("Could not import simulation\n\ntest error"))
 }
@@ -378,3 +492,253 @@ func Test_DeleteSimulations_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
 	Expect(err).ToNot(BeNil())
 	Expect(err.Error()).To(Equal("Could not delete simulation\n\ntest error"))
 }
+
+func Test_PutSimulationSource_SendsCorrectHTTPRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "PUT",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/sources/payments",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: "json",
+								Value:   `{"simulation": true}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"simulation": true}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	err := PutSimulationSource(target, "payments", `{"simulation": true}`)
+	Expect(err).To(BeNil())
+}
+
+func Test_GetSimulationSources_GetsSourcesFromHoverfly(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "GET",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/sources",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"sources": [{"name": "payments", "pairs": 2, "webSocketPairs": 0}]}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	view, err := GetSimulationSources(target)
+	Expect(err).To(BeNil())
+	Expect(view.Sources).To(Equal([]v2.SimulationSourceView{{Name: "payments", Pairs: 2}}))
+}
+
+func Test_DeleteSimulationSource_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "DELETE",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/sources/payments",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 404,
+						Body:   `{"error": "Simulation source payments not found"}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	err := DeleteSimulationSource(target, "payments")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not delete simulation source\n\nSimulation source payments not found"))
+}
+
+func Test_GetSimulationPairs_GetsPairsFromHoverfly(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "GET",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/pairs",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"pairs": [{"id": "first", "source": "payments", "disabled": true, "request": {}, "response": {"status": 200}}]}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	view, err := GetSimulationPairs(target)
+	Expect(err).To(BeNil())
+	Expect(view.Pairs).To(HaveLen(1))
+	Expect(view.Pairs[0].Id).To(Equal("first"))
+	Expect(view.Pairs[0].Source).To(Equal("payments"))
+	Expect(view.Pairs[0].Disabled).To(BeTrue())
+}
+
+func Test_PatchSimulationPair_SendsCorrectHTTPRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "PATCH",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/pairs/first",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: "json",
+								Value:   `{"disabled": true}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"id": "first", "disabled": true, "request": {}, "response": {"status": 200}}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	disabled := true
+	view, err := PatchSimulationPair(target, "first", v2.SimulationPairPatchView{Disabled: &disabled})
+	Expect(err).To(BeNil())
+	Expect(view.Id).To(Equal("first"))
+	Expect(view.Disabled).To(BeTrue())
+}
+
+func Test_DeleteSimulationPair_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "DELETE",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/simulation/pairs/first",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 404,
+						Body:   `{"error": "Pair first not found"}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	err := DeleteSimulationPair(target, "first")
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not delete pair\n\nPair first not found"))
+}
@@ -71,3 +71,45 @@ func DeleteCsvDataSource(dataSourceName string, target configuration.Target) err
 
 	return nil
 }
+
+func SetJsonTemplateDataSource(dataSourceName, format, content string, target configuration.Target) error {
+
+	jsonDataSource := v2.JSONDataSourceView{
+		Data:   content,
+		Format: format,
+		Name:   dataSourceName,
+	}
+	marshalledJsonDataSource, err := json.Marshal(jsonDataSource)
+	if err != nil {
+		return err
+	}
+	response, err := doRequest(target, "PUT", v2ApiJsonTemplateDataSourceAction, string(marshalledJsonDataSource), nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not set "+format+" data source")
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func DeleteJsonDataSource(dataSourceName string, target configuration.Target) error {
+
+	response, err := doRequest(target, "DELETE", v2ApiJsonTemplateDataSourceAction+"/"+dataSourceName, "", nil)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not delete data source")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package wrapper
+
+import (
+	"encoding/json"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/hoverctl/configuration"
+)
+
+// VerifySimulation asks Hoverfly to replay its simulation against the real services and report the differences
+func VerifySimulation(target configuration.Target, diffFilterView v2.DiffFilterView) (v2.VerificationView, error) {
+	view := v2.VerificationView{}
+
+	body, err := json.Marshal(diffFilterView)
+	if err != nil {
+		return view, err
+	}
+
+	response, err := doRequest(target, "POST", v2ApiVerify, string(body), nil)
+	if err != nil {
+		return view, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not verify simulation")
+	if err != nil {
+		return view, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&view)
+	return view, err
+}
+
+// VerifyRequests asks Hoverfly to check the requests in its journal against the expectation
+func VerifyRequests(target configuration.Target, requestVerificationView v2.RequestVerificationView) (v2.RequestVerificationResultView, error) {
+	view := v2.RequestVerificationResultView{}
+
+	body, err := json.Marshal(requestVerificationView)
+	if err != nil {
+		return view, err
+	}
+
+	response, err := doRequest(target, "POST", v2ApiJournalVerify, string(body), nil)
+	if err != nil {
+		return view, err
+	}
+
+	defer response.Body.Close()
+
+	err = handleResponseError(response, "Could not verify requests")
+	if err != nil {
+		return view, err
+	}
+
+	err = json.NewDecoder(response.Body).Decode(&view)
+	return view, err
+}
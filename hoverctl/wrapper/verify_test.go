@@ -0,0 +1,160 @@
+package wrapper
+
+import (
+	"testing"
+
+	v2 "github.com/SpectoLabs/hoverfly/core/handlers/v2"
+	"github.com/SpectoLabs/hoverfly/core/matching/matchers"
+	. "github.com/onsi/gomega"
+)
+
+func Test_VerifySimulation_SendsCorrectHTTPRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "POST",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/verify",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Json,
+								Value:   `{"excludedHeaders": ["Date"], "excludedResponseFields": null}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"passed": false, "results": [{"request": {"method": "GET", "host": "test.com", "path": "/"}, "passed": false, "error": "connection refused"}]}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	verification, err := VerifySimulation(target, v2.DiffFilterView{ExcludedHeaders: []string{"Date"}})
+	Expect(err).To(BeNil())
+
+	Expect(verification.Passed).To(BeFalse())
+	Expect(verification.Results).To(HaveLen(1))
+	Expect(verification.Results[0].Request.Host).To(Equal("test.com"))
+	Expect(verification.Results[0].Error).To(Equal("connection refused"))
+}
+
+func Test_VerifySimulation_ErrorsWhen_HoverflyNotAccessible(t *testing.T) {
+	RegisterTestingT(t)
+
+	_, err := VerifySimulation(inaccessibleTarget, v2.DiffFilterView{})
+
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not connect to Hoverfly at something:1234"))
+}
+
+func Test_VerifyRequests_SendsCorrectHTTPRequest(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "POST",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/verify",
+							},
+						},
+						Body: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.JsonPartial,
+								Value:   `{"request": {"path": [{"matcher": "exact", "value": "/hello"}]}, "exactly": 2}`,
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 200,
+						Body:   `{"passed": false, "message": "Expected exactly 2 matching requests but received 1 matching request", "count": 1}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	exactly := 2
+	result, err := VerifyRequests(target, v2.RequestVerificationView{
+		Request: &v2.RequestMatcherViewV5{
+			Path: []v2.MatcherViewV5{{Matcher: matchers.Exact, Value: "/hello"}},
+		},
+		Exactly: &exactly,
+	})
+	Expect(err).To(BeNil())
+
+	Expect(result.Passed).To(BeFalse())
+	Expect(result.Count).To(Equal(1))
+	Expect(result.Message).To(Equal("Expected exactly 2 matching requests but received 1 matching request"))
+}
+
+func Test_VerifyRequests_ErrorsWhen_HoverflyReturnsNon200(t *testing.T) {
+	RegisterTestingT(t)
+
+	hoverfly.DeleteSimulation()
+	hoverfly.PutSimulation(v2.SimulationViewV5{
+		DataViewV5: v2.DataViewV5{
+			RequestResponsePairs: []v2.RequestMatcherResponsePairViewV5{
+				{
+					RequestMatcher: v2.RequestMatcherViewV5{
+						Method: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "POST",
+							},
+						},
+						Path: []v2.MatcherViewV5{
+							{
+								Matcher: matchers.Exact,
+								Value:   "/api/v2/journal/verify",
+							},
+						},
+					},
+					Response: v2.ResponseDetailsViewV5{
+						Status: 400,
+						Body:   `{"error": "No \"request\" or \"inOrder\" object in verification"}`,
+					},
+				},
+			},
+		},
+		MetaView: v2.MetaView{
+			SchemaVersion: "v2",
+		},
+	})
+
+	_, err := VerifyRequests(target, v2.RequestVerificationView{})
+	Expect(err).ToNot(BeNil())
+	Expect(err.Error()).To(Equal("Could not verify requests\n\nNo \"request\" or \"inOrder\" object in verification"))
+}